@@ -0,0 +1,437 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	dbsql "github.com/databricks/databricks-sql-go"
+	"github.com/databricks/databricks-sql-go/auth"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+const (
+	// Scope requested for OAuth tokens used against the SQL endpoints
+	oauthScopeAllAPIs = "all-apis"
+
+	// How long before expiry a cached OAuth token is renewed, unless
+	// OptionOAuthRefreshBeforeExpiry is set
+	defaultTokenRefreshBuffer = 5 * time.Minute
+
+	// Limit of each token request, unless OptionHTTPRequestTimeout is set
+	defaultAuthRequestTimeout = 30 * time.Second
+)
+
+// builtinAuthTypes lists the auth types implemented by the driver
+var builtinAuthTypes = []string{
+	OptionValueAuthTypePAT,
+	OptionValueAuthTypeOAuthM2M,
+	OptionValueAuthTypeOAuthU2M,
+	OptionValueAuthTypeAzureMSI,
+	OptionValueAuthTypeOAuthExternal,
+	OptionValueAuthTypeOAuthFederation,
+	OptionValueAuthTypeGoogleCredentials,
+}
+
+// parseAuthType validates and normalizes a value for OptionAuthType
+func parseAuthType(value string) (string, error) {
+	authType := strings.ToLower(value)
+	if authType == "" || slices.Contains(builtinAuthTypes, authType) {
+		return authType, nil
+	}
+	if _, ok := lookupAuthType(authType); !ok {
+		return "", adbc.Error{
+			Code: adbc.StatusInvalidArgument,
+			Msg:  fmt.Sprintf("invalid auth type: %s (supported: '%s')", value, strings.Join(supportedAuthTypes(), "', '")),
+		}
+	}
+	return authType, nil
+}
+
+// reauthenticator is an Authenticator whose current token can be
+// discarded after the server rejects it
+type reauthenticator interface {
+	auth.Authenticator
+	invalidate()
+}
+
+// tokenAuthenticator adapts an oauth2.TokenSource to the databricks-sql-go
+// Authenticator interface, setting a bearer token on every request.
+type tokenAuthenticator struct {
+	base          oauth2.TokenSource
+	refreshBuffer time.Duration
+
+	mu     sync.Mutex
+	source oauth2.TokenSource
+}
+
+func newTokenAuthenticator(source oauth2.TokenSource, refreshBuffer time.Duration) *tokenAuthenticator {
+	return &tokenAuthenticator{
+		base:          source,
+		refreshBuffer: refreshBuffer,
+		source:        oauth2.ReuseTokenSourceWithExpiry(nil, source, refreshBuffer),
+	}
+}
+
+func (a *tokenAuthenticator) Authenticate(r *http.Request) error {
+	token, err := a.token()
+	if err != nil {
+		return err
+	}
+	token.SetAuthHeader(r)
+	return nil
+}
+
+// token returns the cached token, obtaining a new one if it nears expiry
+func (a *tokenAuthenticator) token() (*oauth2.Token, error) {
+	a.mu.Lock()
+	source := a.source
+	a.mu.Unlock()
+
+	token, err := source.Token()
+	if err != nil {
+		return nil, fmt.Errorf("failed to obtain OAuth token: %w", err)
+	}
+	return token, nil
+}
+
+// invalidate drops the cached token so the next request obtains a new one
+func (a *tokenAuthenticator) invalidate() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.source = oauth2.ReuseTokenSourceWithExpiry(nil, a.base, a.refreshBuffer)
+}
+
+// externalTokenAuthenticator sends an OAuth token obtained by the
+// application, which may replace it at any time through
+// OptionOAuthExternalToken without reopening connections.
+type externalTokenAuthenticator struct {
+	mu    sync.RWMutex
+	token string
+}
+
+func (a *externalTokenAuthenticator) get() string {
+	if a == nil {
+		return ""
+	}
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.token
+}
+
+func (a *externalTokenAuthenticator) set(token string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.token = token
+}
+
+func (a *externalTokenAuthenticator) Authenticate(r *http.Request) error {
+	token := a.get()
+	if token == "" {
+		return fmt.Errorf("no external OAuth token is set")
+	}
+	r.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+// invalidate does nothing: only the application can supply a new token
+func (a *externalTokenAuthenticator) invalidate() {}
+
+// reauthTransport retries a request once with a fresh token when the
+// server rejects the token it was sent with 401 Unauthorized, for example
+// because it was revoked or replaced before its expiry.
+type reauthTransport struct {
+	base  http.RoundTripper
+	authr reauthenticator
+}
+
+func (t *reauthTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// Requests that cannot be replayed, or that were not sent with a
+	// workspace token (such as result downloads from cloud storage), are
+	// passed through unchanged
+	replayable := req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+	sentToken := req.Header.Get("Authorization")
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized || !replayable || sentToken == "" {
+		return resp, err
+	}
+
+	t.authr.invalidate()
+	retry := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, bodyErr := req.GetBody()
+		if bodyErr != nil {
+			return resp, nil
+		}
+		retry.Body = body
+	}
+	if authErr := t.authr.Authenticate(retry); authErr != nil || retry.Header.Get("Authorization") == sentToken {
+		// No different token is available; report the original failure
+		if retry.Body != nil {
+			_ = retry.Body.Close()
+		}
+		return resp, nil
+	}
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+	return t.base.RoundTrip(retry)
+}
+
+// clientCredentialsTokenSource fetches a new token on every call; caching
+// and renewal are handled by the reuse source wrapping it.
+type clientCredentialsTokenSource struct {
+	config clientcredentials.Config
+	client *http.Client
+}
+
+func (s *clientCredentialsTokenSource) Token() (*oauth2.Token, error) {
+	return s.config.Token(oauthContext(s.client))
+}
+
+// TokenProvider supplies access tokens from an application's own
+// credential infrastructure; see NewDatabaseWithTokenProvider. Token
+// returns the token and its expiry, or the zero time if it does not
+// expire. Tokens are cached and a new one is requested shortly before
+// the previous one expires. The context carries the *http.Client the
+// driver requests tokens with, under oauth2.HTTPClient.
+type TokenProvider interface {
+	Token(ctx context.Context) (string, time.Time, error)
+}
+
+// providerTokenSource adapts a TokenProvider to an oauth2.TokenSource
+type providerTokenSource struct {
+	provider TokenProvider
+	client   *http.Client
+}
+
+func (s *providerTokenSource) Token() (*oauth2.Token, error) {
+	accessToken, expiry, err := s.provider.Token(oauthContext(s.client))
+	if err != nil {
+		return nil, err
+	}
+	if accessToken == "" {
+		return nil, fmt.Errorf("token provider returned an empty token")
+	}
+	return &oauth2.Token{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		Expiry:      expiry,
+	}, nil
+}
+
+// oauthContext returns the context of token requests, in which the
+// oauth2 package sends them with client
+func oauthContext(client *http.Client) context.Context {
+	ctx := context.Background()
+	if client == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, oauth2.HTTPClient, client)
+}
+
+// authHTTPClient returns the client sending token requests: with the TLS
+// and proxy settings once Open has created it, and only the proxy before
+func (d *databaseImpl) authHTTPClient() *http.Client {
+	if d.authClient != nil {
+		return d.authClient
+	}
+	return &http.Client{
+		Transport: d.newPooledTransport(nil, d.proxyFunc()),
+		Timeout:   cmp.Or(d.httpRequestTimeout, defaultAuthRequestTimeout),
+	}
+}
+
+// tokenRefreshBuffer returns how long before expiry tokens are renewed
+func (d *databaseImpl) tokenRefreshBuffer() time.Duration {
+	if d.oauthRefreshBeforeExpiry > 0 {
+		return d.oauthRefreshBeforeExpiry
+	}
+	return defaultTokenRefreshBuffer
+}
+
+// oidcTokenEndpoint returns the workspace OAuth token endpoint
+func (d *databaseImpl) oidcTokenEndpoint() string {
+	return d.workspaceURL("/oidc/v1/token")
+}
+
+// resolveAuthType determines the authentication mechanism, either from
+// OptionAuthType or inferred from the credentials that were supplied.
+func (d *databaseImpl) resolveAuthType() (string, error) {
+	if d.authType != "" {
+		return d.authType, nil
+	}
+
+	hasOAuth := d.oauthClientID != "" || d.oauthClientSecret != ""
+
+	if d.externalToken.get() != "" {
+		if d.accessToken != "" || hasOAuth {
+			return "", adbc.Error{
+				Code: adbc.StatusInvalidArgument,
+				Msg:  "[db] cannot specify both an external OAuth token and other credentials",
+			}
+		}
+		return OptionValueAuthTypeOAuthExternal, nil
+	}
+
+	if d.accessToken == "" && !hasOAuth {
+		return "", adbc.Error{
+			Code: adbc.StatusInvalidArgument,
+			Msg:  "[db] access token or OAuth config is required",
+		}
+	} else if d.accessToken != "" && hasOAuth {
+		return "", adbc.Error{
+			Code: adbc.StatusInvalidArgument,
+			Msg:  "[db] cannot specify both access token and OAuth config",
+		}
+	}
+
+	if d.accessToken != "" {
+		return OptionValueAuthTypePAT, nil
+	}
+	return OptionValueAuthTypeOAuthM2M, nil
+}
+
+// resolveAuthOptions returns the connector options that authenticate
+// requests according to the configured auth type, along with the
+// authenticator to consult when a token is rejected, if it can renew it.
+func (d *databaseImpl) resolveAuthOptions() ([]dbsql.ConnOption, reauthenticator, error) {
+	authr, err := d.resolveAuthenticator()
+	if err != nil {
+		return nil, nil, err
+	}
+	if authr == nil {
+		return []dbsql.ConnOption{dbsql.WithAccessToken(d.accessToken)}, nil, nil
+	}
+	return []dbsql.ConnOption{dbsql.WithAuthenticator(authr)}, authr, nil
+}
+
+// resolveAuthenticator creates the authenticator for the configured auth
+// type, or returns nil for a personal access token.
+func (d *databaseImpl) resolveAuthenticator() (reauthenticator, error) {
+	if d.tokenProvider != nil {
+		if d.authType != "" || d.accessToken != "" || d.oauthClientID != "" || d.oauthClientSecret != "" || d.externalToken.get() != "" {
+			return nil, adbc.Error{
+				Code: adbc.StatusInvalidArgument,
+				Msg:  "[db] cannot combine a token provider with other credentials",
+			}
+		}
+		source := &providerTokenSource{provider: d.tokenProvider, client: d.authHTTPClient()}
+		return newTokenAuthenticator(source, d.tokenRefreshBuffer()), nil
+	}
+
+	authType, err := d.resolveAuthType()
+	if err != nil {
+		return nil, err
+	}
+
+	switch authType {
+	case OptionValueAuthTypePAT:
+		if d.accessToken == "" {
+			return nil, adbc.Error{
+				Code: adbc.StatusInvalidArgument,
+				Msg:  fmt.Sprintf("[db] %s is required for auth type '%s'", OptionAccessToken, authType),
+			}
+		}
+		return nil, nil
+	case OptionValueAuthTypeOAuthM2M:
+		if d.oauthClientID == "" || d.oauthClientSecret == "" {
+			return nil, adbc.Error{
+				Code: adbc.StatusInvalidArgument,
+				Msg:  fmt.Sprintf("[db] %s and %s are required for auth type '%s'", OptionOAuthClientID, OptionOAuthClientSecret, authType),
+			}
+		}
+		return d.newOAuthM2MAuthenticator(), nil
+	case OptionValueAuthTypeOAuthU2M:
+		return d.newOAuthU2MAuthenticator(), nil
+	case OptionValueAuthTypeAzureMSI:
+		return d.newAzureMSIAuthenticator(), nil
+	case OptionValueAuthTypeOAuthFederation:
+		authr, err := d.newFederationAuthenticator()
+		if err != nil {
+			return nil, adbc.Error{
+				Code: adbc.StatusInvalidArgument,
+				Msg:  fmt.Sprintf("[db] %v", err),
+			}
+		}
+		return authr, nil
+	case OptionValueAuthTypeGoogleCredentials:
+		authr, err := d.newGoogleCredentialsAuthenticator()
+		if err != nil {
+			return nil, adbc.Error{
+				Code: adbc.StatusInvalidArgument,
+				Msg:  fmt.Sprintf("[db] %v", err),
+			}
+		}
+		return authr, nil
+	case OptionValueAuthTypeOAuthExternal:
+		if d.externalToken.get() == "" {
+			return nil, adbc.Error{
+				Code: adbc.StatusInvalidArgument,
+				Msg:  fmt.Sprintf("[db] %s is required for auth type '%s'", OptionOAuthExternalToken, authType),
+			}
+		}
+		return d.externalToken, nil
+	default:
+		factory, ok := lookupAuthType(authType)
+		if !ok {
+			return nil, adbc.Error{
+				Code: adbc.StatusInvalidArgument,
+				Msg:  fmt.Sprintf("[db] unsupported auth type: %s", authType),
+			}
+		}
+		authr, err := d.newRegisteredAuthenticator(factory)
+		if err != nil {
+			return nil, adbc.Error{
+				Code: adbc.StatusInvalidArgument,
+				Msg:  fmt.Sprintf("[db] failed to initialize auth type '%s': %v", authType, err),
+			}
+		}
+		return authr, nil
+	}
+}
+
+// newOAuthM2MAuthenticator creates an authenticator that obtains tokens for
+// a service principal with the client credentials grant, renewing them
+// shortly before they expire.
+func (d *databaseImpl) newOAuthM2MAuthenticator() *tokenAuthenticator {
+	tokenEndpoint := d.oauthTokenEndpoint
+	if tokenEndpoint == "" {
+		tokenEndpoint = d.oidcTokenEndpoint()
+	}
+
+	source := &clientCredentialsTokenSource{
+		config: clientcredentials.Config{
+			ClientID:     d.oauthClientID,
+			ClientSecret: d.oauthClientSecret,
+			TokenURL:     tokenEndpoint,
+			Scopes:       []string{oauthScopeAllAPIs},
+			AuthStyle:    oauth2.AuthStyleInHeader,
+		},
+		client: d.authHTTPClient(),
+	}
+	return newTokenAuthenticator(source, d.tokenRefreshBuffer())
+}
@@ -0,0 +1,121 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+const (
+	// Azure Instance Metadata Service endpoint issuing managed identity tokens
+	azureIMDSTokenEndpoint = "http://169.254.169.254/metadata/identity/oauth2/token"
+	azureIMDSAPIVersion    = "2018-02-01"
+
+	// Application ID of the Azure Databricks first-party resource
+	azureDatabricksResourceID = "2ff814a6-3304-4ab8-85cb-cd0e6f879c1d"
+)
+
+// azureMSITokenSource fetches Azure AD tokens for the Databricks resource
+// from the instance metadata service of the VM or pod it runs on. When
+// clientID is set it selects a user-assigned identity; otherwise the
+// system-assigned identity is used.
+type azureMSITokenSource struct {
+	endpoint string
+	clientID string
+	client   *http.Client
+}
+
+// newAzureMSIAuthenticator creates an authenticator using the managed
+// identity of the Azure host, renewing tokens shortly before they expire.
+func (d *databaseImpl) newAzureMSIAuthenticator() *tokenAuthenticator {
+	endpoint := d.oauthTokenEndpoint
+	if endpoint == "" {
+		endpoint = azureIMDSTokenEndpoint
+	}
+
+	source := &azureMSITokenSource{
+		endpoint: endpoint,
+		clientID: d.oauthClientID,
+		// IMDS is link-local and must never be reached through a proxy
+		client: &http.Client{
+			Transport: &http.Transport{Proxy: nil},
+			Timeout:   30 * time.Second,
+		},
+	}
+	return newTokenAuthenticator(source, d.tokenRefreshBuffer())
+}
+
+func (s *azureMSITokenSource) Token() (*oauth2.Token, error) {
+	query := url.Values{}
+	query.Set("api-version", azureIMDSAPIVersion)
+	query.Set("resource", azureDatabricksResourceID)
+	if s.clientID != "" {
+		query.Set("client_id", s.clientID)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, s.endpoint+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create managed identity token request: %w", err)
+	}
+	req.Header.Set("Metadata", "true")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach Azure instance metadata service: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		var body struct {
+			Error            string `json:"error"`
+			ErrorDescription string `json:"error_description"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&body)
+		return nil, fmt.Errorf("managed identity token request failed with status %d: %s %s", resp.StatusCode, body.Error, body.ErrorDescription)
+	}
+
+	// IMDS encodes the numeric fields as strings
+	var body struct {
+		AccessToken string      `json:"access_token"`
+		TokenType   string      `json:"token_type"`
+		ExpiresOn   json.Number `json:"expires_on"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode managed identity token: %w", err)
+	}
+	if body.AccessToken == "" {
+		return nil, fmt.Errorf("managed identity token response did not include an access token")
+	}
+
+	token := &oauth2.Token{
+		AccessToken: body.AccessToken,
+		TokenType:   body.TokenType,
+	}
+	if body.ExpiresOn != "" {
+		expiresOn, err := strconv.ParseInt(body.ExpiresOn.String(), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid managed identity token expiry %q: %w", body.ExpiresOn, err)
+		}
+		token.Expiry = time.Unix(expiresOn, 0)
+	}
+	return token, nil
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAzureMSIAuthenticator(t *testing.T) {
+	expiresOn := time.Now().Add(time.Hour).Unix()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "true", r.Header.Get("Metadata"))
+		assert.Equal(t, azureDatabricksResourceID, r.URL.Query().Get("resource"))
+		assert.Equal(t, "identity", r.URL.Query().Get("client_id"))
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"access_token": "msi-token",
+			"token_type":   "Bearer",
+			"expires_on":   strconv.FormatInt(expiresOn, 10),
+		})
+	}))
+	defer srv.Close()
+
+	d := &databaseImpl{
+		authType:           OptionValueAuthTypeAzureMSI,
+		oauthClientID:      "identity",
+		oauthTokenEndpoint: srv.URL,
+	}
+	opts, _, err := d.resolveAuthOptions()
+	require.NoError(t, err)
+	assert.Len(t, opts, 1)
+
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	require.NoError(t, d.newAzureMSIAuthenticator().Authenticate(req))
+	assert.Equal(t, "Bearer msi-token", req.Header.Get("Authorization"))
+}
+
+func TestAzureMSIAuthenticatorError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"error":             "invalid_request",
+			"error_description": "Identity not found",
+		})
+	}))
+	defer srv.Close()
+
+	d := &databaseImpl{oauthTokenEndpoint: srv.URL}
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	err := d.newAzureMSIAuthenticator().Authenticate(req)
+	assert.ErrorContains(t, err, "Identity not found")
+}
@@ -0,0 +1,182 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+const (
+	tokenExchangeGrantType = "urn:ietf:params:oauth:grant-type:token-exchange"
+	jwtTokenType           = "urn:ietf:params:oauth:token-type:jwt"
+
+	// Environment variables set in GitHub Actions jobs with the
+	// id-token: write permission
+	githubOIDCRequestURLEnv   = "ACTIONS_ID_TOKEN_REQUEST_URL"
+	githubOIDCRequestTokenEnv = "ACTIONS_ID_TOKEN_REQUEST_TOKEN"
+)
+
+// federationTokenSource exchanges an OIDC identity token issued to the
+// workload (a Kubernetes service account token, a GitHub Actions token,
+// ...) for a Databricks OAuth token, as configured by a federation policy.
+type federationTokenSource struct {
+	tokenURL string
+	clientID string
+	// identityToken returns the current OIDC token of the workload
+	identityToken func(ctx context.Context) (string, error)
+	client        *http.Client
+}
+
+// newFederationAuthenticator creates an authenticator using workload
+// identity federation, renewing tokens shortly before they expire.
+func (d *databaseImpl) newFederationAuthenticator() (*tokenAuthenticator, error) {
+	tokenEndpoint := d.oauthTokenEndpoint
+	if tokenEndpoint == "" {
+		tokenEndpoint = d.oidcTokenEndpoint()
+	}
+	audience := d.oauthIdentityTokenAudience
+	if audience == "" {
+		audience = tokenEndpoint
+	}
+
+	source := &federationTokenSource{
+		tokenURL: tokenEndpoint,
+		clientID: d.oauthClientID,
+		client:   d.authHTTPClient(),
+	}
+
+	switch {
+	case d.oauthIdentityTokenFile != "":
+		path := d.oauthIdentityTokenFile
+		source.identityToken = func(context.Context) (string, error) {
+			// Re-read on every exchange; projected tokens are rotated in place
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return "", fmt.Errorf("failed to read identity token: %w", err)
+			}
+			return strings.TrimSpace(string(data)), nil
+		}
+	case os.Getenv(githubOIDCRequestURLEnv) != "":
+		requestURL := os.Getenv(githubOIDCRequestURLEnv)
+		requestToken := os.Getenv(githubOIDCRequestTokenEnv)
+		source.identityToken = func(ctx context.Context) (string, error) {
+			return fetchGitHubIdentityToken(ctx, source.client, requestURL, requestToken, audience)
+		}
+	default:
+		return nil, errors.New("no identity token available: set " + OptionOAuthIdentityTokenFile + " or run in GitHub Actions with id-token permission")
+	}
+
+	return newTokenAuthenticator(source, d.tokenRefreshBuffer()), nil
+}
+
+func (s *federationTokenSource) Token() (*oauth2.Token, error) {
+	ctx := context.Background()
+	subjectToken, err := s.identityToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if subjectToken == "" {
+		return nil, errors.New("identity token is empty")
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", tokenExchangeGrantType)
+	form.Set("subject_token", subjectToken)
+	form.Set("subject_token_type", jwtTokenType)
+	form.Set("scope", oauthScopeAllAPIs)
+	if s.clientID != "" {
+		form.Set("client_id", s.clientID)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create token exchange request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("token exchange request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var body struct {
+		AccessToken      string `json:"access_token"`
+		TokenType        string `json:"token_type"`
+		ExpiresIn        int64  `json:"expires_in"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil && resp.StatusCode == http.StatusOK {
+		return nil, fmt.Errorf("failed to decode token exchange response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK || body.AccessToken == "" {
+		return nil, fmt.Errorf("token exchange failed with status %d: %s %s", resp.StatusCode, body.Error, body.ErrorDescription)
+	}
+
+	token := &oauth2.Token{
+		AccessToken: body.AccessToken,
+		TokenType:   body.TokenType,
+	}
+	if body.ExpiresIn > 0 {
+		token.Expiry = time.Now().Add(time.Duration(body.ExpiresIn) * time.Second)
+	}
+	return token, nil
+}
+
+// fetchGitHubIdentityToken requests an OIDC token for the running GitHub
+// Actions job
+func fetchGitHubIdentityToken(ctx context.Context, client *http.Client, requestURL, requestToken, audience string) (string, error) {
+	u, err := url.Parse(requestURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid %s: %w", githubOIDCRequestURLEnv, err)
+	}
+	query := u.Query()
+	query.Set("audience", audience)
+	u.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+requestToken)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to request GitHub identity token: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("GitHub identity token request failed with status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Value string `json:"value"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("failed to decode GitHub identity token: %w", err)
+	}
+	return body.Value, nil
+}
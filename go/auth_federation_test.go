@@ -0,0 +1,102 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTokenExchangeServer(t *testing.T, expectedSubject string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, tokenExchangeGrantType, r.PostForm.Get("grant_type"))
+		assert.Equal(t, jwtTokenType, r.PostForm.Get("subject_token_type"))
+		assert.Equal(t, "sp-client", r.PostForm.Get("client_id"))
+		if r.PostForm.Get("subject_token") != expectedSubject {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"access_token": "databricks-token",
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+		})
+	}))
+}
+
+func TestFederationAuthenticatorWithTokenFile(t *testing.T) {
+	srv := newTokenExchangeServer(t, "k8s-jwt")
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(path, []byte("k8s-jwt\n"), 0o600))
+
+	d := &databaseImpl{
+		authType:               OptionValueAuthTypeOAuthFederation,
+		oauthClientID:          "sp-client",
+		oauthTokenEndpoint:     srv.URL,
+		oauthIdentityTokenFile: path,
+	}
+	_, authr, err := d.resolveAuthOptions()
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	require.NoError(t, authr.Authenticate(req))
+	assert.Equal(t, "Bearer databricks-token", req.Header.Get("Authorization"))
+}
+
+func TestFederationAuthenticatorWithGitHubActions(t *testing.T) {
+	srv := newTokenExchangeServer(t, "github-jwt")
+	defer srv.Close()
+
+	github := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer request-token", r.Header.Get("Authorization"))
+		assert.Equal(t, srv.URL, r.URL.Query().Get("audience"))
+		_ = json.NewEncoder(w).Encode(map[string]string{"value": "github-jwt"})
+	}))
+	defer github.Close()
+
+	t.Setenv(githubOIDCRequestURLEnv, github.URL+"/token?api-version=2.0")
+	t.Setenv(githubOIDCRequestTokenEnv, "request-token")
+
+	d := &databaseImpl{
+		oauthClientID:      "sp-client",
+		oauthTokenEndpoint: srv.URL,
+	}
+	authr, err := d.newFederationAuthenticator()
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	require.NoError(t, authr.Authenticate(req))
+	assert.Equal(t, "Bearer databricks-token", req.Header.Get("Authorization"))
+}
+
+func TestFederationAuthenticatorWithoutIdentityToken(t *testing.T) {
+	t.Setenv(githubOIDCRequestURLEnv, "")
+
+	d := &databaseImpl{authType: OptionValueAuthTypeOAuthFederation}
+	_, _, err := d.resolveAuthOptions()
+	assert.ErrorContains(t, err, OptionOAuthIdentityTokenFile)
+}
@@ -0,0 +1,236 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/jws"
+	"golang.org/x/oauth2/jwt"
+)
+
+const (
+	googleTokenEndpoint  = "https://oauth2.googleapis.com/token"
+	googleMetadataServer = "http://metadata.google.internal/computeMetadata/v1"
+	googleCloudScope     = "https://www.googleapis.com/auth/cloud-platform"
+
+	// Environment variable naming the Application Default Credentials key
+	googleCredentialsEnv = "GOOGLE_APPLICATION_CREDENTIALS"
+
+	// Header carrying the service account's Google access token, which
+	// Databricks on GCP uses to reach resources in the customer project
+	googleAccessTokenHeader = "X-Databricks-GCP-SA-Access-Token"
+)
+
+// googleServiceAccountKey holds the fields of a service account JSON key
+// used to sign token requests
+type googleServiceAccountKey struct {
+	Type         string `json:"type"`
+	ClientEmail  string `json:"client_email"`
+	PrivateKey   string `json:"private_key"`
+	PrivateKeyID string `json:"private_key_id"`
+	TokenURI     string `json:"token_uri"`
+}
+
+// googleCredentialsAuthenticator authenticates to a GCP workspace as a
+// Google service account. Requests carry a Google ID token for the
+// workspace URL as the bearer token, along with a Google access token for
+// the service account.
+type googleCredentialsAuthenticator struct {
+	idToken     *tokenAuthenticator
+	accessToken *tokenAuthenticator
+}
+
+// newGoogleCredentialsAuthenticator creates an authenticator from the
+// configured service account key, falling back to Application Default
+// Credentials: the key named by GOOGLE_APPLICATION_CREDENTIALS, or the
+// service account attached to the GCE instance or GKE workload.
+func (d *databaseImpl) newGoogleCredentialsAuthenticator() (*googleCredentialsAuthenticator, error) {
+	audience := "https://" + d.serverHostname
+
+	credentials := d.googleCredentials
+	if credentials == "" {
+		credentials = os.Getenv(googleCredentialsEnv)
+	}
+
+	var idSource, accessSource oauth2.TokenSource
+	if credentials != "" {
+		key, err := loadGoogleServiceAccountKey(credentials)
+		if err != nil {
+			return nil, err
+		}
+		tokenURL := d.oauthTokenEndpoint
+		if tokenURL == "" {
+			tokenURL = key.TokenURI
+		}
+		if tokenURL == "" {
+			tokenURL = googleTokenEndpoint
+		}
+
+		config := jwt.Config{
+			Email:        key.ClientEmail,
+			PrivateKey:   []byte(key.PrivateKey),
+			PrivateKeyID: key.PrivateKeyID,
+			TokenURL:     tokenURL,
+		}
+		idConfig := config
+		idConfig.PrivateClaims = map[string]any{"target_audience": audience}
+		idConfig.UseIDToken = true
+		accessConfig := config
+		accessConfig.Scopes = []string{googleCloudScope}
+
+		ctx := oauthContext(d.authHTTPClient())
+		idSource = idConfig.TokenSource(ctx)
+		accessSource = accessConfig.TokenSource(ctx)
+	} else {
+		server := d.oauthTokenEndpoint
+		if server == "" {
+			server = googleMetadataServer
+		}
+		// The metadata server is link-local and must never be reached
+		// through a proxy
+		client := &http.Client{
+			Transport: &http.Transport{Proxy: nil},
+			Timeout:   30 * time.Second,
+		}
+		idSource = &googleMetadataTokenSource{server: server, audience: audience, client: client}
+		accessSource = &googleMetadataTokenSource{server: server, client: client}
+	}
+
+	return &googleCredentialsAuthenticator{
+		idToken:     newTokenAuthenticator(idSource, d.tokenRefreshBuffer()),
+		accessToken: newTokenAuthenticator(accessSource, d.tokenRefreshBuffer()),
+	}, nil
+}
+
+// loadGoogleServiceAccountKey reads a service account key given either
+// inline as JSON or as the path to a key file
+func loadGoogleServiceAccountKey(value string) (*googleServiceAccountKey, error) {
+	data := []byte(value)
+	if !strings.HasPrefix(strings.TrimSpace(value), "{") {
+		var err error
+		data, err = os.ReadFile(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read Google credentials: %w", err)
+		}
+	}
+
+	var key googleServiceAccountKey
+	if err := json.Unmarshal(data, &key); err != nil {
+		return nil, fmt.Errorf("failed to parse Google credentials: %w", err)
+	}
+	if key.Type != "service_account" {
+		return nil, fmt.Errorf("unsupported Google credentials type '%s': a service account key is required", key.Type)
+	}
+	if key.ClientEmail == "" || key.PrivateKey == "" {
+		return nil, fmt.Errorf("Google service account key is missing client_email or private_key")
+	}
+	return &key, nil
+}
+
+func (a *googleCredentialsAuthenticator) Authenticate(r *http.Request) error {
+	if err := a.idToken.Authenticate(r); err != nil {
+		return err
+	}
+	token, err := a.accessToken.token()
+	if err != nil {
+		return err
+	}
+	r.Header.Set(googleAccessTokenHeader, token.AccessToken)
+	return nil
+}
+
+func (a *googleCredentialsAuthenticator) invalidate() {
+	a.idToken.invalidate()
+	a.accessToken.invalidate()
+}
+
+// googleMetadataTokenSource fetches tokens for the default service account
+// from the GCE metadata server: an ID token when audience is set, and an
+// access token otherwise.
+type googleMetadataTokenSource struct {
+	server   string
+	audience string
+	client   *http.Client
+}
+
+func (s *googleMetadataTokenSource) Token() (*oauth2.Token, error) {
+	path := "/instance/service-accounts/default/token"
+	if s.audience != "" {
+		query := url.Values{}
+		query.Set("audience", s.audience)
+		query.Set("format", "full")
+		path = "/instance/service-accounts/default/identity?" + query.Encode()
+	}
+
+	req, err := http.NewRequest(http.MethodGet, s.server+path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create metadata server request: %w", err)
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach GCE metadata server: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read metadata server response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("metadata server token request failed with status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	if s.audience != "" {
+		// The identity endpoint returns the bare JWT
+		idToken := strings.TrimSpace(string(body))
+		claims, err := jws.Decode(idToken)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode Google ID token: %w", err)
+		}
+		return &oauth2.Token{
+			AccessToken: idToken,
+			TokenType:   "Bearer",
+			Expiry:      time.Unix(claims.Exp, 0),
+		}, nil
+	}
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+		TokenType   string `json:"token_type"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.Unmarshal(body, &token); err != nil {
+		return nil, fmt.Errorf("failed to decode Google access token: %w", err)
+	}
+	if token.AccessToken == "" {
+		return nil, fmt.Errorf("metadata server response did not include an access token")
+	}
+	return &oauth2.Token{
+		AccessToken: token.AccessToken,
+		TokenType:   token.TokenType,
+		Expiry:      time.Now().Add(time.Duration(token.ExpiresIn) * time.Second),
+	}, nil
+}
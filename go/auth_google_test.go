@@ -0,0 +1,154 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2/jws"
+)
+
+// newTestIDToken signs a JWT that expires in an hour
+func newTestIDToken(t *testing.T, key *rsa.PrivateKey, audience string) string {
+	token, err := jws.Encode(&jws.Header{Algorithm: "RS256", Typ: "JWT"}, &jws.ClaimSet{
+		Iss: "https://accounts.google.com",
+		Aud: audience,
+		Exp: time.Now().Add(time.Hour).Unix(),
+	}, key)
+	require.NoError(t, err)
+	return token
+}
+
+func TestGoogleCredentialsServiceAccountKey(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "urn:ietf:params:oauth:grant-type:jwt-bearer", r.PostForm.Get("grant_type"))
+		// jws.Decode drops private claims, so read the payload directly
+		parts := strings.Split(r.PostForm.Get("assertion"), ".")
+		require.Len(t, parts, 3)
+		payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+		require.NoError(t, err)
+		var claims map[string]any
+		require.NoError(t, json.Unmarshal(payload, &claims))
+		assert.Equal(t, "sa@project.iam.gserviceaccount.com", claims["iss"])
+
+		w.Header().Set("Content-Type", "application/json")
+		if audience, ok := claims["target_audience"].(string); ok {
+			assert.Equal(t, "https://example.gcp.databricks.com", audience)
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"id_token": newTestIDToken(t, key, audience),
+			})
+			return
+		}
+		assert.Equal(t, googleCloudScope, claims["scope"])
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"access_token": "google-access-token",
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+		})
+	}))
+	defer srv.Close()
+
+	keyJSON, err := json.Marshal(googleServiceAccountKey{
+		Type:        "service_account",
+		ClientEmail: "sa@project.iam.gserviceaccount.com",
+		PrivateKey:  string(keyPEM),
+		TokenURI:    srv.URL,
+	})
+	require.NoError(t, err)
+	keyFile := filepath.Join(t.TempDir(), "key.json")
+	require.NoError(t, os.WriteFile(keyFile, keyJSON, 0o600))
+
+	for _, credentials := range []string{string(keyJSON), keyFile} {
+		d := &databaseImpl{
+			serverHostname:    "example.gcp.databricks.com",
+			authType:          OptionValueAuthTypeGoogleCredentials,
+			googleCredentials: credentials,
+		}
+		_, authr, err := d.resolveAuthOptions()
+		require.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		require.NoError(t, authr.Authenticate(req))
+		claims, err := jws.Decode(req.Header.Get("Authorization")[len("Bearer "):])
+		require.NoError(t, err)
+		assert.Equal(t, "https://example.gcp.databricks.com", claims.Aud)
+		assert.Equal(t, "google-access-token", req.Header.Get(googleAccessTokenHeader))
+	}
+}
+
+func TestGoogleCredentialsMetadataServer(t *testing.T) {
+	t.Setenv(googleCredentialsEnv, "")
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Google", r.Header.Get("Metadata-Flavor"))
+		switch r.URL.Path {
+		case "/instance/service-accounts/default/identity":
+			_, _ = w.Write([]byte(newTestIDToken(t, key, r.URL.Query().Get("audience"))))
+		case "/instance/service-accounts/default/token":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"access_token": "metadata-access-token",
+				"token_type":   "Bearer",
+				"expires_in":   3600,
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	d := &databaseImpl{
+		serverHostname:     "example.gcp.databricks.com",
+		oauthTokenEndpoint: srv.URL,
+	}
+	authr, err := d.newGoogleCredentialsAuthenticator()
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	require.NoError(t, authr.Authenticate(req))
+	claims, err := jws.Decode(req.Header.Get("Authorization")[len("Bearer "):])
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.gcp.databricks.com", claims.Aud)
+	assert.Equal(t, "metadata-access-token", req.Header.Get(googleAccessTokenHeader))
+}
+
+func TestGoogleCredentialsRejectsUserCredentials(t *testing.T) {
+	d := &databaseImpl{
+		authType:          OptionValueAuthTypeGoogleCredentials,
+		googleCredentials: `{"type": "authorized_user", "client_id": "id"}`,
+	}
+	_, _, err := d.resolveAuthOptions()
+	assert.ErrorContains(t, err, "service account key is required")
+}
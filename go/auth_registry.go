@@ -0,0 +1,104 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// AuthConfig is the database configuration passed to an AuthFactory
+type AuthConfig struct {
+	ServerHostname string
+	HTTPPath       string
+	ClientID       string
+	ClientSecret   string
+	TokenEndpoint  string
+	// Options set with the OptionAuthPrefix prefix, keyed by full name
+	Options map[string]string
+}
+
+// AuthFactory creates the TokenProvider for a custom auth type. It is
+// called each time the database builds its connection pool; tokens are
+// cached and renewed shortly before expiry as for built-in auth types.
+type AuthFactory func(config AuthConfig) (TokenProvider, error)
+
+var (
+	authRegistryMu sync.RWMutex
+	authRegistry   = map[string]AuthFactory{}
+)
+
+// RegisterAuthType makes a custom auth type available as a value of
+// OptionAuthType, so that credential flows such as corporate SSO can be
+// added without changing the driver. Names are case-insensitive. Like
+// sql.Register, it panics if factory is nil or the name is empty, built
+// in, or already registered.
+func RegisterAuthType(name string, factory AuthFactory) {
+	key := strings.ToLower(name)
+	if key == "" {
+		panic("databricks: RegisterAuthType name is empty")
+	}
+	if factory == nil {
+		panic("databricks: RegisterAuthType factory is nil")
+	}
+	if slices.Contains(builtinAuthTypes, key) {
+		panic("databricks: RegisterAuthType cannot replace built-in auth type " + name)
+	}
+
+	authRegistryMu.Lock()
+	defer authRegistryMu.Unlock()
+	if _, dup := authRegistry[key]; dup {
+		panic("databricks: RegisterAuthType called twice for auth type " + name)
+	}
+	authRegistry[key] = factory
+}
+
+// lookupAuthType returns the factory of a registered auth type
+func lookupAuthType(name string) (AuthFactory, bool) {
+	authRegistryMu.RLock()
+	defer authRegistryMu.RUnlock()
+	factory, ok := authRegistry[name]
+	return factory, ok
+}
+
+// supportedAuthTypes lists the built-in and registered auth types
+func supportedAuthTypes() []string {
+	authRegistryMu.RLock()
+	defer authRegistryMu.RUnlock()
+	return append(slices.Clone(builtinAuthTypes), slices.Sorted(maps.Keys(authRegistry))...)
+}
+
+// newRegisteredAuthenticator creates an authenticator for a registered
+// auth type
+func (d *databaseImpl) newRegisteredAuthenticator(factory AuthFactory) (*tokenAuthenticator, error) {
+	provider, err := factory(AuthConfig{
+		ServerHostname: d.serverHostname,
+		HTTPPath:       d.httpPath,
+		ClientID:       d.oauthClientID,
+		ClientSecret:   d.oauthClientSecret,
+		TokenEndpoint:  d.oauthTokenEndpoint,
+		Options:        maps.Clone(d.authOptions),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if provider == nil {
+		return nil, fmt.Errorf("auth factory returned no token provider")
+	}
+	return newTokenAuthenticator(&providerTokenSource{provider: provider, client: d.authHTTPClient()}, d.tokenRefreshBuffer()), nil
+}
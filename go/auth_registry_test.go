@@ -0,0 +1,78 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type staticTokenProvider string
+
+func (p staticTokenProvider) Token(ctx context.Context) (string, time.Time, error) {
+	return string(p), time.Time{}, nil
+}
+
+func TestRegisterAuthType(t *testing.T) {
+	RegisterAuthType("Test-Vault", func(config AuthConfig) (TokenProvider, error) {
+		path, ok := config.Options["databricks.auth.vault.path"]
+		if !ok {
+			return nil, errors.New("vault path is required")
+		}
+		return staticTokenProvider("vault:" + path), nil
+	})
+	t.Cleanup(func() {
+		authRegistryMu.Lock()
+		defer authRegistryMu.Unlock()
+		delete(authRegistry, "test-vault")
+	})
+
+	assert.Contains(t, supportedAuthTypes(), "test-vault")
+	assert.Panics(t, func() {
+		RegisterAuthType("test-vault", func(AuthConfig) (TokenProvider, error) { return nil, nil })
+	})
+	assert.Panics(t, func() {
+		RegisterAuthType(OptionValueAuthTypePAT, func(AuthConfig) (TokenProvider, error) { return nil, nil })
+	})
+
+	d := &databaseImpl{}
+	require.NoError(t, d.SetOption(OptionAuthType, "TEST-VAULT"))
+
+	_, _, err := d.resolveAuthOptions()
+	assert.ErrorContains(t, err, "vault path is required")
+
+	require.NoError(t, d.SetOption("databricks.auth.vault.path", "secret/databricks"))
+	val, err := d.GetOption("databricks.auth.vault.path")
+	require.NoError(t, err)
+	assert.Equal(t, "secret/databricks", val)
+
+	_, authr, err := d.resolveAuthOptions()
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	require.NoError(t, authr.Authenticate(req))
+	assert.Equal(t, "Bearer vault:secret/databricks", req.Header.Get("Authorization"))
+}
+
+func TestUnregisteredAuthType(t *testing.T) {
+	_, err := parseAuthType("corporate-sso")
+	assert.ErrorContains(t, err, "invalid auth type")
+}
@@ -0,0 +1,327 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTokenServer serves OAuth tokens that expire after expiresIn seconds,
+// counting how many were issued.
+func newTokenServer(t *testing.T, expiresIn int, issued *atomic.Int32) *httptest.Server {
+	return httptest.NewServer(tokenHandler(t, expiresIn, issued))
+}
+
+// tokenHandler issues the tokens of newTokenServer
+func tokenHandler(t *testing.T, expiresIn int, issued *atomic.Int32) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		clientID, clientSecret, ok := r.BasicAuth()
+		if !ok || clientID != "client" || clientSecret != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "client_credentials", r.PostForm.Get("grant_type"))
+		assert.Equal(t, oauthScopeAllAPIs, r.PostForm.Get("scope"))
+
+		n := issued.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"access_token": fmt.Sprintf("token-%d", n),
+			"token_type":   "Bearer",
+			"expires_in":   expiresIn,
+		})
+	})
+}
+
+func TestOAuthM2MAuthenticator(t *testing.T) {
+	var issued atomic.Int32
+	srv := newTokenServer(t, 3600, &issued)
+	defer srv.Close()
+
+	d := &databaseImpl{
+		serverHostname:     "example.cloud.databricks.com",
+		oauthClientID:      "client",
+		oauthClientSecret:  "secret",
+		oauthTokenEndpoint: srv.URL,
+	}
+	authr := d.newOAuthM2MAuthenticator()
+
+	for range 3 {
+		req := httptest.NewRequest(http.MethodPost, "/sql/1.0/warehouses/abc", nil)
+		require.NoError(t, authr.Authenticate(req))
+		assert.Equal(t, "Bearer token-1", req.Header.Get("Authorization"))
+	}
+	assert.EqualValues(t, 1, issued.Load(), "token should be reused until it nears expiry")
+}
+
+func TestOAuthM2MAuthenticatorTLS(t *testing.T) {
+	// Tokens are requested with the TLS settings of the database, which
+	// alone trust the token endpoint
+	var issued atomic.Int32
+	srv := httptest.NewTLSServer(tokenHandler(t, 3600, &issued))
+	defer srv.Close()
+	pool := x509.NewCertPool()
+	pool.AddCert(srv.Certificate())
+
+	newDatabase := func(pool *x509.CertPool) *databaseImpl {
+		d := &databaseImpl{
+			serverHostname:     "example.cloud.databricks.com",
+			httpPath:           "/sql/1.0/warehouses/abc",
+			oauthClientID:      "client",
+			oauthClientSecret:  "secret",
+			oauthTokenEndpoint: srv.URL,
+			sslCertPool:        pool,
+		}
+		_, err := d.resolveConnectionOptions()
+		require.NoError(t, err)
+		return d
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	require.NoError(t, newDatabase(pool).newOAuthM2MAuthenticator().Authenticate(req))
+	assert.Equal(t, "Bearer token-1", req.Header.Get("Authorization"))
+
+	req = httptest.NewRequest(http.MethodPost, "/", nil)
+	assert.ErrorContains(t, newDatabase(nil).newOAuthM2MAuthenticator().Authenticate(req), "certificate")
+}
+
+func TestOAuthM2MAuthenticatorRenewsNearExpiry(t *testing.T) {
+	var issued atomic.Int32
+	// Tokens expiring within the refresh buffer are renewed on every use
+	srv := newTokenServer(t, 60, &issued)
+	defer srv.Close()
+
+	d := &databaseImpl{
+		oauthClientID:      "client",
+		oauthClientSecret:  "secret",
+		oauthTokenEndpoint: srv.URL,
+	}
+	authr := d.newOAuthM2MAuthenticator()
+
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	require.NoError(t, authr.Authenticate(req))
+	assert.Equal(t, "Bearer token-1", req.Header.Get("Authorization"))
+
+	req = httptest.NewRequest(http.MethodPost, "/", nil)
+	require.NoError(t, authr.Authenticate(req))
+	assert.Equal(t, "Bearer token-2", req.Header.Get("Authorization"))
+}
+
+func TestResolveAuthType(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		db       databaseImpl
+		expected string
+		errMsg   string
+	}{
+		{"pat", databaseImpl{accessToken: "dapi"}, OptionValueAuthTypePAT, ""},
+		{"m2m", databaseImpl{oauthClientID: "id", oauthClientSecret: "secret"}, OptionValueAuthTypeOAuthM2M, ""},
+		{"explicit", databaseImpl{authType: OptionValueAuthTypeOAuthM2M}, OptionValueAuthTypeOAuthM2M, ""},
+		{"none", databaseImpl{}, "", "access token or OAuth config is required"},
+		{"both", databaseImpl{accessToken: "dapi", oauthClientID: "id"}, "", "cannot specify both"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			authType, err := tc.db.resolveAuthType()
+			if tc.errMsg != "" {
+				var adbcErr adbc.Error
+				require.ErrorAs(t, err, &adbcErr)
+				assert.Equal(t, adbc.StatusInvalidArgument, adbcErr.Code)
+				assert.Contains(t, adbcErr.Msg, tc.errMsg)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, authType)
+		})
+	}
+}
+
+func TestResolveAuthOptionsRequiresBothClientCredentials(t *testing.T) {
+	d := &databaseImpl{oauthClientID: "id"}
+	_, _, err := d.resolveAuthOptions()
+	var adbcErr adbc.Error
+	require.ErrorAs(t, err, &adbcErr)
+	assert.Equal(t, adbc.StatusInvalidArgument, adbcErr.Code)
+	assert.Contains(t, adbcErr.Msg, OptionOAuthClientSecret)
+}
+
+type countingTokenProvider struct {
+	calls  atomic.Int32
+	expiry time.Duration
+}
+
+func (p *countingTokenProvider) Token(ctx context.Context) (string, time.Time, error) {
+	n := p.calls.Add(1)
+	return fmt.Sprintf("provided-%d", n), time.Now().Add(p.expiry), nil
+}
+
+func TestTokenProviderAuthenticator(t *testing.T) {
+	provider := &countingTokenProvider{expiry: time.Hour}
+	d := &databaseImpl{tokenProvider: provider}
+
+	opts, _, err := d.resolveAuthOptions()
+	require.NoError(t, err)
+	assert.Len(t, opts, 1)
+
+	authr := newTokenAuthenticator(&providerTokenSource{provider: provider}, defaultTokenRefreshBuffer)
+	for range 2 {
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		require.NoError(t, authr.Authenticate(req))
+		assert.Equal(t, "Bearer provided-1", req.Header.Get("Authorization"))
+	}
+
+	// Tokens close to expiry are requested again
+	provider.expiry = time.Minute
+	authr = newTokenAuthenticator(&providerTokenSource{provider: provider}, defaultTokenRefreshBuffer)
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	require.NoError(t, authr.Authenticate(req))
+	req = httptest.NewRequest(http.MethodPost, "/", nil)
+	require.NoError(t, authr.Authenticate(req))
+	assert.Equal(t, "Bearer provided-3", req.Header.Get("Authorization"))
+}
+
+func TestTokenProviderRejectsOtherCredentials(t *testing.T) {
+	d := &databaseImpl{tokenProvider: &countingTokenProvider{}, accessToken: "dapi"}
+	_, _, err := d.resolveAuthOptions()
+	var adbcErr adbc.Error
+	require.ErrorAs(t, err, &adbcErr)
+	assert.Equal(t, adbc.StatusInvalidArgument, adbcErr.Code)
+}
+
+func TestExternalTokenOption(t *testing.T) {
+	d := &databaseImpl{}
+	require.NoError(t, d.SetOption(OptionOAuthExternalToken, "first"))
+	assert.True(t, d.needsRefresh)
+
+	authType, err := d.resolveAuthType()
+	require.NoError(t, err)
+	assert.Equal(t, OptionValueAuthTypeOAuthExternal, authType)
+
+	_, authr, err := d.resolveAuthOptions()
+	require.NoError(t, err)
+
+	// Replacing the token applies to the existing authenticator without
+	// rebuilding the connection pool
+	d.needsRefresh = false
+	require.NoError(t, d.SetOption(OptionOAuthExternalToken, "second"))
+	assert.False(t, d.needsRefresh)
+
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	require.NoError(t, authr.Authenticate(req))
+	assert.Equal(t, "Bearer second", req.Header.Get("Authorization"))
+
+	val, err := d.GetOption(OptionOAuthExternalToken)
+	require.NoError(t, err)
+	assert.Equal(t, "second", val)
+
+	d.accessToken = "dapi"
+	_, err = d.resolveAuthType()
+	assert.ErrorContains(t, err, "external OAuth token")
+}
+
+func TestReauthTransportRetriesWithNewToken(t *testing.T) {
+	authr := &externalTokenAuthenticator{token: "stale"}
+	var seen []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := r.Header.Get("Authorization")
+		seen = append(seen, token)
+		if token == "Bearer stale" {
+			// Simulate the application replacing the rejected token
+			authr.set("fresh")
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		_, _ = w.Write(body)
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: &reauthTransport{base: http.DefaultTransport, authr: authr}}
+	req, err := http.NewRequest(http.MethodPost, srv.URL, strings.NewReader("payload"))
+	require.NoError(t, err)
+	require.NoError(t, authr.Authenticate(req))
+
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "payload", string(body))
+	assert.Equal(t, []string{"Bearer stale", "Bearer fresh"}, seen)
+}
+
+func TestReauthTransportWithoutNewToken(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	authr := &externalTokenAuthenticator{token: "revoked"}
+	client := &http.Client{Transport: &reauthTransport{base: http.DefaultTransport, authr: authr}}
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+	require.NoError(t, authr.Authenticate(req))
+
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	assert.EqualValues(t, 1, requests.Load())
+}
+
+func TestOAuthRefreshBeforeExpiry(t *testing.T) {
+	var issued atomic.Int32
+	// Tokens are valid for 20 minutes
+	srv := newTokenServer(t, 20*60, &issued)
+	defer srv.Close()
+
+	d := &databaseImpl{
+		oauthClientID:      "client",
+		oauthClientSecret:  "secret",
+		oauthTokenEndpoint: srv.URL,
+	}
+	require.NoError(t, d.SetOption(OptionOAuthRefreshBeforeExpiry, "30m"))
+	val, err := d.GetOption(OptionOAuthRefreshBeforeExpiry)
+	require.NoError(t, err)
+	assert.Equal(t, "30m0s", val)
+
+	authr := d.newOAuthM2MAuthenticator()
+	for range 2 {
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		require.NoError(t, authr.Authenticate(req))
+	}
+	assert.EqualValues(t, 2, issued.Load(), "tokens expiring within the threshold should be renewed")
+
+	var adbcErr adbc.Error
+	require.ErrorAs(t, d.SetOption(OptionOAuthRefreshBeforeExpiry, "soon"), &adbcErr)
+	assert.Equal(t, adbc.StatusInvalidArgument, adbcErr.Code)
+}
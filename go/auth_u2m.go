@@ -0,0 +1,224 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html"
+	"log/slog"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/pkg/browser"
+	"golang.org/x/oauth2"
+)
+
+const (
+	// Public OAuth client registered by Databricks for SQL connectors
+	defaultU2MClientID = "databricks-sql-connector"
+
+	defaultU2MRedirectPort = 8030
+	defaultU2MLoginTimeout = 2 * time.Minute
+)
+
+// u2mTokenSource implements the OAuth authorization code flow with PKCE.
+// The first call opens the workspace login page in a browser and waits for
+// the redirect on a local callback listener; afterwards tokens are renewed
+// with the refresh token, falling back to a new login if it is rejected.
+type u2mTokenSource struct {
+	config       oauth2.Config
+	redirectPort int
+	loginTimeout time.Duration
+	logger       *slog.Logger
+	// openURL presents the authorization URL to the user
+	openURL func(url string) error
+	// Sends token requests
+	client *http.Client
+
+	mu           sync.Mutex
+	refreshToken string
+}
+
+// oidcAuthorizationEndpoint returns the workspace OAuth authorization
+// endpoint
+func (d *databaseImpl) oidcAuthorizationEndpoint() string {
+	return d.workspaceURL("/oidc/v1/authorize")
+}
+
+// newOAuthU2MAuthenticator creates an authenticator that signs the user in
+// through their browser, reusing a configured refresh token if one is set.
+func (d *databaseImpl) newOAuthU2MAuthenticator() *tokenAuthenticator {
+	clientID := d.oauthClientID
+	if clientID == "" {
+		clientID = defaultU2MClientID
+	}
+	tokenEndpoint := d.oauthTokenEndpoint
+	if tokenEndpoint == "" {
+		tokenEndpoint = d.oidcTokenEndpoint()
+	}
+	redirectPort := d.oauthRedirectPort
+	if redirectPort == 0 {
+		redirectPort = defaultU2MRedirectPort
+	}
+
+	openURL := d.oauthLoginHandler
+	if openURL == nil {
+		openURL = openBrowser
+	}
+
+	source := &u2mTokenSource{
+		config: oauth2.Config{
+			ClientID:     clientID,
+			ClientSecret: d.oauthClientSecret,
+			Endpoint: oauth2.Endpoint{
+				AuthURL:  d.oidcAuthorizationEndpoint(),
+				TokenURL: tokenEndpoint,
+			},
+			Scopes: []string{"sql", "offline_access"},
+		},
+		redirectPort: redirectPort,
+		loginTimeout: defaultU2MLoginTimeout,
+		logger:       d.Logger,
+		openURL:      openURL,
+		refreshToken: d.oauthRefreshToken,
+		client:       d.authHTTPClient(),
+	}
+	return newTokenAuthenticator(source, d.tokenRefreshBuffer())
+}
+
+func (s *u2mTokenSource) Token() (*oauth2.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ctx := oauthContext(s.client)
+
+	if s.refreshToken != "" {
+		// A token without an access token is always refreshed
+		token, err := s.config.TokenSource(ctx, &oauth2.Token{RefreshToken: s.refreshToken}).Token()
+		if err == nil {
+			if token.RefreshToken != "" {
+				s.refreshToken = token.RefreshToken
+			}
+			return token, nil
+		}
+		s.logInfo("OAuth refresh token was rejected, starting browser login", "error", err)
+	}
+
+	token, err := s.login(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.refreshToken = token.RefreshToken
+	return token, nil
+}
+
+// login runs an interactive authorization code flow
+func (s *u2mTokenSource) login(ctx context.Context) (*oauth2.Token, error) {
+	listener, err := net.Listen("tcp", fmt.Sprintf("localhost:%d", s.redirectPort))
+	if err != nil {
+		return nil, fmt.Errorf("failed to start OAuth callback listener: %w", err)
+	}
+	defer func() { _ = listener.Close() }()
+
+	config := s.config
+	config.RedirectURL = fmt.Sprintf("http://localhost:%d", listener.Addr().(*net.TCPAddr).Port)
+
+	stateBytes := make([]byte, 16)
+	if _, err := rand.Read(stateBytes); err != nil {
+		return nil, fmt.Errorf("failed to generate OAuth state: %w", err)
+	}
+	state := hex.EncodeToString(stateBytes)
+	verifier := oauth2.GenerateVerifier()
+	loginURL := config.AuthCodeURL(state, oauth2.S256ChallengeOption(verifier))
+
+	type callbackResult struct {
+		code string
+		err  error
+	}
+	resultCh := make(chan callbackResult, 1)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		var result callbackResult
+		switch {
+		case query.Get("state") != state:
+			// Ignore stray requests such as /favicon.ico, and those not
+			// answering this login
+			http.NotFound(w, r)
+			return
+		case query.Get("error") != "":
+			result.err = fmt.Errorf("identity provider error: %s: %s", query.Get("error"), query.Get("error_description"))
+		default:
+			result.code = query.Get("code")
+		}
+
+		if result.err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = fmt.Fprintf(w, "<html><body><p>Login failed: %s</p></body></html>", html.EscapeString(result.err.Error()))
+		} else {
+			_, _ = fmt.Fprint(w, "<html><body><p>Login successful. You may close this window.</p></body></html>")
+		}
+
+		select {
+		case resultCh <- result:
+		default:
+		}
+	})
+
+	srv := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 3 * time.Second,
+	}
+	defer func() { _ = srv.Close() }()
+	go func() { _ = srv.Serve(listener) }()
+
+	s.logInfo("open the following URL in a browser to log in to Databricks", "url", loginURL)
+	if err := s.openURL(loginURL); err != nil {
+		if s.logger != nil {
+			s.logger.Warn("unable to open the OAuth login URL, open it in a browser to log in to Databricks", "url", loginURL, "error", err)
+		}
+	}
+
+	select {
+	case result := <-resultCh:
+		if result.err != nil {
+			return nil, result.err
+		}
+		token, err := config.Exchange(ctx, result.code, oauth2.VerifierOption(verifier))
+		if err != nil {
+			return nil, fmt.Errorf("failed to exchange OAuth authorization code: %w", err)
+		}
+		return token, nil
+	case <-time.After(s.loginTimeout):
+		return nil, errors.New("timed out waiting for OAuth login to complete")
+	}
+}
+
+func (s *u2mTokenSource) logInfo(msg string, args ...any) {
+	if s.logger != nil {
+		s.logger.Info(msg, args...)
+	}
+}
+
+func openBrowser(url string) error {
+	return browser.OpenURL(url)
+}
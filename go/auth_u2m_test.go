@@ -0,0 +1,137 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+// newU2MTestSource returns a token source whose "browser" follows the
+// authorization URL straight back to the callback listener.
+func newU2MTestSource(t *testing.T, tokenURL string) *u2mTokenSource {
+	return &u2mTokenSource{
+		config: oauth2.Config{
+			ClientID: defaultU2MClientID,
+			Endpoint: oauth2.Endpoint{
+				AuthURL:  "https://example.cloud.databricks.com/oidc/v1/authorize",
+				TokenURL: tokenURL,
+			},
+			Scopes: []string{"sql", "offline_access"},
+		},
+		loginTimeout: 10 * time.Second,
+		openURL: func(loginURL string) error {
+			u, err := url.Parse(loginURL)
+			require.NoError(t, err)
+			query := u.Query()
+			assert.Equal(t, "S256", query.Get("code_challenge_method"))
+			assert.NotEmpty(t, query.Get("code_challenge"))
+
+			callback := query.Get("redirect_uri") + "?code=the-code&state=" + url.QueryEscape(query.Get("state"))
+			go func() {
+				resp, err := http.Get(callback)
+				if err == nil {
+					_ = resp.Body.Close()
+				}
+			}()
+			return nil
+		},
+	}
+}
+
+func TestOAuthU2MLoginAndRefresh(t *testing.T) {
+	var grants []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		grant := r.PostForm.Get("grant_type")
+		grants = append(grants, grant)
+
+		switch grant {
+		case "authorization_code":
+			assert.Equal(t, "the-code", r.PostForm.Get("code"))
+			assert.NotEmpty(t, r.PostForm.Get("code_verifier"))
+		case "refresh_token":
+			assert.Equal(t, "refresh-1", r.PostForm.Get("refresh_token"))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"access_token":  "access-" + grant,
+			"refresh_token": "refresh-1",
+			"token_type":    "Bearer",
+			"expires_in":    3600,
+		})
+	}))
+	defer srv.Close()
+
+	source := newU2MTestSource(t, srv.URL)
+
+	token, err := source.Token()
+	require.NoError(t, err)
+	assert.Equal(t, "access-authorization_code", token.AccessToken)
+
+	token, err = source.Token()
+	require.NoError(t, err)
+	assert.Equal(t, "access-refresh_token", token.AccessToken)
+
+	assert.Equal(t, []string{"authorization_code", "refresh_token"}, grants)
+}
+
+func TestOAuthU2MLoginError(t *testing.T) {
+	source := newU2MTestSource(t, "http://127.0.0.1:1/token")
+	source.openURL = func(loginURL string) error {
+		u, err := url.Parse(loginURL)
+		require.NoError(t, err)
+		redirect := u.Query().Get("redirect_uri")
+		go func() {
+			// A callback without the state of the login is ignored
+			resp, err := http.Get(redirect + "?error=server_error&error_description=forged")
+			if err == nil {
+				assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+				_ = resp.Body.Close()
+			}
+			resp, err = http.Get(redirect + "?error=access_denied&error_description=denied&state=" + url.QueryEscape(u.Query().Get("state")))
+			if err == nil {
+				_ = resp.Body.Close()
+			}
+		}()
+		return nil
+	}
+
+	_, err := source.Token()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "access_denied")
+}
+
+func TestOAuthU2MLoginHandler(t *testing.T) {
+	var presented string
+	d := &databaseImpl{serverHostname: "example.cloud.databricks.com"}
+	WithOAuthLoginHandler(func(loginURL string) error {
+		presented = loginURL
+		return nil
+	})(d)
+
+	source := d.newOAuthU2MAuthenticator().base.(*u2mTokenSource)
+	require.NoError(t, source.openURL("https://example.cloud.databricks.com/oidc/v1/authorize"))
+	assert.Equal(t, "https://example.cloud.databricks.com/oidc/v1/authorize", presented)
+}
@@ -0,0 +1,124 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+)
+
+// The bound data goes from none to bound by Bind or BindStream, and from
+// bound to consumed by the execution reading it, unless an ingest with
+// OptionValueIngestRepeatReingest rewinds it to bound again. Binding
+// again always starts over from bound.
+
+// bind replaces the bound data with stream, which the statement now owns
+func (s *statementImpl) bind(stream array.RecordReader) {
+	if s.boundStream != nil {
+		s.boundStream.Release()
+	}
+	s.boundStream = stream
+	s.boundConsumed = false
+}
+
+// releaseBound releases the bound data once an execution has read it
+func (s *statementImpl) releaseBound() {
+	if s.boundStream != nil {
+		s.boundStream.Release()
+		s.boundStream = nil
+	}
+	s.boundConsumed = true
+}
+
+// bindState returns the value of OptionStatementBindState
+func (s *statementImpl) bindState() string {
+	switch {
+	case s.boundStream != nil:
+		return OptionValueBindStateBound
+	case s.boundConsumed:
+		return OptionValueBindStateConsumed
+	default:
+		return OptionValueBindStateNone
+	}
+}
+
+// errNoBoundData reports an execution needing bound data with none bound,
+// telling apart data consumed by the execution before, which a retry
+// must bind again
+func (s *statementImpl) errNoBoundData(msg string) error {
+	if s.boundConsumed {
+		return s.ErrorHelper.Errorf(adbc.StatusInvalidState, "%s: the data bound was consumed by the previous execution and must be bound again", msg)
+	}
+	return s.ErrorHelper.Errorf(adbc.StatusInvalidState, "%s", msg)
+}
+
+// replayBound makes the bound data replayable, keeping its batches as
+// they are read, and returns it
+func (s *statementImpl) replayBound() *replayReader {
+	if r, ok := s.boundStream.(*replayReader); ok {
+		return r
+	}
+	r := &replayReader{RecordReader: s.boundStream}
+	s.boundStream = r
+	return r
+}
+
+// replayReader reads a stream once, keeping its batches, so that after
+// rewind it reads them again, followed by those of the stream not yet
+// read
+type replayReader struct {
+	array.RecordReader
+	kept []arrow.RecordBatch
+	// Index in kept of the next batch
+	pos int
+	cur arrow.RecordBatch
+}
+
+func (r *replayReader) Next() bool {
+	r.cur = nil
+	if r.pos < len(r.kept) {
+		r.cur = r.kept[r.pos]
+		r.pos++
+		return true
+	}
+	if !r.RecordReader.Next() {
+		return false
+	}
+	r.cur = r.RecordReader.RecordBatch()
+	r.cur.Retain()
+	r.kept = append(r.kept, r.cur)
+	r.pos++
+	return true
+}
+
+func (r *replayReader) RecordBatch() arrow.RecordBatch { return r.cur }
+
+func (r *replayReader) Record() arrow.RecordBatch { return r.cur }
+
+// rewind starts reading the kept batches again
+func (r *replayReader) rewind() {
+	r.pos = 0
+	r.cur = nil
+}
+
+func (r *replayReader) Release() {
+	for _, rec := range r.kept {
+		rec.Release()
+	}
+	r.kept = nil
+	r.cur = nil
+	r.RecordReader.Release()
+}
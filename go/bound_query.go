@@ -0,0 +1,175 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+)
+
+// boundRow is the query parameters of a row of the bound data
+type boundRow struct {
+	args []driver.NamedValue
+	pos  rowPosition
+}
+
+// executeBoundQuery runs the query once for each row of the bound data,
+// whose columns are the values of its ? parameter markers in order. The
+// results of a single row are streamed; those of several rows are read
+// and returned together, in the order of the rows.
+func (s *statementImpl) executeBoundQuery(ctx context.Context) (array.RecordReader, int64, error) {
+	defer s.releaseBound()
+
+	rows, err := s.boundRows()
+	if err != nil {
+		return nil, -1, err
+	}
+	switch len(rows) {
+	case 0:
+		return nil, -1, s.ErrorHelper.Errorf(adbc.StatusInvalidState, "bound data has no rows to run the query with")
+	case 1:
+		reader, err := s.runQuery(ctx, rows[0].args)
+		return reader, -1, err
+	}
+
+	var schema *arrow.Schema
+	var batches []arrow.RecordBatch
+	defer func() {
+		for _, batch := range batches {
+			batch.Release()
+		}
+	}()
+	for _, row := range rows {
+		reader, err := s.runQuery(ctx, row.args)
+		if err != nil {
+			return nil, -1, atRow(err, row.pos)
+		}
+		if schema == nil {
+			schema = reader.Schema()
+		} else if !reader.Schema().Equal(schema) {
+			reader.Release()
+			return nil, -1, s.rowErrorf(row.pos, adbc.StatusInternal, "query returned a schema different from that of the first row: %s", reader.Schema())
+		}
+		for reader.Next() {
+			batch := reader.RecordBatch()
+			batch.Retain()
+			batches = append(batches, batch)
+		}
+		err = reader.Err()
+		reader.Release()
+		if err != nil {
+			return nil, -1, s.rowErrorf(row.pos, adbc.StatusIO, "failed to read results: %v", err)
+		}
+	}
+
+	reader, err := array.NewRecordReader(schema, batches)
+	if err != nil {
+		return nil, -1, s.ErrorHelper.Errorf(adbc.StatusInternal, "failed to create record reader: %v", err)
+	}
+	return reader, -1, nil
+}
+
+// executeBoundUpdate runs the statement once for each row of the bound
+// data, whose columns are the values of its ? parameter markers in order,
+// returning the total number of rows affected. The rows are all read
+// before any is run, so that a value that cannot be bound runs none.
+func (s *statementImpl) executeBoundUpdate(ctx context.Context) (int64, error) {
+	defer s.releaseBound()
+
+	if s.query == "" {
+		return -1, s.ErrorHelper.Errorf(adbc.StatusInvalidState, "no query set")
+	}
+	if s.conn.txMode == transactionStaged {
+		return -1, s.ErrorHelper.Errorf(adbc.StatusNotImplemented,
+			"running a statement with bound data while autocommit is disabled requires a warehouse supporting transactions")
+	}
+	if err := s.conn.checkSQLLength(s.query); err != nil {
+		return -1, err
+	}
+
+	rows, err := s.boundRows()
+	if err != nil {
+		return -1, err
+	}
+	if len(rows) == 0 {
+		return -1, s.ErrorHelper.Errorf(adbc.StatusInvalidState, "bound data has no rows to run the statement with")
+	}
+
+	totalRows := int64(0)
+	for _, row := range rows {
+		args := make([]any, len(row.args))
+		for i, arg := range row.args {
+			args[i] = arg.Value
+		}
+		var result sql.Result
+		if s.prepared != nil {
+			result, err = s.prepared.ExecContext(ctx, args...)
+		} else {
+			result, err = s.conn.conn.ExecContext(ctx, s.query, args...)
+		}
+		if err != nil {
+			return totalRows, s.rowErrorf(row.pos, adbc.StatusInternal, "failed to execute update: %v", retryBudgetCause(ctx, err))
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return totalRows, s.rowErrorf(row.pos, adbc.StatusInternal, "failed to get rows affected: %v", err)
+		}
+		totalRows += affected
+	}
+	return totalRows, nil
+}
+
+// boundRows reads the bound data as the parameters of each row
+func (s *statementImpl) boundRows() ([]boundRow, error) {
+	var rows []boundRow
+	for batchIdx := 0; s.boundStream.Next(); batchIdx++ {
+		recordBatch := s.boundStream.RecordBatch()
+		for rowIdx := range int(recordBatch.NumRows()) {
+			args := make([]driver.NamedValue, recordBatch.NumCols())
+			for colIdx, col := range recordBatch.Columns() {
+				value, err := extractGoValue(col, rowIdx)
+				if err != nil {
+					return nil, s.rowErrorf(newRowPosition(recordBatch, batchIdx, rowIdx, colIdx),
+						adbc.StatusNotImplemented, "cannot bind parameter: %v", err)
+				}
+				args[colIdx] = driver.NamedValue{Ordinal: colIdx + 1, Value: value}
+			}
+			rows = append(rows, boundRow{args: args, pos: newRowPosition(recordBatch, batchIdx, rowIdx, -1)})
+		}
+	}
+	if err := s.boundStream.Err(); err != nil {
+		return nil, s.ErrorHelper.Errorf(adbc.StatusInternal, "stream error: %v", err)
+	}
+	return rows, nil
+}
+
+// atRow locates the error of running the query for the bound row at pos
+// in its message and details
+func atRow(err error, pos rowPosition) error {
+	var adbcErr adbc.Error
+	if !errors.As(err, &adbcErr) {
+		return err
+	}
+	adbcErr.Msg = fmt.Sprintf("%s at %s", adbcErr.Msg, pos)
+	adbcErr.Details = append(adbcErr.Details, pos.details()...)
+	return adbcErr
+}
@@ -0,0 +1,194 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newBoundQueryDriver returns a fake driver answering each query with a
+// row of the text of its parameters, or an error for a parameter in
+// failures, appending the parameters it receives to *args
+func newBoundQueryDriver(t *testing.T, args *[][]any, failures map[any]error) *fakeDriver {
+	return &fakeDriver{query: func(query string, named []driver.NamedValue) (driver.Rows, error) {
+		values := make([]any, len(named))
+		for i, arg := range named {
+			require.Equal(t, i+1, arg.Ordinal)
+			values[i] = arg.Value
+			if err := failures[arg.Value]; err != nil {
+				return nil, err
+			}
+		}
+		*args = append(*args, values)
+
+		schema := arrow.NewSchema([]arrow.Field{{Name: "params", Type: arrow.BinaryTypes.String}}, nil)
+		bldr := array.NewRecordBuilder(memory.DefaultAllocator, schema)
+		defer bldr.Release()
+		bldr.Field(0).(*array.StringBuilder).Append(fmt.Sprint(values...))
+		rec := bldr.NewRecordBatch()
+		defer rec.Release()
+
+		var stream, schemaBytes bytes.Buffer
+		w := ipc.NewWriter(&stream, ipc.WithSchema(schema))
+		require.NoError(t, w.Write(rec))
+		require.NoError(t, w.Close())
+		require.NoError(t, ipc.NewWriter(&schemaBytes, ipc.WithSchema(schema)).Close())
+		return &mockRows{iterator: &mockIPCStreamIterator{streams: [][]byte{stream.Bytes()}, schema: schemaBytes.Bytes()}}, nil
+	}}
+}
+
+func TestBoundQuery(t *testing.T) {
+	var args [][]any
+	db := sql.OpenDB(newBoundQueryDriver(t, &args, map[any]error{"boom": errors.New("[DIVIDE_BY_ZERO] division by zero")}))
+	defer func() { _ = db.Close() }()
+	conn, err := db.Conn(context.Background())
+	require.NoError(t, err)
+	s := &statementImpl{conn: &connectionImpl{conn: conn}}
+	s.conn.Alloc = memory.DefaultAllocator
+	require.NoError(t, s.SetSqlQuery("SELECT * FROM orders WHERE id = ? AND status = ?"))
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int32, Nullable: true},
+		{Name: "status", Type: arrow.BinaryTypes.String, Nullable: true},
+	}, nil)
+	bind := func(ids []int32, valid []bool, statuses ...string) {
+		bldr := array.NewRecordBuilder(memory.DefaultAllocator, schema)
+		defer bldr.Release()
+		bldr.Field(0).(*array.Int32Builder).AppendValues(ids, valid)
+		bldr.Field(1).(*array.StringBuilder).AppendValues(statuses, nil)
+		rec := bldr.NewRecordBatch()
+		defer rec.Release()
+		require.NoError(t, s.Bind(context.Background(), rec))
+	}
+	query := func() string {
+		rdr, _, err := s.ExecuteQuery(context.Background())
+		require.NoError(t, err)
+		defer rdr.Release()
+		var rows []any
+		for rdr.Next() {
+			for i := range int(rdr.RecordBatch().NumRows()) {
+				rows = append(rows, rdr.RecordBatch().Column(0).GetOneForMarshal(i))
+			}
+		}
+		require.NoError(t, rdr.Err())
+		return fmt.Sprint(rows)
+	}
+
+	// A single row is the parameters of the query
+	bind([]int32{7}, nil, "open")
+	assert.Equal(t, "[7open]", query())
+	assert.Equal(t, [][]any{{int64(7), "open"}}, args)
+	assert.Nil(t, s.boundStream)
+
+	// Several rows run the query for each, returning the results in order
+	args = nil
+	bind([]int32{1, 0, 3}, []bool{true, false, true}, "open", "closed", "open")
+	assert.Equal(t, "[1open <nil>closed 3open]", query())
+	assert.Equal(t, [][]any{{int64(1), "open"}, {nil, "closed"}, {int64(3), "open"}}, args)
+
+	// A failure is located at its row
+	bind([]int32{1, 2}, nil, "open", "boom")
+	_, _, err = s.ExecuteQuery(context.Background())
+	var adbcErr adbc.Error
+	require.ErrorAs(t, err, &adbcErr)
+	assert.Contains(t, adbcErr.Msg, "[DIVIDE_BY_ZERO] division by zero at batch 0, row 1")
+	assert.Len(t, adbcErr.Details, 2)
+
+	bind(nil, nil)
+	_, _, err = s.ExecuteQuery(context.Background())
+	require.ErrorAs(t, err, &adbcErr)
+	assert.Equal(t, adbc.StatusInvalidState, adbcErr.Code)
+
+	// Values without a parameter type are rejected before running
+	args = nil
+	listSchema := arrow.NewSchema([]arrow.Field{{Name: "ids", Type: arrow.ListOf(arrow.PrimitiveTypes.Int32)}}, nil)
+	bldr := array.NewRecordBuilder(memory.DefaultAllocator, listSchema)
+	defer bldr.Release()
+	bldr.Field(0).(*array.ListBuilder).Append(true)
+	rec := bldr.NewRecordBatch()
+	defer rec.Release()
+	require.NoError(t, s.Bind(context.Background(), rec))
+	_, _, err = s.ExecuteQuery(context.Background())
+	require.ErrorAs(t, err, &adbcErr)
+	assert.Equal(t, adbc.StatusNotImplemented, adbcErr.Code)
+	assert.Contains(t, adbcErr.Msg, "column `ids`")
+	assert.Empty(t, args)
+}
+
+func TestBoundUpdate(t *testing.T) {
+	drv := &fakeDriver{}
+	db := sql.OpenDB(drv)
+	defer func() { _ = db.Close() }()
+	conn, err := db.Conn(context.Background())
+	require.NoError(t, err)
+	s := &statementImpl{conn: &connectionImpl{conn: conn}}
+	require.NoError(t, s.SetSqlQuery("UPDATE orders SET status = ? WHERE id = ?"))
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "status", Type: arrow.BinaryTypes.String},
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+	}, nil)
+	bldr := array.NewRecordBuilder(memory.DefaultAllocator, schema)
+	defer bldr.Release()
+	bldr.Field(0).(*array.StringBuilder).AppendValues([]string{"open", "closed", "open"}, nil)
+	bldr.Field(1).(*array.Int64Builder).AppendValues([]int64{1, 2, 3}, nil)
+	rec := bldr.NewRecordBatch()
+	defer rec.Release()
+
+	// The statement runs for each row, counting the rows of all
+	require.NoError(t, s.Bind(context.Background(), rec))
+	affected, err := s.ExecuteUpdate(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), affected)
+	assert.Equal(t, [][]any{{"open", "1"}, {"closed", "2"}, {"open", "3"}}, drv.args)
+	assert.Nil(t, s.boundStream)
+
+	// A failure is located at its row, with the rows affected before it
+	drv.args = nil
+	drv.execErr = func(args []any) error {
+		if args[0] == "closed" {
+			return errors.New("[DELTA_CONCURRENT_APPEND] conflict")
+		}
+		return nil
+	}
+	require.NoError(t, s.Bind(context.Background(), rec))
+	affected, err = s.ExecuteUpdate(context.Background())
+	var adbcErr adbc.Error
+	require.ErrorAs(t, err, &adbcErr)
+	assert.Contains(t, adbcErr.Msg, "[DELTA_CONCURRENT_APPEND] conflict")
+	assert.Contains(t, adbcErr.Msg, "batch 0, row 1")
+	assert.Equal(t, int64(1), affected)
+
+	// Staged transactions cannot replay bound statements
+	s.conn.txMode = transactionStaged
+	require.NoError(t, s.Bind(context.Background(), rec))
+	_, err = s.ExecuteUpdate(context.Background())
+	require.ErrorAs(t, err, &adbcErr)
+	assert.Equal(t, adbc.StatusNotImplemented, adbcErr.Code)
+}
@@ -0,0 +1,234 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/adbc-drivers/driverbase-go/driverbase"
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-go/v18/arrow"
+)
+
+// deleteByKeysOptions configures deleting rows from a table using the key
+// columns of a bound Arrow stream.
+type deleteByKeysOptions struct {
+	TableName   string
+	CatalogName string
+	SchemaName  string
+	// Number of bound rows to match per DELETE statement
+	BatchSize int
+}
+
+func newDeleteByKeysOptions() deleteByKeysOptions {
+	return deleteByKeysOptions{
+		BatchSize: DefaultDeleteBatchSize,
+	}
+}
+
+// SetOption handles delete-by-keys statement options, returning whether the
+// key was recognized.
+func (o *deleteByKeysOptions) SetOption(eh *driverbase.ErrorHelper, key, val string) (bool, error) {
+	switch key {
+	case OptionStatementDeleteTargetTable:
+		o.TableName = val
+	case OptionStatementDeleteTargetCatalog:
+		o.CatalogName = val
+	case OptionStatementDeleteTargetDbSchema:
+		o.SchemaName = val
+	case OptionStatementDeleteBatchSize:
+		size, err := strconv.Atoi(val)
+		if err != nil || size < 1 {
+			return true, eh.Errorf(adbc.StatusInvalidArgument, "invalid statement option %s=%s", key, val)
+		}
+		o.BatchSize = size
+	default:
+		return false, nil
+	}
+	return true, nil
+}
+
+// GetOption returns the value of a delete-by-keys statement option,
+// returning whether the key was recognized.
+func (o *deleteByKeysOptions) GetOption(key string) (string, bool) {
+	switch key {
+	case OptionStatementDeleteTargetTable:
+		return o.TableName, true
+	case OptionStatementDeleteTargetCatalog:
+		return o.CatalogName, true
+	case OptionStatementDeleteTargetDbSchema:
+		return o.SchemaName, true
+	case OptionStatementDeleteBatchSize:
+		return strconv.Itoa(o.BatchSize), true
+	}
+	return "", false
+}
+
+func (o *deleteByKeysOptions) IsSet() bool {
+	return o.TableName != ""
+}
+
+// executeDeleteByKeys deletes every row of the target table whose key
+// columns match a row of the bound stream. Each field of the bound schema
+// is treated as a key column, and rows are deleted in batches of
+// DELETE FROM ... WHERE key IN (...) statements.
+func (s *statementImpl) executeDeleteByKeys(ctx context.Context) (int64, error) {
+	if s.boundStream == nil {
+		return -1, s.errNoBoundData("no key data bound for delete")
+	}
+
+	defer s.releaseBound()
+
+	opts := &s.deleteOptions
+	schema := s.boundStream.Schema()
+	if schema.NumFields() == 0 {
+		return -1, s.ErrorHelper.Errorf(adbc.StatusInvalidArgument, "bound key data has no columns")
+	}
+
+	// A catalog alone would be read as the schema
+	dbSchema := opts.SchemaName
+	if opts.CatalogName != "" && dbSchema == "" {
+		var err error
+		if dbSchema, err = s.conn.GetCurrentDbSchema(); err != nil {
+			return -1, err
+		}
+	}
+	tableName := buildTableName(opts.CatalogName, dbSchema, opts.TableName)
+
+	// A key that loses information could match other rows
+	var checkConversions func(rec arrow.RecordBatch, idx int, row int64) error
+	if s.conn.strictConversions {
+		checkConversions = s.rowConversionCheck(schema, nil)
+	}
+
+	batchSize, err := s.conn.deleteRowsPerStatement(tableName, schema, opts.BatchSize)
+	if err != nil {
+		return -1, err
+	}
+	totalRows := int64(0)
+	params := make([]any, 0, batchSize*schema.NumFields())
+	pending := 0
+
+	flush := func() error {
+		if pending == 0 {
+			return nil
+		}
+		deleteSQL := buildDeleteByKeysSQL(tableName, schema, pending)
+		result, err := s.conn.conn.ExecContext(ctx, deleteSQL, params...)
+		if err != nil {
+			return s.ErrorHelper.Errorf(adbc.StatusInternal, "failed to delete rows: %v", err)
+		}
+		rows, _ := result.RowsAffected()
+		totalRows += rows
+		params = params[:0]
+		pending = 0
+		return nil
+	}
+
+	var row int64
+	for batchIdx := 0; s.boundStream.Next(); batchIdx++ {
+		recordBatch := s.boundStream.RecordBatch()
+
+		for rowIdx := range int(recordBatch.NumRows()) {
+			if checkConversions != nil {
+				if err := checkConversions(recordBatch, rowIdx, row); err != nil {
+					return totalRows, err
+				}
+			}
+			row++
+
+			for colIdx := range int(recordBatch.NumCols()) {
+				arr := recordBatch.Column(colIdx)
+				if arr.IsNull(rowIdx) {
+					// NULL never compares equal, so the row cannot match anything
+					return totalRows, s.rowErrorf(newRowPosition(recordBatch, batchIdx, rowIdx, colIdx),
+						adbc.StatusInvalidArgument, "key column %s contains NULL", schema.Field(colIdx).Name)
+				}
+				val, err := extractGoValue(arr, rowIdx)
+				if err != nil {
+					return totalRows, s.rowErrorf(newRowPosition(recordBatch, batchIdx, rowIdx, colIdx),
+						adbc.StatusInternal, "failed to extract go value: %v", err)
+				}
+				params = append(params, val)
+			}
+			pending++
+
+			if pending >= batchSize {
+				if err := flush(); err != nil {
+					return totalRows, err
+				}
+			}
+		}
+	}
+
+	if err := s.boundStream.Err(); err != nil {
+		return totalRows, s.ErrorHelper.Errorf(adbc.StatusInternal, "stream error: %v", err)
+	}
+
+	if err := flush(); err != nil {
+		return totalRows, err
+	}
+
+	return totalRows, nil
+}
+
+// buildDeleteByKeysSQL generates a parameterized DELETE matching numRows
+// key tuples. A single key column uses `key IN (?, ...)`, while composite
+// keys use the tuple form `(k1, k2) IN ((?, ?), ...)`.
+func buildDeleteByKeysSQL(tableName string, schema *arrow.Schema, numRows int) string {
+	var sql strings.Builder
+
+	sql.WriteString("DELETE FROM ")
+	sql.WriteString(tableName)
+	sql.WriteString(" WHERE ")
+
+	composite := schema.NumFields() > 1
+	if composite {
+		sql.WriteString("(")
+	}
+	for i, field := range schema.Fields() {
+		if i > 0 {
+			sql.WriteString(", ")
+		}
+		sql.WriteString(quoteIdentifier(field.Name))
+	}
+	if composite {
+		sql.WriteString(")")
+	}
+
+	sql.WriteString(" IN (")
+	for row := range numRows {
+		if row > 0 {
+			sql.WriteString(", ")
+		}
+		if composite {
+			sql.WriteString("(")
+		}
+		for i, field := range schema.Fields() {
+			if i > 0 {
+				sql.WriteString(", ")
+			}
+			sql.WriteString(parameterPlaceholder(field.Type))
+		}
+		if composite {
+			sql.WriteString(")")
+		}
+	}
+	sql.WriteString(")")
+
+	return sql.String()
+}
@@ -0,0 +1,148 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/adbc-drivers/driverbase-go/driverbase"
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildDeleteByKeysSQL(t *testing.T) {
+	single := arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+	}, nil)
+	assert.Equal(t,
+		"DELETE FROM `main`.`default`.`users` WHERE `id` IN (?, ?, ?)",
+		buildDeleteByKeysSQL(buildTableName("main", "default", "users"), single, 3))
+
+	composite := arrow.NewSchema([]arrow.Field{
+		{Name: "tenant", Type: arrow.BinaryTypes.String},
+		{Name: "hash", Type: &arrow.FixedSizeBinaryType{ByteWidth: 4}},
+	}, nil)
+	assert.Equal(t,
+		"DELETE FROM `users` WHERE (`tenant`, `hash`) IN ((?, UNHEX(?)), (?, UNHEX(?)))",
+		buildDeleteByKeysSQL(buildTableName("", "", "users"), composite, 2))
+}
+
+func TestDeleteByKeysOptions(t *testing.T) {
+	eh := driverbase.ErrorHelper{DriverName: "databricks"}
+	opts := newDeleteByKeysOptions()
+	assert.False(t, opts.IsSet())
+
+	handled, err := opts.SetOption(&eh, OptionStatementDeleteTargetTable, "users")
+	require.NoError(t, err)
+	assert.True(t, handled)
+	assert.True(t, opts.IsSet())
+
+	handled, err = opts.SetOption(&eh, OptionStatementDeleteBatchSize, "10")
+	require.NoError(t, err)
+	assert.True(t, handled)
+	val, ok := opts.GetOption(OptionStatementDeleteBatchSize)
+	assert.True(t, ok)
+	assert.Equal(t, "10", val)
+
+	_, err = opts.SetOption(&eh, OptionStatementDeleteBatchSize, "0")
+	var adbcErr adbc.Error
+	require.ErrorAs(t, err, &adbcErr)
+	assert.Equal(t, adbc.StatusInvalidArgument, adbcErr.Code)
+
+	handled, err = opts.SetOption(&eh, adbc.OptionKeyIngestTargetTable, "users")
+	require.NoError(t, err)
+	assert.False(t, handled)
+}
+
+func TestExecuteDeleteByKeys(t *testing.T) {
+	drv := &fakeDriver{}
+	db := sql.OpenDB(drv)
+	defer func() { _ = db.Close() }()
+	conn, err := db.Conn(context.Background())
+	require.NoError(t, err)
+	s := &statementImpl{conn: &connectionImpl{conn: conn}, deleteOptions: newDeleteByKeysOptions()}
+	s.deleteOptions.TableName = "users"
+	s.deleteOptions.BatchSize = 2
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "tenant", Type: arrow.BinaryTypes.String},
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+	}, nil)
+	newBatch := func(tenants []string, ids []int64) arrow.RecordBatch {
+		bldr := array.NewRecordBuilder(memory.DefaultAllocator, schema)
+		defer bldr.Release()
+		bldr.Field(0).(*array.StringBuilder).AppendValues(tenants, nil)
+		bldr.Field(1).(*array.Int64Builder).AppendValues(ids, nil)
+		return bldr.NewRecordBatch()
+	}
+	first := newBatch([]string{"a", "a", "b"}, []int64{1, 2, 1})
+	defer first.Release()
+	second := newBatch([]string{"b", "c"}, []int64{2, 1})
+	defer second.Release()
+	bind := func() {
+		stream, err := array.NewRecordReader(schema, []arrow.RecordBatch{first, second})
+		require.NoError(t, err)
+		require.NoError(t, s.BindStream(context.Background(), stream))
+	}
+
+	// Keys are matched batch_size at a time, across record batches, and
+	// the rows deleted by each statement are summed
+	bind()
+	affected, err := s.ExecuteUpdate(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), affected)
+	pair := "DELETE FROM `users` WHERE (`tenant`, `id`) IN ((?, ?), (?, ?))"
+	assert.Equal(t, []string{pair, pair, "DELETE FROM `users` WHERE (`tenant`, `id`) IN ((?, ?))"}, drv.execs)
+	assert.Equal(t, [][]any{
+		{"a", "1", "a", "2"},
+		{"b", "1", "b", "2"},
+		{"c", "1"},
+	}, drv.args)
+	assert.Nil(t, s.boundStream)
+
+	// A maximum SQL length fitting fewer keys than batch_size wins
+	drv.execs, drv.args = nil, nil
+	s.conn.maxSQLLength = len(pair) - 1
+	bind()
+	affected, err = s.ExecuteUpdate(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), affected)
+	assert.Len(t, drv.execs, 5)
+	assert.Equal(t, []any{"b", "2"}, drv.args[3])
+
+	// A failure reports the rows deleted before it
+	drv.execs, drv.args = nil, nil
+	s.conn.maxSQLLength = 0
+	drv.execErr = func(args []any) error {
+		if args[0] == "c" {
+			return errors.New("[DELTA_CONCURRENT_DELETE_DELETE] conflict")
+		}
+		return nil
+	}
+	bind()
+	affected, err = s.ExecuteUpdate(context.Background())
+	var adbcErr adbc.Error
+	require.ErrorAs(t, err, &adbcErr)
+	assert.Contains(t, adbcErr.Msg, "DELTA_CONCURRENT_DELETE_DELETE")
+	assert.Equal(t, int64(2), affected)
+	assert.Len(t, drv.args, 2)
+}
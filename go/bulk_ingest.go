@@ -17,7 +17,10 @@ package databricks
 import (
 	"context"
 	"database/sql/driver"
+	"errors"
 	"fmt"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/adbc-drivers/driverbase-go/driverbase"
@@ -26,63 +29,494 @@ import (
 	"github.com/apache/arrow-go/v18/arrow/array"
 )
 
+// ingestOptions holds Databricks-specific bulk ingestion settings that
+// complement driverbase.BulkIngestOptions.
+type ingestOptions struct {
+	// Write DEFAULT instead of NULL for null values in columns of the
+	// target table that declare a default
+	NullAsDefault bool
+	// Columns declared NOT NULL in created tables regardless of the
+	// nullability of their fields
+	NotNullColumns []string
+	// Identifies the ingest for skipping rows already written, see
+	// OptionStatementIngestIdempotencyKey; empty to disable
+	IdempotencyKey    string
+	IdempotencyColumn string
+	// Table also written with the bound data, see
+	// OptionStatementIngestShadowTargetTable; empty to disable
+	ShadowTableName   string
+	ShadowCatalogName string
+	ShadowSchemaName  string
+	// How batches adding fields are handled, see
+	// OptionStatementIngestSchemaEvolution; empty to fail
+	SchemaEvolution string
+	// Column of the bound change feed holding each row's operation, and
+	// the key columns it applies to, see
+	// OptionStatementIngestCDCOperationColumn; empty to disable
+	CDCOperationColumn string
+	CDCKeyColumns      []string
+	// What an ingest of data already ingested does, see
+	// OptionStatementIngestRepeat; empty to fail
+	Repeat string
+}
+
+// Column holding row idempotency keys when no other is set
+const defaultIdempotencyColumn = "_adbc_idempotency_key"
+
+// idempotencyColumn returns the name of the column holding row keys
+func (o *ingestOptions) idempotencyColumn() string {
+	if o.IdempotencyColumn != "" {
+		return o.IdempotencyColumn
+	}
+	return defaultIdempotencyColumn
+}
+
+// shadowOptions returns the options for writing the shadow table: those
+// of the target table with the shadow table's name, or nil if disabled
+func (o *ingestOptions) shadowOptions(target *driverbase.BulkIngestOptions) *driverbase.BulkIngestOptions {
+	if o.ShadowTableName == "" {
+		return nil
+	}
+	opts := *target
+	opts.TableName = o.ShadowTableName
+	opts.CatalogName = o.ShadowCatalogName
+	opts.SchemaName = o.ShadowSchemaName
+	return &opts
+}
+
+// rowIdempotencyKey returns the key stored with a row of the bound data
+func rowIdempotencyKey(key string, batch, row int) string {
+	return fmt.Sprintf("%s/%d/%d", key, batch, row)
+}
+
+// SetOption handles Databricks-specific ingest statement options,
+// returning whether the key was recognized.
+func (o *ingestOptions) SetOption(eh *driverbase.ErrorHelper, key, val string) (bool, error) {
+	switch key {
+	case OptionStatementIngestNullAsDefault:
+		enabled, err := strconv.ParseBool(val)
+		if err != nil {
+			return true, eh.Errorf(adbc.StatusInvalidArgument, "invalid statement option %s=%s", key, val)
+		}
+		o.NullAsDefault = enabled
+	case OptionStatementIngestNotNullColumns:
+		o.NotNullColumns = nil
+		for column := range strings.SplitSeq(val, ",") {
+			if column = strings.TrimSpace(column); column != "" {
+				o.NotNullColumns = append(o.NotNullColumns, column)
+			}
+		}
+	case OptionStatementIngestCDCOperationColumn:
+		o.CDCOperationColumn = val
+	case OptionStatementIngestCDCKeyColumns:
+		o.CDCKeyColumns = nil
+		for column := range strings.SplitSeq(val, ",") {
+			if column = strings.TrimSpace(column); column != "" {
+				o.CDCKeyColumns = append(o.CDCKeyColumns, column)
+			}
+		}
+	case OptionStatementIngestIdempotencyKey:
+		o.IdempotencyKey = val
+	case OptionStatementIngestIdempotencyColumn:
+		o.IdempotencyColumn = val
+	case OptionStatementIngestShadowTargetTable:
+		o.ShadowTableName = val
+	case OptionStatementIngestShadowTargetCatalog:
+		o.ShadowCatalogName = val
+	case OptionStatementIngestShadowTargetDbSchema:
+		o.ShadowSchemaName = val
+	case OptionStatementIngestSchemaEvolution:
+		switch val {
+		case "", OptionValueSchemaEvolutionFail, OptionValueSchemaEvolutionAddColumns, OptionValueSchemaEvolutionIgnore:
+			o.SchemaEvolution = val
+		default:
+			return true, eh.Errorf(adbc.StatusInvalidArgument, "invalid statement option %s=%s (supported: '%s', '%s', '%s')", key, val,
+				OptionValueSchemaEvolutionFail, OptionValueSchemaEvolutionAddColumns, OptionValueSchemaEvolutionIgnore)
+		}
+	case OptionStatementIngestRepeat:
+		switch val {
+		case "", OptionValueIngestRepeatError, OptionValueIngestRepeatReingest:
+			o.Repeat = val
+		default:
+			return true, eh.Errorf(adbc.StatusInvalidArgument, "invalid statement option %s=%s (supported: '%s', '%s')", key, val,
+				OptionValueIngestRepeatError, OptionValueIngestRepeatReingest)
+		}
+	default:
+		return false, nil
+	}
+	return true, nil
+}
+
+// GetOption returns the value of a Databricks-specific ingest statement
+// option, returning whether the key was recognized.
+func (o *ingestOptions) GetOption(key string) (string, bool) {
+	switch key {
+	case OptionStatementIngestNullAsDefault:
+		if o.NullAsDefault {
+			return adbc.OptionValueEnabled, true
+		}
+		return adbc.OptionValueDisabled, true
+	case OptionStatementIngestNotNullColumns:
+		return strings.Join(o.NotNullColumns, ","), true
+	case OptionStatementIngestCDCOperationColumn:
+		return o.CDCOperationColumn, true
+	case OptionStatementIngestCDCKeyColumns:
+		return strings.Join(o.CDCKeyColumns, ","), true
+	case OptionStatementIngestIdempotencyKey:
+		return o.IdempotencyKey, true
+	case OptionStatementIngestIdempotencyColumn:
+		return o.idempotencyColumn(), true
+	case OptionStatementIngestShadowTargetTable:
+		return o.ShadowTableName, true
+	case OptionStatementIngestShadowTargetCatalog:
+		return o.ShadowCatalogName, true
+	case OptionStatementIngestShadowTargetDbSchema:
+		return o.ShadowSchemaName, true
+	case OptionStatementIngestSchemaEvolution:
+		if o.SchemaEvolution == "" {
+			return OptionValueSchemaEvolutionFail, true
+		}
+		return o.SchemaEvolution, true
+	case OptionStatementIngestRepeat:
+		if o.Repeat == "" {
+			return OptionValueIngestRepeatError, true
+		}
+		return o.Repeat, true
+	}
+	return "", false
+}
+
+// bulkIngestOption returns the value of a standard ingest statement
+// option, returning whether the key was recognized.
+func bulkIngestOption(opts *driverbase.BulkIngestOptions, key string) (string, bool) {
+	switch key {
+	case adbc.OptionKeyIngestTargetTable:
+		return opts.TableName, true
+	case adbc.OptionValueIngestTargetCatalog:
+		return opts.CatalogName, true
+	case adbc.OptionValueIngestTargetDBSchema:
+		return opts.SchemaName, true
+	case adbc.OptionKeyIngestMode:
+		return opts.Mode, true
+	case adbc.OptionValueIngestTemporary:
+		if opts.Temporary {
+			return adbc.OptionValueEnabled, true
+		}
+		return adbc.OptionValueDisabled, true
+	}
+	return "", false
+}
+
+// ingestTableName builds the name of the ingest target table. Unset parts
+// resolve against the connection's current catalog and schema, except
+// that a catalog without a schema uses the current schema, as a two-part
+// name would otherwise be read as schema.table. Temporary tables cannot
+// be qualified, so the catalog and schema are ignored for them.
+func ingestTableName(opts *driverbase.BulkIngestOptions, currentDbSchema func() (string, error)) (string, error) {
+	if opts.Temporary {
+		return buildTableName("", "", opts.TableName), nil
+	}
+
+	dbSchema := opts.SchemaName
+	if opts.CatalogName != "" && dbSchema == "" {
+		var err error
+		if dbSchema, err = currentDbSchema(); err != nil {
+			return "", err
+		}
+	}
+	return buildTableName(opts.CatalogName, dbSchema, opts.TableName), nil
+}
+
+// ingestTarget is a table written by an ingest, with the statements and
+// state for writing its rows
+type ingestTarget struct {
+	opts      *driverbase.BulkIngestOptions
+	tableName string
+	insertSQL string
+	// INSERT statements keyed by the columns written as DEFAULT
+	defaultInsertSQL map[string]string
+	// Columns whose null values are written as DEFAULT; nil if none
+	hasDefault []bool
+	// Keys of rows written by earlier attempts
+	writtenKeys map[string]bool
+	// Fails rows that would lose information; nil if disabled
+	checkConversions func(rec arrow.RecordBatch, idx int, row int64) error
+	rows             int64
+}
+
+// shadowIngestResult reports the ingest into the shadow table, which does
+// not affect the ingest into the target table
+type shadowIngestResult struct {
+	rows int64
+	err  error
+}
+
 // executeIngest performs bulk insert using parameterized INSERT statements
 func (s *statementImpl) executeIngest(ctx context.Context) (int64, error) {
 	if s.boundStream == nil {
-		return -1, s.ErrorHelper.Errorf(adbc.StatusInvalidState, "no data bound for ingestion")
+		return -1, s.errNoBoundData("no data bound for ingestion")
 	}
 
-	defer func() {
-		s.boundStream.Release()
-		s.boundStream = nil
-	}()
+	if s.ingestOptions.Repeat == OptionValueIngestRepeatReingest {
+		// Kept for the next ExecuteUpdate, whether or not this one fails
+		defer s.replayBound().rewind()
+	} else {
+		defer s.releaseBound()
+	}
 
-	opts := &s.bulkIngestOptions
+	if s.ingestOptions.CDCOperationColumn != "" {
+		return s.executeCDCIngest(ctx)
+	}
 
-	tableName := buildTableName(opts.CatalogName, opts.SchemaName, opts.TableName)
+	schema := s.boundStream.Schema()
+	tableSchema := s.ingestTableSchema(schema)
+	idempotencyKey := s.ingestOptions.IdempotencyKey
+	s.addedColumns = nil
 
-	if err := s.createTableIfNeeded(ctx, tableName, s.boundStream.Schema(), opts); err != nil {
+	target, err := s.prepareIngestTarget(ctx, &s.bulkIngestOptions, schema, tableSchema)
+	if err != nil {
 		return -1, err
 	}
 
-	insertSQL, err := buildInsertSQL(tableName, s.boundStream.Schema())
-	if err != nil {
-		return -1, err
+	// The shadow table is written alongside, until it fails
+	var shadow *ingestTarget
+	s.shadowIngest = nil
+	if shadowOpts := s.ingestOptions.shadowOptions(&s.bulkIngestOptions); shadowOpts != nil {
+		s.shadowIngest = &shadowIngestResult{}
+		if shadow, err = s.prepareIngestTarget(ctx, shadowOpts, schema, tableSchema); err != nil {
+			s.shadowIngest.err = err
+		}
+	}
+	failShadow := func(err error) {
+		s.shadowIngest.rows = shadow.rows
+		s.shadowIngest.err = err
+		shadow = nil
 	}
+	defer func() {
+		if shadow != nil {
+			s.shadowIngest.rows = shadow.rows
+		}
+	}()
 
-	totalRows := int64(0)
-	params := make([]driver.NamedValue, s.boundStream.Schema().NumFields())
+	values := make([]any, schema.NumFields())
+	params := make([]driver.NamedValue, 0, tableSchema.NumFields())
+	useDefault := make([]bool, tableSchema.NumFields())
 
-	for s.boundStream.Next() {
+	memStats := &memoryStats{}
+	s.memoryStats = memStats
+	var batchSize int64
+	defer func() { memStats.add(-batchSize) }()
+
+	// Index of the first row of the batch in the bound data
+	var batchStart int64
+	for batchIdx := 0; s.boundStream.Next(); batchIdx++ {
 		recordBatch := s.boundStream.RecordBatch()
+		// The stream's previous batch is released by Next
+		memStats.add(-batchSize)
+		batchSize = recordBufferSize(recordBatch)
+		memStats.add(batchSize)
 
+		if !recordBatch.Schema().Equal(schema) {
+			added, err := s.batchAddedFields(batchIdx, schema, recordBatch.Schema())
+			if err != nil {
+				return target.rows, err
+			}
+			if len(added) > 0 && s.ingestOptions.SchemaEvolution == OptionValueSchemaEvolutionAddColumns {
+				schema = arrow.NewSchema(append(slices.Clone(schema.Fields()), added...), nil)
+				tableSchema = s.ingestTableSchema(schema)
+				if err := s.addIngestColumns(ctx, target, schema, tableSchema, added); err != nil {
+					return target.rows, err
+				}
+				if shadow != nil {
+					if err := s.addIngestColumns(ctx, shadow, schema, tableSchema, added); err != nil {
+						failShadow(err)
+					}
+				}
+				values = make([]any, schema.NumFields())
+				params = make([]driver.NamedValue, 0, tableSchema.NumFields())
+				useDefault = make([]bool, tableSchema.NumFields())
+			}
+		}
+
+		// Batches are checked before any of their rows is written
 		for rowIdx := range int(recordBatch.NumRows()) {
-			// Extract Go values from Arrow columns
-			for colIdx := range int(recordBatch.NumCols()) {
-				arr := recordBatch.Column(colIdx)
-				val, err := extractGoValue(arr, rowIdx)
-				if err != nil {
-					return totalRows, s.ErrorHelper.Errorf(adbc.StatusInternal, "failed to extract go value: %v", err)
+			if target.checkConversions != nil {
+				if err := target.checkConversions(recordBatch, rowIdx, batchStart+int64(rowIdx)); err != nil {
+					return target.rows, err
+				}
+			}
+			if shadow != nil && shadow.checkConversions != nil {
+				if err := shadow.checkConversions(recordBatch, rowIdx, batchStart+int64(rowIdx)); err != nil {
+					failShadow(err)
 				}
-				params[colIdx].Value = val
 			}
+		}
+		batchStart += recordBatch.NumRows()
 
-			// Use ExecContext directly instead of PrepareContext because Databricks doesn't do server-side statement preparation
-			result, err := s.conn.conn.ExecContext(ctx, insertSQL, valuesToInterfaces(params)...)
-			if err != nil {
-				return totalRows, s.ErrorHelper.Errorf(adbc.StatusInternal, "failed to execute the query: %v", err)
+		for rowIdx := range int(recordBatch.NumRows()) {
+			var rowKey string
+			if idempotencyKey != "" {
+				rowKey = rowIdempotencyKey(idempotencyKey, batchIdx, rowIdx)
+			}
+
+			// Extract Go values from Arrow columns, leaving out fields
+			// ignored by the schema evolution policy
+			for colIdx := range schema.NumFields() {
+				val, err := extractGoValue(recordBatch.Column(colIdx), rowIdx)
+				if err != nil {
+					return target.rows, s.rowErrorf(newRowPosition(recordBatch, batchIdx, rowIdx, colIdx),
+						adbc.StatusInternal, "failed to extract go value: %v", err)
+				}
+				values[colIdx] = val
 			}
 
-			rows, _ := result.RowsAffected()
-			totalRows += rows
+			if err := s.writeIngestRow(ctx, target, tableSchema, values, rowKey, params, useDefault); err != nil {
+				pos := newRowPosition(recordBatch, batchIdx, rowIdx, failedColumn(schema, err))
+				return target.rows, s.rowErrorf(pos, adbc.StatusInternal, "failed to execute the query: %v", err)
+			}
+			if shadow != nil {
+				if err := s.writeIngestRow(ctx, shadow, tableSchema, values, rowKey, params, useDefault); err != nil {
+					pos := newRowPosition(recordBatch, batchIdx, rowIdx, failedColumn(schema, err))
+					failShadow(s.rowErrorf(pos, adbc.StatusInternal, "failed to write to the shadow table: %v", err))
+				}
+			}
 		}
 	}
 
 	if err := s.boundStream.Err(); err != nil {
-		return totalRows, s.ErrorHelper.Errorf(adbc.StatusInternal, "stream error: %v", err)
+		return target.rows, s.ErrorHelper.Errorf(adbc.StatusInternal, "stream error: %v", err)
+	}
+
+	return target.rows, nil
+}
+
+// prepareIngestTarget creates the table of an ingest if needed and looks
+// up what writing its rows requires
+func (s *statementImpl) prepareIngestTarget(ctx context.Context, opts *driverbase.BulkIngestOptions, schema, tableSchema *arrow.Schema) (*ingestTarget, error) {
+	tableName, err := ingestTableName(opts, s.conn.GetCurrentDbSchema)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Mode != adbc.OptionValueIngestModeAppend {
+		if err := validateNewIdentifier("table", opts.TableName); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.createTableIfNeeded(ctx, tableName, tableSchema, opts); err != nil {
+		return nil, err
+	}
+
+	t := &ingestTarget{opts: opts, tableName: tableName, defaultInsertSQL: map[string]string{}}
+	if t.checkConversions, err = s.ingestConversionCheck(ctx, schema, opts); err != nil {
+		return nil, err
+	}
+
+	// Tables just created or replaced hold no rows of earlier attempts
+	idempotencyKey := s.ingestOptions.IdempotencyKey
+	if idempotencyKey != "" && (opts.Mode == adbc.OptionValueIngestModeAppend || opts.Mode == adbc.OptionValueIngestModeCreateAppend) {
+		if t.writtenKeys, err = s.writtenIdempotencyKeys(ctx, tableName, idempotencyKey); err != nil {
+			return nil, err
+		}
+	}
+
+	if s.ingestOptions.NullAsDefault && !opts.Temporary &&
+		(opts.Mode == adbc.OptionValueIngestModeAppend || opts.Mode == adbc.OptionValueIngestModeCreateAppend) {
+		// Tables created by the ingest itself never declare defaults
+		defaults, err := s.columnsWithDefaults(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		if len(defaults) > 0 {
+			t.hasDefault = make([]bool, schema.NumFields())
+			for i, field := range schema.Fields() {
+				t.hasDefault[i] = defaults[identifierKey(field.Name)]
+			}
+		}
 	}
 
-	return totalRows, nil
+	if t.insertSQL, err = buildInsertSQL(tableName, tableSchema, nil); err != nil {
+		return nil, err
+	}
+	if err := s.conn.checkSQLLength(t.insertSQL); err != nil {
+		return nil, err
+	}
+	if err := s.stageIngest(ctx, t, tableSchema); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// writeIngestRow inserts a row of values into the table of t, unless an
+// earlier attempt wrote it. params and useDefault are scratch space.
+func (s *statementImpl) writeIngestRow(ctx context.Context, t *ingestTarget, tableSchema *arrow.Schema, values []any, rowKey string, params []driver.NamedValue, useDefault []bool) error {
+	if rowKey != "" && t.writtenKeys[rowKey] {
+		return nil
+	}
+
+	params = params[:0]
+	rowSQL := t.insertSQL
+	anyDefault := false
+	for colIdx, val := range values {
+		useDefault[colIdx] = t.hasDefault != nil && t.hasDefault[colIdx] && val == nil
+		if useDefault[colIdx] {
+			anyDefault = true
+			continue
+		}
+		params = append(params, driver.NamedValue{Ordinal: len(params) + 1, Value: val})
+	}
+	if rowKey != "" {
+		params = append(params, driver.NamedValue{Ordinal: len(params) + 1, Value: rowKey})
+	}
+
+	if anyDefault {
+		key := defaultColumnsKey(useDefault)
+		if cached, ok := t.defaultInsertSQL[key]; ok {
+			rowSQL = cached
+		} else {
+			var err error
+			if rowSQL, err = buildInsertSQL(t.tableName, tableSchema, useDefault); err != nil {
+				return err
+			}
+			t.defaultInsertSQL[key] = rowSQL
+		}
+	}
+
+	// Use ExecContext directly instead of PrepareContext because Databricks doesn't do server-side statement preparation
+	result, err := s.conn.conn.ExecContext(ctx, rowSQL, valuesToInterfaces(params)...)
+	if err != nil {
+		return err
+	}
+	rows, _ := result.RowsAffected()
+	t.rows += rows
+	return nil
+}
+
+// writtenIdempotencyKeys returns the row keys of an ingest identified by
+// key that are already stored in the table
+func (s *statementImpl) writtenIdempotencyKeys(ctx context.Context, tableName string, key string) (keys map[string]bool, err error) {
+	column := quoteIdentifier(s.ingestOptions.idempotencyColumn())
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE startswith(%s, ?)", column, tableName, column)
+	rows, err := s.conn.conn.QueryContext(ctx, query, key+"/")
+	if err != nil {
+		return nil, s.ErrorHelper.Errorf(adbc.StatusInternal, "failed to query idempotency keys: %v", err)
+	}
+	defer func() {
+		err = errors.Join(err, rows.Close())
+	}()
+
+	keys = map[string]bool{}
+	for rows.Next() {
+		var rowKey string
+		if err := rows.Scan(&rowKey); err != nil {
+			return nil, s.ErrorHelper.Errorf(adbc.StatusInternal, "failed to scan idempotency key: %v", err)
+		}
+		keys[rowKey] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, s.ErrorHelper.Errorf(adbc.StatusInternal, "failed to read idempotency keys: %v", err)
+	}
+	return keys, nil
 }
 
 // createTableIfNeeded creates/drops table based on ingest mode
@@ -111,6 +545,27 @@ func (s *statementImpl) createTableIfNeeded(ctx context.Context, tableName strin
 
 // createTable generates and executes CREATE TABLE DDL
 func (s *statementImpl) createTable(ctx context.Context, tableName string, schema *arrow.Schema, ifNotExists bool) error {
+	createSQL, err := buildCreateTableSQL(tableName, schema, ifNotExists, s.ingestOptions.NotNullColumns)
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.conn.conn.ExecContext(ctx, createSQL); err != nil {
+		return s.ErrorHelper.Errorf(adbc.StatusInternal, "failed to create table: %v", err)
+	}
+	return nil
+}
+
+// buildCreateTableSQL generates CREATE TABLE DDL for schema. Fields that
+// are not nullable, and the columns in notNull, are declared NOT NULL.
+func buildCreateTableSQL(tableName string, schema *arrow.Schema, ifNotExists bool, notNull []string) (string, error) {
+	// Databricks column names are case-insensitive
+	forceNotNull := make(map[string]bool, len(notNull))
+	for _, column := range notNull {
+		forceNotNull[identifierKey(column)] = true
+	}
+	matched := make(map[string]bool, len(notNull))
+
 	var sql strings.Builder
 	sql.WriteString("CREATE TABLE ")
 	if ifNotExists {
@@ -120,27 +575,41 @@ func (s *statementImpl) createTable(ctx context.Context, tableName string, schem
 	sql.WriteString(" (")
 
 	for i, field := range schema.Fields() {
+		if err := validateNewIdentifier("column", field.Name); err != nil {
+			return "", err
+		}
 		if i > 0 {
 			sql.WriteString(", ")
 		}
 		sql.WriteString(quoteIdentifier(field.Name))
 		sql.WriteString(" ")
 		sql.WriteString(arrowTypeToDatabricksType(field.Type))
-		if !field.Nullable {
+
+		required := forceNotNull[identifierKey(field.Name)]
+		if required {
+			matched[identifierKey(field.Name)] = true
+		}
+		if !field.Nullable || required {
 			sql.WriteString(" NOT NULL")
 		}
 	}
 	sql.WriteString(")")
 
-	_, err := s.conn.conn.ExecContext(ctx, sql.String())
-	if err != nil {
-		return s.ErrorHelper.Errorf(adbc.StatusInternal, "failed to create table: %v", err)
+	for _, column := range notNull {
+		if !matched[identifierKey(column)] {
+			return "", adbc.Error{
+				Code: adbc.StatusInvalidArgument,
+				Msg:  fmt.Sprintf("%s names column '%s', which is not in the bound data", OptionStatementIngestNotNullColumns, column),
+			}
+		}
 	}
-	return nil
+	return sql.String(), nil
 }
 
-// buildInsertSQL generates parameterized INSERT statement
-func buildInsertSQL(tableName string, schema *arrow.Schema) (string, error) {
+// buildInsertSQL generates parameterized INSERT statement. Columns flagged
+// in useDefault, which may be nil, are written as DEFAULT without a
+// parameter.
+func buildInsertSQL(tableName string, schema *arrow.Schema, useDefault []bool) (string, error) {
 	var sql strings.Builder
 
 	sql.WriteString("INSERT INTO ")
@@ -161,11 +630,10 @@ func buildInsertSQL(tableName string, schema *arrow.Schema) (string, error) {
 			sql.WriteString(", ")
 		}
 
-		if field.Type.ID() == arrow.FIXED_SIZE_BINARY {
-			// Use UNHEX() to convert hex string to binary
-			sql.WriteString("UNHEX(?)")
+		if useDefault != nil && useDefault[i] {
+			sql.WriteString("DEFAULT")
 		} else {
-			sql.WriteString("?")
+			sql.WriteString(parameterPlaceholder(field.Type))
 		}
 	}
 
@@ -173,6 +641,171 @@ func buildInsertSQL(tableName string, schema *arrow.Schema) (string, error) {
 	return sql.String(), nil
 }
 
+// defaultColumnsKey encodes which columns of a row are written as DEFAULT
+func defaultColumnsKey(useDefault []bool) string {
+	key := make([]byte, len(useDefault))
+	for i, d := range useDefault {
+		if d {
+			key[i] = '1'
+		} else {
+			key[i] = '0'
+		}
+	}
+	return string(key)
+}
+
+// ingestTargetNamespace returns the catalog and schema of the ingest
+// target table
+func (s *statementImpl) ingestTargetNamespace(opts *driverbase.BulkIngestOptions) (catalog, dbSchema string, err error) {
+	catalog = opts.CatalogName
+	if catalog == "" {
+		if catalog, err = s.conn.GetCurrentCatalog(); err != nil {
+			return "", "", err
+		}
+	}
+	dbSchema = opts.SchemaName
+	if dbSchema == "" {
+		if dbSchema, err = s.conn.GetCurrentDbSchema(); err != nil {
+			return "", "", err
+		}
+	}
+	return catalog, dbSchema, nil
+}
+
+// columnsWithDefaults returns the lower-cased names of the columns of the
+// ingest target table that declare a DEFAULT value
+func (s *statementImpl) columnsWithDefaults(ctx context.Context, opts *driverbase.BulkIngestOptions) (columns map[string]bool, err error) {
+	catalog, dbSchema, err := s.ingestTargetNamespace(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(
+		"SELECT COLUMN_NAME FROM %s.information_schema.COLUMNS WHERE lower(TABLE_SCHEMA) = lower(%s) AND lower(TABLE_NAME) = lower(%s) AND COLUMN_DEFAULT IS NOT NULL",
+		quoteIdentifier(catalog), quoteString(dbSchema), quoteString(opts.TableName))
+
+	rows, err := s.conn.conn.QueryContext(ctx, query)
+	if err != nil {
+		return nil, s.ErrorHelper.Errorf(adbc.StatusInternal, "failed to query column defaults: %v", err)
+	}
+	defer func() {
+		err = errors.Join(err, rows.Close())
+	}()
+
+	columns = map[string]bool{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, s.ErrorHelper.Errorf(adbc.StatusInternal, "failed to scan column defaults: %v", err)
+		}
+		columns[identifierKey(name)] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, s.ErrorHelper.Errorf(adbc.StatusInternal, "failed to read column defaults: %v", err)
+	}
+	return columns, nil
+}
+
+// targetColumnTypes returns the Databricks types of the columns of the
+// ingest target table, keyed by lower-cased column name
+func (s *statementImpl) targetColumnTypes(ctx context.Context, opts *driverbase.BulkIngestOptions) (types map[string]string, err error) {
+	catalog, dbSchema, err := s.ingestTargetNamespace(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(
+		"SELECT COLUMN_NAME, FULL_DATA_TYPE FROM %s.information_schema.COLUMNS WHERE lower(TABLE_SCHEMA) = lower(%s) AND lower(TABLE_NAME) = lower(%s)",
+		quoteIdentifier(catalog), quoteString(dbSchema), quoteString(opts.TableName))
+
+	rows, err := s.conn.conn.QueryContext(ctx, query)
+	if err != nil {
+		return nil, s.ErrorHelper.Errorf(adbc.StatusInternal, "failed to query column types: %v", err)
+	}
+	defer func() {
+		err = errors.Join(err, rows.Close())
+	}()
+
+	types = map[string]string{}
+	for rows.Next() {
+		var name, typ string
+		if err := rows.Scan(&name, &typ); err != nil {
+			return nil, s.ErrorHelper.Errorf(adbc.StatusInternal, "failed to scan column types: %v", err)
+		}
+		types[identifierKey(name)] = typ
+	}
+	if err := rows.Err(); err != nil {
+		return nil, s.ErrorHelper.Errorf(adbc.StatusInternal, "failed to read column types: %v", err)
+	}
+	return types, nil
+}
+
+// ingestConversionCheck returns the strict conversion check of the
+// bound rows, see OptionStrictConversions; nil if none applies. Fields are
+// checked against the types of an existing target table, or else the
+// types the ingest creates the table with.
+func (s *statementImpl) ingestConversionCheck(ctx context.Context, schema *arrow.Schema, opts *driverbase.BulkIngestOptions) (func(rec arrow.RecordBatch, idx int, row int64) error, error) {
+	if !s.conn.strictConversions {
+		return nil, nil
+	}
+
+	var targetTypes map[string]string
+	if !opts.Temporary && (opts.Mode == adbc.OptionValueIngestModeAppend || opts.Mode == adbc.OptionValueIngestModeCreateAppend) {
+		var err error
+		if targetTypes, err = s.targetColumnTypes(ctx, opts); err != nil {
+			return nil, err
+		}
+	}
+	return s.rowConversionCheck(schema, targetTypes), nil
+}
+
+// rowConversionCheck returns a check that fails if a value of a row would
+// lose information when written to a column of the type in targetTypes,
+// keyed by lower-cased field name, or else the type created for the field.
+// It returns nil if no value can lose information.
+func (s *statementImpl) rowConversionCheck(schema *arrow.Schema, targetTypes map[string]string) func(rec arrow.RecordBatch, idx int, row int64) error {
+	checks := make([]conversionCheck, schema.NumFields())
+	targets := make([]string, schema.NumFields())
+	anyCheck := false
+	for i, field := range schema.Fields() {
+		target, ok := targetTypes[identifierKey(field.Name)]
+		if !ok {
+			target = arrowTypeToDatabricksType(field.Type)
+		}
+		checks[i] = newConversionCheck(field.Type, target)
+		targets[i] = target
+		anyCheck = anyCheck || checks[i] != nil
+	}
+	if !anyCheck {
+		return nil
+	}
+
+	return func(rec arrow.RecordBatch, idx int, row int64) error {
+		for i, check := range checks {
+			if check == nil || rec.Column(i).IsNull(idx) {
+				continue
+			}
+			if loss := check(rec.Column(i), idx); loss != "" {
+				field := schema.Field(i)
+				return s.ErrorHelper.Errorf(adbc.StatusInvalidArgument,
+					"column %s: converting %s to %s loses information at row %d: %s (set %s=false to allow)",
+					quoteIdentifier(field.Name), field.Type, targets[i], row, loss, OptionStrictConversions)
+			}
+		}
+		return nil
+	}
+}
+
+// parameterPlaceholder returns the SQL parameter marker for a value of the
+// given Arrow type, as produced by extractGoValue
+func parameterPlaceholder(dt arrow.DataType) string {
+	if dt.ID() == arrow.FIXED_SIZE_BINARY {
+		// Use UNHEX() to convert hex string to binary
+		return "UNHEX(?)"
+	}
+	return "?"
+}
+
 // buildTableName constructs catalog.schema.table name
 func buildTableName(catalog, schema, table string) string {
 	parts := []string{}
@@ -186,9 +819,10 @@ func buildTableName(catalog, schema, table string) string {
 	return strings.Join(parts, ".")
 }
 
-// quoteIdentifier quotes a Databricks identifier with backticks
+// quoteIdentifier quotes a Databricks identifier with backticks, in
+// Unicode normalization form C
 func quoteIdentifier(id string) string {
-	escaped := strings.ReplaceAll(id, "`", "``")
+	escaped := strings.ReplaceAll(normalizeIdentifier(id), "`", "``")
 	return fmt.Sprintf("`%s`", escaped)
 }
 
@@ -273,8 +907,9 @@ func extractGoValue(arr arrow.Array, idx int) (any, error) {
 
 	case arrow.DECIMAL128:
 		dec := arr.(*array.Decimal128)
-		// Return as string, databricks-sql-go will infer DECIMAL type
-		return dec.ValueStr(idx), nil
+		// Return as string, databricks-sql-go will infer DECIMAL type.
+		// ValueStr is wrong on 32-bit platforms and may use exponents.
+		return formatDecimal128(dec.Value(idx), dec.DataType().(*arrow.Decimal128Type).Scale), nil
 
 	default:
 		return nil, fmt.Errorf("unsupported Arrow type: %s", arr.DataType())
@@ -283,45 +918,54 @@ func extractGoValue(arr arrow.Array, idx int) (any, error) {
 
 // arrowTypeToDatabricksType maps Arrow types to Databricks DDL types for CREATE TABLE
 func arrowTypeToDatabricksType(dt arrow.DataType) string {
+	if typ, ok := lookupDatabricksType(dt); ok {
+		return typ
+	}
+	return "STRING" // Fallback
+}
+
+// lookupDatabricksType returns the Databricks type for an Arrow type, and
+// false if the Arrow type has no direct equivalent
+func lookupDatabricksType(dt arrow.DataType) (string, bool) {
 	switch dt.ID() {
 	case arrow.BOOL:
-		return "BOOLEAN"
+		return "BOOLEAN", true
 	case arrow.INT8:
-		return "TINYINT"
+		return "TINYINT", true
 	case arrow.INT16:
-		return "SMALLINT"
+		return "SMALLINT", true
 	case arrow.INT32:
-		return "INT"
+		return "INT", true
 	case arrow.INT64:
-		return "BIGINT"
+		return "BIGINT", true
 	case arrow.UINT8:
-		return "SMALLINT"
+		return "SMALLINT", true
 	case arrow.UINT16:
-		return "INT"
+		return "INT", true
 	case arrow.UINT32:
-		return "BIGINT"
+		return "BIGINT", true
 	case arrow.UINT64:
-		return "BIGINT"
+		return "BIGINT", true
 	case arrow.FLOAT32:
-		return "FLOAT"
+		return "FLOAT", true
 	case arrow.FLOAT64:
-		return "DOUBLE"
+		return "DOUBLE", true
 	case arrow.STRING, arrow.LARGE_STRING, arrow.STRING_VIEW:
-		return "STRING"
+		return "STRING", true
 	case arrow.BINARY, arrow.LARGE_BINARY, arrow.BINARY_VIEW, arrow.FIXED_SIZE_BINARY:
-		return "BINARY"
+		return "BINARY", true
 	case arrow.DATE32, arrow.DATE64:
-		return "DATE"
+		return "DATE", true
 	case arrow.TIMESTAMP:
 		ts := dt.(*arrow.TimestampType)
 		if ts.TimeZone != "" {
-			return "TIMESTAMP"
+			return "TIMESTAMP", true
 		}
-		return "TIMESTAMP_NTZ"
+		return "TIMESTAMP_NTZ", true
 	case arrow.DECIMAL128:
 		dec := dt.(*arrow.Decimal128Type)
-		return fmt.Sprintf("DECIMAL(%d, %d)", dec.Precision, dec.Scale)
+		return fmt.Sprintf("DECIMAL(%d, %d)", dec.Precision, dec.Scale), true
 	default:
-		return "STRING" // Fallback
+		return "", false
 	}
 }
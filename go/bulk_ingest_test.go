@@ -0,0 +1,383 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/adbc-drivers/driverbase-go/driverbase"
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildInsertSQL(t *testing.T) {
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "status", Type: arrow.BinaryTypes.String, Nullable: true},
+		{Name: "hash", Type: &arrow.FixedSizeBinaryType{ByteWidth: 4}, Nullable: true},
+	}, nil)
+	tableName := buildTableName("", "", "events")
+
+	sql, err := buildInsertSQL(tableName, schema, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO `events` (`id`, `status`, `hash`) VALUES (?, ?, UNHEX(?))", sql)
+
+	sql, err = buildInsertSQL(tableName, schema, []bool{false, true, true})
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO `events` (`id`, `status`, `hash`) VALUES (?, DEFAULT, DEFAULT)", sql)
+}
+
+func TestIngestOptions(t *testing.T) {
+	eh := driverbase.ErrorHelper{DriverName: "databricks"}
+	var opts ingestOptions
+
+	val, ok := opts.GetOption(OptionStatementIngestNullAsDefault)
+	assert.True(t, ok)
+	assert.Equal(t, adbc.OptionValueDisabled, val)
+
+	handled, err := opts.SetOption(&eh, OptionStatementIngestNullAsDefault, adbc.OptionValueEnabled)
+	require.NoError(t, err)
+	assert.True(t, handled)
+	assert.True(t, opts.NullAsDefault)
+
+	_, err = opts.SetOption(&eh, OptionStatementIngestNullAsDefault, "maybe")
+	var adbcErr adbc.Error
+	require.ErrorAs(t, err, &adbcErr)
+	assert.Equal(t, adbc.StatusInvalidArgument, adbcErr.Code)
+
+	handled, err = opts.SetOption(&eh, adbc.OptionKeyIngestMode, adbc.OptionValueIngestModeAppend)
+	require.NoError(t, err)
+	assert.False(t, handled)
+}
+
+func TestIngestTableName(t *testing.T) {
+	eh := driverbase.ErrorHelper{DriverName: "databricks"}
+	currentDbSchema := func() (string, error) { return "current", nil }
+
+	for _, tc := range []struct {
+		name     string
+		options  map[string]string
+		expected string
+	}{
+		{"table only", map[string]string{}, "`events`"},
+		{"schema", map[string]string{adbc.OptionValueIngestTargetDBSchema: "sales"}, "`sales`.`events`"},
+		{"catalog and schema", map[string]string{
+			adbc.OptionValueIngestTargetCatalog:  "main",
+			adbc.OptionValueIngestTargetDBSchema: "sales",
+		}, "`main`.`sales`.`events`"},
+		{"catalog uses current schema", map[string]string{adbc.OptionValueIngestTargetCatalog: "main"}, "`main`.`current`.`events`"},
+		{"temporary ignores namespace", map[string]string{
+			adbc.OptionValueIngestTemporary:      adbc.OptionValueEnabled,
+			adbc.OptionValueIngestTargetCatalog:  "main",
+			adbc.OptionValueIngestTargetDBSchema: "sales",
+		}, "`events`"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			opts := driverbase.NewBulkIngestOptions()
+			tc.options[adbc.OptionKeyIngestTargetTable] = "events"
+			// Apply temporary first, as setting it clears the namespace
+			keys := []string{adbc.OptionValueIngestTemporary, adbc.OptionKeyIngestTargetTable,
+				adbc.OptionValueIngestTargetCatalog, adbc.OptionValueIngestTargetDBSchema}
+			for _, key := range keys {
+				if val, ok := tc.options[key]; ok {
+					handled, err := opts.SetOption(&eh, key, val)
+					require.NoError(t, err)
+					require.True(t, handled)
+				}
+			}
+
+			name, err := ingestTableName(&opts, currentDbSchema)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, name)
+
+			for key, val := range tc.options {
+				got, ok := bulkIngestOption(&opts, key)
+				assert.True(t, ok)
+				assert.Equal(t, val, got, key)
+			}
+		})
+	}
+}
+
+func TestBuildCreateTableSQL(t *testing.T) {
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "Email", Type: arrow.BinaryTypes.String, Nullable: true},
+		{Name: "note", Type: arrow.BinaryTypes.String, Nullable: true},
+	}, nil)
+	tableName := buildTableName("", "", "users")
+
+	sql, err := buildCreateTableSQL(tableName, schema, false, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "CREATE TABLE `users` (`id` BIGINT NOT NULL, `Email` STRING, `note` STRING)", sql)
+
+	sql, err = buildCreateTableSQL(tableName, schema, true, []string{"email"})
+	require.NoError(t, err)
+	assert.Equal(t, "CREATE TABLE IF NOT EXISTS `users` (`id` BIGINT NOT NULL, `Email` STRING NOT NULL, `note` STRING)", sql)
+
+	_, err = buildCreateTableSQL(tableName, schema, false, []string{"emial"})
+	var adbcErr adbc.Error
+	require.ErrorAs(t, err, &adbcErr)
+	assert.Equal(t, adbc.StatusInvalidArgument, adbcErr.Code)
+}
+
+func TestIngestNotNullColumnsOption(t *testing.T) {
+	eh := driverbase.ErrorHelper{DriverName: "databricks"}
+	var opts ingestOptions
+
+	handled, err := opts.SetOption(&eh, OptionStatementIngestNotNullColumns, " id, email ,")
+	require.NoError(t, err)
+	assert.True(t, handled)
+	assert.Equal(t, []string{"id", "email"}, opts.NotNullColumns)
+
+	val, ok := opts.GetOption(OptionStatementIngestNotNullColumns)
+	assert.True(t, ok)
+	assert.Equal(t, "id,email", val)
+
+	_, err = opts.SetOption(&eh, OptionStatementIngestNotNullColumns, "")
+	require.NoError(t, err)
+	assert.Empty(t, opts.NotNullColumns)
+}
+
+func TestIngestIdempotencyKey(t *testing.T) {
+	schema := arrow.NewSchema([]arrow.Field{{Name: "id", Type: arrow.PrimitiveTypes.Int64}}, nil)
+	bldr := array.NewRecordBuilder(memory.DefaultAllocator, schema)
+	defer bldr.Release()
+	bldr.Field(0).(*array.Int64Builder).AppendValues([]int64{10, 11}, nil)
+	rec := bldr.NewRecordBatch()
+	defer rec.Release()
+
+	ingest := func(mode string, writtenKeys ...string) *fakeDriver {
+		drv := &fakeDriver{keys: writtenKeys}
+		db := sql.OpenDB(drv)
+		defer func() { _ = db.Close() }()
+		conn, err := db.Conn(context.Background())
+		require.NoError(t, err)
+
+		stream, err := array.NewRecordReader(schema, []arrow.RecordBatch{rec})
+		require.NoError(t, err)
+		s := &statementImpl{
+			conn:              &connectionImpl{conn: conn},
+			bulkIngestOptions: driverbase.NewBulkIngestOptions(),
+			boundStream:       stream,
+		}
+		s.bulkIngestOptions.TableName = "events"
+		s.bulkIngestOptions.Mode = mode
+		s.ingestOptions.IdempotencyKey = "load-1"
+
+		_, err = s.executeIngest(context.Background())
+		require.NoError(t, err)
+		return drv
+	}
+
+	// A retried append skips the rows an earlier attempt wrote
+	drv := ingest(adbc.OptionValueIngestModeAppend, "load-1/0/0")
+	assert.Equal(t, []string{
+		"SELECT `_adbc_idempotency_key` FROM `events` WHERE startswith(`_adbc_idempotency_key`, ?)",
+		"INSERT INTO `events` (`id`, `_adbc_idempotency_key`) VALUES (?, ?)",
+	}, drv.execs)
+	assert.Equal(t, [][]any{{"11", "load-1/0/1"}}, drv.args)
+
+	// A created table has the key column and no earlier rows to look up
+	drv = ingest(adbc.OptionValueIngestModeCreate)
+	assert.Equal(t, "CREATE TABLE `events` (`id` BIGINT NOT NULL, `_adbc_idempotency_key` STRING)", drv.execs[0])
+	assert.Len(t, drv.execs, 3)
+}
+
+func TestIngestShadowTable(t *testing.T) {
+	schema := arrow.NewSchema([]arrow.Field{{Name: "id", Type: arrow.PrimitiveTypes.Int64}}, nil)
+	bldr := array.NewRecordBuilder(memory.DefaultAllocator, schema)
+	defer bldr.Release()
+	bldr.Field(0).(*array.Int64Builder).AppendValues([]int64{10, 11, 12}, nil)
+	rec := bldr.NewRecordBatch()
+	defer rec.Release()
+
+	// The shadow table rejects the second row
+	drv := &fakeDriver{}
+	var shadowInserts int
+	drv.execErr = func([]any) error {
+		if strings.Contains(drv.execs[len(drv.execs)-1], "`events_v2`") {
+			if shadowInserts++; shadowInserts == 2 {
+				return errors.New("[DELTA_TABLE_NOT_FOUND] table was dropped")
+			}
+		}
+		return nil
+	}
+	db := sql.OpenDB(drv)
+	defer func() { _ = db.Close() }()
+	conn, err := db.Conn(context.Background())
+	require.NoError(t, err)
+
+	stream, err := array.NewRecordReader(schema, []arrow.RecordBatch{rec})
+	require.NoError(t, err)
+	s := &statementImpl{
+		conn:              &connectionImpl{conn: conn},
+		bulkIngestOptions: driverbase.NewBulkIngestOptions(),
+		boundStream:       stream,
+	}
+	s.bulkIngestOptions.TableName = "events"
+	s.bulkIngestOptions.Mode = adbc.OptionValueIngestModeAppend
+	_, err = s.ingestOptions.SetOption(&s.ErrorHelper, OptionStatementIngestShadowTargetTable, "events_v2")
+	require.NoError(t, err)
+	_, err = s.ingestOptions.SetOption(&s.ErrorHelper, OptionStatementIngestShadowTargetDbSchema, "staging")
+	require.NoError(t, err)
+
+	rows, err := s.executeIngest(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), rows)
+	assert.Equal(t, []string{
+		"INSERT INTO `events` (`id`) VALUES (?)",
+		"INSERT INTO `staging`.`events_v2` (`id`) VALUES (?)",
+		"INSERT INTO `events` (`id`) VALUES (?)",
+		"INSERT INTO `staging`.`events_v2` (`id`) VALUES (?)",
+		"INSERT INTO `events` (`id`) VALUES (?)",
+	}, drv.execs)
+
+	shadowRows, err := s.getOptionInt(OptionStatementIngestShadowRowCount)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), shadowRows)
+	require.Error(t, s.shadowIngest.err)
+	assert.ErrorContains(t, s.shadowIngest.err, "failed to write to the shadow table: [DELTA_TABLE_NOT_FOUND] table was dropped at batch 0, row 1")
+}
+
+func TestIngestRepeat(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.DefaultAllocator)
+	defer mem.AssertSize(t, 0)
+	schema := arrow.NewSchema([]arrow.Field{{Name: "id", Type: arrow.PrimitiveTypes.Int64}}, nil)
+	bldr := array.NewRecordBuilder(mem, schema)
+	defer bldr.Release()
+	batch := func(ids ...int64) arrow.RecordBatch {
+		bldr.Field(0).(*array.Int64Builder).AppendValues(ids, nil)
+		return bldr.NewRecordBatch()
+	}
+
+	newStatement := func(drv *fakeDriver) *statementImpl {
+		db := sql.OpenDB(drv)
+		t.Cleanup(func() { _ = db.Close() })
+		conn, err := db.Conn(context.Background())
+		require.NoError(t, err)
+		s := &statementImpl{
+			conn:              &connectionImpl{conn: conn},
+			bulkIngestOptions: driverbase.NewBulkIngestOptions(),
+		}
+		s.bulkIngestOptions.TableName = "events"
+		s.bulkIngestOptions.Mode = adbc.OptionValueIngestModeAppend
+		return s
+	}
+	bindState := func(s *statementImpl) string {
+		state, err := s.getOption(OptionStatementBindState)
+		require.NoError(t, err)
+		return state
+	}
+
+	t.Run("error", func(t *testing.T) {
+		drv := &fakeDriver{}
+		s := newStatement(drv)
+		assert.Equal(t, OptionValueBindStateNone, bindState(s))
+		_, err := s.executeIngest(context.Background())
+		require.ErrorContains(t, err, "no data bound for ingestion")
+		assert.NotContains(t, err.Error(), "consumed")
+
+		rec := batch(1, 2)
+		require.NoError(t, s.Bind(context.Background(), rec))
+		rec.Release()
+		assert.Equal(t, OptionValueBindStateBound, bindState(s))
+		rows, err := s.executeIngest(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), rows)
+		assert.Equal(t, OptionValueBindStateConsumed, bindState(s))
+
+		// Ingesting again fails until data is bound again, whatever
+		// target is set
+		s.bulkIngestOptions.TableName = "events_copy"
+		_, err = s.executeIngest(context.Background())
+		var adbcErr adbc.Error
+		require.ErrorAs(t, err, &adbcErr)
+		assert.Equal(t, adbc.StatusInvalidState, adbcErr.Code)
+		assert.ErrorContains(t, err, "the data bound was consumed by the previous execution and must be bound again")
+		assert.Len(t, drv.args, 2)
+
+		rec = batch(3)
+		require.NoError(t, s.Bind(context.Background(), rec))
+		rec.Release()
+		rows, err = s.executeIngest(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), rows)
+		assert.Equal(t, [][]any{{"1"}, {"2"}, {"3"}}, drv.args)
+		require.NoError(t, s.Close())
+	})
+
+	t.Run("reingest", func(t *testing.T) {
+		// The first attempt fails at the second row of the second batch
+		drv := &fakeDriver{}
+		failed := false
+		drv.execErr = func(args []any) error {
+			if args[0] == "4" && !failed {
+				failed = true
+				return errors.New("connection reset")
+			}
+			return nil
+		}
+		s := newStatement(drv)
+		_, err := s.ingestOptions.SetOption(&s.ErrorHelper, OptionStatementIngestRepeat, OptionValueIngestRepeatReingest)
+		require.NoError(t, err)
+
+		first, second := batch(1, 2), batch(3, 4)
+		stream, err := array.NewRecordReader(schema, []arrow.RecordBatch{first, second})
+		require.NoError(t, err)
+		first.Release()
+		second.Release()
+		require.NoError(t, s.BindStream(context.Background(), stream))
+		stream.Release()
+
+		_, err = s.executeIngest(context.Background())
+		require.ErrorContains(t, err, "connection reset")
+		assert.Equal(t, OptionValueBindStateBound, bindState(s))
+
+		// The retry ingests all of the stream again, into the target set
+		// at the time
+		s.bulkIngestOptions.TableName = "events_retry"
+		rows, err := s.executeIngest(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, int64(4), rows)
+		assert.Equal(t, [][]any{{"1"}, {"2"}, {"3"}, {"1"}, {"2"}, {"3"}, {"4"}}, drv.args)
+		assert.Equal(t, "INSERT INTO `events_retry` (`id`) VALUES (?)", drv.execs[len(drv.execs)-1])
+
+		// Binding again discards the kept batches
+		rec := batch(5)
+		require.NoError(t, s.Bind(context.Background(), rec))
+		rec.Release()
+		rows, err = s.executeIngest(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), rows)
+		require.NoError(t, s.Close())
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		var opts ingestOptions
+		_, err := opts.SetOption(&driverbase.ErrorHelper{}, OptionStatementIngestRepeat, "twice")
+		require.ErrorContains(t, err, "invalid statement option databricks.statement.ingest.repeat=twice")
+		val, _ := opts.GetOption(OptionStatementIngestRepeat)
+		assert.Equal(t, OptionValueIngestRepeatError, val)
+	})
+}
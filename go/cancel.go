@@ -0,0 +1,189 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+)
+
+var (
+	// errStatementCanceled is the cause of the contexts ended by Cancel
+	errStatementCanceled = errors.New("statement canceled")
+	// errStatementClosed is the cause of the contexts of the results
+	// that closing their statement ended
+	errStatementClosed = errors.New("statement closed")
+)
+
+// operation is a call of a statement that Cancel can stop, along with the
+// reading of its results
+type operation struct {
+	s      *statementImpl
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+}
+
+// startOperation returns a context for a call that Cancel ends, replacing
+// the statement's previous operation as the one Cancel stops
+func (s *statementImpl) startOperation(ctx context.Context) (context.Context, *operation) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	op := &operation{s: s, ctx: ctx, cancel: cancel}
+	s.cancelMu.Lock()
+	s.operation = op
+	s.cancelMu.Unlock()
+	return ctx, op
+}
+
+// Cancel stops the statement's running call, or the reading of the
+// results of its last ExecuteQuery. The server is asked to cancel the
+// running query, and downloads of results from cloud storage stop. The
+// call, or the reader, fails with adbc.StatusCancelled. Unlike the other
+// methods of the statement, Cancel may be called while another is in
+// progress. It does nothing if nothing is running.
+func (s *statementImpl) Cancel() error {
+	s.cancelMu.Lock()
+	op := s.operation
+	s.cancelMu.Unlock()
+	if op != nil {
+		op.cancel(errStatementCanceled)
+	}
+	return nil
+}
+
+// err returns the error of the operation failing with err, reporting
+// that it was canceled if Cancel ended it
+func (op *operation) err(err error) error {
+	if err == nil || !errors.Is(context.Cause(op.ctx), errStatementCanceled) {
+		return err
+	}
+	return op.s.ErrorHelper.Errorf(adbc.StatusCancelled, "statement canceled")
+}
+
+// finish ends an operation that returned err
+func (op *operation) finish(err error) error {
+	err = op.err(err)
+	op.cancel(nil)
+	op.s.cancelMu.Lock()
+	if op.s.operation == op {
+		op.s.operation = nil
+	}
+	op.s.cancelMu.Unlock()
+	return err
+}
+
+// results ends an operation that returned reader once it is released, or
+// once the statement is closed
+func (op *operation) results(reader array.RecordReader, err error) (array.RecordReader, error) {
+	if err != nil {
+		return nil, op.finish(err)
+	}
+	r := &operationReader{RecordReader: reader, op: op}
+	r.refCount.Store(1)
+	op.s.cancelMu.Lock()
+	if op.s.readers == nil {
+		op.s.readers = map[*operationReader]struct{}{}
+	}
+	op.s.readers[r] = struct{}{}
+	op.s.cancelMu.Unlock()
+	return r, nil
+}
+
+// closeReaders closes the readers of the statement's results that are
+// still held, for Close
+func (s *statementImpl) closeReaders() {
+	s.cancelMu.Lock()
+	readers := make([]*operationReader, 0, len(s.readers))
+	for r := range s.readers {
+		readers = append(readers, r)
+	}
+	s.cancelMu.Unlock()
+	for _, r := range readers {
+		r.close(errStatementClosed)
+	}
+}
+
+// operationReader reads the results of an operation, ending it once
+// released. It holds a single reference to the results, which closing the
+// statement releases early, along with the server operation and the
+// batches buffered for them.
+type operationReader struct {
+	array.RecordReader
+	op       *operation
+	refCount atomic.Int64
+
+	mu     sync.Mutex
+	closed bool
+}
+
+func (r *operationReader) Next() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return !r.closed && r.RecordReader.Next()
+}
+
+func (r *operationReader) Record() arrow.RecordBatch {
+	return r.RecordBatch()
+}
+
+func (r *operationReader) RecordBatch() arrow.RecordBatch {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return nil
+	}
+	return r.RecordReader.RecordBatch()
+}
+
+func (r *operationReader) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return r.op.s.ErrorHelper.Errorf(adbc.StatusInvalidState, "statement was closed before its results were read")
+	}
+	return r.op.err(r.RecordReader.Err())
+}
+
+func (r *operationReader) Retain() {
+	r.refCount.Add(1)
+}
+
+func (r *operationReader) Release() {
+	if r.refCount.Add(-1) == 0 {
+		r.close(nil)
+	}
+}
+
+// close releases the results and ends the operation, first ending its
+// context with cause so that a Next in progress returns
+func (r *operationReader) close(cause error) {
+	r.op.cancel(cause)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return
+	}
+	r.closed = true
+	r.RecordReader.Release()
+	_ = r.op.finish(nil)
+	r.op.s.cancelMu.Lock()
+	delete(r.op.s.readers, r)
+	r.op.s.cancelMu.Unlock()
+}
@@ -0,0 +1,157 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// contextReader is a reader failing with the error of its context
+type contextReader struct {
+	array.RecordReader
+	ctx context.Context
+}
+
+func (r *contextReader) Next() bool { return r.ctx.Err() == nil && r.RecordReader.Next() }
+func (r *contextReader) Err() error { return r.ctx.Err() }
+
+func TestCancel(t *testing.T) {
+	started := make(chan struct{})
+	drv := &fakeDriver{before: blockUntil(started, nil)}
+	db := sql.OpenDB(drv)
+	defer func() { _ = db.Close() }()
+	conn, err := db.Conn(context.Background())
+	require.NoError(t, err)
+	s := &statementImpl{conn: &connectionImpl{conn: conn}}
+	require.NoError(t, s.SetSqlQuery("UPDATE t SET v = 1"))
+
+	// Nothing is running
+	require.NoError(t, s.Cancel())
+
+	// A running call fails as canceled
+	done := make(chan error)
+	go func() {
+		_, err := s.ExecuteUpdate(context.Background())
+		done <- err
+	}()
+	<-started
+	require.NoError(t, s.Cancel())
+	var adbcErr adbc.Error
+	require.ErrorAs(t, <-done, &adbcErr)
+	assert.Equal(t, adbc.StatusCancelled, adbcErr.Code)
+	assert.Nil(t, s.operation)
+
+	// As does reading the results of a query
+	ctx, op := s.startOperation(context.Background())
+	results, err := array.NewRecordReader(arrow.NewSchema(nil, nil), nil)
+	require.NoError(t, err)
+	reader, err := op.results(&contextReader{RecordReader: results, ctx: ctx}, nil)
+	require.NoError(t, err)
+	require.NoError(t, s.Cancel())
+	assert.False(t, reader.Next())
+	require.ErrorAs(t, reader.Err(), &adbcErr)
+	assert.Equal(t, adbc.StatusCancelled, adbcErr.Code)
+	reader.Release()
+	assert.Nil(t, s.operation)
+
+	// Failures of their own are kept
+	ctx, op = s.startOperation(context.Background())
+	assert.Equal(t, context.DeadlineExceeded, op.finish(context.DeadlineExceeded))
+	assert.ErrorIs(t, ctx.Err(), context.Canceled)
+}
+
+func TestCloseReleasesAbandonedReaders(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+	s := &statementImpl{conn: &connectionImpl{}}
+	read := func() (array.RecordReader, *mockRows) {
+		ctx, op := s.startOperation(context.Background())
+		rows := newInt64Rows(t, 2)
+		results, err := newIPCReaderAdapter(ctx, rows, &resultStats{}, mem)
+		require.NoError(t, err)
+		reader, err := op.results(results, nil)
+		require.NoError(t, err)
+		require.True(t, reader.Next())
+		return reader, rows
+	}
+
+	// Results released by the caller are not released again
+	released, releasedRows := read()
+	released.Release()
+	assert.Equal(t, 1, releasedRows.closed)
+
+	// Those abandoned are released, closing their server operation and
+	// freeing their batches
+	abandoned, rows := read()
+	abandoned.Retain()
+	require.NoError(t, s.Close())
+	assert.Equal(t, 1, rows.closed)
+	assert.Equal(t, 1, releasedRows.closed)
+	assert.Empty(t, s.readers)
+	assert.Nil(t, s.operation)
+
+	// Reading them further fails, and releasing them does nothing
+	assert.False(t, abandoned.Next())
+	assert.Nil(t, abandoned.RecordBatch())
+	requireInvalidState(t, abandoned.Err())
+	abandoned.Release()
+	abandoned.Release()
+	assert.Equal(t, 1, rows.closed)
+}
+
+func TestQueryTimeout(t *testing.T) {
+	drv := &fakeDriver{before: blockUntil(make(chan struct{}, 1), nil)}
+	db := sql.OpenDB(drv)
+	defer func() { _ = db.Close() }()
+	conn, err := db.Conn(context.Background())
+	require.NoError(t, err)
+	s := &statementImpl{conn: &connectionImpl{conn: conn}, queryTimeout: time.Minute}
+	require.NoError(t, s.SetSqlQuery("UPDATE t SET v = 1"))
+
+	val, err := s.GetOption(OptionQueryTimeout)
+	require.NoError(t, err)
+	assert.Equal(t, "1m0s", val)
+	var adbcErr adbc.Error
+	require.ErrorAs(t, s.SetOption(OptionQueryTimeout, "-1s"), &adbcErr)
+	assert.Equal(t, adbc.StatusInvalidArgument, adbcErr.Code)
+
+	// A query running longer is canceled
+	require.NoError(t, s.SetOption(OptionQueryTimeout, "20ms"))
+	_, err = s.ExecuteUpdate(context.Background())
+	require.ErrorAs(t, err, &adbcErr)
+	assert.Equal(t, adbc.StatusTimeout, adbcErr.Code)
+	assert.Contains(t, adbcErr.Msg, "20ms")
+
+	// As is one the server stopped
+	err = s.timeoutErr(context.Background(), 0)(errors.New("unexpected operation state TIMEDOUT_STATE"))
+	require.ErrorAs(t, err, &adbcErr)
+	assert.Equal(t, adbc.StatusTimeout, adbcErr.Code)
+
+	require.NoError(t, s.SetOption(OptionQueryTimeout, ""))
+	val, err = s.GetOption(OptionQueryTimeout)
+	require.NoError(t, err)
+	assert.Empty(t, val)
+}
@@ -0,0 +1,257 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql/driver"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-go/v18/arrow"
+)
+
+// Column of change feed staging tables holding the position of each
+// change in the feed
+const cdcSequenceColumn = "_adbc_cdc_seq"
+
+// cdcOperation returns the operation of a change feed row, "I", "U" or
+// "D", or false if val is not one
+func cdcOperation(val any) (string, bool) {
+	op, ok := val.(string)
+	if !ok {
+		return "", false
+	}
+	switch strings.ToUpper(op) {
+	case "I", "INSERT":
+		return "I", true
+	case "U", "UPDATE":
+		return "U", true
+	case "D", "DELETE":
+		return "D", true
+	}
+	return "", false
+}
+
+// executeCDCIngest applies the bound change feed to the ingest target
+// table: the rows are written to a staging table with their position in
+// the feed, and merged into the target with the last change to each key.
+// The staging table is dropped afterwards.
+func (s *statementImpl) executeCDCIngest(ctx context.Context) (rowsAffected int64, err error) {
+	opts := &s.bulkIngestOptions
+	cdc := &s.ingestOptions
+	switch {
+	case opts.Mode != adbc.OptionValueIngestModeAppend && opts.Mode != adbc.OptionValueIngestModeCreateAppend:
+		return -1, s.ErrorHelper.Errorf(adbc.StatusInvalidArgument, "%s requires ingest mode %s or %s",
+			OptionStatementIngestCDCOperationColumn, adbc.OptionValueIngestModeAppend, adbc.OptionValueIngestModeCreateAppend)
+	case len(cdc.CDCKeyColumns) == 0:
+		return -1, s.ErrorHelper.Errorf(adbc.StatusInvalidArgument, "%s requires %s",
+			OptionStatementIngestCDCOperationColumn, OptionStatementIngestCDCKeyColumns)
+	case cdc.IdempotencyKey != "" || cdc.ShadowTableName != "":
+		return -1, s.ErrorHelper.Errorf(adbc.StatusInvalidArgument, "%s cannot be combined with %s or %s",
+			OptionStatementIngestCDCOperationColumn, OptionStatementIngestIdempotencyKey, OptionStatementIngestShadowTargetTable)
+	case opts.Temporary:
+		return -1, s.ErrorHelper.Errorf(adbc.StatusNotImplemented, "%s is not supported for temporary tables",
+			OptionStatementIngestCDCOperationColumn)
+	case s.conn.txMode == transactionStaged:
+		return -1, s.ErrorHelper.Errorf(adbc.StatusNotImplemented,
+			"%s requires a warehouse supporting transactions when autocommit is disabled", OptionStatementIngestCDCOperationColumn)
+	}
+
+	// The staging table holds the bound fields, with the operation, and
+	// the position of each change; the target table the fields without
+	// the operation
+	schema := s.boundStream.Schema()
+	opIdx := -1
+	var keys, columns []string
+	var targetFields []arrow.Field
+	for i, field := range schema.Fields() {
+		switch {
+		case identifierKey(field.Name) == identifierKey(cdc.CDCOperationColumn):
+			opIdx = i
+		case identifierKey(field.Name) == identifierKey(cdcSequenceColumn):
+			return -1, s.ErrorHelper.Errorf(adbc.StatusInvalidArgument, "bound data has field %s, which change feed ingest uses",
+				quoteIdentifier(field.Name))
+		default:
+			targetFields = append(targetFields, field)
+			columns = append(columns, field.Name)
+		}
+	}
+	if opIdx < 0 {
+		return -1, s.ErrorHelper.Errorf(adbc.StatusInvalidArgument, "%s names column %s, which is not in the bound data",
+			OptionStatementIngestCDCOperationColumn, quoteIdentifier(cdc.CDCOperationColumn))
+	}
+	if !slices.Contains([]arrow.Type{arrow.STRING, arrow.LARGE_STRING, arrow.STRING_VIEW}, schema.Field(opIdx).Type.ID()) {
+		return -1, s.ErrorHelper.Errorf(adbc.StatusInvalidArgument, "operation column %s must be a string, not %s",
+			quoteIdentifier(cdc.CDCOperationColumn), schema.Field(opIdx).Type)
+	}
+	keyIdx := make([]int, len(cdc.CDCKeyColumns))
+	for i, key := range cdc.CDCKeyColumns {
+		keyIdx[i] = slices.IndexFunc(schema.Fields(), func(f arrow.Field) bool { return identifierKey(f.Name) == identifierKey(key) })
+		if keyIdx[i] < 0 || keyIdx[i] == opIdx {
+			return -1, s.ErrorHelper.Errorf(adbc.StatusInvalidArgument, "%s names column %s, which is not in the bound data",
+				OptionStatementIngestCDCKeyColumns, quoteIdentifier(key))
+		}
+		keys = append(keys, schema.Field(keyIdx[i]).Name)
+	}
+	if len(keys) == len(columns) {
+		return -1, s.ErrorHelper.Errorf(adbc.StatusInvalidArgument, "bound data has no columns besides the keys and the operation")
+	}
+
+	tableName, err := ingestTableName(opts, s.conn.GetCurrentDbSchema)
+	if err != nil {
+		return -1, err
+	}
+	if err := s.createTableIfNeeded(ctx, tableName, arrow.NewSchema(targetFields, nil), opts); err != nil {
+		return -1, err
+	}
+	checkConversions, err := s.ingestConversionCheck(ctx, schema, opts)
+	if err != nil {
+		return -1, err
+	}
+
+	suffix := make([]byte, 8)
+	_, _ = rand.Read(suffix)
+	stagingOpts := *opts
+	stagingOpts.TableName = stagingTablePrefix + hex.EncodeToString(suffix)
+	stagingName, err := ingestTableName(&stagingOpts, s.conn.GetCurrentDbSchema)
+	if err != nil {
+		return -1, err
+	}
+	stagingSchema := arrow.NewSchema(append(slices.Clone(schema.Fields()),
+		arrow.Field{Name: cdcSequenceColumn, Type: arrow.PrimitiveTypes.Int64}), nil)
+	if err := s.createTable(ctx, stagingName, stagingSchema, false); err != nil {
+		return -1, err
+	}
+	defer func() {
+		if _, dropErr := s.conn.conn.ExecContext(context.WithoutCancel(ctx), "DROP TABLE IF EXISTS "+stagingName); dropErr != nil {
+			err = errors.Join(err, s.ErrorHelper.Errorf(adbc.StatusIO, "failed to drop %s: %v", stagingName, dropErr))
+		}
+	}()
+
+	staging := &ingestTarget{opts: &stagingOpts, tableName: stagingName, defaultInsertSQL: map[string]string{}}
+	if staging.insertSQL, err = buildInsertSQL(stagingName, stagingSchema, nil); err != nil {
+		return -1, err
+	}
+	if err := s.conn.checkSQLLength(staging.insertSQL); err != nil {
+		return -1, err
+	}
+
+	memStats := &memoryStats{}
+	s.memoryStats = memStats
+	var batchSize int64
+	defer func() { memStats.add(-batchSize) }()
+
+	values := make([]any, stagingSchema.NumFields())
+	params := make([]driver.NamedValue, 0, stagingSchema.NumFields())
+	useDefault := make([]bool, stagingSchema.NumFields())
+	var seq int64
+	for batchIdx := 0; s.boundStream.Next(); batchIdx++ {
+		recordBatch := s.boundStream.RecordBatch()
+		memStats.add(-batchSize)
+		batchSize = recordBufferSize(recordBatch)
+		memStats.add(batchSize)
+		if !recordBatch.Schema().Equal(schema) {
+			return -1, s.ErrorHelper.Errorf(adbc.StatusInvalidArgument, "batch %d of the bound change feed has schema %s, not %s",
+				batchIdx, recordBatch.Schema(), schema)
+		}
+
+		for rowIdx := range int(recordBatch.NumRows()) {
+			if checkConversions != nil {
+				if err := checkConversions(recordBatch, rowIdx, seq); err != nil {
+					return -1, err
+				}
+			}
+			for colIdx := range schema.NumFields() {
+				val, err := extractGoValue(recordBatch.Column(colIdx), rowIdx)
+				if err != nil {
+					return -1, s.rowErrorf(newRowPosition(recordBatch, batchIdx, rowIdx, colIdx),
+						adbc.StatusInternal, "failed to extract go value: %v", err)
+				}
+				values[colIdx] = val
+			}
+			op, ok := cdcOperation(values[opIdx])
+			if !ok {
+				return -1, s.rowErrorf(newRowPosition(recordBatch, batchIdx, rowIdx, opIdx),
+					adbc.StatusInvalidArgument, "operation must be I, U or D")
+			}
+			values[opIdx] = op
+			for _, idx := range keyIdx {
+				if values[idx] == nil {
+					// NULL never compares equal, so the row cannot match anything
+					return -1, s.rowErrorf(newRowPosition(recordBatch, batchIdx, rowIdx, idx),
+						adbc.StatusInvalidArgument, "key column %s contains NULL", schema.Field(idx).Name)
+				}
+			}
+			values[len(values)-1] = strconv.FormatInt(seq, 10)
+			seq++
+
+			if err := s.writeIngestRow(ctx, staging, stagingSchema, values, "", params, useDefault); err != nil {
+				pos := newRowPosition(recordBatch, batchIdx, rowIdx, failedColumn(schema, err))
+				return -1, s.rowErrorf(pos, adbc.StatusInternal, "failed to stage the change: %v", err)
+			}
+		}
+	}
+	if err := s.boundStream.Err(); err != nil {
+		return -1, s.ErrorHelper.Errorf(adbc.StatusInternal, "stream error: %v", err)
+	}
+
+	result, err := s.conn.conn.ExecContext(ctx, buildCDCMergeSQL(tableName, stagingName, cdc.CDCOperationColumn, keys, columns))
+	if err != nil {
+		return -1, s.ErrorHelper.Errorf(adbc.StatusInternal, "failed to merge the change feed into %s: %v", tableName, err)
+	}
+	rowsAffected, _ = result.RowsAffected()
+	return rowsAffected, nil
+}
+
+// buildCDCMergeSQL generates the MERGE applying the changes staged in
+// stagingName to tableName. Only the last change to each key is applied:
+// deletes remove the row, and inserts and updates insert or update it.
+func buildCDCMergeSQL(tableName, stagingName, opColumn string, keys, columns []string) string {
+	quote := func(prefix string, names []string) []string {
+		quoted := make([]string, len(names))
+		for i, name := range names {
+			quoted[i] = prefix + quoteIdentifier(name)
+		}
+		return quoted
+	}
+
+	conditions := make([]string, len(keys))
+	for i, key := range keys {
+		conditions[i] = fmt.Sprintf("t.%s = s.%s", quoteIdentifier(key), quoteIdentifier(key))
+	}
+	var updates []string
+	for _, column := range columns {
+		if !slices.ContainsFunc(keys, func(key string) bool { return identifierKey(key) == identifierKey(column) }) {
+			updates = append(updates, fmt.Sprintf("t.%s = s.%s", quoteIdentifier(column), quoteIdentifier(column)))
+		}
+	}
+	op := "s." + quoteIdentifier(opColumn)
+
+	var sql strings.Builder
+	fmt.Fprintf(&sql, "MERGE INTO %s t USING (SELECT * FROM %s QUALIFY row_number() OVER (PARTITION BY %s ORDER BY %s DESC) = 1) s ON %s",
+		tableName, stagingName, strings.Join(quote("", keys), ", "), quoteIdentifier(cdcSequenceColumn), strings.Join(conditions, " AND "))
+	fmt.Fprintf(&sql, " WHEN MATCHED AND %s = 'D' THEN DELETE", op)
+	fmt.Fprintf(&sql, " WHEN MATCHED THEN UPDATE SET %s", strings.Join(updates, ", "))
+	fmt.Fprintf(&sql, " WHEN NOT MATCHED AND %s <> 'D' THEN INSERT (%s) VALUES (%s)",
+		op, strings.Join(quote("", columns), ", "), strings.Join(quote("s.", columns), ", "))
+	return sql.String()
+}
@@ -0,0 +1,124 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+
+	"github.com/adbc-drivers/driverbase-go/driverbase"
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCDCIngest(t *testing.T) {
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+		{Name: "name", Type: arrow.BinaryTypes.String, Nullable: true},
+		{Name: "op", Type: arrow.BinaryTypes.String},
+	}, nil)
+	changes := func(ids []int64, valid []bool, ops ...string) arrow.RecordBatch {
+		bldr := array.NewRecordBuilder(memory.DefaultAllocator, schema)
+		defer bldr.Release()
+		bldr.Field(0).(*array.Int64Builder).AppendValues(ids, valid)
+		for range ids {
+			bldr.Field(1).(*array.StringBuilder).Append("a")
+		}
+		bldr.Field(2).(*array.StringBuilder).AppendValues(ops, nil)
+		rec := bldr.NewRecordBatch()
+		t.Cleanup(rec.Release)
+		return rec
+	}
+
+	ingest := func(rec arrow.RecordBatch, mode string, keys string) (*fakeDriver, int64, error) {
+		drv := &fakeDriver{}
+		db := sql.OpenDB(drv)
+		t.Cleanup(func() { _ = db.Close() })
+		conn, err := db.Conn(context.Background())
+		require.NoError(t, err)
+
+		stream, err := array.NewRecordReader(schema, []arrow.RecordBatch{rec})
+		require.NoError(t, err)
+		s := &statementImpl{
+			conn:              &connectionImpl{conn: conn},
+			bulkIngestOptions: driverbase.NewBulkIngestOptions(),
+			boundStream:       stream,
+		}
+		s.bulkIngestOptions.TableName = "customers"
+		s.bulkIngestOptions.SchemaName = "crm"
+		s.bulkIngestOptions.Mode = mode
+		for key, val := range map[string]string{OptionStatementIngestCDCOperationColumn: "op", OptionStatementIngestCDCKeyColumns: keys} {
+			_, err := s.ingestOptions.SetOption(&s.ErrorHelper, key, val)
+			require.NoError(t, err)
+		}
+		rows, err := s.executeIngest(context.Background())
+		return drv, rows, err
+	}
+
+	// Changes are staged in the order of the feed, then merged
+	drv, _, err := ingest(changes([]int64{1, 2, 1}, nil, "I", "u", "delete"), adbc.OptionValueIngestModeCreateAppend, "ID")
+	require.NoError(t, err)
+	require.Len(t, drv.execs, 7)
+	assert.Equal(t, "CREATE TABLE IF NOT EXISTS `crm`.`customers` (`id` BIGINT, `name` STRING)", drv.execs[0])
+	staging := regexp.MustCompile("^CREATE TABLE (`crm`.`_adbc_staging_[0-9a-f]{16}`) \\(`id` BIGINT, `name` STRING, `op` STRING NOT NULL, `_adbc_cdc_seq` BIGINT NOT NULL\\)$").
+		FindStringSubmatch(drv.execs[1])
+	require.NotNil(t, staging, drv.execs[1])
+	assert.Equal(t, "INSERT INTO "+staging[1]+" (`id`, `name`, `op`, `_adbc_cdc_seq`) VALUES (?, ?, ?, ?)", drv.execs[2])
+	assert.Equal(t, [][]any{{"1", "a", "I", "0"}, {"2", "a", "U", "1"}, {"1", "a", "D", "2"}}, drv.args[2:5])
+	assert.Equal(t, "MERGE INTO `crm`.`customers` t USING (SELECT * FROM "+staging[1]+
+		" QUALIFY row_number() OVER (PARTITION BY `id` ORDER BY `_adbc_cdc_seq` DESC) = 1) s ON t.`id` = s.`id`"+
+		" WHEN MATCHED AND s.`op` = 'D' THEN DELETE"+
+		" WHEN MATCHED THEN UPDATE SET t.`name` = s.`name`"+
+		" WHEN NOT MATCHED AND s.`op` <> 'D' THEN INSERT (`id`, `name`) VALUES (s.`id`, s.`name`)", drv.execs[5])
+	assert.Equal(t, "DROP TABLE IF EXISTS "+staging[1], drv.execs[6])
+
+	// The staging table is dropped when a change is invalid
+	for _, tc := range []struct {
+		rec  arrow.RecordBatch
+		mode string
+		keys string
+		msg  string
+	}{
+		{changes([]int64{1}, nil, "X"), adbc.OptionValueIngestModeAppend, "id", "operation must be I, U or D"},
+		{changes([]int64{1, 0}, []bool{true, false}, "I", "D"), adbc.OptionValueIngestModeAppend, "id", "key column id contains NULL"},
+	} {
+		drv, _, err := ingest(tc.rec, tc.mode, tc.keys)
+		var adbcErr adbc.Error
+		require.ErrorAs(t, err, &adbcErr)
+		assert.Equal(t, adbc.StatusInvalidArgument, adbcErr.Code)
+		assert.Contains(t, adbcErr.Msg, tc.msg)
+		assert.Regexp(t, "^DROP TABLE IF EXISTS `crm`.`_adbc_staging_", drv.execs[len(drv.execs)-1])
+	}
+
+	// Nothing is written for invalid options
+	for _, tc := range []struct {
+		mode, keys, msg string
+	}{
+		{adbc.OptionValueIngestModeReplace, "id", "requires ingest mode " + adbc.OptionValueIngestModeAppend},
+		{adbc.OptionValueIngestModeAppend, "", "requires " + OptionStatementIngestCDCKeyColumns},
+		{adbc.OptionValueIngestModeAppend, "id,email", "names column `email`, which is not in the bound data"},
+		{adbc.OptionValueIngestModeAppend, "id,name", "no columns besides the keys"},
+	} {
+		drv, _, err := ingest(changes([]int64{1}, nil, "I"), tc.mode, tc.keys)
+		assert.ErrorContains(t, err, tc.msg)
+		assert.Empty(t, drv.execs)
+	}
+}
@@ -0,0 +1,341 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command compatmatrix runs the integration tests of the driver against
+// several versions of databricks-sql-go, and optionally several HTTP
+// paths, such as warehouses on the current and preview channels, and
+// reports the tests whose outcome differs from that with the pinned
+// version on the first HTTP path. It catches upstream changes in the
+// behavior the driver relies on, or works around, before the pin is
+// moved.
+//
+// The workspace is read from the environment, as for the integration
+// tests; -target overrides DATABRICKS_HTTPPATH:
+//
+//	compatmatrix -version v1.7.1 -version v1.8.0 -version latest \
+//	    -target current=/sql/1.0/warehouses/abc -target preview=/sql/1.0/warehouses/def \
+//	    -run 'TestE2E|TestStatement' -o compat.md
+//
+// Each version is tried with a copy of go.mod, leaving the module's own
+// untouched. The command exits with status 1 if any outcome differs, or
+// the tests could not run with a version.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+const sqlGoModule = "github.com/databricks/databricks-sql-go"
+
+// Outcome of a test that did not report one, such as when the tests did
+// not build
+const outcomeMissing = "-"
+
+// listFlag is a flag that may be given several times
+type listFlag []string
+
+func (l *listFlag) String() string { return strings.Join(*l, ", ") }
+
+func (l *listFlag) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// target is an HTTP path to run the tests against
+type target struct {
+	name     string
+	httpPath string
+}
+
+// column is the outcomes of the tests with one version against one target
+type column struct {
+	version  string
+	target   string
+	outcomes map[string]string
+	// Why the tests did not run, if they did not
+	failure string
+}
+
+func (c *column) title() string {
+	if c.target == "" {
+		return c.version
+	}
+	return c.version + " / " + c.target
+}
+
+// testEvent is an event of go test -json
+type testEvent struct {
+	Action string
+	Test   string
+}
+
+func main() {
+	var (
+		versions listFlag
+		targets  listFlag
+		dir      = flag.String("dir", ".", "directory of the driver's Go module")
+		pkg      = flag.String("pkg", ".", "package of the integration tests")
+		run      = flag.String("run", "", "run only the tests matching this regular expression")
+		timeout  = flag.Duration("timeout", 30*time.Minute, "timeout of each run of the tests")
+		output   = flag.String("o", "", "file to write the report to; standard output if empty")
+	)
+	flag.Var(&versions, "version", "databricks-sql-go version to test, or latest; may be repeated")
+	flag.Var(&targets, "target", "HTTP path to test against as name=path; may be repeated")
+	flag.Parse()
+
+	differs, err := runMatrix(*dir, *pkg, *run, *timeout, versions, targets, *output)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "compatmatrix:", err)
+		os.Exit(2)
+	}
+	if differs {
+		os.Exit(1)
+	}
+}
+
+func runMatrix(dir, pkg, run string, timeout time.Duration, versions, targetSpecs []string, output string) (bool, error) {
+	pinned, err := pinnedVersion(dir)
+	if err != nil {
+		return false, err
+	}
+	// The pinned version is the baseline, compared to first
+	versions = slices.DeleteFunc(slices.Clone(versions), func(v string) bool { return v == pinned })
+	versions = slices.Insert(versions, 0, pinned)
+
+	targets, err := parseTargets(targetSpecs)
+	if err != nil {
+		return false, err
+	}
+
+	tmp, err := os.MkdirTemp("", "compatmatrix")
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = os.RemoveAll(tmp) }()
+
+	var columns []*column
+	for i, version := range versions {
+		modfile := filepath.Join(tmp, fmt.Sprintf("v%d", i), "go.mod")
+		resolved, err := pinModfile(dir, modfile, version)
+		for _, t := range targets {
+			col := &column{version: resolved, target: t.name, outcomes: map[string]string{}}
+			if version == pinned {
+				col.version += " (pinned)"
+			}
+			if err != nil {
+				col.failure = err.Error()
+			} else {
+				fmt.Fprintf(os.Stderr, "compatmatrix: testing %s\n", col.title())
+				col.failure = runTests(dir, pkg, modfile, run, timeout, t, col.outcomes)
+			}
+			columns = append(columns, col)
+		}
+	}
+
+	w := io.Writer(os.Stdout)
+	if output != "" {
+		f, err := os.Create(output)
+		if err != nil {
+			return false, err
+		}
+		defer func() { _ = f.Close() }()
+		w = f
+	}
+	return report(w, columns), nil
+}
+
+// pinnedVersion returns the version of databricks-sql-go in go.mod
+func pinnedVersion(dir string) (string, error) {
+	cmd := exec.Command("go", "list", "-m", "-f", "{{.Version}}", sqlGoModule)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to read the version of %s: %w", sqlGoModule, err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// parseTargets parses name=path targets, defaulting to the HTTP path of
+// the environment
+func parseTargets(specs []string) ([]target, error) {
+	if len(specs) == 0 {
+		return []target{{httpPath: os.Getenv("DATABRICKS_HTTPPATH")}}, nil
+	}
+	targets := make([]target, 0, len(specs))
+	for _, spec := range specs {
+		name, path, ok := strings.Cut(spec, "=")
+		if !ok || name == "" || path == "" {
+			return nil, fmt.Errorf("invalid -target %q: expected name=path", spec)
+		}
+		targets = append(targets, target{name: name, httpPath: path})
+	}
+	return targets, nil
+}
+
+// pinModfile writes a copy of the module's go.mod and go.sum to modfile
+// requiring the version of databricks-sql-go, and returns the version it
+// resolved to
+func pinModfile(dir, modfile, version string) (string, error) {
+	if err := os.MkdirAll(filepath.Dir(modfile), 0o755); err != nil {
+		return version, err
+	}
+	for _, name := range []string{"go.mod", "go.sum"} {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return version, err
+		}
+		if err := os.WriteFile(filepath.Join(filepath.Dir(modfile), name), data, 0o644); err != nil {
+			return version, err
+		}
+	}
+
+	get := exec.Command("go", "get", "-modfile="+modfile, sqlGoModule+"@"+version)
+	get.Dir = dir
+	if out, err := get.CombinedOutput(); err != nil {
+		return version, fmt.Errorf("go get failed: %s", firstLine(out))
+	}
+	list := exec.Command("go", "list", "-modfile="+modfile, "-m", "-f", "{{.Version}}", sqlGoModule)
+	list.Dir = dir
+	out, err := list.Output()
+	if err != nil {
+		return version, fmt.Errorf("go list failed: %w", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// runTests runs the tests with modfile against the target, recording the
+// outcome of each, and returns why they did not run, if they did not
+func runTests(dir, pkg, modfile, run string, timeout time.Duration, t target, outcomes map[string]string) string {
+	args := []string{"test", "-modfile=" + modfile, "-count=1", "-json", "-timeout=" + timeout.String()}
+	if run != "" {
+		args = append(args, "-run="+run)
+	}
+	cmd := exec.Command("go", append(args, pkg)...)
+	cmd.Dir = dir
+	cmd.Env = os.Environ()
+	if t.httpPath != "" {
+		cmd.Env = append(cmd.Env, "DATABRICKS_HTTPPATH="+t.httpPath)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	scanner.Buffer(nil, 16<<20)
+	for scanner.Scan() {
+		var event testEvent
+		if json.Unmarshal(scanner.Bytes(), &event) != nil || event.Test == "" {
+			continue
+		}
+		switch event.Action {
+		case "pass", "fail", "skip":
+			outcomes[event.Test] = event.Action
+		}
+	}
+
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		return err.Error()
+	}
+	if len(outcomes) == 0 && err != nil {
+		return "tests did not run: " + firstLine(stderr.Bytes())
+	}
+	return ""
+}
+
+// report writes the outcomes as Markdown, and reports whether any differs
+// from the baseline, the first column
+func report(w io.Writer, columns []*column) bool {
+	fmt.Fprintln(w, "# databricks-sql-go compatibility")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "| version | passed | failed | skipped | |")
+	fmt.Fprintln(w, "|---|---|---|---|---|")
+	for _, col := range columns {
+		counts := map[string]int{}
+		for _, outcome := range col.outcomes {
+			counts[outcome]++
+		}
+		fmt.Fprintf(w, "| %s | %d | %d | %d | %s |\n", col.title(), counts["pass"], counts["fail"], counts["skip"], col.failure)
+	}
+
+	var tests []string
+	for _, col := range columns {
+		for test := range col.outcomes {
+			tests = append(tests, test)
+		}
+	}
+	slices.Sort(tests)
+	tests = slices.Compact(tests)
+
+	// Columns whose tests did not run are in the summary alone
+	baseline := columns[0]
+	ran := slices.DeleteFunc(slices.Clone(columns), func(c *column) bool { return c.failure != "" })
+	var differing []string
+	for _, test := range tests {
+		for _, col := range ran {
+			if outcome(col, test) != outcome(baseline, test) {
+				differing = append(differing, test)
+				break
+			}
+		}
+	}
+	anyFailure := slices.ContainsFunc(columns, func(c *column) bool { return c.failure != "" })
+
+	fmt.Fprintln(w)
+	if len(differing) == 0 {
+		fmt.Fprintf(w, "Every test has the same outcome as with %s.\n", baseline.title())
+		return anyFailure
+	}
+	fmt.Fprintf(w, "## Outcomes differing from %s\n\n", baseline.title())
+	fmt.Fprint(w, "| test |")
+	for _, col := range ran {
+		fmt.Fprintf(w, " %s |", col.title())
+	}
+	fmt.Fprintln(w)
+	fmt.Fprint(w, "|---|")
+	fmt.Fprint(w, strings.Repeat("---|", len(ran)))
+	fmt.Fprintln(w)
+	for _, test := range differing {
+		fmt.Fprintf(w, "| %s |", test)
+		for _, col := range ran {
+			fmt.Fprintf(w, " %s |", outcome(col, test))
+		}
+		fmt.Fprintln(w)
+	}
+	return true
+}
+
+func outcome(col *column, test string) string {
+	if outcome, ok := col.outcomes[test]; ok {
+		return outcome
+	}
+	return outcomeMissing
+}
+
+func firstLine(out []byte) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(string(out)), "\n")
+	return line
+}
@@ -0,0 +1,336 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command loadtest runs a mix of queries and bulk ingests from concurrent
+// connections against a workspace, and reports the latency percentiles
+// and error rate of each, for capacity planning and for catching
+// regressions in connection pooling and retries.
+//
+// The workspace is read from DATABRICKS_HOST, DATABRICKS_HTTPPATH and
+// DATABRICKS_TOKEN, or DATABRICKS_OAUTH_CLIENT_ID and
+// DATABRICKS_OAUTH_CLIENT_SECRET, as for the integration tests. Other
+// driver options are given with -option, and may be used to go through
+// a fault-injecting proxy with -proxy:
+//
+//	loadtest -connections 16 -duration 5m \
+//	    -query 'SELECT 1' -query 'SELECT * FROM samples.nyctaxi.trips LIMIT 10000' \
+//	    -ingest-table main.scratch.loadtest -ingest-weight 2 \
+//	    -option databricks.retry_budget.max_retries=100
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"maps"
+	"math"
+	"math/rand/v2"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/adbc-drivers/databricks/go"
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+// Longest error message kept when counting errors by message
+const maxErrorLength = 120
+
+// listFlag is a flag that may be given several times
+type listFlag []string
+
+func (l *listFlag) String() string { return strings.Join(*l, ", ") }
+
+func (l *listFlag) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// workload is one kind of operation of the mix
+type workload struct {
+	name   string
+	weight int
+	run    func(ctx context.Context, cnxn adbc.Connection) error
+}
+
+// results are the outcomes of a workload's operations
+type results struct {
+	mu        sync.Mutex
+	latencies []time.Duration
+	errors    int
+	messages  map[string]int
+}
+
+func (r *results) record(latency time.Duration, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.latencies = append(r.latencies, latency)
+	if err == nil {
+		return
+	}
+	r.errors++
+	msg, _, _ := strings.Cut(err.Error(), "\n")
+	if len(msg) > maxErrorLength {
+		msg = msg[:maxErrorLength] + "..."
+	}
+	r.messages[msg]++
+}
+
+// percentile returns the latency that the fraction p of sorted are at
+// most
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	idx := int(math.Ceil(p*float64(len(sorted)))) - 1
+	return sorted[max(idx, 0)]
+}
+
+func main() {
+	var (
+		connections  = flag.Int("connections", 8, "number of concurrent connections")
+		duration     = flag.Duration("duration", time.Minute, "how long to run the workload")
+		queries      listFlag
+		ingestTable  = flag.String("ingest-table", "", "table to bulk ingest into, created if missing; no ingests if empty")
+		ingestRows   = flag.Int("ingest-rows", 1000, "rows of each bulk ingest")
+		ingestWeight = flag.Int("ingest-weight", 1, "weight of ingests in the mix, where each query weighs 1")
+		proxy        = flag.String("proxy", "", "URL of an HTTP proxy to connect through")
+		options      listFlag
+	)
+	flag.Var(&queries, "query", "query to run; may be repeated")
+	flag.Var(&options, "option", "driver option as key=value; may be repeated")
+	flag.Parse()
+
+	if err := run(*connections, *duration, queries, *ingestTable, *ingestRows, *ingestWeight, *proxy, options); err != nil {
+		fmt.Fprintln(os.Stderr, "loadtest:", err)
+		os.Exit(1)
+	}
+}
+
+func run(connections int, duration time.Duration, queries []string, ingestTable string, ingestRows, ingestWeight int, proxy string, options []string) error {
+	if connections <= 0 {
+		return errors.New("-connections must be positive")
+	}
+	opts, err := databaseOptions(proxy, options)
+	if err != nil {
+		return err
+	}
+
+	var workloads []workload
+	for i, query := range queries {
+		workloads = append(workloads, workload{name: fmt.Sprintf("query %d", i+1), weight: 1, run: queryWorkload(query)})
+	}
+	if ingestTable != "" && ingestWeight > 0 {
+		workloads = append(workloads, workload{name: "ingest", weight: ingestWeight, run: ingestWorkload(ingestTable, ingestRows)})
+	}
+	if len(workloads) == 0 {
+		return errors.New("nothing to run: give -query or -ingest-table")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	db, err := databricks.NewDriver(memory.DefaultAllocator).NewDatabase(opts)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+
+	outcomes := make([]*results, len(workloads))
+	totalWeight := 0
+	for i, w := range workloads {
+		outcomes[i] = &results{messages: map[string]int{}}
+		totalWeight += w.weight
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, duration)
+	defer cancel()
+	start := time.Now()
+	var wg sync.WaitGroup
+	connectErrs := make(chan error, connections)
+	for range connections {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cnxn, err := db.Open(runCtx)
+			if err != nil {
+				connectErrs <- err
+				return
+			}
+			defer func() { _ = cnxn.Close() }()
+
+			for runCtx.Err() == nil {
+				pick := rand.IntN(totalWeight)
+				i := 0
+				for pick >= workloads[i].weight {
+					pick -= workloads[i].weight
+					i++
+				}
+				opStart := time.Now()
+				err := workloads[i].run(runCtx, cnxn)
+				if runCtx.Err() != nil {
+					// Cut short by the end of the run
+					return
+				}
+				outcomes[i].record(time.Since(opStart), err)
+			}
+		}()
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+	close(connectErrs)
+
+	failedConnections := 0
+	for err := range connectErrs {
+		failedConnections++
+		fmt.Fprintln(os.Stderr, "loadtest: failed to connect:", err)
+	}
+	if failedConnections == connections {
+		return errors.New("no connection could be opened")
+	}
+	report(workloads, outcomes, elapsed, connections-failedConnections)
+	return nil
+}
+
+// databaseOptions returns the driver options from the environment, proxy
+// and key=value options
+func databaseOptions(proxy string, options []string) (map[string]string, error) {
+	opts := map[string]string{}
+	for key, env := range map[string]string{
+		databricks.OptionServerHostname:    "DATABRICKS_HOST",
+		databricks.OptionHTTPPath:          "DATABRICKS_HTTPPATH",
+		databricks.OptionAccessToken:       "DATABRICKS_TOKEN",
+		databricks.OptionOAuthClientID:     "DATABRICKS_OAUTH_CLIENT_ID",
+		databricks.OptionOAuthClientSecret: "DATABRICKS_OAUTH_CLIENT_SECRET",
+		databricks.OptionCatalog:           "DATABRICKS_CATALOG",
+		databricks.OptionSchema:            "DATABRICKS_SCHEMA",
+	} {
+		if value := os.Getenv(env); value != "" {
+			opts[key] = value
+		}
+	}
+	if proxy != "" {
+		opts[databricks.OptionProxyURL] = proxy
+	}
+	for _, option := range options {
+		key, value, ok := strings.Cut(option, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid -option %q: expected key=value", option)
+		}
+		opts[key] = value
+	}
+	return opts, nil
+}
+
+// queryWorkload runs the query and reads all of its results
+func queryWorkload(query string) func(context.Context, adbc.Connection) error {
+	return func(ctx context.Context, cnxn adbc.Connection) error {
+		stmt, err := cnxn.NewStatement()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = stmt.Close() }()
+		if err := stmt.SetSqlQuery(query); err != nil {
+			return err
+		}
+		reader, _, err := stmt.ExecuteQuery(ctx)
+		if err != nil {
+			return err
+		}
+		defer reader.Release()
+		for reader.Next() {
+		}
+		return reader.Err()
+	}
+}
+
+// ingestWorkload appends rows to the table, creating it if missing
+func ingestWorkload(table string, rows int) func(context.Context, adbc.Connection) error {
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "payload", Type: arrow.BinaryTypes.String},
+		{Name: "created_at", Type: &arrow.TimestampType{Unit: arrow.Microsecond, TimeZone: "UTC"}},
+	}, nil)
+	return func(ctx context.Context, cnxn adbc.Connection) error {
+		bldr := array.NewRecordBuilder(memory.DefaultAllocator, schema)
+		defer bldr.Release()
+		now := arrow.Timestamp(time.Now().UnixMicro())
+		for i := range rows {
+			bldr.Field(0).(*array.Int64Builder).Append(rand.Int64())
+			bldr.Field(1).(*array.StringBuilder).Append(fmt.Sprintf("row %d", i))
+			bldr.Field(2).(*array.TimestampBuilder).Append(now)
+		}
+		rec := bldr.NewRecordBatch()
+		defer rec.Release()
+
+		stmt, err := cnxn.NewStatement()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = stmt.Close() }()
+		if err := stmt.SetOption(adbc.OptionKeyIngestTargetTable, table); err != nil {
+			return err
+		}
+		if err := stmt.SetOption(adbc.OptionKeyIngestMode, adbc.OptionValueIngestModeCreateAppend); err != nil {
+			return err
+		}
+		if err := stmt.Bind(ctx, rec); err != nil {
+			return err
+		}
+		_, err = stmt.ExecuteUpdate(ctx)
+		return err
+	}
+}
+
+// report prints the latency percentiles and error rate of each workload,
+// and the most frequent errors
+func report(workloads []workload, outcomes []*results, elapsed time.Duration, connections int) {
+	fmt.Printf("%d connections for %s\n\n", connections, elapsed.Round(time.Millisecond))
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "workload\tops\tops/s\terrors\terror rate\tp50\tp90\tp99\tmax\t")
+	for i, w := range workloads {
+		r := outcomes[i]
+		sorted := slices.Clone(r.latencies)
+		slices.Sort(sorted)
+		rate := 0.0
+		if len(sorted) > 0 {
+			rate = float64(r.errors) / float64(len(sorted))
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%.2f\t%d\t%.2f%%\t%s\t%s\t%s\t%s\t\n", w.name, len(sorted),
+			float64(len(sorted))/elapsed.Seconds(), r.errors, rate*100,
+			percentile(sorted, 0.5).Round(time.Millisecond), percentile(sorted, 0.9).Round(time.Millisecond),
+			percentile(sorted, 0.99).Round(time.Millisecond), percentile(sorted, 1).Round(time.Millisecond))
+	}
+	_ = tw.Flush()
+
+	for i, w := range workloads {
+		messages := outcomes[i].messages
+		if len(messages) == 0 {
+			continue
+		}
+		keys := slices.SortedFunc(maps.Keys(messages), func(a, b string) int { return messages[b] - messages[a] })
+		fmt.Printf("\nerrors of %s:\n", w.name)
+		for _, msg := range keys[:min(len(keys), 5)] {
+			fmt.Printf("  %6d  %s\n", messages[msg], msg)
+		}
+	}
+}
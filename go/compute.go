@@ -0,0 +1,85 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"cmp"
+	"regexp"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+)
+
+// clusterHTTPPathRe extracts the workspace and cluster IDs from the HTTP
+// path of an all-purpose cluster
+var clusterHTTPPathRe = regexp.MustCompile(`^/?sql/protocolv1/o/([^/?]+)/([^/?]+)`)
+
+// computeInfo is the kind of compute that an HTTP path connects to, from
+// the form of the path
+type computeInfo struct {
+	// InfoValueComputeWarehouse, InfoValueComputeCluster or
+	// InfoValueComputeUnknown
+	kind string
+	// ID of the warehouse or cluster; empty if unknown
+	id string
+}
+
+// httpPathCompute returns the compute that path connects to
+func httpPathCompute(path string) computeInfo {
+	if match := warehouseHTTPPathRe.FindStringSubmatch(path); match != nil {
+		return computeInfo{kind: InfoValueComputeWarehouse, id: match[1]}
+	}
+	if match := clusterHTTPPathRe.FindStringSubmatch(path); match != nil {
+		return computeInfo{kind: InfoValueComputeCluster, id: match[2]}
+	}
+	return computeInfo{kind: InfoValueComputeUnknown}
+}
+
+// warehouseRequired returns the error for a feature only SQL warehouses
+// have, used on a connection without the warehouse's REST client
+func (c *connectionImpl) warehouseRequired(feature string) error {
+	if c.compute.kind == InfoValueComputeCluster {
+		return c.ErrorHelper.Errorf(adbc.StatusNotImplemented,
+			"%s requires the HTTP path of a SQL warehouse, and is not supported on all-purpose clusters", feature)
+	}
+	return c.ErrorHelper.Errorf(adbc.StatusNotImplemented,
+		"%s requires the HTTP path of a SQL warehouse, and is not supported with %s", feature, adbc.OptionKeyURI)
+}
+
+// registerComputeInfo sets the driver-specific info codes describing the
+// connection's compute and the features it supports
+func (c *connectionImpl) registerComputeInfo() error {
+	var id any
+	if c.compute.id != "" {
+		id = c.compute.id
+	}
+	var transactions any
+	if c.transactions != nil {
+		transactions = *c.transactions
+	}
+	for code, value := range map[adbc.InfoCode]any{
+		InfoComputeType:          c.compute.kind,
+		InfoComputeID:            id,
+		InfoSupportsSubmitAsync:  c.warehouse != nil,
+		InfoSupportsUsageMetrics: c.usage != nil,
+		InfoSupportsWaitForStart: c.compute.kind == InfoValueComputeWarehouse && c.warehouse != nil,
+		InfoSupportsTransactions: transactions,
+		InfoWarehouseType:        cmp.Or(c.warehouseType, OptionValueWarehouseTypeUnknown),
+	} {
+		if err := c.DriverInfo.RegisterInfoCode(code, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
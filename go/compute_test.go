@@ -0,0 +1,84 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/adbc-drivers/driverbase-go/driverbase"
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPPathCompute(t *testing.T) {
+	for path, want := range map[string]computeInfo{
+		"/sql/1.0/warehouses/abc123":                  {kind: InfoValueComputeWarehouse, id: "abc123"},
+		"sql/1.0/endpoints/abc123":                    {kind: InfoValueComputeWarehouse, id: "abc123"},
+		"/sql/protocolv1/o/1234567890/0123-456789-ab": {kind: InfoValueComputeCluster, id: "0123-456789-ab"},
+		"sql/protocolv1/o/0/0123-456789-ab":           {kind: InfoValueComputeCluster, id: "0123-456789-ab"},
+		"/sql/protocolv1/o/1234567890":                {kind: InfoValueComputeUnknown},
+		"/gateway/sql":                                {kind: InfoValueComputeUnknown},
+	} {
+		assert.Equal(t, want, httpPathCompute(path), path)
+	}
+}
+
+func TestClusterComputeInfo(t *testing.T) {
+	drv := &fakeDriver{keys: []string{`{"dbr_version":"15.4.x-scala2.12"}`}}
+	c := newTransactionConnection(t, drv, executionError{msg: "[PARSE_SYNTAX_ERROR] Syntax error at or near 'TRANSACTION'"})
+	c.ConnectionImplBase = driverbase.ConnectionImplBase{DriverInfo: driverbase.DefaultDriverInfo("Databricks")}
+	c.compute = httpPathCompute("/sql/protocolv1/o/1234567890/0123-456789-ab")
+	ctx := context.Background()
+
+	info := func() map[adbc.InfoCode]any {
+		require.NoError(t, c.PrepareDriverInfo(ctx, nil))
+		values := map[adbc.InfoCode]any{}
+		for _, code := range []adbc.InfoCode{InfoComputeType, InfoComputeID, InfoSupportsSubmitAsync,
+			InfoSupportsUsageMetrics, InfoSupportsWaitForStart, InfoSupportsTransactions, InfoWarehouseType, adbc.InfoVendorVersion} {
+			values[code], _ = c.DriverInfo.GetInfoForInfoCode(code)
+		}
+		return values
+	}
+	assert.Equal(t, map[adbc.InfoCode]any{
+		InfoComputeType:          InfoValueComputeCluster,
+		InfoComputeID:            "0123-456789-ab",
+		InfoSupportsSubmitAsync:  false,
+		InfoSupportsUsageMetrics: false,
+		InfoSupportsWaitForStart: false,
+		InfoSupportsTransactions: nil,
+		InfoWarehouseType:        OptionValueWarehouseTypeUnknown,
+		adbc.InfoVendorVersion:   "15.4.x-scala2.12",
+	}, info())
+
+	// Once the session rejects a transaction, changes are staged without
+	// trying again
+	require.NoError(t, c.SetAutocommit(false))
+	require.NoError(t, c.SetAutocommit(true))
+	require.NoError(t, c.SetAutocommit(false))
+	assert.Equal(t, transactionStaged, c.txMode)
+	assert.Equal(t, []string{"SELECT current_version()", "BEGIN TRANSACTION"}, drv.execs)
+	drv.keys = []string{`{"dbr_version":"15.4.x-scala2.12"}`}
+	assert.Equal(t, false, info()[InfoSupportsTransactions])
+
+	// Warehouse-only features name the cluster
+	s := &statementImpl{conn: c, query: "SELECT 1"}
+	require.NoError(t, c.SetAutocommit(true))
+	_, err := s.submitUpdate(ctx)
+	assert.ErrorContains(t, err, OptionStatementSubmitAsync+" requires the HTTP path of a SQL warehouse, and is not supported on all-purpose clusters")
+	err = c.refreshUsage(ctx)
+	assert.ErrorContains(t, err, "usage requires the HTTP path of a SQL warehouse, and is not supported on all-purpose clusters")
+}
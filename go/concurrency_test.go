@@ -0,0 +1,153 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"testing"
+
+	"github.com/adbc-drivers/driverbase-go/driverbase"
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newBlockingConnection returns a connection whose statements signal
+// started once running, then wait until release is closed, so tests can
+// act while a statement is executing
+func newBlockingConnection(t *testing.T) (c *connectionImpl, started, release chan struct{}) {
+	started, release = make(chan struct{}), make(chan struct{})
+	db := sql.OpenDB(&fakeDriver{before: blockUntil(started, release)})
+	t.Cleanup(func() { _ = db.Close() })
+
+	conn, err := db.Conn(context.Background())
+	require.NoError(t, err)
+	return &connectionImpl{
+		ConnectionImplBase: driverbase.ConnectionImplBase{
+			ErrorHelper: driverbase.ErrorHelper{DriverName: "databricks"},
+		},
+		conn: conn,
+	}, started, release
+}
+
+func requireInvalidState(t *testing.T, err error) {
+	t.Helper()
+	var adbcErr adbc.Error
+	require.ErrorAs(t, err, &adbcErr)
+	assert.Equal(t, adbc.StatusInvalidState, adbcErr.Code)
+}
+
+func TestStatementRejectsCallsDuringExecution(t *testing.T) {
+	conn, started, release := newBlockingConnection(t)
+	stmt, err := conn.NewStatement()
+	require.NoError(t, err)
+	require.NoError(t, stmt.SetSqlQuery("DELETE FROM t"))
+
+	done := make(chan error)
+	go func() {
+		_, err := stmt.ExecuteUpdate(context.Background())
+		done <- err
+	}()
+	<-started
+
+	requireInvalidState(t, stmt.SetSqlQuery("SELECT 1"))
+	requireInvalidState(t, stmt.(adbc.GetSetOptions).SetOption(OptionStatementIngestNullAsDefault, adbc.OptionValueEnabled))
+	requireInvalidState(t, stmt.Close())
+	// The connection cannot be closed or reconfigured under the statement
+	requireInvalidState(t, conn.Close())
+	requireInvalidState(t, conn.SetCurrentCatalog("main"))
+
+	close(release)
+	require.NoError(t, <-done)
+
+	require.NoError(t, stmt.Close())
+	requireInvalidState(t, stmt.SetSqlQuery("SELECT 1"))
+	require.NoError(t, conn.Close())
+
+	_, err = conn.NewStatement()
+	requireInvalidState(t, err)
+}
+
+func TestStatementConcurrentCalls(t *testing.T) {
+	conn, started, release := newBlockingConnection(t)
+	close(release)
+	go func() {
+		for range started {
+		}
+	}()
+	defer close(started)
+
+	stmt, err := conn.NewStatement()
+	require.NoError(t, err)
+	defer func() { require.NoError(t, stmt.Close()) }()
+	opts := stmt.(adbc.GetSetOptions)
+
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 50 {
+				var err error
+				switch i % 4 {
+				case 0:
+					err = stmt.SetSqlQuery("UPDATE t SET x = 1")
+				case 1:
+					_, err = opts.GetOption(OptionStatementIngestNullAsDefault)
+				case 2:
+					err = opts.SetOption(OptionStatementDeleteBatchSize, "10")
+				case 3:
+					_, err = stmt.ExecuteUpdate(context.Background())
+				}
+				if err != nil {
+					var adbcErr adbc.Error
+					if assert.ErrorAs(t, err, &adbcErr) {
+						assert.Equal(t, adbc.StatusInvalidState, adbcErr.Code, adbcErr.Msg)
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestLazyConnect(t *testing.T) {
+	db := sql.OpenDB(&fakeDriver{})
+	defer func() { _ = db.Close() }()
+
+	c := &connectionImpl{
+		ConnectionImplBase: driverbase.ConnectionImplBase{ErrorHelper: driverbase.ErrorHelper{DriverName: "databricks"}},
+		pool:               db,
+	}
+	stmt, err := c.NewStatement()
+	require.NoError(t, err)
+	require.NoError(t, stmt.Close())
+	assert.Equal(t, 0, db.Stats().OpenConnections, "creating a statement should not start a session")
+
+	_, err = c.GetCatalogs(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, db.Stats().InUse)
+
+	require.NoError(t, c.Close())
+	assert.Equal(t, 0, db.Stats().InUse)
+	requireInvalidState(t, c.Close())
+
+	// A connection that was never used closes without a session
+	unused := &connectionImpl{pool: db}
+	require.NoError(t, unused.Close())
+	requireInvalidState(t, unused.acquire())
+}
@@ -0,0 +1,50 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"reflect"
+	"time"
+
+	dbsql "github.com/databricks/databricks-sql-go"
+)
+
+// withPollInterval sets how often databricks-sql-go polls the status of a
+// running operation. The setting lives on the driver's internal config and
+// has no exported ConnOption, so the option is assembled with reflection;
+// SetOption refuses OptionQueryPollInterval if pollIntervalSupported
+// finds no such field.
+func withPollInterval(interval time.Duration) dbsql.ConnOption {
+	optType := reflect.TypeOf(dbsql.ConnOption(nil))
+	fn := reflect.MakeFunc(optType, func(args []reflect.Value) []reflect.Value {
+		if args[0].IsNil() {
+			return nil
+		}
+		args[0].Elem().FieldByName(pollIntervalFieldName).Set(reflect.ValueOf(interval))
+		return nil
+	})
+	return fn.Interface().(dbsql.ConnOption)
+}
+
+// Field of databricks-sql-go's config holding the poll interval
+const pollIntervalFieldName = "PollInterval"
+
+// pollIntervalSupported returns whether databricks-sql-go's config has
+// the field withPollInterval sets
+func pollIntervalSupported() bool {
+	cfgType := reflect.TypeOf(dbsql.ConnOption(nil)).In(0).Elem()
+	field, ok := cfgType.FieldByName(pollIntervalFieldName)
+	return ok && field.IsExported() && field.Type == reflect.TypeOf(time.Duration(0))
+}
@@ -0,0 +1,142 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	dbsql "github.com/databricks/databricks-sql-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithPollInterval(t *testing.T) {
+	require.True(t, pollIntervalSupported())
+
+	// Apply the option to a fresh instance of the driver's config type
+	cfgType := reflect.TypeOf(dbsql.ConnOption(nil)).In(0).Elem()
+	cfg := reflect.New(cfgType)
+
+	reflect.ValueOf(withPollInterval(250 * time.Millisecond)).Call([]reflect.Value{cfg})
+
+	field := cfg.Elem().FieldByName("PollInterval")
+	require.True(t, field.IsValid())
+	assert.Equal(t, 250*time.Millisecond, time.Duration(field.Int()))
+}
+
+// usesCloudFetch returns whether connections of d download results with
+// CloudFetch, which databricks-sql-go does by default
+func usesCloudFetch(t *testing.T, d *databaseImpl) bool {
+	opts, err := d.resolveConnectionOptions()
+	require.NoError(t, err)
+	cfgType := reflect.TypeOf(dbsql.ConnOption(nil)).In(0).Elem()
+	cfg := reflect.New(cfgType)
+	for _, opt := range append([]dbsql.ConnOption{dbsql.WithCloudFetch(true)}, opts...) {
+		reflect.ValueOf(opt).Call([]reflect.Value{cfg})
+	}
+	return cfg.Elem().FieldByName("UseCloudFetch").Bool()
+}
+
+func TestSessionSettings(t *testing.T) {
+	d := &databaseImpl{
+		serverHostname: "example.cloud.databricks.com",
+		httpPath:       "/sql/1.0/warehouses/abc",
+		accessToken:    "dapi-token",
+	}
+	require.NoError(t, d.SetOption(OptionSessionANSIMode, "TRUE"))
+	require.NoError(t, d.SetOption(OptionSessionDefaultCollation, " unicode_ci "))
+	val, err := d.GetOption(OptionSessionDefaultCollation)
+	require.NoError(t, err)
+	assert.Equal(t, "UNICODE_CI", val)
+
+	sessionParams := func() any {
+		opts, err := d.resolveConnectionOptions()
+		require.NoError(t, err)
+		cfgType := reflect.TypeOf(dbsql.ConnOption(nil)).In(0).Elem()
+		cfg := reflect.New(cfgType)
+		for _, opt := range opts {
+			reflect.ValueOf(opt).Call([]reflect.Value{cfg})
+		}
+		return cfg.Elem().FieldByName("SessionParams").Interface()
+	}
+	assert.Equal(t, map[string]string{
+		"ANSI_MODE":                           "true",
+		"spark.sql.session.collation.default": "UNICODE_CI",
+	}, sessionParams())
+
+	// Clusters take the Spark configuration of ANSI mode
+	require.NoError(t, d.SetOption(OptionHTTPPath, "/sql/protocolv1/o/123/0123-456789-abcdef"))
+	require.NoError(t, d.SetOption(OptionSessionDefaultCollation, ""))
+	assert.Equal(t, map[string]string{"spark.sql.ansi.enabled": "true"}, sessionParams())
+
+	var adbcErr adbc.Error
+	require.ErrorAs(t, d.SetOption(OptionSessionANSIMode, "strict"), &adbcErr)
+	assert.Equal(t, adbc.StatusInvalidArgument, adbcErr.Code)
+	require.ErrorAs(t, d.SetOption(OptionSessionDefaultCollation, "UNICODE; DROP"), &adbcErr)
+	assert.Equal(t, adbc.StatusInvalidArgument, adbcErr.Code)
+
+	// A URI would leave the workspace's defaults
+	d.uri = "token:dapi-token@example.cloud.databricks.com:443/sql/1.0/warehouses/abc"
+	_, err = d.initializeConnectionPool(context.Background())
+	require.ErrorAs(t, err, &adbcErr)
+	assert.Contains(t, adbcErr.Msg, OptionSessionANSIMode)
+}
+
+func TestImpersonateUser(t *testing.T) {
+	d := &databaseImpl{
+		serverHostname: "example.cloud.databricks.com",
+		httpPath:       "/sql/1.0/warehouses/abc",
+		accessToken:    "dapi-token",
+	}
+	require.NoError(t, d.SetOption(OptionImpersonateUser, " alice@example.com "))
+	require.NoError(t, d.SetOption(OptionQueryTags, "team:data"))
+	val, err := d.GetOption(OptionImpersonateUser)
+	require.NoError(t, err)
+	assert.Equal(t, "alice@example.com", val)
+
+	// The user is session configuration, alongside the other parameters
+	opts, err := d.resolveConnectionOptions()
+	require.NoError(t, err)
+	cfgType := reflect.TypeOf(dbsql.ConnOption(nil)).In(0).Elem()
+	cfg := reflect.New(cfgType)
+	for _, opt := range opts {
+		reflect.ValueOf(opt).Call([]reflect.Value{cfg})
+	}
+	assert.Equal(t, map[string]string{
+		"hive.server2.proxy.user": "alice@example.com",
+		"QUERY_TAGS":              "team:data",
+	}, cfg.Elem().FieldByName("SessionParams").Interface())
+
+	// Connecting with a URI would run statements as the principal
+	d.uri = "token:dapi-token@example.cloud.databricks.com:443/sql/1.0/warehouses/abc"
+	_, err = d.initializeConnectionPool(context.Background())
+	var adbcErr adbc.Error
+	require.ErrorAs(t, err, &adbcErr)
+	assert.Equal(t, adbc.StatusInvalidArgument, adbcErr.Code)
+
+	// As would the Statement Execution API
+	pool := sql.OpenDB(&fakeDriver{})
+	defer func() { _ = pool.Close() }()
+	s := &statementImpl{conn: &connectionImpl{pool: pool, impersonateUser: "alice@example.com"}}
+	require.NoError(t, s.SetOption(OptionStatementSubmitAsync, adbc.OptionValueEnabled))
+	require.NoError(t, s.SetSqlQuery("INSERT INTO events VALUES (1)"))
+	_, err = s.ExecuteUpdate(context.Background())
+	assert.ErrorContains(t, err, "while impersonating a user")
+}
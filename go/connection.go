@@ -23,15 +23,22 @@
 package databricks
 
 import (
+	"cmp"
 	"context"
 	"database/sql"
+	"database/sql/driver"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"regexp"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/adbc-drivers/driverbase-go/driverbase"
 	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-go/v18/arrow"
 	_ "github.com/databricks/databricks-sql-go"
 	dbsqlerr "github.com/databricks/databricks-sql-go/errors"
 )
@@ -39,91 +46,333 @@ import (
 type connectionImpl struct {
 	driverbase.ConnectionImplBase
 
-	// Connection settings
-	catalog  string
-	dbSchema string
+	// The current catalog and schema, filled in from the session by
+	// resolveNamespace where not set by options, and the ones the session
+	// started with
+	catalog           string
+	dbSchema          string
+	defaultCatalog    string
+	defaultDbSchema   string
+	namespaceResolved bool
+	// A statement may have changed the current catalog or schema
+	namespaceStale bool
+	namespaceMu    sync.Mutex
+	// Options that changed the settings of the session, and whether a
+	// SET statement did, see sessionState
+	sessionSettings []string
+	sessionChanged  atomic.Bool
+
+	// Statements running at least this long are logged; 0 disables
+	slowQueryThreshold time.Duration
+	// Hash rather than redact the literals of logged statements
+	queryLogHashParams bool
+	// Limits retries across statements; nil if unlimited
+	retryBudget *retryBudget
+	// Shared with the database's other connections; nil if disabled
+	metadataCache    *metadataCache
+	metadataThrottle *metadataThrottle
+	// Fail bulk operations on lossy conversions
+	strictConversions bool
+	// Match GetObjects filters case-sensitively
+	exactFilters bool
+	// Default OptionQueryTimeout of the connection's statements
+	queryTimeout time.Duration
+	// Default OptionFetchSize of the connection's statements, and that of
+	// the partitions it reads
+	fetchSize int64
+	// Add the provenance of results to their schema metadata
+	resultProvenance bool
+	// Limits the memory of result readers together; nil if unlimited
+	memoryBudget *memoryBudget
+	// Compute that the HTTP path connects to, and the warehouse's type
+	compute       computeInfo
+	warehouseType string
+	// Attribute the statements of the session
+	queryTags []queryTag
+	// User the session's statements run as, see OptionImpersonateUser
+	impersonateUser string
+	// Longest SQL text sent; 0 if unchecked
+	maxSQLLength int
+	// REST APIs of the warehouse; nil if the HTTP path is not a warehouse's
+	warehouse *warehouseClient
+	// Metrics of the statements run; nil without warehouse
+	usage *connectionUsage
+	// How changes are made transactional while autocommit is disabled,
+	// and the changes staged for commit
+	txMode   transactionMode
+	staged   []stagedChange
+	stagedMu sync.Mutex
+	// Whether the session supports transactions; nil until autocommit is
+	// first disabled
+	transactions *bool
+
+	// Pings the session while idle; nil if disabled
+	keepAlive *keepAlive
+	// Closes the session while idle; nil if disabled
+	idleClose *idleClose
+	// Result readers not yet released, see sessionReader
+	openReaders atomic.Int64
+	// Operations in progress, counted under connectMu so that an idle
+	// session is only closed while there are none
+	active atomic.Int64
+	// When an operation last started or ended, in Unix nanoseconds
+	lastUsed atomic.Int64
 
 	// Database connection
 	conn *sql.Conn
+	// Pool that conn is taken from on first use when connecting lazily;
+	// nil once connected. The connection is closed when both are nil.
+	pool *sql.DB
+	// Serializes taking conn from pool
+	connectMu sync.Mutex
+
+	// Held shared by every operation using conn, and exclusively by Close
+	// and namespace changes. Locks are only ever tried, so conflicting
+	// concurrent calls fail with StatusInvalidState instead of blocking.
+	mu sync.RWMutex
+}
+
+// acquire marks the start of an operation using the connection, failing
+// if it is closed or being closed or reconfigured concurrently. A lazily
+// connected connection establishes its session first.
+func (c *connectionImpl) acquire() error {
+	if err := c.acquireOpen(); err != nil {
+		return err
+	}
+	if err := c.connect(); err != nil {
+		c.release()
+		return err
+	}
+	return nil
+}
+
+// acquireOpen is like acquire, but does not establish the session
+func (c *connectionImpl) acquireOpen() error {
+	if !c.mu.TryRLock() {
+		return adbc.Error{
+			Code: adbc.StatusInvalidState,
+			Msg:  "connection is being closed or reconfigured by another call",
+		}
+	}
+	if !c.begin() {
+		c.mu.RUnlock()
+		return adbc.Error{
+			Code: adbc.StatusInvalidState,
+			Msg:  "connection is closed",
+		}
+	}
+	c.lastUsed.Store(time.Now().UnixNano())
+	return nil
+}
+
+func (c *connectionImpl) release() {
+	c.lastUsed.Store(time.Now().UnixNano())
+	c.active.Add(-1)
+	c.mu.RUnlock()
+}
+
+// acquireExclusive is like acquire, but also fails while any other
+// operation is in progress.
+func (c *connectionImpl) acquireExclusive() error {
+	if !c.mu.TryLock() {
+		return adbc.Error{
+			Code: adbc.StatusInvalidState,
+			Msg:  "connection is in use by another call",
+		}
+	}
+	if !c.begin() {
+		c.mu.Unlock()
+		return adbc.Error{
+			Code: adbc.StatusInvalidState,
+			Msg:  "connection is closed",
+		}
+	}
+	if err := c.connect(); err != nil {
+		c.releaseExclusive()
+		return err
+	}
+	c.lastUsed.Store(time.Now().UnixNano())
+	return nil
+}
+
+func (c *connectionImpl) releaseExclusive() {
+	c.lastUsed.Store(time.Now().UnixNano())
+	c.active.Add(-1)
+	c.mu.Unlock()
+}
+
+// begin counts an operation in progress, unless the connection is closed
+func (c *connectionImpl) begin() bool {
+	c.connectMu.Lock()
+	defer c.connectMu.Unlock()
+	if c.conn == nil && c.pool == nil {
+		return false
+	}
+	c.active.Add(1)
+	return true
+}
+
+func (c *connectionImpl) closed() bool {
+	c.connectMu.Lock()
+	defer c.connectMu.Unlock()
+	return c.conn == nil && c.pool == nil
+}
+
+// connect takes the session of a lazily connected connection from the
+// pool, if it has not already
+func (c *connectionImpl) connect() error {
+	c.connectMu.Lock()
+	defer c.connectMu.Unlock()
+	if c.conn != nil || c.pool == nil {
+		return nil
+	}
+
+	conn, err := c.pool.Conn(context.Background())
+	if err != nil {
+		return adbc.Error{
+			Code: adbc.StatusIO,
+			Msg:  fmt.Sprintf("failed to connect: %v", err),
+		}
+	}
+	c.conn = conn
+	c.pool = nil
+	return nil
 }
 
 func (c *connectionImpl) Close() error {
+	if !c.mu.TryLock() {
+		return adbc.Error{
+			Code: adbc.StatusInvalidState,
+			Msg:  "connection is in use by another call",
+		}
+	}
+	defer c.mu.Unlock()
+	if c.closed() {
+		return adbc.Error{
+			Code: adbc.StatusInvalidState,
+			Msg:  "connection is closed",
+		}
+	}
+
+	c.keepAlive.stop()
+	c.keepAlive = nil
+	c.idleClose.stop()
+	c.idleClose = nil
+
+	// Changes not committed are discarded, as the warehouse does with
+	// those of a transaction when its session ends
+	var stagedErr error
+	if c.conn != nil {
+		stagedErr = c.dropStaged(context.Background())
+	}
+
+	// A lazy connection that was never used holds no session
+	c.pool = nil
 	if c.conn == nil {
-		return adbc.Error{Code: adbc.StatusInvalidState}
+		return nil
 	}
 	defer func() {
 		c.conn = nil
 	}()
-	return c.conn.Close()
+	return errors.Join(stagedErr, c.conn.Close())
 }
 
 func (c *connectionImpl) NewStatement() (adbc.Statement, error) {
+	if err := c.acquireOpen(); err != nil {
+		return nil, err
+	}
+	defer c.release()
+
 	return &statementImpl{
 		StatementImplBase: driverbase.NewStatementImplBase(&c.ConnectionImplBase, c.ErrorHelper),
 		conn:              c,
 		bulkIngestOptions: driverbase.NewBulkIngestOptions(),
+		deleteOptions:     newDeleteByKeysOptions(),
+		queryTimeout:      c.queryTimeout,
+		fetchSize:         c.fetchSize,
 	}, nil
 }
 
-func (c *connectionImpl) SetAutocommit(autocommit bool) error {
-	// Databricks SQL doesn't support explicit transaction control in the same way
-	// as traditional databases. Most operations are implicitly committed.
-	if !autocommit {
-		return adbc.Error{
-			Code: adbc.StatusNotImplemented,
-			Msg:  "disabling autocommit is not supported",
+// resolveNamespace fills in the current catalog and schema not set by
+// options from the session, which starts in the warehouse's defaults, and
+// records them as the session defaults. The caller must hold the
+// connection.
+func (c *connectionImpl) resolveNamespace(ctx context.Context) (catalog, dbSchema string, err error) {
+	c.namespaceMu.Lock()
+	defer c.namespaceMu.Unlock()
+
+	if !c.namespaceResolved || c.namespaceStale {
+		if c.namespaceStale || c.catalog == "" || c.dbSchema == "" {
+			var sessionCatalog, sessionSchema string
+			err := c.conn.QueryRowContext(ctx, "SELECT current_catalog(), current_schema()").Scan(&sessionCatalog, &sessionSchema)
+			if err != nil {
+				return "", "", adbc.Error{
+					Code: adbc.StatusInternal,
+					Msg:  fmt.Sprintf("failed to get current catalog and schema: %v", err),
+				}
+			}
+			if c.namespaceStale || c.catalog == "" {
+				c.catalog = sessionCatalog
+			}
+			if c.namespaceStale || c.dbSchema == "" {
+				c.dbSchema = sessionSchema
+			}
 		}
-	}
-	return nil
-}
-
-// CurrentNamespacer interface implementation
-func (c *connectionImpl) GetCurrentCatalog() (string, error) {
-	if c.catalog != "" {
-		return c.catalog, nil
-	}
-
-	if c.conn == nil {
-		return "", adbc.Error{
-			Code: adbc.StatusInvalidState,
-			Msg:  "connection is nil",
+		if !c.namespaceResolved {
+			c.defaultCatalog = c.catalog
+			c.defaultDbSchema = c.dbSchema
+			c.namespaceResolved = true
 		}
+		c.namespaceStale = false
 	}
+	return c.catalog, c.dbSchema, nil
+}
 
-	var catalog string
-	err := c.conn.QueryRowContext(context.Background(), "SELECT current_catalog()").Scan(&catalog)
-	if err != nil {
-		return "", adbc.Error{
-			Code: adbc.StatusInternal,
-			Msg:  fmt.Sprintf("failed to get current catalog: %v", err),
+// namespaceStatementRe matches statements that change the current catalog
+// or schema
+var namespaceStatementRe = regexp.MustCompile(`(?i)^\s*(USE|SET\s+(CATALOG|SCHEMA|DATABASE))\s`)
+
+// beforeStatement prepares for running query on the connection, returning
+// whether it may change the current catalog or schema. The session
+// defaults are recorded before they can be left.
+func (c *connectionImpl) beforeStatement(ctx context.Context, query string) (bool, error) {
+	if !namespaceStatementRe.MatchString(query) {
+		if sessionSettingStatementRe.MatchString(query) {
+			c.sessionChanged.Store(true)
 		}
+		return false, nil
 	}
+	_, _, err := c.resolveNamespace(ctx)
+	return true, err
+}
 
-	return catalog, nil
+// afterNamespaceChange makes the next lookup of the current catalog or
+// schema ask the session, after a statement that may have changed them
+func (c *connectionImpl) afterNamespaceChange() {
+	c.namespaceMu.Lock()
+	defer c.namespaceMu.Unlock()
+	c.namespaceStale = true
 }
 
-func (c *connectionImpl) GetCurrentDbSchema() (string, error) {
-	if c.dbSchema != "" {
-		return c.dbSchema, nil
+// CurrentNamespacer interface implementation
+func (c *connectionImpl) GetCurrentCatalog() (string, error) {
+	if err := c.acquire(); err != nil {
+		return "", err
 	}
+	defer c.release()
 
-	if c.conn == nil {
-		return "", adbc.Error{
-			Code: adbc.StatusInvalidState,
-			Msg:  "connection is nil",
-		}
-	}
+	catalog, _, err := c.resolveNamespace(context.Background())
+	return catalog, err
+}
 
-	var schema string
-	err := c.conn.QueryRowContext(context.Background(), "SELECT current_schema()").Scan(&schema)
-	if err != nil {
-		return "", adbc.Error{
-			Code: adbc.StatusInternal,
-			Msg:  fmt.Sprintf("failed to get current schema: %v", err),
-		}
+func (c *connectionImpl) GetCurrentDbSchema() (string, error) {
+	if err := c.acquire(); err != nil {
+		return "", err
 	}
+	defer c.release()
 
-	return schema, nil
+	_, dbSchema, err := c.resolveNamespace(context.Background())
+	return dbSchema, err
 }
 
 func (c *connectionImpl) SetCurrentCatalog(catalog string) error {
@@ -133,14 +382,17 @@ func (c *connectionImpl) SetCurrentCatalog(catalog string) error {
 			Msg:  "catalog cannot be empty",
 		}
 	}
-	if c.conn == nil {
-		return adbc.Error{
-			Code: adbc.StatusInvalidState,
-			Msg:  "failed to set catalog: connection is nil",
-		}
+	if err := c.acquireExclusive(); err != nil {
+		return err
+	}
+	defer c.releaseExclusive()
+
+	// Record the session defaults before leaving them
+	if _, _, err := c.resolveNamespace(context.Background()); err != nil {
+		return err
 	}
-	escapedCatalog := strings.ReplaceAll(catalog, "`", "``")
-	_, err := c.conn.ExecContext(context.Background(), fmt.Sprintf("USE CATALOG `%s`", escapedCatalog))
+
+	_, err := c.conn.ExecContext(context.Background(), "USE CATALOG "+quoteIdentifier(catalog))
 	if err != nil {
 		return adbc.Error{
 			Code: adbc.StatusInternal,
@@ -158,14 +410,16 @@ func (c *connectionImpl) SetCurrentDbSchema(schema string) error {
 			Msg:  "schema cannot be empty",
 		}
 	}
-	if c.conn == nil {
-		return adbc.Error{
-			Code: adbc.StatusInvalidState,
-			Msg:  "failed to set db schema: connection is nil",
-		}
+	if err := c.acquireExclusive(); err != nil {
+		return err
 	}
-	escapedSchema := strings.ReplaceAll(schema, "`", "``")
-	_, err := c.conn.ExecContext(context.Background(), fmt.Sprintf("USE SCHEMA `%s`", escapedSchema))
+	defer c.releaseExclusive()
+
+	if _, _, err := c.resolveNamespace(context.Background()); err != nil {
+		return err
+	}
+
+	_, err := c.conn.ExecContext(context.Background(), "USE SCHEMA "+quoteIdentifier(schema))
 	if err != nil {
 		return adbc.Error{
 			Code: adbc.StatusInternal,
@@ -182,31 +436,24 @@ func (c *connectionImpl) ListTableTypes(ctx context.Context) ([]string, error) {
 	return []string{"TABLE", "VIEW", "EXTERNAL_TABLE", "MANAGED_TABLE", "STREAMING_TABLE", "MATERIALIZED_VIEW"}, nil
 }
 
-// Transaction methods (Databricks has limited transaction support)
-func (c *connectionImpl) Commit(ctx context.Context) error {
-	// Most operations are auto-committed.
-	return adbc.Error{
-		Code: adbc.StatusNotImplemented,
-		Msg:  "Commit is not supported",
+// DbObjectsEnumerator interface implementation
+func (c *connectionImpl) GetCatalogs(ctx context.Context, catalogFilter *string) ([]string, error) {
+	if err := c.acquire(); err != nil {
+		return nil, err
 	}
-}
+	defer c.release()
 
-func (c *connectionImpl) Rollback(ctx context.Context) error {
-	// Databricks SQL doesn't support explicit transactions in the traditional sense.
-	// Most operations are auto-committed.
-	return adbc.Error{
-		Code: adbc.StatusNotImplemented,
-		Msg:  "rollback is not supported",
-	}
+	key := metadataCacheKey("catalogs", catalogFilter)
+	return loadMetadata(ctx, c, key, func(ctx context.Context) ([]string, error) {
+		return c.getCatalogs(ctx, catalogFilter)
+	})
 }
 
-// DbObjectsEnumerator interface implementation
-func (c *connectionImpl) GetCatalogs(ctx context.Context, catalogFilter *string) (catalogs []string, err error) {
+func (c *connectionImpl) getCatalogs(ctx context.Context, catalogFilter *string) (catalogs []string, err error) {
 	catalogs = []string{}
 	query := "SHOW CATALOGS"
 	if catalogFilter != nil {
-		escapedFilter := strings.ReplaceAll(*catalogFilter, "'", "''")
-		query += fmt.Sprintf(" LIKE '%s'", escapedFilter)
+		query += " LIKE " + quoteString(normalizeIdentifier(*catalogFilter))
 	}
 	var rows *sql.Rows
 	rows, err = c.conn.QueryContext(ctx, query)
@@ -228,19 +475,32 @@ func (c *connectionImpl) GetCatalogs(ctx context.Context, catalogFilter *string)
 				Msg:  fmt.Sprintf("failed to scan catalog: %v", err),
 			}
 		}
+		if c.filterExcludes(catalogFilter, catalog) {
+			continue
+		}
 		catalogs = append(catalogs, catalog)
 	}
 
 	return catalogs, errors.Join(err, rows.Err())
 }
 
-func (c *connectionImpl) GetDBSchemasForCatalog(ctx context.Context, catalog string, schemaFilter *string) (schemas []string, err error) {
+func (c *connectionImpl) GetDBSchemasForCatalog(ctx context.Context, catalog string, schemaFilter *string) ([]string, error) {
+	if err := c.acquire(); err != nil {
+		return nil, err
+	}
+	defer c.release()
+
+	key := metadataCacheKey("db_schemas", &catalog, schemaFilter)
+	return loadMetadata(ctx, c, key, func(ctx context.Context) ([]string, error) {
+		return c.getDBSchemasForCatalog(ctx, catalog, schemaFilter)
+	})
+}
+
+func (c *connectionImpl) getDBSchemasForCatalog(ctx context.Context, catalog string, schemaFilter *string) (schemas []string, err error) {
 	schemas = []string{}
-	escapedCatalog := strings.ReplaceAll(catalog, "`", "``")
-	query := fmt.Sprintf("SHOW SCHEMAS IN `%s`", escapedCatalog)
+	query := "SHOW SCHEMAS IN " + quoteIdentifier(catalog)
 	if schemaFilter != nil {
-		escapedFilter := strings.ReplaceAll(*schemaFilter, "'", "''")
-		query += fmt.Sprintf(" LIKE '%s'", escapedFilter)
+		query += " LIKE " + quoteString(normalizeIdentifier(*schemaFilter))
 	}
 
 	var rows *sql.Rows
@@ -262,6 +522,9 @@ func (c *connectionImpl) GetDBSchemasForCatalog(ctx context.Context, catalog str
 				Msg:  fmt.Sprintf("failed to scan schema: %v", err),
 			}
 		}
+		if c.filterExcludes(schemaFilter, schema) {
+			continue
+		}
 		schemas = append(schemas, schema)
 	}
 
@@ -269,18 +532,29 @@ func (c *connectionImpl) GetDBSchemasForCatalog(ctx context.Context, catalog str
 	return schemas, err
 }
 
-func (c *connectionImpl) GetTablesForDBSchema(ctx context.Context, catalog string, schema string, tableFilter *string, columnFilter *string, includeColumns bool) (tables []driverbase.TableInfo, err error) {
-	if includeColumns {
-		return c.getTablesWithColumns(ctx, catalog, schema, tableFilter, columnFilter)
+func (c *connectionImpl) GetTablesForDBSchema(ctx context.Context, catalog string, schema string, tableFilter *string, columnFilter *string, includeColumns bool) ([]driverbase.TableInfo, error) {
+	if err := c.acquire(); err != nil {
+		return nil, err
 	}
+	defer c.release()
+
+	if includeColumns {
+		key := metadataCacheKey("columns", &catalog, &schema, tableFilter, columnFilter)
+		return loadMetadata(ctx, c, key, func(ctx context.Context) ([]driverbase.TableInfo, error) {
+			return c.getTablesWithColumns(ctx, catalog, schema, tableFilter, columnFilter)
+		})
+	}
+	key := metadataCacheKey("tables", &catalog, &schema, tableFilter)
+	return loadMetadata(ctx, c, key, func(ctx context.Context) ([]driverbase.TableInfo, error) {
+		return c.getTables(ctx, catalog, schema, tableFilter)
+	})
+}
 
+func (c *connectionImpl) getTables(ctx context.Context, catalog string, schema string, tableFilter *string) (tables []driverbase.TableInfo, err error) {
 	tables = []driverbase.TableInfo{}
-	escapedCatalog := strings.ReplaceAll(catalog, "`", "``")
-	escapedSchema := strings.ReplaceAll(schema, "`", "``")
-	query := fmt.Sprintf("SHOW TABLES IN `%s`.`%s`", escapedCatalog, escapedSchema)
+	query := "SHOW TABLES IN " + quoteIdentifier(catalog) + "." + quoteIdentifier(schema)
 	if tableFilter != nil {
-		escapedFilter := strings.ReplaceAll(*tableFilter, "'", "''")
-		query += fmt.Sprintf(" LIKE '%s'", escapedFilter)
+		query += " LIKE " + quoteString(normalizeIdentifier(*tableFilter))
 	}
 
 	var rows *sql.Rows
@@ -303,6 +577,10 @@ func (c *connectionImpl) GetTablesForDBSchema(ctx context.Context, catalog strin
 			}
 		}
 
+		if c.filterExcludes(tableFilter, tableName) {
+			continue
+		}
+
 		tableInfo := driverbase.TableInfo{
 			TableName:        tableName,
 			TableType:        "TABLE", // Default to TABLE, could be improved with more detailed queries
@@ -333,47 +611,82 @@ func (c *connectionImpl) getTablesWithColumns(ctx context.Context, catalog strin
 		// Hive Metastore and system catalog metadata are only available via the system-level information_schema
 		queryBuilder.WriteString("system.information_schema.COLUMNS c ")
 		queryBuilder.WriteString("WHERE c.table_catalog = ")
-		queryBuilder.WriteString(quoteString(catalog))
+		queryBuilder.WriteString(quoteString(normalizeIdentifier(catalog)))
 		queryBuilder.WriteString(" AND c.TABLE_SCHEMA = ")
-		queryBuilder.WriteString(quoteString(schema))
+		queryBuilder.WriteString(quoteString(normalizeIdentifier(schema)))
 	} else {
 		// Unity Catalog catalogs have their own information_schema
 		queryBuilder.WriteString(quoteIdentifier(catalog))
 		queryBuilder.WriteString(".information_schema.COLUMNS c WHERE c.TABLE_SCHEMA = ")
-		queryBuilder.WriteString(quoteString(schema))
+		queryBuilder.WriteString(quoteString(normalizeIdentifier(schema)))
 	}
 
+	// LIKE compares case, ILIKE ignores it
+	like := " ILIKE "
+	if c.exactFilters {
+		like = " LIKE "
+	}
 	if tableFilter != nil {
-		queryBuilder.WriteString(" AND c.TABLE_NAME LIKE ")
-		queryBuilder.WriteString(quoteString(*tableFilter))
+		queryBuilder.WriteString(" AND c.TABLE_NAME")
+		queryBuilder.WriteString(like)
+		queryBuilder.WriteString(quoteString(normalizeIdentifier(*tableFilter)))
 	}
 	if columnFilter != nil {
-		queryBuilder.WriteString(" AND c.COLUMN_NAME LIKE ")
-		queryBuilder.WriteString(quoteString(*columnFilter))
+		queryBuilder.WriteString(" AND c.COLUMN_NAME")
+		queryBuilder.WriteString(like)
+		queryBuilder.WriteString(quoteString(normalizeIdentifier(*columnFilter)))
 	}
 
-	queryBuilder.WriteString(" ORDER BY c.TABLE_NAME, c.ordinal_position")
-
-	rows, err := c.conn.QueryContext(ctx, queryBuilder.String())
-	if err != nil {
-		// If we don't have permissions on the catalog, this will
-		// error. Catch that and simply return no tables instead of
-		// blowing up.
-		var dbExecutionErr dbsqlerr.DBExecutionError
-		if errors.As(err, &dbExecutionErr) && dbExecutionErr.SqlState() == "42501" {
-			return tables, nil
+	// Page through the columns by position, so that schemas with very many
+	// tables are not fetched as a single result
+	baseQuery := queryBuilder.String()
+	var currentTable *driverbase.TableInfo
+	for {
+		query := baseQuery
+		if currentTable != nil {
+			lastColumn := currentTable.TableColumns[len(currentTable.TableColumns)-1]
+			query += fmt.Sprintf(" AND (c.TABLE_NAME > %s OR (c.TABLE_NAME = %s AND c.ordinal_position > %d))",
+				quoteString(currentTable.TableName), quoteString(currentTable.TableName), *lastColumn.OrdinalPosition-1)
 		}
-		return nil, adbc.Error{
-			Code: adbc.StatusInternal,
-			Msg:  fmt.Sprintf("failed to query tables with columns: %v", err),
+		query += fmt.Sprintf(" ORDER BY c.TABLE_NAME, c.ordinal_position LIMIT %d", metadataPageSize)
+
+		var count int
+		tables, currentTable, count, err = c.scanColumnsPage(ctx, query, tables, currentTable)
+		if err != nil {
+			var adbcErr adbc.Error
+			if errors.As(err, &adbcErr) {
+				return nil, err
+			}
+			// If we don't have permissions on the catalog, this will
+			// error. Catch that and simply return no tables instead of
+			// blowing up.
+			var dbExecutionErr dbsqlerr.DBExecutionError
+			if errors.As(err, &dbExecutionErr) && dbExecutionErr.SqlState() == "42501" {
+				return []driverbase.TableInfo{}, nil
+			}
+			return nil, adbc.Error{
+				Code: adbc.StatusInternal,
+				Msg:  fmt.Sprintf("failed to query tables with columns: %v", err),
+			}
+		}
+		if count < metadataPageSize || currentTable == nil || currentTable.TableColumns[len(currentTable.TableColumns)-1].OrdinalPosition == nil {
+			return tables, nil
 		}
 	}
+}
+
+// scanColumnsPage runs one page of the columns query of getTablesWithColumns,
+// appending its columns to tables and returning the number of rows read.
+// Errors running the query are returned as-is for the caller to classify.
+func (c *connectionImpl) scanColumnsPage(ctx context.Context, query string, tables []driverbase.TableInfo, currentTable *driverbase.TableInfo) (_ []driverbase.TableInfo, _ *driverbase.TableInfo, count int, err error) {
+	rows, err := c.conn.QueryContext(ctx, query)
+	if err != nil {
+		return nil, nil, 0, err
+	}
 	defer func() {
 		err = errors.Join(err, rows.Close())
 	}()
 
-	var currentTable *driverbase.TableInfo
-
 	for rows.Next() {
 		var tableName, columnName, dataType, isNullable string
 		var ordinalPosition sql.NullInt32
@@ -383,11 +696,12 @@ func (c *connectionImpl) getTablesWithColumns(ctx context.Context, catalog strin
 			&ordinalPosition, &columnName,
 			&dataType, &isNullable,
 		); err != nil {
-			return nil, adbc.Error{
+			return nil, nil, 0, adbc.Error{
 				Code: adbc.StatusInternal,
 				Msg:  fmt.Sprintf("failed to scan table with columns: %v", err),
 			}
 		}
+		count++
 
 		// Check if we need to create a new table entry
 		if currentTable == nil || currentTable.TableName != tableName {
@@ -429,11 +743,117 @@ func (c *connectionImpl) getTablesWithColumns(ctx context.Context, catalog strin
 		currentTable.TableColumns = append(currentTable.TableColumns, columnInfo)
 	}
 
-	return tables, errors.Join(err, rows.Err())
+	return tables, currentTable, count, errors.Join(err, rows.Err())
+}
+
+// GetTableSchema implements adbc.Connection, reading the schema of an
+// empty result from the table.
+func (c *connectionImpl) GetTableSchema(ctx context.Context, catalog *string, dbSchema *string, tableName string) (*arrow.Schema, error) {
+	if err := c.acquire(); err != nil {
+		return nil, err
+	}
+	defer c.release()
+
+	// Omitted names are the current ones, as in ingestion. They are
+	// qualified in full so that cached schemas do not depend on the
+	// current namespace.
+	currentCatalog, currentSchema, err := c.resolveNamespace(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if catalog == nil || *catalog == "" {
+		catalog = &currentCatalog
+	}
+	if dbSchema == nil || *dbSchema == "" {
+		dbSchema = &currentSchema
+	}
+
+	key := metadataCacheKey("table_schema", catalog, dbSchema, &tableName)
+	return loadMetadata(ctx, c, key, func(ctx context.Context) (*arrow.Schema, error) {
+		return c.getTableSchema(ctx, *catalog, *dbSchema, tableName)
+	})
+}
+
+func (c *connectionImpl) getTableSchema(ctx context.Context, catalog, dbSchema, tableName string) (*arrow.Schema, error) {
+	query := "SELECT * FROM " + buildTableName(catalog, dbSchema, tableName) + " LIMIT 0"
+
+	var driverRows driver.Rows
+	err := c.conn.Raw(func(driverConn any) error {
+		var err error
+		driverRows, err = driverConn.(driver.QueryerContext).QueryContext(ctx, query, nil)
+		return err
+	})
+	if err != nil {
+		var dbExecutionErr dbsqlerr.DBExecutionError
+		if errors.As(err, &dbExecutionErr) && dbExecutionErr.SqlState() == "42P01" {
+			return nil, adbc.Error{
+				Code: adbc.StatusNotFound,
+				Msg:  fmt.Sprintf("table not found: %v", err),
+			}
+		}
+		return nil, adbc.Error{
+			Code: adbc.StatusInternal,
+			Msg:  fmt.Sprintf("failed to get table schema: %v", err),
+		}
+	}
+
+	reader, err := newIPCReaderAdapter(ctx, driverRows, &resultStats{}, c.Alloc)
+	if err != nil {
+		return nil, errors.Join(err, driverRows.Close())
+	}
+	defer reader.Release()
+	return reader.Schema(), nil
+}
+
+// GetOption implements adbc.GetSetOptions
+func (c *connectionImpl) GetOption(key string) (string, error) {
+	switch key {
+	case OptionSessionDefaultCatalog, OptionSessionDefaultDbSchema:
+		if err := c.acquire(); err != nil {
+			return "", err
+		}
+		defer c.release()
+
+		if _, _, err := c.resolveNamespace(context.Background()); err != nil {
+			return "", err
+		}
+		if key == OptionSessionDefaultCatalog {
+			return c.defaultCatalog, nil
+		}
+		return c.defaultDbSchema, nil
+	case OptionConnectionUsageStatementCount, OptionConnectionUsageReadBytes, OptionConnectionUsageTaskTimeMs,
+		OptionConnectionUsagePendingCount:
+		return c.usageOption(key)
+	case OptionWarehouseType:
+		return cmp.Or(c.warehouseType, OptionValueWarehouseTypeUnknown), nil
+	case OptionQueryTags:
+		return formatQueryTags(c.queryTags), nil
+	}
+	return c.ConnectionImplBase.GetOption(key)
+}
+
+// SetOption implements adbc.PostInitOptions
+func (c *connectionImpl) SetOption(key, value string) error {
+	switch key {
+	case OptionMetadataCacheInvalidate:
+		c.metadataCache.invalidate()
+		return nil
+	case OptionQueryTags:
+		return c.setQueryTags(value)
+	}
+	return c.ConnectionImplBase.SetOption(key, value)
 }
 
 // PrepareDriverInfo implements driverbase.DriverInfoPreparer.
 func (c *connectionImpl) PrepareDriverInfo(ctx context.Context, infoCodes []adbc.InfoCode) error {
+	if err := c.acquire(); err != nil {
+		return err
+	}
+	defer c.release()
+	if err := c.registerComputeInfo(); err != nil {
+		return err
+	}
+
 	var versionJSON string
 	err := c.conn.QueryRowContext(ctx, "SELECT current_version()").Scan(&versionJSON)
 	if err != nil {
@@ -458,7 +878,9 @@ func (c *connectionImpl) PrepareDriverInfo(ctx context.Context, infoCodes []adbc
 	return c.DriverInfo.RegisterInfoCode(adbc.InfoVendorVersion, version)
 }
 
-// quoteString escapes string literals using single quotes
+// quoteString escapes string literals using single quotes. Backslashes
+// are doubled, as Databricks reads them as escapes in literals.
 func quoteString(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
 	return fmt.Sprintf("'%s'", strings.ReplaceAll(value, "'", "''"))
 }
@@ -0,0 +1,238 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/decimal128"
+)
+
+// conversionCheck describes how writing a bound value to a Databricks
+// column loses information, or returns "" if it does not. It is only
+// called for non-null values.
+type conversionCheck func(arr arrow.Array, idx int) string
+
+// newConversionCheck returns the check for writing values of type dt to a
+// column of the Databricks type target, or nil if no value can lose
+// information. Conversions the server rejects outright are not checked.
+func newConversionCheck(dt arrow.DataType, target string) conversionCheck {
+	target = strings.ToUpper(strings.TrimSpace(target))
+
+	switch dt.ID() {
+	case arrow.TIMESTAMP:
+		if dt.(*arrow.TimestampType).Unit == arrow.Nanosecond && strings.HasPrefix(target, "TIMESTAMP") {
+			return func(arr arrow.Array, idx int) string {
+				if arr.(*array.Timestamp).Value(idx)%1000 != 0 {
+					return "nanoseconds would be truncated, as Databricks timestamps have microsecond precision"
+				}
+				return ""
+			}
+		}
+	case arrow.FLOAT64:
+		if target == "FLOAT" || target == "REAL" {
+			return func(arr arrow.Array, idx int) string {
+				val := arr.(*array.Float64).Value(idx)
+				if !math.IsNaN(val) && float64(float32(val)) != val {
+					return "the value cannot be represented exactly as a 32-bit float"
+				}
+				return ""
+			}
+		}
+	case arrow.INT8, arrow.INT16, arrow.INT32, arrow.INT64,
+		arrow.UINT8, arrow.UINT16, arrow.UINT32, arrow.UINT64, arrow.DECIMAL128:
+		if minVal, maxVal, ok := integerTypeRange(target); ok {
+			if srcMin, srcMax, ok := arrowIntegerRange(dt); ok && srcMin.Cmp(minVal) >= 0 && srcMax.Cmp(maxVal) <= 0 {
+				return nil
+			}
+			return func(arr arrow.Array, idx int) string {
+				return exactNumericLoss(arr, idx, 0, func(n *big.Int) bool {
+					return n.Cmp(minVal) >= 0 && n.Cmp(maxVal) <= 0
+				})
+			}
+		}
+		if precision, scale, ok := parseDecimalType(target); ok {
+			limit := pow10(precision)
+			return func(arr arrow.Array, idx int) string {
+				return exactNumericLoss(arr, idx, scale, func(n *big.Int) bool {
+					return new(big.Int).Abs(n).Cmp(limit) < 0
+				})
+			}
+		}
+	}
+	return nil
+}
+
+// exactNumericLoss checks an integer or decimal value scaled to scale
+// digits, describing the loss if digits are dropped or fits rejects the
+// scaled value
+func exactNumericLoss(arr arrow.Array, idx int, scale int32, fits func(*big.Int) bool) string {
+	n, valueScale := exactNumericValue(arr, idx)
+	if valueScale > scale {
+		var rem big.Int
+		n.QuoRem(n, pow10(valueScale-scale), &rem)
+		if rem.Sign() != 0 {
+			return fmt.Sprintf("value %s would be rounded to %d decimal places", arr.ValueStr(idx), scale)
+		}
+	} else {
+		n.Mul(n, pow10(scale-valueScale))
+	}
+	if !fits(n) {
+		return fmt.Sprintf("value %s is out of range", arr.ValueStr(idx))
+	}
+	return ""
+}
+
+// exactNumericValue returns an integer or decimal value as an unscaled
+// integer and its scale
+func exactNumericValue(arr arrow.Array, idx int) (*big.Int, int32) {
+	switch arr := arr.(type) {
+	case *array.Int8:
+		return big.NewInt(int64(arr.Value(idx))), 0
+	case *array.Int16:
+		return big.NewInt(int64(arr.Value(idx))), 0
+	case *array.Int32:
+		return big.NewInt(int64(arr.Value(idx))), 0
+	case *array.Int64:
+		return big.NewInt(arr.Value(idx)), 0
+	case *array.Uint8:
+		return big.NewInt(int64(arr.Value(idx))), 0
+	case *array.Uint16:
+		return big.NewInt(int64(arr.Value(idx))), 0
+	case *array.Uint32:
+		return big.NewInt(int64(arr.Value(idx))), 0
+	case *array.Uint64:
+		return new(big.Int).SetUint64(arr.Value(idx)), 0
+	case *array.Decimal128:
+		return decimal128BigInt(arr.Value(idx)), arr.DataType().(*arrow.Decimal128Type).Scale
+	}
+	panic(fmt.Sprintf("unexpected array type %T", arr))
+}
+
+// integerTypeRange returns the range of a Databricks integer type
+func integerTypeRange(typ string) (*big.Int, *big.Int, bool) {
+	var bits uint
+	switch typ {
+	case "TINYINT", "BYTE":
+		bits = 8
+	case "SMALLINT", "SHORT":
+		bits = 16
+	case "INT", "INTEGER":
+		bits = 32
+	case "BIGINT", "LONG":
+		bits = 64
+	default:
+		return nil, nil, false
+	}
+	maxVal := new(big.Int).Lsh(big.NewInt(1), bits-1)
+	minVal := new(big.Int).Neg(maxVal)
+	return minVal, maxVal.Sub(maxVal, big.NewInt(1)), true
+}
+
+// arrowIntegerRange returns the range of an Arrow integer type
+func arrowIntegerRange(dt arrow.DataType) (*big.Int, *big.Int, bool) {
+	var bits uint
+	signed := true
+	switch dt.ID() {
+	case arrow.INT8, arrow.INT16, arrow.INT32, arrow.INT64:
+		bits = uint(dt.(arrow.FixedWidthDataType).BitWidth())
+	case arrow.UINT8, arrow.UINT16, arrow.UINT32, arrow.UINT64:
+		bits = uint(dt.(arrow.FixedWidthDataType).BitWidth())
+		signed = false
+	default:
+		return nil, nil, false
+	}
+	if signed {
+		maxVal := new(big.Int).Lsh(big.NewInt(1), bits-1)
+		return new(big.Int).Neg(maxVal), maxVal.Sub(maxVal, big.NewInt(1)), true
+	}
+	maxVal := new(big.Int).Lsh(big.NewInt(1), bits)
+	return new(big.Int), maxVal.Sub(maxVal, big.NewInt(1)), true
+}
+
+// parseDecimalType returns the precision and scale of a Databricks
+// DECIMAL type, which default to 10 and 0
+func parseDecimalType(typ string) (int32, int32, bool) {
+	params, ok := strings.CutPrefix(typ, "DECIMAL")
+	if !ok {
+		if params, ok = strings.CutPrefix(typ, "NUMERIC"); !ok {
+			return 0, 0, false
+		}
+	}
+	params = strings.TrimSpace(params)
+	if params == "" {
+		return 10, 0, true
+	}
+	params, ok = strings.CutPrefix(params, "(")
+	if !ok {
+		return 0, 0, false
+	}
+	if params, ok = strings.CutSuffix(params, ")"); !ok {
+		return 0, 0, false
+	}
+
+	precisionStr, scaleStr, hasScale := strings.Cut(params, ",")
+	precision, err := strconv.ParseInt(strings.TrimSpace(precisionStr), 10, 32)
+	if err != nil {
+		return 0, 0, false
+	}
+	var scale int64
+	if hasScale {
+		if scale, err = strconv.ParseInt(strings.TrimSpace(scaleStr), 10, 32); err != nil {
+			return 0, 0, false
+		}
+	}
+	return int32(precision), int32(scale), true
+}
+
+// decimal128BigInt converts n to a big.Int. Unlike decimal128.Num.BigInt,
+// it does not drop the upper half of each 64-bit word on 32-bit platforms.
+func decimal128BigInt(n decimal128.Num) *big.Int {
+	b := big.NewInt(n.HighBits())
+	b.Lsh(b, 64)
+	return b.Add(b, new(big.Int).SetUint64(n.LowBits()))
+}
+
+// formatDecimal128 formats n in plain notation with scale decimal places
+func formatDecimal128(n decimal128.Num, scale int32) string {
+	b := decimal128BigInt(n)
+	sign := ""
+	if b.Sign() < 0 {
+		sign = "-"
+		b.Neg(b)
+	}
+	digits := b.String()
+	if scale <= 0 {
+		if b.Sign() != 0 {
+			digits += strings.Repeat("0", int(-scale))
+		}
+		return sign + digits
+	}
+	if len(digits) <= int(scale) {
+		digits = strings.Repeat("0", int(scale)-len(digits)+1) + digits
+	}
+	point := len(digits) - int(scale)
+	return sign + digits[:point] + "." + digits[point:]
+}
+
+func pow10(n int32) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
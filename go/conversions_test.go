@@ -0,0 +1,162 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/adbc-drivers/driverbase-go/driverbase"
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/decimal128"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConversionCheck(t *testing.T) {
+	mem := memory.DefaultAllocator
+
+	uints := array.NewUint64Builder(mem)
+	uints.AppendValues([]uint64{math.MaxInt64, math.MaxInt64 + 1}, nil)
+	uintArr := uints.NewArray()
+	defer uintArr.Release()
+
+	ints := array.NewInt64Builder(mem)
+	ints.AppendValues([]int64{127, 128, -128, -129}, nil)
+	intArr := ints.NewArray()
+	defer intArr.Release()
+
+	decType := &arrow.Decimal128Type{Precision: 10, Scale: 3}
+	decs := array.NewDecimal128Builder(mem, decType)
+	// 12.500, 12.345, 1234.000
+	decs.AppendValues([]decimal128.Num{decimal128.FromI64(12500), decimal128.FromI64(12345), decimal128.FromI64(1234000)}, nil)
+	decArr := decs.NewArray()
+	defer decArr.Release()
+
+	tsType := &arrow.TimestampType{Unit: arrow.Nanosecond}
+	tss := array.NewTimestampBuilder(mem, tsType)
+	tss.AppendValues([]arrow.Timestamp{1_000_000, 1_000_001}, nil)
+	tsArr := tss.NewArray()
+	defer tsArr.Release()
+
+	floats := array.NewFloat64Builder(mem)
+	floats.AppendValues([]float64{0.5, 0.1, math.NaN()}, nil)
+	floatArr := floats.NewArray()
+	defer floatArr.Release()
+
+	for _, tc := range []struct {
+		name   string
+		arr    arrow.Array
+		target string
+		lossy  []bool
+	}{
+		{"uint64 to BIGINT", uintArr, "BIGINT", []bool{false, true}},
+		{"int64 to TINYINT", intArr, "tinyint", []bool{false, true, false, true}},
+		{"decimal to DECIMAL(4,1)", decArr, "decimal(4,1)", []bool{false, true, true}},
+		{"decimal to INT", decArr, "INT", []bool{true, true, false}},
+		{"int64 to DECIMAL(4,1)", intArr, "DECIMAL(4, 1)", []bool{false, false, false, false}},
+		{"int64 to DECIMAL(3,1)", intArr, "DECIMAL(3,1)", []bool{true, true, true, true}},
+		{"nanosecond timestamp", tsArr, "TIMESTAMP_NTZ", []bool{false, true}},
+		{"double to FLOAT", floatArr, "FLOAT", []bool{false, true, false}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			check := newConversionCheck(tc.arr.DataType(), tc.target)
+			require.NotNil(t, check)
+			for i, lossy := range tc.lossy {
+				assert.Equal(t, lossy, check(tc.arr, i) != "", "value %d: %s", i, tc.arr.ValueStr(i))
+			}
+		})
+	}
+
+	// Conversions that cannot lose information are not checked
+	assert.Nil(t, newConversionCheck(arrow.PrimitiveTypes.Int32, "BIGINT"))
+	assert.Nil(t, newConversionCheck(arrow.PrimitiveTypes.Float64, "DOUBLE"))
+	assert.Nil(t, newConversionCheck(&arrow.TimestampType{Unit: arrow.Microsecond}, "TIMESTAMP"))
+	assert.Nil(t, newConversionCheck(arrow.PrimitiveTypes.Uint64, "STRING"))
+}
+
+func TestIngestStrictConversions(t *testing.T) {
+	schema := arrow.NewSchema([]arrow.Field{{Name: "id", Type: arrow.PrimitiveTypes.Uint64}}, nil)
+	bldr := array.NewRecordBuilder(memory.DefaultAllocator, schema)
+	defer bldr.Release()
+	bldr.Field(0).(*array.Uint64Builder).AppendValues([]uint64{1, math.MaxUint64}, nil)
+	rec := bldr.NewRecordBatch()
+	defer rec.Release()
+
+	drv := &fakeDriver{}
+	db := sql.OpenDB(drv)
+	defer func() { _ = db.Close() }()
+	conn, err := db.Conn(context.Background())
+	require.NoError(t, err)
+
+	stream, err := array.NewRecordReader(schema, []arrow.RecordBatch{rec})
+	require.NoError(t, err)
+	s := &statementImpl{
+		StatementImplBase: driverbase.StatementImplBase{ErrorHelper: driverbase.ErrorHelper{DriverName: "databricks"}},
+		conn:              &connectionImpl{conn: conn, strictConversions: true},
+		bulkIngestOptions: driverbase.NewBulkIngestOptions(),
+		boundStream:       stream,
+	}
+	s.bulkIngestOptions.TableName = "events"
+	s.bulkIngestOptions.Mode = adbc.OptionValueIngestModeReplace
+
+	_, err = s.executeIngest(context.Background())
+	var adbcErr adbc.Error
+	require.ErrorAs(t, err, &adbcErr)
+	assert.Equal(t, adbc.StatusInvalidArgument, adbcErr.Code)
+	assert.Contains(t, adbcErr.Msg, "column `id`: converting uint64 to BIGINT loses information at row 1")
+
+	// The table is created, but no row of the batch is written
+	for _, query := range drv.execs {
+		assert.False(t, strings.HasPrefix(query, "INSERT"), query)
+	}
+}
+
+func TestStrictConversionsOption(t *testing.T) {
+	d := &databaseImpl{}
+	require.NoError(t, d.SetOptions(map[string]string{OptionStrictConversions: "true"}))
+	val, err := d.GetOption(OptionStrictConversions)
+	require.NoError(t, err)
+	assert.Equal(t, "true", val)
+
+	err = d.SetOptions(map[string]string{OptionStrictConversions: "sometimes"})
+	var adbcErr adbc.Error
+	require.ErrorAs(t, err, &adbcErr)
+	assert.Equal(t, adbc.StatusInvalidArgument, adbcErr.Code)
+}
+
+func TestFormatDecimal128(t *testing.T) {
+	for _, tc := range []struct {
+		n        decimal128.Num
+		scale    int32
+		expected string
+	}{
+		{decimal128.FromI64(12345), 2, "123.45"},
+		{decimal128.FromI64(-5), 3, "-0.005"},
+		{decimal128.FromI64(0), 2, "0.00"},
+		{decimal128.FromI64(42), 0, "42"},
+		{decimal128.FromI64(42), -2, "4200"},
+		{decimal128.New(math.MaxInt64, math.MaxUint64), 0, "170141183460469231731687303715884105727"},
+		{decimal128.New(math.MinInt64, 0), 38, "-1.70141183460469231731687303715884105728"},
+	} {
+		assert.Equal(t, tc.expected, formatDecimal128(tc.n, tc.scale))
+	}
+}
@@ -0,0 +1,439 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/adbc-drivers/driverbase-go/driverbase"
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+)
+
+// Formats of files that COPY INTO loads
+var copyIntoFileFormats = []string{"AVRO", "BINARYFILE", "CSV", "JSON", "ORC", "PARQUET", "TEXT"}
+
+// Statuses of the files in a COPY INTO result
+const (
+	copyIntoStatusLoaded    = "LOADED"
+	copyIntoStatusSkipped   = "SKIPPED"
+	copyIntoStatusValidated = "VALIDATED"
+	copyIntoStatusFailed    = "FAILED"
+)
+
+// copyIntoResultSchema is the schema of the result of a COPY INTO
+// statement mode: one row per file
+var copyIntoResultSchema = arrow.NewSchema([]arrow.Field{
+	{Name: "file_path", Type: arrow.BinaryTypes.String},
+	{Name: "status", Type: arrow.BinaryTypes.String},
+	{Name: "num_rows", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+	{Name: "error", Type: arrow.BinaryTypes.String, Nullable: true},
+}, nil)
+
+// copyIntoOptions configures loading files from cloud storage into a
+// table with COPY INTO, see OptionStatementCopyIntoTargetTable
+type copyIntoOptions struct {
+	TableName   string
+	CatalogName string
+	SchemaName  string
+	// Directory the files are in, and their format
+	Source     string
+	FileFormat string
+	// Files to load, relative to Source, or a glob pattern matching them;
+	// every file of Source if neither is set
+	Files   []string
+	Pattern string
+	// "ALL" or a number of rows to validate instead of loading; empty to
+	// load
+	Validate string
+	// FORMAT_OPTIONS and COPY_OPTIONS of the statement
+	FormatOptions map[string]string
+	CopyOptions   map[string]string
+}
+
+// SetOption handles COPY INTO statement options, returning whether the key
+// was recognized.
+func (o *copyIntoOptions) SetOption(eh *driverbase.ErrorHelper, key, val string) (bool, error) {
+	if name, ok := strings.CutPrefix(key, OptionStatementCopyIntoFormatOptionPrefix); ok && name != "" {
+		o.FormatOptions = setCopyIntoOption(o.FormatOptions, name, val)
+		return true, nil
+	}
+	if name, ok := strings.CutPrefix(key, OptionStatementCopyIntoCopyOptionPrefix); ok && name != "" {
+		o.CopyOptions = setCopyIntoOption(o.CopyOptions, name, val)
+		return true, nil
+	}
+
+	switch key {
+	case OptionStatementCopyIntoTargetTable:
+		o.TableName = val
+	case OptionStatementCopyIntoTargetCatalog:
+		o.CatalogName = val
+	case OptionStatementCopyIntoTargetDbSchema:
+		o.SchemaName = val
+	case OptionStatementCopyIntoSource:
+		o.Source = val
+	case OptionStatementCopyIntoFileFormat:
+		format := strings.ToUpper(val)
+		if val != "" && !slices.Contains(copyIntoFileFormats, format) {
+			return true, eh.Errorf(adbc.StatusInvalidArgument, "invalid statement option %s=%s: must be one of %s",
+				key, val, strings.Join(copyIntoFileFormats, ", "))
+		}
+		o.FileFormat = format
+	case OptionStatementCopyIntoFiles:
+		o.Files = nil
+		for _, file := range strings.Split(val, ",") {
+			if file = strings.TrimSpace(file); file != "" {
+				o.Files = append(o.Files, file)
+			}
+		}
+	case OptionStatementCopyIntoPattern:
+		if _, err := globRegexp(val); err != nil {
+			return true, eh.Errorf(adbc.StatusInvalidArgument, "invalid statement option %s=%s: %v", key, val, err)
+		}
+		o.Pattern = val
+	case OptionStatementCopyIntoValidate:
+		switch rows, err := strconv.ParseInt(val, 10, 64); {
+		case val == "":
+			o.Validate = ""
+		case strings.EqualFold(val, "all"):
+			o.Validate = "ALL"
+		case err == nil && rows > 0:
+			o.Validate = val
+		default:
+			return true, eh.Errorf(adbc.StatusInvalidArgument, "invalid statement option %s=%s: must be all or a number of rows", key, val)
+		}
+	default:
+		return false, nil
+	}
+	return true, nil
+}
+
+// setCopyIntoOption sets or, for an empty value, removes the option name
+func setCopyIntoOption(options map[string]string, name, val string) map[string]string {
+	if val == "" {
+		delete(options, name)
+		return options
+	}
+	if options == nil {
+		options = map[string]string{}
+	}
+	options[name] = val
+	return options
+}
+
+// GetOption returns the value of a COPY INTO statement option, returning
+// whether the key was recognized.
+func (o *copyIntoOptions) GetOption(key string) (string, bool) {
+	if name, ok := strings.CutPrefix(key, OptionStatementCopyIntoFormatOptionPrefix); ok && name != "" {
+		return o.FormatOptions[name], true
+	}
+	if name, ok := strings.CutPrefix(key, OptionStatementCopyIntoCopyOptionPrefix); ok && name != "" {
+		return o.CopyOptions[name], true
+	}
+
+	switch key {
+	case OptionStatementCopyIntoTargetTable:
+		return o.TableName, true
+	case OptionStatementCopyIntoTargetCatalog:
+		return o.CatalogName, true
+	case OptionStatementCopyIntoTargetDbSchema:
+		return o.SchemaName, true
+	case OptionStatementCopyIntoSource:
+		return o.Source, true
+	case OptionStatementCopyIntoFileFormat:
+		return o.FileFormat, true
+	case OptionStatementCopyIntoFiles:
+		return strings.Join(o.Files, ","), true
+	case OptionStatementCopyIntoPattern:
+		return o.Pattern, true
+	case OptionStatementCopyIntoValidate:
+		return strings.ToLower(o.Validate), true
+	}
+	return "", false
+}
+
+func (o *copyIntoOptions) IsSet() bool {
+	return o.TableName != ""
+}
+
+// executeCopyInto loads the files of the COPY INTO options into the
+// target table, returning the result of each. Each file is loaded by its
+// own COPY INTO, so that its result is known; a file that fails does not
+// keep the others from loading.
+func (s *statementImpl) executeCopyInto(ctx context.Context) (array.RecordReader, error) {
+	opts := &s.copyIntoOptions
+	switch {
+	case s.boundStream != nil:
+		return nil, s.ErrorHelper.Errorf(adbc.StatusInvalidState, "bound data cannot be loaded with COPY INTO")
+	case opts.Source == "" || opts.FileFormat == "":
+		return nil, s.ErrorHelper.Errorf(adbc.StatusInvalidArgument, "%s requires %s and %s",
+			OptionStatementCopyIntoTargetTable, OptionStatementCopyIntoSource, OptionStatementCopyIntoFileFormat)
+	case len(opts.Files) > 0 && opts.Pattern != "":
+		return nil, s.ErrorHelper.Errorf(adbc.StatusInvalidArgument, "%s and %s cannot both be set",
+			OptionStatementCopyIntoFiles, OptionStatementCopyIntoPattern)
+	case s.conn.txMode == transactionStaged:
+		return nil, s.ErrorHelper.Errorf(adbc.StatusNotImplemented,
+			"COPY INTO while autocommit is disabled requires a warehouse supporting transactions")
+	}
+
+	// A catalog alone would be read as the schema
+	dbSchema := opts.SchemaName
+	if opts.CatalogName != "" && dbSchema == "" {
+		var err error
+		if dbSchema, err = s.conn.GetCurrentDbSchema(); err != nil {
+			return nil, err
+		}
+	}
+	tableName := buildTableName(opts.CatalogName, dbSchema, opts.TableName)
+
+	files := opts.Files
+	if len(files) == 0 {
+		var err error
+		if files, err = s.listCopyIntoFiles(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	bldr := array.NewRecordBuilder(s.conn.Alloc, copyIntoResultSchema)
+	defer bldr.Release()
+	for _, file := range files {
+		bldr.Field(0).(*array.StringBuilder).Append(strings.TrimSuffix(opts.Source, "/") + "/" + file)
+		rows, err := s.copyIntoFile(ctx, buildCopyIntoSQL(tableName, opts, file))
+		switch {
+		case err != nil:
+			if ctx.Err() != nil {
+				return nil, s.ErrorHelper.Errorf(adbc.StatusCancelled, "COPY INTO %s canceled: %v", tableName, err)
+			}
+			bldr.Field(1).(*array.StringBuilder).Append(copyIntoStatusFailed)
+			bldr.Field(2).AppendNull()
+			bldr.Field(3).(*array.StringBuilder).Append(err.Error())
+			continue
+		case opts.Validate != "":
+			bldr.Field(1).(*array.StringBuilder).Append(copyIntoStatusValidated)
+		case rows != nil && *rows == 0:
+			bldr.Field(1).(*array.StringBuilder).Append(copyIntoStatusSkipped)
+		default:
+			bldr.Field(1).(*array.StringBuilder).Append(copyIntoStatusLoaded)
+		}
+		if rows != nil {
+			bldr.Field(2).(*array.Int64Builder).Append(*rows)
+		} else {
+			bldr.Field(2).AppendNull()
+		}
+		bldr.Field(3).AppendNull()
+	}
+
+	rec := bldr.NewRecordBatch()
+	defer rec.Release()
+	return array.NewRecordReader(copyIntoResultSchema, []arrow.RecordBatch{rec})
+}
+
+// copyIntoFile runs the COPY INTO of one file, returning the rows it
+// loaded or validated, or nil if the result does not say
+func (s *statementImpl) copyIntoFile(ctx context.Context, query string) (*int64, error) {
+	rows, err := s.conn.conn.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+	for rows.Next() {
+		values, err := scanNamedRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		for _, column := range []string{"num_inserted_rows", "num_affected_rows"} {
+			if val, ok := values[column]; ok && val != nil {
+				n, err := strconv.ParseInt(fmt.Sprint(val), 10, 64)
+				if err != nil {
+					return nil, fmt.Errorf("invalid %s %v", column, val)
+				}
+				return &n, rows.Close()
+			}
+		}
+	}
+	return nil, rows.Err()
+}
+
+// listCopyIntoFiles returns the files of the source directory that the
+// pattern matches, or all of them if no pattern is set, relative to the
+// source. Patterns are matched within one directory.
+func (s *statementImpl) listCopyIntoFiles(ctx context.Context) ([]string, error) {
+	opts := &s.copyIntoOptions
+	dir, namePattern := splitCopyIntoPattern(opts.Pattern)
+	if strings.Contains(namePattern, "/") {
+		return nil, s.ErrorHelper.Errorf(adbc.StatusNotImplemented,
+			"%s %s matches files in several directories; list the files with %s instead",
+			OptionStatementCopyIntoPattern, opts.Pattern, OptionStatementCopyIntoFiles)
+	}
+	re, err := globRegexp(namePattern)
+	if err != nil {
+		return nil, s.ErrorHelper.Errorf(adbc.StatusInvalidArgument, "invalid %s %s: %v", OptionStatementCopyIntoPattern, opts.Pattern, err)
+	}
+
+	path := strings.TrimSuffix(opts.Source, "/") + "/" + dir
+	rows, err := s.conn.conn.QueryContext(ctx, "LIST "+quoteString(path))
+	if err != nil {
+		return nil, s.ErrorHelper.Errorf(adbc.StatusIO, "failed to list files in %s: %v", path, err)
+	}
+	defer func() { _ = rows.Close() }()
+	var files []string
+	for rows.Next() {
+		values, err := scanNamedRow(rows)
+		if err != nil {
+			return nil, s.ErrorHelper.Errorf(adbc.StatusIO, "failed to list files in %s: %v", path, err)
+		}
+		name, _ := values["name"].(string)
+		// Directories are listed with a trailing slash
+		if name == "" || strings.HasSuffix(name, "/") || (namePattern != "" && !re.MatchString(name)) {
+			continue
+		}
+		files = append(files, dir+name)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, s.ErrorHelper.Errorf(adbc.StatusIO, "failed to list files in %s: %v", path, err)
+	}
+	slices.Sort(files)
+	return files, nil
+}
+
+// scanNamedRow returns the values of the current row by column name
+func scanNamedRow(rows *sql.Rows) (map[string]any, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	values := make([]any, len(columns))
+	ptrs := make([]any, len(columns))
+	for i := range values {
+		ptrs[i] = &values[i]
+	}
+	if err := rows.Scan(ptrs...); err != nil {
+		return nil, err
+	}
+	named := make(map[string]any, len(columns))
+	for i, column := range columns {
+		named[column] = values[i]
+	}
+	return named, nil
+}
+
+// splitCopyIntoPattern splits a glob pattern into the directory before
+// its first wildcard, with a trailing slash, and the rest
+func splitCopyIntoPattern(pattern string) (dir, rest string) {
+	wildcard := strings.IndexAny(pattern, `*?[{\`)
+	if wildcard < 0 {
+		wildcard = len(pattern)
+	}
+	slash := strings.LastIndexByte(pattern[:wildcard], '/')
+	return pattern[:slash+1], pattern[slash+1:]
+}
+
+// globRegexp compiles a COPY INTO glob pattern: * and ? match within a
+// path segment, [...] and [^...] match a character of a set, {a,b}
+// matches either alternative, and \ escapes the next character
+func globRegexp(glob string) (*regexp.Regexp, error) {
+	var b strings.Builder
+	b.WriteString("^")
+	depth := 0
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; c {
+		case '*':
+			b.WriteString("[^/]*")
+		case '?':
+			b.WriteString("[^/]")
+		case '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 1 {
+				return nil, fmt.Errorf("unterminated [ at offset %d", i)
+			}
+			class := glob[i+1 : i+1+end]
+			negated := strings.HasPrefix(class, "^") || strings.HasPrefix(class, "!")
+			if negated {
+				class = class[1:]
+				b.WriteString("[^")
+			} else {
+				b.WriteString("[")
+			}
+			b.WriteString(strings.NewReplacer(`\`, `\\`, "[", `\[`).Replace(class))
+			b.WriteString("]")
+			i += end + 1
+		case '{':
+			depth++
+			b.WriteString("(?:")
+		case '}':
+			if depth == 0 {
+				return nil, fmt.Errorf("unmatched } at offset %d", i)
+			}
+			depth--
+			b.WriteString(")")
+		case ',':
+			if depth > 0 {
+				b.WriteString("|")
+			} else {
+				b.WriteString(",")
+			}
+		case '\\':
+			if i+1 == len(glob) {
+				return nil, fmt.Errorf("trailing \\")
+			}
+			i++
+			b.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		default:
+			b.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		}
+	}
+	if depth > 0 {
+		return nil, fmt.Errorf("unterminated {")
+	}
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
+
+// buildCopyIntoSQL generates the COPY INTO loading one file of the source
+// into tableName
+func buildCopyIntoSQL(tableName string, opts *copyIntoOptions, file string) string {
+	var sql strings.Builder
+	fmt.Fprintf(&sql, "COPY INTO %s FROM %s FILEFORMAT = %s", tableName, quoteString(opts.Source), opts.FileFormat)
+	switch opts.Validate {
+	case "":
+	case "ALL":
+		sql.WriteString(" VALIDATE ALL")
+	default:
+		fmt.Fprintf(&sql, " VALIDATE %s ROWS", opts.Validate)
+	}
+	fmt.Fprintf(&sql, " FILES = (%s)", quoteString(file))
+	for _, clause := range []struct {
+		name    string
+		options map[string]string
+	}{{"FORMAT_OPTIONS", opts.FormatOptions}, {"COPY_OPTIONS", opts.CopyOptions}} {
+		if len(clause.options) == 0 {
+			continue
+		}
+		pairs := make([]string, 0, len(clause.options))
+		for _, name := range slices.Sorted(maps.Keys(clause.options)) {
+			pairs = append(pairs, quoteString(name)+" = "+quoteString(clause.options[name]))
+		}
+		fmt.Fprintf(&sql, " %s (%s)", clause.name, strings.Join(pairs, ", "))
+	}
+	return sql.String()
+}
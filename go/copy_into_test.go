@@ -0,0 +1,191 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/adbc-drivers/driverbase-go/driverbase"
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Matches the file of a generated COPY INTO
+var copyIntoFileRe = regexp.MustCompile(`FILES = \('([^']*)'\)`)
+
+// newCopyIntoDriver returns a fake driver answering LIST with the given
+// file names, and COPY INTO of a file with the rows it inserted, or an
+// error if the file is in failures
+func newCopyIntoDriver(listing []string, inserted map[string]int64, failures map[string]error) *fakeDriver {
+	return &fakeDriver{query: func(query string, _ []driver.NamedValue) (driver.Rows, error) {
+		if strings.HasPrefix(query, "LIST ") {
+			rows := &fakeRows{columns: []string{"path", "name", "size"}}
+			for _, name := range listing {
+				rows.rows = append(rows.rows, []driver.Value{"s3://landing/" + name, name, int64(10)})
+			}
+			return rows, nil
+		}
+		file := copyIntoFileRe.FindStringSubmatch(query)[1]
+		if err := failures[file]; err != nil {
+			return nil, err
+		}
+		n := inserted[file]
+		return &fakeRows{
+			columns: []string{"num_affected_rows", "num_inserted_rows"},
+			rows:    [][]driver.Value{{n, n}},
+		}, nil
+	}}
+}
+
+func TestCopyInto(t *testing.T) {
+	drv := newCopyIntoDriver(
+		[]string{"2026/", "a.csv", "b.csv", "c.json", "d.csv"},
+		map[string]int64{"a.csv": 3, "d.csv": 0},
+		map[string]error{"b.csv": errors.New("[CSV_MALFORMED] malformed record")},
+	)
+	db := sql.OpenDB(drv)
+	defer func() { _ = db.Close() }()
+	conn, err := db.Conn(context.Background())
+	require.NoError(t, err)
+
+	s := &statementImpl{conn: &connectionImpl{conn: conn}}
+	s.conn.Alloc = memory.DefaultAllocator
+	for key, val := range map[string]string{
+		OptionStatementCopyIntoTargetTable:                            "events",
+		OptionStatementCopyIntoTargetDbSchema:                         "raw",
+		OptionStatementCopyIntoSource:                                 "s3://landing/",
+		OptionStatementCopyIntoFileFormat:                             "csv",
+		OptionStatementCopyIntoPattern:                                "{a,b,d}.csv",
+		OptionStatementCopyIntoFormatOptionPrefix + "header":          "true",
+		OptionStatementCopyIntoFormatOptionPrefix + "delimiter":       "'",
+		OptionStatementCopyIntoCopyOptionPrefix + "mergeSchema":       "true",
+		OptionStatementCopyIntoCopyOptionPrefix + "force":             "true",
+		OptionStatementCopyIntoCopyOptionPrefix + "rescuedDataColumn": "",
+	} {
+		_, err := s.copyIntoOptions.SetOption(&s.ErrorHelper, key, val)
+		require.NoError(t, err, key)
+	}
+	_, err = s.copyIntoOptions.SetOption(&s.ErrorHelper, OptionStatementCopyIntoCopyOptionPrefix+"force", "")
+	require.NoError(t, err)
+
+	rdr, _, err := s.executeQuery(context.Background())
+	require.NoError(t, err)
+	defer rdr.Release()
+	require.True(t, rdr.Next())
+	assert.JSONEq(t, `[
+		{"file_path": "s3://landing/a.csv", "status": "LOADED", "num_rows": 3, "error": null},
+		{"file_path": "s3://landing/b.csv", "status": "FAILED", "num_rows": null, "error": "[CSV_MALFORMED] malformed record"},
+		{"file_path": "s3://landing/d.csv", "status": "SKIPPED", "num_rows": 0, "error": null}
+	]`, recordJSON(t, rdr.RecordBatch()))
+	assert.Equal(t, []string{
+		"LIST 's3://landing/'",
+		"COPY INTO `raw`.`events` FROM 's3://landing/' FILEFORMAT = CSV FILES = ('a.csv')" +
+			" FORMAT_OPTIONS ('delimiter' = '''', 'header' = 'true') COPY_OPTIONS ('mergeSchema' = 'true')",
+		"COPY INTO `raw`.`events` FROM 's3://landing/' FILEFORMAT = CSV FILES = ('b.csv')" +
+			" FORMAT_OPTIONS ('delimiter' = '''', 'header' = 'true') COPY_OPTIONS ('mergeSchema' = 'true')",
+		"COPY INTO `raw`.`events` FROM 's3://landing/' FILEFORMAT = CSV FILES = ('d.csv')" +
+			" FORMAT_OPTIONS ('delimiter' = '''', 'header' = 'true') COPY_OPTIONS ('mergeSchema' = 'true')",
+	}, drv.execs)
+
+	// Listed files are loaded without LIST, and validated rows reported
+	drv.execs = nil
+	for key, val := range map[string]string{
+		OptionStatementCopyIntoPattern:  "",
+		OptionStatementCopyIntoFiles:    "2026/a.csv, a.csv",
+		OptionStatementCopyIntoValidate: "ALL",
+	} {
+		_, err := s.copyIntoOptions.SetOption(&s.ErrorHelper, key, val)
+		require.NoError(t, err, key)
+	}
+	rdr, _, err = s.executeQuery(context.Background())
+	require.NoError(t, err)
+	defer rdr.Release()
+	require.True(t, rdr.Next())
+	assert.JSONEq(t, `[
+		{"file_path": "s3://landing/2026/a.csv", "status": "VALIDATED", "num_rows": 0, "error": null},
+		{"file_path": "s3://landing/a.csv", "status": "VALIDATED", "num_rows": 3, "error": null}
+	]`, recordJSON(t, rdr.RecordBatch()))
+	require.Len(t, drv.execs, 2)
+	assert.Contains(t, drv.execs[0], "FILEFORMAT = CSV VALIDATE ALL FILES = ('2026/a.csv')")
+	validate, err := s.getOption(OptionStatementCopyIntoValidate)
+	require.NoError(t, err)
+	assert.Equal(t, "all", validate)
+
+	_, err = s.ExecuteUpdate(context.Background())
+	assert.ErrorContains(t, err, "returned by ExecuteQuery")
+
+	// Files and a pattern are exclusive
+	_, err = s.copyIntoOptions.SetOption(&s.ErrorHelper, OptionStatementCopyIntoPattern, "*.csv")
+	require.NoError(t, err)
+	_, _, err = s.executeQuery(context.Background())
+	assert.ErrorContains(t, err, "cannot both be set")
+}
+
+func TestCopyIntoOptions(t *testing.T) {
+	var opts copyIntoOptions
+	eh := &driverbase.ErrorHelper{}
+	for key, val := range map[string]string{
+		OptionStatementCopyIntoFileFormat: "xlsx",
+		OptionStatementCopyIntoValidate:   "0",
+		OptionStatementCopyIntoPattern:    "{a,b.csv",
+	} {
+		_, err := opts.SetOption(eh, key, val)
+		var adbcErr adbc.Error
+		require.ErrorAs(t, err, &adbcErr, key)
+		assert.Equal(t, adbc.StatusInvalidArgument, adbcErr.Code)
+	}
+
+	for glob, matches := range map[string]map[string]bool{
+		"*.csv":          {"a.csv": true, "a.json": false, "dir/a.csv": false},
+		"part-?.parquet": {"part-1.parquet": true, "part-10.parquet": false},
+		"[ab]*.json":     {"a.json": true, "c.json": false},
+		"[^ab]*.json":    {"a.json": false, "c.json": true},
+		"{x,y}.txt":      {"x.txt": true, "y.txt": true, "z.txt": false},
+		`a\*.csv`:        {"a*.csv": true, "ab.csv": false},
+		"café.csv":       {"café.csv": true},
+	} {
+		re, err := globRegexp(glob)
+		require.NoError(t, err, glob)
+		for name, want := range matches {
+			assert.Equal(t, want, re.MatchString(name), "%s %s", glob, name)
+		}
+	}
+
+	for pattern, want := range map[string][2]string{
+		"":                 {"", ""},
+		"*.csv":            {"", "*.csv"},
+		"2026/10/*.csv":    {"2026/10/", "*.csv"},
+		"2026/*/data.csv":  {"2026/", "*/data.csv"},
+		"2026/10/data.csv": {"2026/10/", "data.csv"},
+	} {
+		dir, rest := splitCopyIntoPattern(pattern)
+		assert.Equal(t, want, [2]string{dir, rest}, pattern)
+	}
+}
+
+// recordJSON returns the rows of rec as JSON
+func recordJSON(t *testing.T, rec interface{ MarshalJSON() ([]byte, error) }) string {
+	data, err := rec.MarshalJSON()
+	require.NoError(t, err)
+	return string(data)
+}
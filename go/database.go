@@ -23,11 +23,16 @@
 package databricks
 
 import (
+	"cmp"
 	"context"
+	"crypto"
 	"database/sql"
+	"database/sql/driver"
 	"errors"
 	"fmt"
 	"net"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -35,11 +40,12 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"net/http"
-	"os"
+	"net/url"
 
 	"github.com/adbc-drivers/driverbase-go/driverbase"
 	"github.com/apache/arrow-adbc/go/adbc"
 	dbsql "github.com/databricks/databricks-sql-go"
+	"github.com/databricks/databricks-sql-go/auth"
 )
 
 const (
@@ -48,6 +54,22 @@ const (
 	DEFAULT_RETRY_WAIT_MAX = 30 * time.Second
 )
 
+// Session configuration naming the user that a session's statements run
+// as, see OptionImpersonateUser
+const impersonateUserSessionParam = "hive.server2.proxy.user"
+
+// Session configuration of OptionSessionANSIMode on warehouses and on
+// all-purpose clusters, and of OptionSessionDefaultCollation
+const (
+	ansiModeSessionParam         = "ANSI_MODE"
+	clusterANSIModeSessionParam  = "spark.sql.ansi.enabled"
+	defaultCollationSessionParam = "spark.sql.session.collation.default"
+)
+
+// collationNameRe matches the names of collations, such as UNICODE_CI or
+// an ICU locale with specifiers like DE_CI_AI
+var collationNameRe = regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`)
+
 type databaseImpl struct {
 	driverbase.DatabaseImplBase
 
@@ -57,29 +79,151 @@ type databaseImpl struct {
 
 	// Connection parameters
 	uri            string
+	databricksURI  string
 	serverHostname string
 	httpPath       string
 	accessToken    string
 	port           int
 	catalog        string
 	schema         string
+	// Port given as part of serverHostname; 0 if none
+	hostnamePort int
+
+	// Standby HTTP paths, tried in order when httpPath fails
+	failoverHTTPPaths []string
+
+	// Timeouts for dialing the workspace and for each HTTP request; 0 for
+	// the defaults
+	connectTimeout     time.Duration
+	httpRequestTimeout time.Duration
+	// HTTP transport tuning; zero values for the defaults
+	http2Disabled       bool
+	maxIdleConnsPerHost int
+	tcpKeepAlive        time.Duration
+	tcpKeepAliveSet     bool
 
 	// Query options
-	queryTimeout        time.Duration
-	maxRows             int
-	queryRetryCount     int
-	downloadThreadCount int
+	queryTimeout       time.Duration
+	pollInterval       time.Duration
+	slowQueryThreshold time.Duration
+	retryBudgetRetries int
+	retryBudgetTime    time.Duration
+
+	// When set, Open waits for the warehouse to be running
+	warehouseWaitForStart time.Duration
+	warehouse             *warehouseClient
+	maxRows               int
+	queryRetryCount       int
+	fetchSize             int64
+	downloadThreadCount   int
+
+	// Type of the warehouse, read when the connection pool is created
+	warehouseType string
+	// use_cached_result of sessions; empty for the warehouse's default
+	useCachedResult string
+	// Session settings of OptionSessionANSIMode and
+	// OptionSessionDefaultCollation; empty to leave the defaults
+	ansiMode         string
+	defaultCollation string
+	// Attribute the statements of every session
+	queryTags []queryTag
+	// User the statements of every session run as; empty for the
+	// authenticated identity
+	impersonateUser string
+	// Longest SQL text sent; 0 if unchecked
+	maxSQLLength int
+
+	// Results of metadata queries; nil when OptionMetadataCacheTTL is unset
+	metadataCache *metadataCache
+	// Limits of metadata queries, and the throttle applying them to the
+	// connections of the pool; nil if unlimited
+	metadataMaxConcurrency int
+	metadataMaxRetries     int
+	metadataBackoff        time.Duration
+	metadataThrottle       *metadataThrottle
+
+	// Connection pool limits; 0 means no limit, except that poolMaxIdle
+	// only applies when poolMaxIdleSet
+	poolMaxOpen     int
+	poolMaxIdle     int
+	poolMaxIdleSet  bool
+	poolIdleTimeout time.Duration
+	poolMaxLifetime time.Duration
+
+	// Connections start their session on first use rather than in Open
+	lazyConnect bool
+	// Connections replace sessions the server reports invalid
+	autoReconnect bool
+	// Run on each new session
+	initSQL string
+	// Receives every statement run; nil if unset
+	queryLogger QueryLogger
+	// Hash rather than redact parameters reported to queryLogger
+	queryLogHashParams bool
+	// Bulk operations fail on lossy conversions
+	strictConversions bool
+	// Match GetObjects filters case-sensitively
+	exactFilters bool
+	// Add the provenance of results to their schema metadata
+	resultProvenance bool
+	// Limit of each connection's result memory; 0 if unlimited
+	memoryLimit     int64
+	memoryLimitWait time.Duration
+	// Idle connections ping their session this often; 0 if disabled
+	keepAliveInterval time.Duration
+	// Idle connections close their session after this long; 0 if disabled
+	idleCloseAfter time.Duration
 
 	// TLS/SSL options
 	sslMode     string
 	sslRootCert string
 	sslCertPool *x509.CertPool
-	sslInsecure bool
 
-	// OAuth options (for future expansion)
-	oauthClientID     string
-	oauthClientSecret string
-	oauthRefreshToken string
+	// Proxy options; proxyURL is nil when OptionProxyURL is unset
+	proxyURL      *url.URL
+	proxyUser     string
+	proxyPassword string
+
+	// Extra headers for workspace requests, see OptionHTTPHeaderPrefix
+	httpHeaders http.Header
+
+	// TLS client certificate for mutual TLS
+	sslClientCert       string
+	sslClientKey        string
+	sslClientCertChain  [][]byte
+	sslClientPrivateKey crypto.PrivateKey
+	// Name checked against the server certificate instead of the hostname
+	sslServerName string
+
+	// Authentication options
+	authType string
+
+	// OAuth options
+	oauthClientID      string
+	oauthClientSecret  string
+	oauthRefreshToken  string
+	oauthTokenEndpoint string
+	oauthRedirectPort  int
+	// Presents the U2M login URL instead of the browser, if set
+	oauthLoginHandler func(loginURL string) error
+
+	oauthRefreshBeforeExpiry time.Duration
+
+	oauthIdentityTokenFile     string
+	oauthIdentityTokenAudience string
+
+	googleCredentials string
+
+	// Options for registered auth types, see OptionAuthPrefix
+	authOptions map[string]string
+
+	// Token supplied by the application, shared with open connections
+	externalToken *externalTokenAuthenticator
+
+	// Supplied programmatically with NewDatabaseWithTokenProvider
+	tokenProvider TokenProvider
+	// Sends token requests, created with the transport in Open
+	authClient *http.Client
 }
 
 func (d *databaseImpl) resolveConnectionOptions() ([]dbsql.ConnOption, error) {
@@ -97,49 +241,95 @@ func (d *databaseImpl) resolveConnectionOptions() ([]dbsql.ConnOption, error) {
 		}
 	}
 
-	// FIXME: Support other auth methods
-	if d.accessToken == "" && d.oauthClientID == "" && d.oauthClientSecret == "" {
-		return nil, adbc.Error{
-			Code: adbc.StatusInvalidArgument,
-			Msg:  "[db] access token or OAuth config is required",
-		}
-	} else if d.accessToken != "" && (d.oauthClientID != "" || d.oauthClientSecret != "") {
-		return nil, adbc.Error{
-			Code: adbc.StatusInvalidArgument,
-			Msg:  "[db] cannot specify both access token and OAuth config",
-		}
+	port, err := d.endpointPort()
+	if err != nil {
+		return nil, err
 	}
 
+	// databricks-sql-go takes a leading "http" as the scheme, which a
+	// hostname such as https-gateway.internal would otherwise lose
+	hostname := d.serverHostname
+	if strings.HasPrefix(strings.ToLower(hostname), "http") {
+		hostname = "https://" + hostname
+	}
 	opts := []dbsql.ConnOption{
-		dbsql.WithServerHostname(d.serverHostname),
+		dbsql.WithServerHostname(hostname),
 		dbsql.WithHTTPPath(d.httpPath),
+		dbsql.WithPort(port),
 	}
 
-	if d.accessToken != "" {
-		opts = append(opts, dbsql.WithAccessToken(d.accessToken))
-	} else {
-		opts = append(opts, dbsql.WithClientCredentials(d.oauthClientID, d.oauthClientSecret))
+	proxy := d.proxyFunc()
+	tlsConfig, err := d.newTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+	// databricks-sql-go downloads CloudFetch results from cloud storage
+	// with Go's default HTTP client, which would skip these TLS, proxy
+	// and tuning settings, so results are fetched through the workspace
+	// instead
+	if tlsConfig != nil || d.proxyURL != nil || d.tunesTransport() {
+		opts = append(opts, dbsql.WithCloudFetch(false))
 	}
 
-	// Validate and set custom port
-	// Defaults to 443
-	if d.port != 0 {
-		opts = append(opts, dbsql.WithPort(d.port))
-	} else {
-		opts = append(opts, dbsql.WithPort(DEFAULT_PORT))
+	// Tokens are requested with the TLS and proxy settings, but not the
+	// headers, retries and timeouts of workspace requests
+	d.authClient = &http.Client{
+		Transport: d.newPooledTransport(tlsConfig, proxy),
+		Timeout:   cmp.Or(d.httpRequestTimeout, defaultAuthRequestTimeout),
+	}
+	authOpts, authr, err := d.resolveAuthOptions()
+	if err != nil {
+		return nil, err
 	}
+	opts = append(opts, authOpts...)
 
 	// Default namespace for queries (catalog/schema)
 	if d.catalog != "" || d.schema != "" {
 		opts = append(opts, dbsql.WithInitialNamespace(d.catalog, d.schema))
 	}
 
+	sessionParams := map[string]string{}
+	if d.useCachedResult != "" {
+		if httpPathCompute(d.httpPath).kind == InfoValueComputeCluster {
+			return nil, adbc.Error{
+				Code: adbc.StatusInvalidArgument,
+				Msg:  fmt.Sprintf("%s requires a SQL warehouse; all-purpose clusters have no result cache", OptionUseCachedResult),
+			}
+		}
+		sessionParams["use_cached_result"] = d.useCachedResult
+	}
+	if len(d.queryTags) > 0 {
+		sessionParams[queryTagsSessionParam] = formatQueryTags(d.queryTags)
+	}
+	if d.impersonateUser != "" {
+		sessionParams[impersonateUserSessionParam] = d.impersonateUser
+	}
+	if d.ansiMode != "" {
+		if httpPathCompute(d.httpPath).kind == InfoValueComputeCluster {
+			sessionParams[clusterANSIModeSessionParam] = d.ansiMode
+		} else {
+			sessionParams[ansiModeSessionParam] = d.ansiMode
+		}
+	}
+	if d.defaultCollation != "" {
+		sessionParams[defaultCollationSessionParam] = d.defaultCollation
+	}
+	if len(sessionParams) > 0 {
+		opts = append(opts, dbsql.WithSessionParams(sessionParams))
+	}
+
 	if d.queryTimeout > 0 {
 		opts = append(opts, dbsql.WithTimeout(d.queryTimeout))
 	}
 
+	if d.pollInterval > 0 {
+		opts = append(opts, withPollInterval(d.pollInterval))
+	}
+
 	if d.maxRows > 0 {
 		opts = append(opts, dbsql.WithMaxRows(int(d.maxRows)))
+	} else if d.fetchSize > 0 {
+		opts = append(opts, dbsql.WithMaxRows(int(d.fetchSize)))
 	}
 	if d.queryRetryCount >= 0 {
 		opts = append(opts, dbsql.WithRetries(d.queryRetryCount, DEFAULT_RETRY_WAIT_MIN, DEFAULT_RETRY_WAIT_MAX))
@@ -153,45 +343,211 @@ func (d *databaseImpl) resolveConnectionOptions() ([]dbsql.ConnOption, error) {
 	// TLS config is needed. These settings match the defaults from
 	// databricks-sql-go's PooledTransport to ensure reliable connections
 	// for large result set downloads.
-	if d.sslCertPool != nil || d.sslInsecure {
-		tlsConfig := &tls.Config{
-			MinVersion: tls.VersionTLS12,
+	var transport http.RoundTripper
+	if tlsConfig != nil {
+		transport = d.newPooledTransport(tlsConfig, proxy)
+	} else if d.proxyURL != nil || d.connectTimeout > 0 || d.tunesTransport() {
+		transport = d.newPooledTransport(nil, proxy)
+	}
+
+	// Bound every attempt of a request, so retries get their own time
+	if d.httpRequestTimeout > 0 {
+		if transport == nil {
+			transport = d.newPooledTransport(nil, proxy)
 		}
+		transport = &requestTimeoutTransport{base: transport, timeout: d.httpRequestTimeout}
+	}
 
-		if d.sslCertPool != nil {
-			tlsConfig.RootCAs = d.sslCertPool
+	// Retry requests whose token was rejected once a new one is available
+	if authr != nil {
+		if transport == nil {
+			transport = d.newPooledTransport(nil, proxy)
 		}
+		transport = &reauthTransport{base: transport, authr: authr}
+	}
 
-		if d.sslInsecure {
-			tlsConfig.InsecureSkipVerify = true
+	// Report rate limited metadata queries to the throttle
+	if d.metadataMaxConcurrency > 0 || d.metadataMaxRetries > 0 {
+		if transport == nil {
+			transport = d.newPooledTransport(nil, proxy)
 		}
+		transport = &metadataThrottleTransport{base: transport}
+	}
+
+	// Charge retries to the budget of the connection making them
+	if d.retryBudgetRetries > 0 || d.retryBudgetTime > 0 {
+		if transport == nil {
+			transport = d.newPooledTransport(nil, proxy)
+		}
+		transport = &retryBudgetTransport{base: transport}
+	}
 
-		transport := &http.Transport{
-			Proxy: http.ProxyFromEnvironment,
-			DialContext: (&net.Dialer{
-				Timeout:   30 * time.Second,
-				KeepAlive: 30 * time.Second,
-			}).DialContext,
-			TLSClientConfig:       tlsConfig,
-			ForceAttemptHTTP2:     true,
-			MaxIdleConns:          100,
-			IdleConnTimeout:       180 * time.Second,
-			TLSHandshakeTimeout:   10 * time.Second,
-			ExpectContinueTimeout: 1 * time.Second,
-			MaxIdleConnsPerHost:   10,
-			MaxConnsPerHost:       100,
+	if len(d.httpHeaders) > 0 {
+		if transport == nil {
+			transport = d.newPooledTransport(nil, proxy)
+		}
+		transport = &headerTransport{base: transport, headers: d.httpHeaders.Clone()}
+	}
+
+	// Keep queries whose context ends while they start from running on
+	// without a handle to cancel them with
+	if transport == nil {
+		transport = d.newPooledTransport(nil, proxy)
+	}
+	transport = &executeTransport{base: transport}
+	opts = append(opts, dbsql.WithTransport(transport))
+
+	// The REST APIs are called with the same transport and credentials.
+	// Only warehouses have them, so other HTTP paths go without unless
+	// waiting for the warehouse requires it.
+	d.warehouse = nil
+	if d.warehouseWaitForStart > 0 || warehouseHTTPPathRe.MatchString(d.httpPath) {
+		var warehouseAuthr auth.Authenticator
+		if authr != nil {
+			warehouseAuthr = authr
+		}
+		d.warehouse, err = d.newWarehouseClient(transport, d.newDownloadTransport(tlsConfig, proxy), warehouseAuthr)
+		if err != nil {
+			return nil, err
 		}
-		opts = append(opts, dbsql.WithTransport(transport))
 	}
 
 	return opts, nil
 }
 
+// newTLSConfig creates the TLS configuration of the SSL options, or
+// returns nil if they leave the defaults
+func (d *databaseImpl) newTLSConfig() (*tls.Config, error) {
+	if (d.sslClientCertChain == nil) != (d.sslClientPrivateKey == nil) {
+		return nil, adbc.Error{
+			Code: adbc.StatusInvalidArgument,
+			Msg:  fmt.Sprintf("[db] %s and %s must be set together", OptionSSLClientCert, OptionSSLClientKey),
+		}
+	}
+
+	customVerify := d.sslMode == OptionValueSSLModeVerifyCA || d.sslMode == OptionValueSSLModeInsecure
+	d.warnEndpointTLS()
+	if d.sslCertPool == nil && !customVerify && d.sslClientCertChain == nil && d.sslServerName == "" {
+		return nil, nil
+	}
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		// Sent as SNI and checked against the certificate; empty for
+		// the hostname
+		ServerName: d.sslServerName,
+	}
+
+	if d.sslCertPool != nil {
+		tlsConfig.RootCAs = d.sslCertPool
+		if d.sslMode == OptionValueSSLModeSystem {
+			pool, err := systemCertPool(d.sslRootCert)
+			if err != nil {
+				return nil, adbc.Error{
+					Code: adbc.StatusIO,
+					Msg:  fmt.Sprintf("[db] failed to load system root certificates: %v", err),
+				}
+			}
+			tlsConfig.RootCAs = pool
+		}
+	}
+
+	if d.sslClientCertChain != nil {
+		tlsConfig.Certificates = []tls.Certificate{{
+			Certificate: d.sslClientCertChain,
+			PrivateKey:  d.sslClientPrivateKey,
+		}}
+	}
+
+	switch d.sslMode {
+	case OptionValueSSLModeInsecure:
+		tlsConfig.InsecureSkipVerify = true
+	case OptionValueSSLModeVerifyCA:
+		// Replace the default verification with one that skips the
+		// host name check
+		tlsConfig.InsecureSkipVerify = true
+		tlsConfig.VerifyConnection = verifyCertificateChain(tlsConfig.RootCAs)
+	}
+	return tlsConfig, nil
+}
+
+// newPooledTransport creates an HTTP transport with the same settings as
+// databricks-sql-go's PooledTransport, but for the connect timeout and
+// the tuning options set
+func (d *databaseImpl) newPooledTransport(tlsConfig *tls.Config, proxy func(*http.Request) (*url.URL, error)) *http.Transport {
+	connectTimeout := d.connectTimeout
+	if connectTimeout <= 0 {
+		connectTimeout = 30 * time.Second
+	}
+	keepAlive := 30 * time.Second
+	if d.tcpKeepAliveSet {
+		// A negative interval disables the probes
+		keepAlive = cmp.Or(d.tcpKeepAlive, -1)
+	}
+	maxIdlePerHost := cmp.Or(d.maxIdleConnsPerHost, 10)
+	transport := &http.Transport{
+		Proxy: proxy,
+		DialContext: (&net.Dialer{
+			Timeout:   connectTimeout,
+			KeepAlive: keepAlive,
+		}).DialContext,
+		TLSClientConfig:       tlsConfig,
+		ForceAttemptHTTP2:     !d.http2Disabled,
+		MaxIdleConns:          max(100, maxIdlePerHost),
+		IdleConnTimeout:       180 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+		MaxIdleConnsPerHost:   maxIdlePerHost,
+		MaxConnsPerHost:       max(100, maxIdlePerHost),
+	}
+	if d.http2Disabled {
+		// A non-nil map keeps the transport from negotiating HTTP/2
+		transport.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	}
+	return transport
+}
+
+// newDownloadTransport creates the transport for downloading results from
+// cloud storage by presigned links: with the TLS and proxy settings, but
+// not the server name of the workspace, nor the headers, retries and
+// timeouts of requests to it
+func (d *databaseImpl) newDownloadTransport(tlsConfig *tls.Config, proxy func(*http.Request) (*url.URL, error)) http.RoundTripper {
+	if tlsConfig != nil {
+		tlsConfig = tlsConfig.Clone()
+		tlsConfig.ServerName = ""
+	}
+	return d.newPooledTransport(tlsConfig, proxy)
+}
+
+// tunesTransport returns whether options tune the HTTP transport, which
+// databricks-sql-go's own transport would not apply
+func (d *databaseImpl) tunesTransport() bool {
+	return d.http2Disabled || d.maxIdleConnsPerHost > 0 || d.tcpKeepAliveSet
+}
+
 func (d *databaseImpl) initializeConnectionPool(ctx context.Context) (*sql.DB, error) {
 	var db *sql.DB
+	d.warehouseType = OptionValueWarehouseTypeUnknown
+	d.metadataThrottle = newMetadataThrottle(d.metadataMaxConcurrency, d.metadataMaxRetries, d.metadataBackoff, d.Logger)
 
 	// Use URI if provided
 	if d.uri != "" {
+		// Running as the authenticated identity instead would bypass the
+		// security of the impersonated user
+		if d.impersonateUser != "" {
+			return nil, adbc.Error{
+				Code: adbc.StatusInvalidArgument,
+				Msg:  fmt.Sprintf("%s is not supported with %s", OptionImpersonateUser, adbc.OptionKeyURI),
+			}
+		}
+		// Results would silently depend on the workspace's defaults
+		for _, setting := range [][2]string{{OptionSessionANSIMode, d.ansiMode}, {OptionSessionDefaultCollation, d.defaultCollation}} {
+			if setting[1] != "" {
+				return nil, adbc.Error{
+					Code: adbc.StatusInvalidArgument,
+					Msg:  fmt.Sprintf("%s is not supported with %s; set it as a parameter of the URI", setting[0], adbc.OptionKeyURI),
+				}
+			}
+		}
 		var err error
 		db, err = sql.Open("databricks", d.uri)
 		if err != nil {
@@ -203,12 +559,52 @@ func (d *databaseImpl) initializeConnectionPool(ctx context.Context) (*sql.DB, e
 			return nil, err
 		}
 
-		connector, err := dbsql.NewConnector(opts...)
+		var connector driver.Connector
+		connector, err = dbsql.NewConnector(opts...)
 		if err != nil {
 			return nil, err
 		}
+		if len(d.failoverHTTPPaths) > 0 {
+			failover := &failoverConnector{
+				connectors: []driver.Connector{connector},
+				paths:      []string{d.httpPath},
+				logger:     d.Logger,
+			}
+			for _, path := range d.failoverHTTPPaths {
+				standby, err := dbsql.NewConnector(append(slices.Clip(opts), dbsql.WithHTTPPath(path))...)
+				if err != nil {
+					return nil, err
+				}
+				failover.connectors = append(failover.connectors, standby)
+				failover.paths = append(failover.paths, path)
+			}
+			connector = failover
+		}
 
-		db = sql.OpenDB(connector)
+		// Wait before the ping below opens the first session
+		if err := d.waitForWarehouse(ctx); err != nil {
+			return nil, err
+		}
+		d.warehouseType = d.detectWarehouseType(ctx)
+
+		if d.queryLogger != nil {
+			connector = &queryLoggingConnector{Connector: connector, logger: d.queryLogger, hashParams: d.queryLogHashParams}
+		}
+		if statements := splitSQLStatements(d.initSQL); len(statements) > 0 {
+			connector = &initConnector{Connector: connector, statements: statements}
+		}
+
+		if d.autoReconnect || d.idleCloseAfter > 0 {
+			db = sql.OpenDB(&reconnectingConnector{Connector: connector, logger: d.Logger, reportLost: !d.autoReconnect})
+		} else {
+			db = sql.OpenDB(connector)
+		}
+	}
+
+	d.configurePool(db)
+	if d.lazyConnect {
+		// Pinging would start a session
+		return db, nil
 	}
 
 	// Test the connection
@@ -223,6 +619,39 @@ func (d *databaseImpl) initializeConnectionPool(ctx context.Context) (*sql.DB, e
 	return db, nil
 }
 
+// waitForWarehouse waits for the warehouse of the HTTP path to be running,
+// if OptionWarehouseWaitForStart is set. With standby HTTP paths, a
+// warehouse that does not start is left to the failover.
+func (d *databaseImpl) waitForWarehouse(ctx context.Context) error {
+	if d.warehouse == nil || d.warehouseWaitForStart == 0 {
+		return nil
+	}
+	err := d.warehouse.waitForStart(ctx, d.warehouseWaitForStart)
+	if err != nil && len(d.failoverHTTPPaths) > 0 && ctx.Err() == nil {
+		if d.Logger != nil {
+			d.Logger.Warn("warehouse did not start, failing over", "error", err)
+		}
+		return nil
+	}
+	return err
+}
+
+// detectWarehouseType reads the type of the warehouse of the HTTP path.
+// Failing to is only logged, as connecting does not depend on it.
+func (d *databaseImpl) detectWarehouseType(ctx context.Context) string {
+	if d.warehouse == nil {
+		return OptionValueWarehouseTypeUnknown
+	}
+	details, err := d.warehouse.details(ctx)
+	if err != nil {
+		if d.Logger != nil {
+			d.Logger.Warn("failed to read the SQL warehouse type", "warehouse_id", d.warehouse.warehouseID, "error", err)
+		}
+		return OptionValueWarehouseTypeUnknown
+	}
+	return details.kind()
+}
+
 func (d *databaseImpl) Open(ctx context.Context) (adbc.Connection, error) {
 	// Re-initialize the connection pool and settings if anything
 	// has changed, or we have not initialized yet
@@ -242,26 +671,61 @@ func (d *databaseImpl) Open(ctx context.Context) (adbc.Connection, error) {
 		}
 
 		d.db = db
+	} else if err := d.waitForWarehouse(ctx); err != nil {
+		// The warehouse may have stopped since the pool was created
+		return nil, err
 	}
 
-	c, err := d.db.Conn(ctx)
-
-	if err != nil {
-		return nil, err
+	var c *sql.Conn
+	if !d.lazyConnect {
+		var err error
+		if c, err = d.db.Conn(ctx); err != nil {
+			return nil, err
+		}
 	}
 
 	conn := &connectionImpl{
 		ConnectionImplBase: driverbase.NewConnectionImplBase(&d.DatabaseImplBase),
 		catalog:            d.catalog,
 		dbSchema:           d.schema,
+		slowQueryThreshold: d.slowQueryThreshold,
+		queryLogHashParams: d.queryLogHashParams,
+		retryBudget:        newRetryBudget(d.retryBudgetRetries, d.retryBudgetTime),
+		metadataCache:      d.metadataCache,
+		metadataThrottle:   d.metadataThrottle,
+		strictConversions:  d.strictConversions,
+		exactFilters:       d.exactFilters,
+		queryTimeout:       d.queryTimeout,
+		fetchSize:          d.fetchSize,
+		resultProvenance:   d.resultProvenance,
+		memoryBudget:       newMemoryBudget(d.memoryLimit, d.memoryLimitWait),
+		compute:            httpPathCompute(d.httpPath),
+		warehouseType:      d.warehouseType,
+		queryTags:          d.queryTags,
+		impersonateUser:    d.impersonateUser,
+		sessionSettings:    d.sessionSettings(),
+		maxSQLLength:       d.maxSQLLength,
+		warehouse:          d.warehouse,
 		conn:               c,
 	}
+	if c == nil {
+		conn.pool = d.db
+	}
+	if d.warehouse != nil {
+		conn.usage = &connectionUsage{}
+	}
+	conn.lastUsed.Store(time.Now().UnixNano())
+	if d.keepAliveInterval > 0 {
+		conn.keepAlive = startKeepAlive(conn, d.keepAliveInterval)
+	}
+	if d.idleCloseAfter > 0 {
+		conn.idleClose = startIdleClose(conn, d.idleCloseAfter)
+	}
 
 	return driverbase.NewConnectionBuilder(conn).
 		WithAutocommitSetter(conn).
 		WithCurrentNamespacer(conn).
 		WithTableTypeLister(conn).
-		WithDbObjectsEnumerator(conn).
 		WithDriverInfoPreparer(conn).
 		Connection(), nil
 }
@@ -275,37 +739,197 @@ func (d *databaseImpl) Close() error {
 }
 
 func (d *databaseImpl) GetOption(key string) (string, error) {
-	switch key {
+	switch key = canonicalOption(key); key {
 	case adbc.OptionKeyURI:
 		return d.uri, nil
+	case OptionURI:
+		return d.databricksURI, nil
 	case OptionServerHostname:
+		if d.hostnamePort != 0 {
+			return d.serverHostname + ":" + strconv.Itoa(d.hostnamePort), nil
+		}
 		return d.serverHostname, nil
 	case OptionHTTPPath:
 		return d.httpPath, nil
+	case OptionFailoverHTTPPaths:
+		return strings.Join(d.failoverHTTPPaths, ","), nil
 	case OptionAccessToken:
 		return d.accessToken, nil
+	case OptionProxyURL:
+		if d.proxyURL != nil {
+			return d.proxyURL.String(), nil
+		}
+		return "", nil
+	case OptionProxyUser:
+		return d.proxyUser, nil
+	case OptionProxyPassword:
+		return d.proxyPassword, nil
 	case OptionPort:
 		return strconv.Itoa(d.port), nil
 	case OptionCatalog:
 		return d.catalog, nil
 	case OptionSchema:
 		return d.schema, nil
+	case OptionConnectTimeout:
+		if d.connectTimeout > 0 {
+			return d.connectTimeout.String(), nil
+		}
+		return "", nil
+	case OptionHTTPRequestTimeout:
+		if d.httpRequestTimeout > 0 {
+			return d.httpRequestTimeout.String(), nil
+		}
+		return "", nil
+	case OptionHTTP2:
+		return strconv.FormatBool(!d.http2Disabled), nil
+	case OptionMaxIdleConnsPerHost:
+		if d.maxIdleConnsPerHost > 0 {
+			return strconv.Itoa(d.maxIdleConnsPerHost), nil
+		}
+		return "", nil
+	case OptionTCPKeepAlive:
+		if d.tcpKeepAliveSet {
+			return d.tcpKeepAlive.String(), nil
+		}
+		return "", nil
 	case OptionQueryTimeout:
 		if d.queryTimeout > 0 {
 			return d.queryTimeout.String(), nil
 		}
 		return "", nil
+	case OptionQueryPollInterval:
+		if d.pollInterval > 0 {
+			return d.pollInterval.String(), nil
+		}
+		return "", nil
+	case OptionSlowQueryThreshold:
+		if d.slowQueryThreshold > 0 {
+			return d.slowQueryThreshold.String(), nil
+		}
+		return "", nil
+	case OptionRetryBudgetMaxRetries:
+		if d.retryBudgetRetries > 0 {
+			return strconv.Itoa(d.retryBudgetRetries), nil
+		}
+		return "", nil
+	case OptionRetryBudgetMaxTime:
+		if d.retryBudgetTime > 0 {
+			return d.retryBudgetTime.String(), nil
+		}
+		return "", nil
+	case OptionWarehouseWaitForStart:
+		if d.warehouseWaitForStart > 0 {
+			return d.warehouseWaitForStart.String(), nil
+		}
+		return "", nil
+	case OptionUseCachedResult:
+		return d.useCachedResult, nil
+	case OptionSessionANSIMode:
+		return d.ansiMode, nil
+	case OptionSessionDefaultCollation:
+		return d.defaultCollation, nil
+	case OptionMetadataCacheTTL:
+		if d.metadataCache != nil {
+			return d.metadataCache.ttl.String(), nil
+		}
+		return "", nil
+	case OptionMetadataMaxConcurrency:
+		if d.metadataMaxConcurrency > 0 {
+			return strconv.Itoa(d.metadataMaxConcurrency), nil
+		}
+		return "", nil
+	case OptionMetadataMaxRetries:
+		if d.metadataMaxRetries > 0 {
+			return strconv.Itoa(d.metadataMaxRetries), nil
+		}
+		return "", nil
+	case OptionMetadataBackoff:
+		if d.metadataBackoff > 0 {
+			return d.metadataBackoff.String(), nil
+		}
+		return "", nil
+	case OptionPoolMaxOpenConnections:
+		if d.poolMaxOpen > 0 {
+			return strconv.Itoa(d.poolMaxOpen), nil
+		}
+		return "", nil
+	case OptionPoolMaxIdleConnections:
+		if d.poolMaxIdleSet {
+			return strconv.Itoa(d.poolMaxIdle), nil
+		}
+		return "", nil
+	case OptionPoolIdleTimeout:
+		if d.poolIdleTimeout > 0 {
+			return d.poolIdleTimeout.String(), nil
+		}
+		return "", nil
+	case OptionPoolMaxLifetime:
+		if d.poolMaxLifetime > 0 {
+			return d.poolMaxLifetime.String(), nil
+		}
+		return "", nil
+	case OptionLazyConnect:
+		return strconv.FormatBool(d.lazyConnect), nil
+	case OptionAutoReconnect:
+		return strconv.FormatBool(d.autoReconnect), nil
+	case OptionInitSQL:
+		return d.initSQL, nil
+	case OptionQueryTags:
+		return formatQueryTags(d.queryTags), nil
+	case OptionImpersonateUser:
+		return d.impersonateUser, nil
+	case OptionMaxSQLLength:
+		return strconv.Itoa(d.maxSQLLength), nil
+	case OptionQueryLogParameters:
+		if d.queryLogHashParams {
+			return OptionValueQueryLogParametersHash, nil
+		}
+		return OptionValueQueryLogParametersRedact, nil
+	case OptionStrictConversions:
+		return strconv.FormatBool(d.strictConversions), nil
+	case OptionResultProvenance:
+		return strconv.FormatBool(d.resultProvenance), nil
+	case OptionGetObjectsFilterCase:
+		if d.exactFilters {
+			return OptionValueFilterCaseExact, nil
+		}
+		return OptionValueFilterCaseInsensitive, nil
+	case OptionMemoryLimitBytes:
+		if d.memoryLimit > 0 {
+			return strconv.FormatInt(d.memoryLimit, 10), nil
+		}
+		return "", nil
+	case OptionMemoryLimitWait:
+		if d.memoryLimitWait > 0 {
+			return d.memoryLimitWait.String(), nil
+		}
+		return "", nil
+	case OptionKeepAliveInterval:
+		if d.keepAliveInterval > 0 {
+			return d.keepAliveInterval.String(), nil
+		}
+		return "", nil
+	case OptionIdleCloseAfter:
+		if d.idleCloseAfter > 0 {
+			return d.idleCloseAfter.String(), nil
+		}
+		return "", nil
 	case OptionMaxRows:
 		if d.maxRows > 0 {
 			return strconv.Itoa(d.maxRows), nil
 		}
 		return "", nil
+	case OptionFetchSize:
+		if d.fetchSize > 0 {
+			return strconv.FormatInt(d.fetchSize, 10), nil
+		}
+		return "", nil
 	case OptionQueryRetryCount:
 		if d.queryRetryCount > 0 {
 			return strconv.Itoa(d.queryRetryCount), nil
 		}
 		return "", nil
-	case OptionDownloadThreadCount:
+	case OptionCloudFetchMaxParallelDownloads:
 		if d.downloadThreadCount > 0 {
 			return strconv.Itoa(d.downloadThreadCount), nil
 		}
@@ -314,13 +938,48 @@ func (d *databaseImpl) GetOption(key string) (string, error) {
 		return d.sslMode, nil
 	case OptionSSLRootCert:
 		return d.sslRootCert, nil
+	case OptionSSLClientCert:
+		return d.sslClientCert, nil
+	case OptionSSLClientKey:
+		return d.sslClientKey, nil
+	case OptionSSLServerName:
+		return d.sslServerName, nil
 	case OptionOAuthClientID:
 		return d.oauthClientID, nil
 	case OptionOAuthClientSecret:
 		return d.oauthClientSecret, nil
 	case OptionOAuthRefreshToken:
 		return d.oauthRefreshToken, nil
+	case OptionOAuthTokenEndpoint:
+		return d.oauthTokenEndpoint, nil
+	case OptionOAuthExternalToken:
+		return d.externalToken.get(), nil
+	case OptionOAuthRefreshBeforeExpiry:
+		return d.tokenRefreshBuffer().String(), nil
+	case OptionOAuthIdentityTokenFile:
+		return d.oauthIdentityTokenFile, nil
+	case OptionOAuthIdentityTokenAudience:
+		return d.oauthIdentityTokenAudience, nil
+	case OptionGoogleCredentials:
+		return d.googleCredentials, nil
+	case OptionOAuthRedirectPort:
+		if d.oauthRedirectPort > 0 {
+			return strconv.Itoa(d.oauthRedirectPort), nil
+		}
+		return "", nil
+	case OptionAuthType:
+		return d.authType, nil
+	case OptionEffectiveOptions:
+		return d.effectiveOptions()
 	default:
+		if val, ok := d.authOptions[key]; ok {
+			return val, nil
+		}
+		if name, ok := strings.CutPrefix(key, OptionHTTPHeaderPrefix); ok {
+			if val := d.httpHeaders.Get(name); val != "" {
+				return val, nil
+			}
+		}
 		return d.DatabaseImplBase.GetOption(key)
 	}
 }
@@ -347,7 +1006,16 @@ func (d *databaseImpl) SetOptions(options map[string]string) error {
 		}
 	}
 
+	// Options given individually override those from OptionURI
+	if value, ok := options[OptionURI]; ok {
+		if err := d.SetOption(OptionURI, value); err != nil {
+			return err
+		}
+	}
 	for k, v := range options {
+		if k == OptionURI {
+			continue
+		}
 		err := d.SetOption(k, v)
 		if err != nil {
 			return err
@@ -357,6 +1025,15 @@ func (d *databaseImpl) SetOptions(options map[string]string) error {
 }
 
 func (d *databaseImpl) SetOption(key, value string) error {
+	key = d.resolveOptionAlias(key)
+	switch key {
+	case OptionOAuthExternalToken:
+		return d.setExternalToken(value)
+	case OptionMetadataCacheInvalidate:
+		d.metadataCache.invalidate()
+		return nil
+	}
+
 	// We need to re-initialize the db/connection pool if options change
 	d.needsRefresh = true
 	switch key {
@@ -370,12 +1047,70 @@ func (d *databaseImpl) SetOption(key, value string) error {
 				Msg:  fmt.Sprintf("invalid URI scheme: expected 'databricks://', got '%s'", value),
 			}
 		}
+	case OptionURI:
+		opts, err := ParseDSN(value)
+		if err != nil {
+			return err
+		}
+		// Every option is checked on a new database before any is set, so
+		// that an invalid one leaves this database unchanged
+		scratch := &databaseImpl{
+			DatabaseImplBase: d.DatabaseImplBase,
+			port:             DefaultPort,
+			sslMode:          DefaultSSLMode,
+			maxSQLLength:     DefaultMaxSQLLength,
+		}
+		for k, v := range opts {
+			if err := scratch.SetOption(k, v); err != nil {
+				return err
+			}
+		}
+		for k, v := range opts {
+			if err := d.SetOption(k, v); err != nil {
+				return err
+			}
+		}
+		d.databricksURI = value
 	case OptionServerHostname:
-		d.serverHostname = value
+		host, port, err := parseServerHostname(value)
+		if err != nil {
+			return err
+		}
+		d.serverHostname, d.hostnamePort = host, port
 	case OptionHTTPPath:
-		d.httpPath = value
+		path, err := validateHTTPPath(value)
+		if err != nil {
+			return err
+		}
+		d.httpPath = path
+	case OptionFailoverHTTPPaths:
+		paths := parseHTTPPaths(value)
+		for i, path := range paths {
+			var err error
+			if paths[i], err = validateHTTPPath(path); err != nil {
+				return err
+			}
+		}
+		d.failoverHTTPPaths = paths
 	case OptionAccessToken:
 		d.accessToken = value
+	case OptionProxyURL:
+		if value == "" {
+			d.proxyURL = nil
+			break
+		}
+		proxyURL, err := parseProxyURL(value)
+		if err != nil {
+			return adbc.Error{
+				Code: adbc.StatusInvalidArgument,
+				Msg:  fmt.Sprintf("invalid proxy URL: %v", err),
+			}
+		}
+		d.proxyURL = proxyURL
+	case OptionProxyUser:
+		d.proxyUser = value
+	case OptionProxyPassword:
+		d.proxyPassword = value
 	case OptionPort:
 		port, err := strconv.Atoi(value)
 		if err != nil || port < 1 || port > 65535 {
@@ -389,6 +1124,62 @@ func (d *databaseImpl) SetOption(key, value string) error {
 		d.catalog = value
 	case OptionSchema:
 		d.schema = value
+	case OptionConnectTimeout, OptionHTTPRequestTimeout:
+		var timeout time.Duration
+		if value != "" {
+			var err error
+			timeout, err = time.ParseDuration(value)
+			if err != nil || timeout < 0 {
+				return adbc.Error{
+					Code: adbc.StatusInvalidArgument,
+					Msg:  fmt.Sprintf("invalid value for %s: %s", key, value),
+				}
+			}
+		}
+		if key == OptionConnectTimeout {
+			d.connectTimeout = timeout
+		} else {
+			d.httpRequestTimeout = timeout
+		}
+	case OptionHTTP2:
+		enabled := true
+		if value != "" {
+			var err error
+			if enabled, err = strconv.ParseBool(value); err != nil {
+				return adbc.Error{
+					Code: adbc.StatusInvalidArgument,
+					Msg:  fmt.Sprintf("invalid value for %s: %s", key, value),
+				}
+			}
+		}
+		d.http2Disabled = !enabled
+	case OptionMaxIdleConnsPerHost:
+		if value != "" {
+			conns, err := strconv.Atoi(value)
+			if err != nil || conns < 1 {
+				return adbc.Error{
+					Code: adbc.StatusInvalidArgument,
+					Msg:  fmt.Sprintf("invalid value for %s: %s", key, value),
+				}
+			}
+			d.maxIdleConnsPerHost = conns
+		} else {
+			d.maxIdleConnsPerHost = 0
+		}
+	case OptionTCPKeepAlive:
+		if value != "" {
+			interval, err := time.ParseDuration(value)
+			if err != nil || interval < 0 {
+				return adbc.Error{
+					Code: adbc.StatusInvalidArgument,
+					Msg:  fmt.Sprintf("invalid value for %s: %s", key, value),
+				}
+			}
+			d.tcpKeepAlive = interval
+		} else {
+			d.tcpKeepAlive = 0
+		}
+		d.tcpKeepAliveSet = value != ""
 	case OptionQueryTimeout:
 		if value != "" {
 			timeout, err := time.ParseDuration(value)
@@ -400,6 +1191,334 @@ func (d *databaseImpl) SetOption(key, value string) error {
 			}
 			d.queryTimeout = timeout
 		}
+	case OptionQueryPollInterval:
+		if value != "" {
+			if !pollIntervalSupported() {
+				return adbc.Error{
+					Code: adbc.StatusNotImplemented,
+					Msg:  fmt.Sprintf("%s is not supported by this version of databricks-sql-go", OptionQueryPollInterval),
+				}
+			}
+			interval, err := time.ParseDuration(value)
+			if err != nil || interval <= 0 {
+				return adbc.Error{
+					Code: adbc.StatusInvalidArgument,
+					Msg:  fmt.Sprintf("invalid query poll interval: %s", value),
+				}
+			}
+			d.pollInterval = interval
+		} else {
+			d.pollInterval = 0
+		}
+	case OptionKeepAliveInterval:
+		if value != "" {
+			interval, err := time.ParseDuration(value)
+			if err != nil || interval < 0 {
+				return adbc.Error{
+					Code: adbc.StatusInvalidArgument,
+					Msg:  fmt.Sprintf("invalid keep-alive interval: %s", value),
+				}
+			}
+			d.keepAliveInterval = interval
+		} else {
+			d.keepAliveInterval = 0
+		}
+	case OptionIdleCloseAfter:
+		if value != "" {
+			after, err := time.ParseDuration(value)
+			if err != nil || after < 0 {
+				return adbc.Error{
+					Code: adbc.StatusInvalidArgument,
+					Msg:  fmt.Sprintf("invalid idle close time: %s", value),
+				}
+			}
+			if after > 0 && after < minIdleCloseAfter {
+				return adbc.Error{
+					Code: adbc.StatusInvalidArgument,
+					Msg:  fmt.Sprintf("invalid idle close time: %s is less than %s", value, minIdleCloseAfter),
+				}
+			}
+			d.idleCloseAfter = after
+		} else {
+			d.idleCloseAfter = 0
+		}
+	case OptionSlowQueryThreshold:
+		if value != "" {
+			threshold, err := time.ParseDuration(value)
+			if err != nil || threshold < 0 {
+				return adbc.Error{
+					Code: adbc.StatusInvalidArgument,
+					Msg:  fmt.Sprintf("invalid slow query threshold: %s", value),
+				}
+			}
+			d.slowQueryThreshold = threshold
+		} else {
+			d.slowQueryThreshold = 0
+		}
+	case OptionRetryBudgetMaxRetries:
+		if value != "" {
+			retries, err := strconv.Atoi(value)
+			if err != nil || retries < 0 {
+				return adbc.Error{
+					Code: adbc.StatusInvalidArgument,
+					Msg:  fmt.Sprintf("invalid retry budget max retries: %s", value),
+				}
+			}
+			d.retryBudgetRetries = retries
+		} else {
+			d.retryBudgetRetries = 0
+		}
+	case OptionRetryBudgetMaxTime:
+		if value != "" {
+			budget, err := time.ParseDuration(value)
+			if err != nil || budget < 0 {
+				return adbc.Error{
+					Code: adbc.StatusInvalidArgument,
+					Msg:  fmt.Sprintf("invalid retry budget max time: %s", value),
+				}
+			}
+			d.retryBudgetTime = budget
+		} else {
+			d.retryBudgetTime = 0
+		}
+	case OptionWarehouseWaitForStart:
+		if value != "" {
+			wait, err := time.ParseDuration(value)
+			if err != nil || wait < 0 {
+				return adbc.Error{
+					Code: adbc.StatusInvalidArgument,
+					Msg:  fmt.Sprintf("invalid warehouse wait for start: %s", value),
+				}
+			}
+			d.warehouseWaitForStart = wait
+		} else {
+			d.warehouseWaitForStart = 0
+		}
+	case OptionUseCachedResult:
+		if value != "" {
+			useCached, err := strconv.ParseBool(value)
+			if err != nil {
+				return adbc.Error{
+					Code: adbc.StatusInvalidArgument,
+					Msg:  fmt.Sprintf("invalid value for %s: %s", key, value),
+				}
+			}
+			value = strconv.FormatBool(useCached)
+		}
+		d.useCachedResult = value
+	case OptionSessionANSIMode:
+		if value != "" {
+			enabled, err := strconv.ParseBool(value)
+			if err != nil {
+				return adbc.Error{
+					Code: adbc.StatusInvalidArgument,
+					Msg:  fmt.Sprintf("invalid value for %s: %s", key, value),
+				}
+			}
+			value = strconv.FormatBool(enabled)
+		}
+		d.ansiMode = value
+	case OptionSessionDefaultCollation:
+		value = strings.ToUpper(strings.TrimSpace(value))
+		if value != "" && !collationNameRe.MatchString(value) {
+			return adbc.Error{
+				Code: adbc.StatusInvalidArgument,
+				Msg:  fmt.Sprintf("invalid value for %s: %s (expected a collation name such as UTF8_BINARY, UTF8_LCASE or UNICODE_CI)", key, value),
+			}
+		}
+		d.defaultCollation = value
+	case OptionMetadataCacheTTL:
+		var ttl time.Duration
+		if value != "" {
+			var err error
+			ttl, err = time.ParseDuration(value)
+			if err != nil || ttl < 0 {
+				return adbc.Error{
+					Code: adbc.StatusInvalidArgument,
+					Msg:  fmt.Sprintf("invalid metadata cache TTL: %s", value),
+				}
+			}
+		}
+		// Connections already open keep the cache they were given
+		d.metadataCache = newMetadataCache(ttl)
+	case OptionMetadataMaxConcurrency, OptionMetadataMaxRetries:
+		n := 0
+		if value != "" {
+			var err error
+			n, err = strconv.Atoi(value)
+			if err != nil || n < 0 {
+				return adbc.Error{
+					Code: adbc.StatusInvalidArgument,
+					Msg:  fmt.Sprintf("invalid value for %s: %s", key, value),
+				}
+			}
+		}
+		if key == OptionMetadataMaxConcurrency {
+			d.metadataMaxConcurrency = n
+		} else {
+			d.metadataMaxRetries = n
+		}
+	case OptionMetadataBackoff:
+		var backoff time.Duration
+		if value != "" {
+			var err error
+			backoff, err = time.ParseDuration(value)
+			if err != nil || backoff < 0 {
+				return adbc.Error{
+					Code: adbc.StatusInvalidArgument,
+					Msg:  fmt.Sprintf("invalid metadata backoff: %s", value),
+				}
+			}
+		}
+		d.metadataBackoff = backoff
+	case OptionPoolMaxOpenConnections, OptionPoolMaxIdleConnections:
+		n := 0
+		if value != "" {
+			var err error
+			n, err = strconv.Atoi(value)
+			if err != nil || n < 0 {
+				return adbc.Error{
+					Code: adbc.StatusInvalidArgument,
+					Msg:  fmt.Sprintf("invalid value for %s: %s", key, value),
+				}
+			}
+		}
+		if key == OptionPoolMaxOpenConnections {
+			d.poolMaxOpen = n
+		} else {
+			d.poolMaxIdle = n
+			d.poolMaxIdleSet = value != ""
+		}
+	case OptionLazyConnect:
+		lazy, err := strconv.ParseBool(value)
+		if err != nil {
+			return adbc.Error{
+				Code: adbc.StatusInvalidArgument,
+				Msg:  fmt.Sprintf("invalid value for %s: %s", key, value),
+			}
+		}
+		d.lazyConnect = lazy
+	case OptionAutoReconnect:
+		reconnect, err := strconv.ParseBool(value)
+		if err != nil {
+			return adbc.Error{
+				Code: adbc.StatusInvalidArgument,
+				Msg:  fmt.Sprintf("invalid value for %s: %s", key, value),
+			}
+		}
+		d.autoReconnect = reconnect
+	case OptionInitSQL:
+		d.initSQL = value
+	case OptionQueryTags:
+		tags, err := parseQueryTags(value)
+		if err != nil {
+			return err
+		}
+		d.queryTags = tags
+	case OptionImpersonateUser:
+		d.impersonateUser = strings.TrimSpace(value)
+	case OptionMaxSQLLength:
+		length := DefaultMaxSQLLength
+		if value != "" {
+			var err error
+			length, err = strconv.Atoi(value)
+			if err != nil || length < 0 {
+				return adbc.Error{
+					Code: adbc.StatusInvalidArgument,
+					Msg:  fmt.Sprintf("invalid value for %s: %s", key, value),
+				}
+			}
+		}
+		d.maxSQLLength = length
+	case OptionQueryLogParameters:
+		switch strings.ToLower(value) {
+		case "", OptionValueQueryLogParametersRedact:
+			d.queryLogHashParams = false
+		case OptionValueQueryLogParametersHash:
+			d.queryLogHashParams = true
+		default:
+			return adbc.Error{
+				Code: adbc.StatusInvalidArgument,
+				Msg: fmt.Sprintf("invalid value for %s: %s (supported: '%s', '%s')", key, value,
+					OptionValueQueryLogParametersRedact, OptionValueQueryLogParametersHash),
+			}
+		}
+	case OptionStrictConversions:
+		strict, err := strconv.ParseBool(value)
+		if err != nil {
+			return adbc.Error{
+				Code: adbc.StatusInvalidArgument,
+				Msg:  fmt.Sprintf("invalid value for %s: %s", key, value),
+			}
+		}
+		d.strictConversions = strict
+	case OptionResultProvenance:
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return adbc.Error{
+				Code: adbc.StatusInvalidArgument,
+				Msg:  fmt.Sprintf("invalid value for %s: %s", key, value),
+			}
+		}
+		d.resultProvenance = enabled
+	case OptionGetObjectsFilterCase:
+		switch strings.ToLower(value) {
+		case "", OptionValueFilterCaseInsensitive:
+			d.exactFilters = false
+		case OptionValueFilterCaseExact:
+			d.exactFilters = true
+		default:
+			return adbc.Error{
+				Code: adbc.StatusInvalidArgument,
+				Msg: fmt.Sprintf("invalid value for %s: %s (supported: '%s', '%s')", key, value,
+					OptionValueFilterCaseInsensitive, OptionValueFilterCaseExact),
+			}
+		}
+		// Cached results were filtered the other way
+		d.metadataCache.invalidate()
+	case OptionMemoryLimitBytes:
+		if value != "" {
+			limit, err := strconv.ParseInt(value, 10, 64)
+			if err != nil || limit < 0 {
+				return adbc.Error{
+					Code: adbc.StatusInvalidArgument,
+					Msg:  fmt.Sprintf("invalid value for %s: %s", key, value),
+				}
+			}
+			d.memoryLimit = limit
+		} else {
+			d.memoryLimit = 0
+		}
+	case OptionMemoryLimitWait:
+		if value != "" {
+			wait, err := time.ParseDuration(value)
+			if err != nil || wait < 0 {
+				return adbc.Error{
+					Code: adbc.StatusInvalidArgument,
+					Msg:  fmt.Sprintf("invalid value for %s: %s", key, value),
+				}
+			}
+			d.memoryLimitWait = wait
+		} else {
+			d.memoryLimitWait = 0
+		}
+	case OptionPoolIdleTimeout, OptionPoolMaxLifetime:
+		var duration time.Duration
+		if value != "" {
+			var err error
+			duration, err = time.ParseDuration(value)
+			if err != nil || duration < 0 {
+				return adbc.Error{
+					Code: adbc.StatusInvalidArgument,
+					Msg:  fmt.Sprintf("invalid value for %s: %s", key, value),
+				}
+			}
+		}
+		if key == OptionPoolIdleTimeout {
+			d.poolIdleTimeout = duration
+		} else {
+			d.poolMaxLifetime = duration
+		}
 	case OptionMaxRows:
 		if value != "" {
 			maxRows, err := strconv.Atoi(value)
@@ -411,6 +1530,15 @@ func (d *databaseImpl) SetOption(key, value string) error {
 			}
 			d.maxRows = maxRows
 		}
+	case OptionFetchSize:
+		fetchSize, err := parseFetchSize(value)
+		if err != nil {
+			return adbc.Error{
+				Code: adbc.StatusInvalidArgument,
+				Msg:  fmt.Sprintf("invalid fetch size: %v", err),
+			}
+		}
+		d.fetchSize = fetchSize
 	case OptionQueryRetryCount:
 		if value != "" {
 			retryCount, err := strconv.Atoi(value)
@@ -422,7 +1550,7 @@ func (d *databaseImpl) SetOption(key, value string) error {
 			}
 			d.queryRetryCount = retryCount
 		}
-	case OptionDownloadThreadCount:
+	case OptionCloudFetchMaxParallelDownloads:
 		if value != "" {
 			threadCount, err := strconv.Atoi(value)
 			if err != nil {
@@ -434,44 +1562,39 @@ func (d *databaseImpl) SetOption(key, value string) error {
 			d.downloadThreadCount = threadCount
 		}
 	case OptionSSLMode:
-		if value != "" {
-			lowerValue := strings.ToLower(value)
-			switch lowerValue {
-			case "insecure":
-				d.sslMode = lowerValue
-				d.sslInsecure = true
-			case "require":
-				d.sslMode = lowerValue
-				d.sslInsecure = false
-			default:
-				return adbc.Error{
-					Code: adbc.StatusInvalidArgument,
-					Msg:  fmt.Sprintf("invalid SSL mode: %s (supported: 'require', 'insecure')", value),
-				}
+		switch mode := strings.ToLower(value); mode {
+		case "", OptionValueSSLModeRequire, OptionValueSSLModeVerifyCA, OptionValueSSLModeVerifyFull, OptionValueSSLModeInsecure, OptionValueSSLModeSystem:
+			d.sslMode = mode
+		default:
+			return adbc.Error{
+				Code: adbc.StatusInvalidArgument,
+				Msg: fmt.Sprintf("invalid SSL mode: %s (supported: '%s', '%s', '%s', '%s', '%s')", value,
+					OptionValueSSLModeRequire, OptionValueSSLModeVerifyCA, OptionValueSSLModeVerifyFull, OptionValueSSLModeInsecure, OptionValueSSLModeSystem),
+			}
+		}
+	case OptionSSLServerName:
+		if value != "" && !hostnameRe.MatchString(value) {
+			return adbc.Error{
+				Code: adbc.StatusInvalidArgument,
+				Msg:  fmt.Sprintf("invalid %s '%s': not a valid hostname", OptionSSLServerName, value),
 			}
-		} else {
-			d.sslMode = value
-			d.sslInsecure = false
 		}
+		d.sslServerName = value
 	case OptionSSLRootCert:
 		if value != "" {
-			// Validate that the certificate file exists and can be read.
-			// Then, store valid cert.
-
-			caCert, err := os.ReadFile(value)
+			// Validate that the certificates can be read and parsed, from
+			// files, directories or inline PEM/DER. Then, store them.
+			certs, err := loadRootCertificates(value)
 			if err != nil {
 				return adbc.Error{
 					Code: adbc.StatusInvalidArgument,
-					Msg:  fmt.Sprintf("failed to read SSL root certificate: %v", err),
+					Msg:  fmt.Sprintf("failed to load SSL root certificates: %v", err),
 				}
 			}
 
 			caCertPool := x509.NewCertPool()
-			if !caCertPool.AppendCertsFromPEM(caCert) {
-				return adbc.Error{
-					Code: adbc.StatusInvalidArgument,
-					Msg:  "failed to parse SSL root certificate",
-				}
+			for _, cert := range certs {
+				caCertPool.AddCert(cert)
 			}
 
 			d.sslRootCert = value
@@ -480,14 +1603,120 @@ func (d *databaseImpl) SetOption(key, value string) error {
 			d.sslRootCert = value
 			d.sslCertPool = nil
 		}
+	case OptionSSLClientCert:
+		d.sslClientCertChain = nil
+		if value != "" {
+			data, err := loadCertificateData(value)
+			if err != nil {
+				return adbc.Error{
+					Code: adbc.StatusInvalidArgument,
+					Msg:  fmt.Sprintf("failed to read SSL client certificate: %v", err),
+				}
+			}
+			certs, err := parseCertificates(data)
+			if err != nil {
+				return adbc.Error{
+					Code: adbc.StatusInvalidArgument,
+					Msg:  fmt.Sprintf("failed to parse SSL client certificate: %v", err),
+				}
+			}
+			for _, cert := range certs {
+				d.sslClientCertChain = append(d.sslClientCertChain, cert.Raw)
+			}
+		}
+		d.sslClientCert = value
+	case OptionSSLClientKey:
+		d.sslClientPrivateKey = nil
+		if value != "" {
+			data, err := loadCertificateData(value)
+			if err != nil {
+				return adbc.Error{
+					Code: adbc.StatusInvalidArgument,
+					Msg:  fmt.Sprintf("failed to read SSL client key: %v", err),
+				}
+			}
+			key, err := parsePrivateKey(data)
+			if err != nil {
+				return adbc.Error{
+					Code: adbc.StatusInvalidArgument,
+					Msg:  fmt.Sprintf("failed to parse SSL client key: %v", err),
+				}
+			}
+			d.sslClientPrivateKey = key
+		}
+		d.sslClientKey = value
 	case OptionOAuthClientID:
 		d.oauthClientID = value
 	case OptionOAuthClientSecret:
 		d.oauthClientSecret = value
 	case OptionOAuthRefreshToken:
 		d.oauthRefreshToken = value
+	case OptionOAuthTokenEndpoint:
+		d.oauthTokenEndpoint = value
+	case OptionOAuthRefreshBeforeExpiry:
+		if value == "" {
+			d.oauthRefreshBeforeExpiry = 0
+			break
+		}
+		buffer, err := time.ParseDuration(value)
+		if err != nil || buffer <= 0 {
+			return adbc.Error{
+				Code: adbc.StatusInvalidArgument,
+				Msg:  fmt.Sprintf("invalid OAuth refresh before expiry: %s", value),
+			}
+		}
+		d.oauthRefreshBeforeExpiry = buffer
+	case OptionOAuthIdentityTokenFile:
+		d.oauthIdentityTokenFile = value
+	case OptionOAuthIdentityTokenAudience:
+		d.oauthIdentityTokenAudience = value
+	case OptionGoogleCredentials:
+		d.googleCredentials = value
+	case OptionOAuthRedirectPort:
+		if value == "" {
+			d.oauthRedirectPort = 0
+			break
+		}
+		port, err := strconv.Atoi(value)
+		if err != nil || port < 0 || port > 65535 {
+			return adbc.Error{
+				Code: adbc.StatusInvalidArgument,
+				Msg:  fmt.Sprintf("invalid OAuth redirect port: %s", value),
+			}
+		}
+		d.oauthRedirectPort = port
+	case OptionAuthType:
+		authType, err := parseAuthType(value)
+		if err != nil {
+			return err
+		}
+		d.authType = authType
 	default:
+		if strings.HasPrefix(key, OptionHTTPHeaderPrefix) {
+			return d.setHTTPHeader(key, value)
+		}
+		if strings.HasPrefix(key, OptionAuthPrefix) {
+			if d.authOptions == nil {
+				d.authOptions = map[string]string{}
+			}
+			d.authOptions[key] = value
+			return nil
+		}
 		return d.DatabaseImplBase.SetOption(key, value)
 	}
 	return nil
 }
+
+// setExternalToken replaces the external OAuth token. Connections opened
+// with a previous token use the new one from their next request, so the
+// connection pool is only rebuilt when the token is first set or cleared.
+func (d *databaseImpl) setExternalToken(token string) error {
+	if d.externalToken == nil {
+		d.externalToken = &externalTokenAuthenticator{}
+	}
+	if (d.externalToken.get() == "") != (token == "") {
+		d.needsRefresh = true
+	}
+	d.externalToken.set(token)
+	return nil
+}
@@ -43,8 +43,15 @@ import (
 	"github.com/rs/zerolog"
 )
 
+// Numeric options may also be set and read with the typed option methods
+// of adbc.GetSetOptions. Options taking Go durations are then in seconds.
 const (
-	// Connection options
+	// Connection options. The hostname may start with https:// and end
+	// with a port, as in gateway.internal:8443 for private link setups
+	// serving the workspace on another port; OptionPort, if also set, must
+	// match it. IPv6 addresses are given in brackets when followed by a
+	// port, as in [fd00::10]:8443. The HTTP path is only the path, without
+	// host or query.
 	OptionServerHostname = "databricks.server_hostname"
 	OptionHTTPPath       = "databricks.http_path"
 	OptionAccessToken    = "databricks.access_token"
@@ -52,24 +59,608 @@ const (
 	OptionCatalog        = "databricks.catalog"
 	OptionSchema         = "databricks.schema"
 
+	// Read-only connection options: the catalog and schema the session
+	// started in, which are the warehouse's defaults unless set with the
+	// options above. They are also the current catalog and schema until
+	// changed through the connection, or by a USE, SET CATALOG or SET
+	// SCHEMA statement, after which the session is asked again.
+	OptionSessionDefaultCatalog  = "databricks.session.default_catalog"
+	OptionSessionDefaultDbSchema = "databricks.session.default_db_schema"
+
+	// Read-only connection options totaling the server-reported cost of
+	// the statements the connection's statements ran, including bulk
+	// ingests, deletes and submitted statements but not metadata queries:
+	// how many are counted, the bytes they read, and their task time in
+	// milliseconds, from which DBU use can be estimated. Metrics come from
+	// the query history once a statement is done, which can take a few
+	// minutes; statements still awaiting them, up to 10000, are counted in
+	// OptionConnectionUsagePendingCount. Reading an option asks the query
+	// history for them. Requires the HTTP path of a SQL warehouse; not
+	// supported with adbc.OptionKeyURI.
+	OptionConnectionUsageStatementCount = "databricks.connection.usage.statement_count"
+	OptionConnectionUsageReadBytes      = "databricks.connection.usage.read_bytes"
+	OptionConnectionUsageTaskTimeMs     = "databricks.connection.usage.task_time_ms"
+	OptionConnectionUsagePendingCount   = "databricks.connection.usage.pending_count"
+
+	// Comma-separated HTTP paths of standby warehouses, tried in order when
+	// a session cannot be opened on OptionHTTPPath, or fails its first
+	// query, with an error another warehouse may not have (the warehouse is
+	// unreachable, overloaded, or does not start). Rejected credentials and
+	// untrusted certificates are reported without failing over. New
+	// sessions keep using the endpoint that last worked. Each new session
+	// runs a trivial query to check its endpoint. Not used with
+	// adbc.OptionKeyURI.
+	OptionFailoverHTTPPaths = "databricks.failover_http_paths"
+
+	// Connection string setting the options above, see ParseDSN.
+	// Options set alongside it in the same call take precedence. Unlike
+	// adbc.OptionKeyURI, whose URI is handed to databricks-sql-go as is
+	// and cannot be combined with other options, it is split into the
+	// driver's own options, and its query parameters are option names.
+	// Each is checked before any is set, so an invalid URI changes
+	// nothing.
+	OptionURI = "databricks.uri"
+
+	// Read-only database option listing the options in effect as a JSON
+	// object of option names to values, for debugging. Unset options are
+	// left out, defaults the driver resolves are included, and secrets such
+	// as tokens, passwords and inline keys read "<redacted>".
+	OptionEffectiveOptions = "databricks.options"
+
+	// Timeouts, as Go durations, for establishing a TCP connection to the
+	// workspace (default 30s), and for each HTTP request to it including
+	// reading its response (default unlimited beyond the 15 minutes
+	// databricks-sql-go allows Thrift requests). Not supported when
+	// connecting with adbc.uri.
+	OptionConnectTimeout     = "databricks.connect_timeout"
+	OptionHTTPRequestTimeout = "databricks.http.request_timeout"
+	// Tuning of the HTTP client for the workspace, for workloads running
+	// many statements at once: whether HTTP/2 is used when the server
+	// offers it (default true), the most idle connections kept per host
+	// (default 10), and the interval of TCP keep-alive probes as a Go
+	// duration (default 30s; 0 disables them). As CloudFetch downloads
+	// from cloud storage cannot use them, setting any of these fetches
+	// results through the workspace instead. Not supported when
+	// connecting with adbc.uri.
+	OptionHTTP2               = "databricks.http.http2"
+	OptionMaxIdleConnsPerHost = "databricks.http.max_idle_conns_per_host"
+	OptionTCPKeepAlive        = "databricks.http.tcp_keep_alive"
+
 	// Query options
-	OptionQueryTimeout        = "databricks.query.timeout"
-	OptionMaxRows             = "databricks.query.max_rows"
-	OptionQueryRetryCount     = "databricks.query.retry_count"
+	//
+	// OptionQueryTimeout is the longest a statement's query may run, as a Go
+	// duration; empty or 0 for no limit. Set on the database, it is the
+	// default of its statements, which may set their own, and is also
+	// enforced by the server. It bounds each ExecuteQuery, ExecuteUpdate and
+	// ExecutePartitions until the results are ready, not while they are
+	// read. A query running longer is canceled and fails with
+	// adbc.StatusTimeout.
+	OptionQueryTimeout    = "databricks.query.timeout"
+	OptionMaxRows         = "databricks.query.max_rows"
+	OptionQueryRetryCount = "databricks.query.retry_count"
+	// OptionFetchSize is the most rows in each batch of results returned
+	// by ExecuteQuery and ReadPartition; empty or 0 for the batches the
+	// server returns. Larger batches are split, sharing their memory, so
+	// consumers process results in smaller steps. Set on the database, it
+	// is also the number of rows requested from the server at a time,
+	// unless OptionMaxRows is set, and the default of its statements,
+	// which may set their own.
+	OptionFetchSize = "databricks.query.fetch_size"
+	// Number of result chunks downloaded from cloud storage at once
+	OptionCloudFetchMaxParallelDownloads = "databricks.cloudfetch.max_parallel_downloads"
+	// Deprecated: Use OptionCloudFetchMaxParallelDownloads, which this is
+	// an alias of. Setting it logs a warning.
 	OptionDownloadThreadCount = "databricks.download_thread_count"
+	// Interval between status polls of a running query, as a Go duration
+	// (default 1s). The server already holds the execute request open
+	// until short queries finish, so this mainly affects longer ones.
+	OptionQueryPollInterval = "databricks.query.poll_interval"
+	// Statements running at least this long, as a Go duration, are logged
+	// at warning level with their query ID, timings and row count, and
+	// the start of their SQL with string literals redacted or hashed as
+	// set by OptionQueryLogParameters
+	OptionSlowQueryThreshold = "databricks.slow_query_threshold"
+	// Retry budget shared by all statements on a connection: the most
+	// retries, and the most time as a Go duration spent waiting to retry,
+	// before further retries fail immediately. A successful request
+	// restores the budget.
+	OptionRetryBudgetMaxRetries = "databricks.retry_budget.max_retries"
+	OptionRetryBudgetMaxTime    = "databricks.retry_budget.max_time"
+	// Longest time, as a Go duration, that opening a connection waits for
+	// the SQL warehouse to be running, starting it if it is stopped.
+	// Progress is logged at info level. Unset or 0 connects immediately,
+	// leaving the first query to wait for the warehouse.
+	OptionWarehouseWaitForStart = "databricks.warehouse.wait_for_start"
+	// Deprecated: Use OptionWarehouseWaitForStart, which this is an alias
+	// of. Setting it logs a warning.
+	OptionWarehouseWaitStartTimeout = "databricks.warehouse.wait_start_timeout"
+	// Read-only connection option: the type of the SQL warehouse, read
+	// from the SQL warehouses API when the database opens its first
+	// connection. unknown if the HTTP path is not a warehouse's, the
+	// database connects with adbc.uri, or the type could not be read.
+	OptionWarehouseType = "databricks.warehouse.type"
+	// Whether statements may return results cached from an earlier run of
+	// the same query: "true" or "false". Unset leaves the warehouse's
+	// default, which is to use the cache. Sets use_cached_result on each
+	// session; only SQL warehouses have a result cache, so it is rejected
+	// for all-purpose clusters.
+	OptionUseCachedResult = "databricks.use_cached_result"
+	// Session settings affecting how strings compare and sort and how
+	// expressions evaluate, for results that do not depend on the
+	// workspace's defaults. Unset leaves the defaults.
+	// OptionSessionANSIMode, "true" or "false", sets ANSI_MODE on
+	// warehouses and spark.sql.ansi.enabled on all-purpose clusters.
+	// OptionSessionDefaultCollation is the collation of string literals and
+	// of string columns created without one, such as UTF8_BINARY,
+	// UTF8_LCASE, UNICODE or UNICODE_CI; collations are in preview and
+	// require Databricks Runtime 16.1 or above. Neither applies to
+	// statements submitted with OptionStatementSubmitAsync, and neither is
+	// supported with adbc.OptionKeyURI, whose own parameters set them.
+	OptionSessionANSIMode         = "databricks.session.ansi_mode"
+	OptionSessionDefaultCollation = "databricks.session.default_collation"
+
+	// Values for OptionWarehouseType
+	OptionValueWarehouseTypeServerless = "serverless"
+	OptionValueWarehouseTypePro        = "pro"
+	OptionValueWarehouseTypeClassic    = "classic"
+	OptionValueWarehouseTypeUnknown    = "unknown"
+	// How long, as a Go duration, GetObjects and GetTableSchema results are
+	// cached and shared by the connections of a database. Unset or 0
+	// disables caching. DDL does not invalidate the cache; setting
+	// OptionMetadataCacheInvalidate, to any value, on the database or a
+	// connection drops the cached results.
+	OptionMetadataCacheTTL        = "databricks.metadata_cache.ttl"
+	OptionMetadataCacheInvalidate = "databricks.metadata_cache.invalidate"
+	// Limits of the metadata queries of GetObjects, GetTableSchema and
+	// the other metadata calls, independent of the retries of statements.
+	// At most max_concurrency such queries run at once across the
+	// connections of a database; while the workspace rate limits them,
+	// the limit is halved, then grows back as queries succeed. A query
+	// failed by rate limiting runs again up to max_retries times, waiting
+	// backoff, a Go duration (default 1s), doubled for each retry, or as
+	// long as the workspace asks. Unset or 0 means no limit and no
+	// retries.
+	OptionMetadataMaxConcurrency = "databricks.metadata.max_concurrency"
+	OptionMetadataMaxRetries     = "databricks.metadata.max_retries"
+	OptionMetadataBackoff        = "databricks.metadata.backoff"
+	// How GetObjects catalog, schema, table and column filters match
+	// names. insensitive (the default) ignores case, as Databricks does
+	// for unquoted identifiers; exact matches case, so "Orders" does not
+	// find the table orders.
+	OptionGetObjectsFilterCase = "databricks.get_objects.filter_case"
+
+	// Values for OptionGetObjectsFilterCase
+	OptionValueFilterCaseInsensitive = "insensitive"
+	OptionValueFilterCaseExact       = "exact"
+
+	// Most Arrow memory, in bytes, that the result batches of a
+	// connection's readers may retain together. Once reached, reading a
+	// batch waits up to OptionMemoryLimitWait, a Go duration, for other
+	// readers to release theirs, then fails with StatusInternal and
+	// SQLSTATE 53200. Unset or 0 means no limit; unset wait fails
+	// immediately.
+	OptionMemoryLimitBytes = "databricks.memory_limit_bytes"
+	OptionMemoryLimitWait  = "databricks.memory_limit_wait"
+
+	// Connection pool options. Each open connection holds one warehouse
+	// session; once the maximum is reached, opening another waits until
+	// one is closed or the context ends. Closed connections keep their
+	// session idle for reuse, up to the idle maximum (default 2, 0 keeps
+	// none). The timeout and lifetime are Go durations; unset or 0 means
+	// no limit.
+	OptionPoolMaxOpenConnections = "databricks.pool.max_open_connections"
+	OptionPoolMaxIdleConnections = "databricks.pool.max_idle_connections"
+	OptionPoolIdleTimeout        = "databricks.pool.idle_timeout"
+	OptionPoolMaxLifetime        = "databricks.pool.max_lifetime"
+	// When "true", opening a connection does not start a warehouse
+	// session; it is started when the connection is first used to run a
+	// query or read metadata. Invalid credentials or hosts are then only
+	// reported on first use.
+	OptionLazyConnect = "databricks.lazy_connect"
+	// When "true", a session the server no longer knows, as after a
+	// warehouse restart, is replaced with a new one and the statement is
+	// run again, instead of failing with an invalid session handle error.
+	// SET and USE statements run on the old session are replayed on the
+	// new one, the latest for each setting. Not supported when connecting
+	// with adbc.uri.
+	OptionAutoReconnect = "databricks.auto_reconnect"
+	// Semicolon-separated SQL statements run on each new session before it
+	// is used, such as USE CATALOG, CREATE TEMPORARY FUNCTION or SET
+	// query_tags. Sessions opened later by the pool or by
+	// OptionAutoReconnect run them too. If one fails, the session is
+	// closed and the error reported. Not supported when connecting with
+	// adbc.uri.
+	OptionInitSQL = "databricks.init_sql"
+	// Comma-separated key:value tags, such as "team:finance,app:etl",
+	// attributing every statement of a connection, as in the query
+	// history and billing system tables. A tag may omit the value. Set on
+	// the database, the tags apply to each session opened; set on a
+	// connection, they replace the tags of its session. Statements
+	// submitted with OptionStatementSubmitAsync carry them too. The tags
+	// of the database are not applied when connecting with adbc.uri.
+	OptionQueryTags = "databricks.query_tags"
+	// User, such as alice@example.com, that the statements of every
+	// connection run as, when the authenticated service principal may
+	// impersonate users; Unity Catalog row filters, column masks and
+	// grants then apply as for that user. Sent as the
+	// hive.server2.proxy.user session configuration; opening a session
+	// fails if the principal may not impersonate the user. Statements
+	// cannot be submitted with OptionStatementSubmitAsync, which runs
+	// them as the principal. Not supported when connecting with adbc.uri.
+	OptionImpersonateUser = "databricks.impersonate_user"
+	// Longest SQL text, in bytes, that a connection sends to the warehouse
+	// (default DefaultMaxSQLLength, the server's limit); 0 disables the
+	// check. Longer statements fail with StatusInvalidArgument naming
+	// their size, and deleting by keys matches fewer keys per statement
+	// than OptionStatementDeleteBatchSize where needed to stay within it.
+	OptionMaxSQLLength = "databricks.max_sql_length"
+	// How a QueryLogger set with WithQueryLogger receives parameter
+	// values: redact (the default) replaces each with "<redacted>", and
+	// hash with the SHA-256 of its text, so that equal values can be
+	// matched without being revealed. Null values are reported as NULL.
+	// The string literals of statements in the slow query log are
+	// rendered the same way.
+	OptionQueryLogParameters = "databricks.query_log.parameters"
+
+	// Values for OptionQueryLogParameters
+	OptionValueQueryLogParametersRedact = "redact"
+	OptionValueQueryLogParametersHash   = "hash"
+	// Interval, as a Go duration (e.g. "10m"), after which an idle
+	// connection runs a trivial query so the server does not close its
+	// session. Empty or 0 disables the keep-alive.
+	OptionKeepAliveInterval = "databricks.keep_alive_interval"
+	// Time, as a Go duration, after which the session of an idle
+	// connection is closed, so that it does not keep the warehouse from
+	// auto-stopping. The connection stays open, and the next statement
+	// runs in a new session, with the SET and USE statements of the old
+	// one replayed. Sessions are not closed while a result reader is open
+	// or autocommit is disabled, nor once they have created temporary
+	// views or functions or declared variables, which would be lost.
+	// Empty or 0 keeps sessions open; otherwise it must be at least 1s.
+	// Not supported when connecting with adbc.uri.
+	OptionIdleCloseAfter = "databricks.idle_close_after"
+	// When "true", bulk ingestion and deletion fail instead of writing a
+	// value that would lose information in the target column, such as a
+	// uint64 above the BIGINT range, a decimal that overflows or would be
+	// rounded, or a timestamp with nanoseconds. The error names the column.
+	// Each batch of bound data is checked before any of its rows is written.
+	OptionStrictConversions = "databricks.strict_conversions"
+	// When "true", the schemas of query results carry metadata recording
+	// where the results came from, so that files written from them keep
+	// it: MetadataKeyQueryID, MetadataKeyExecutedAt, MetadataKeyDriverVersion,
+	// and MetadataKeyWarehouseID or MetadataKeyClusterID from the HTTP path.
+	// Keys already in the schema metadata are kept.
+	OptionResultProvenance = "databricks.result_provenance"
+
+	// TLS/SSL options. Certificates and keys may be given as a file path,
+	// inline PEM text, or base64-encoded DER. OptionSSLRootCert also takes
+	// several files or directories separated by the OS path list separator
+	// (':' on Unix) and replaces the system roots for the workspace
+	// endpoint. As CloudFetch downloads from cloud storage cannot use
+	// these options, setting any of them fetches results through the
+	// workspace instead.
+	OptionSSLMode       = "databricks.ssl_mode"
+	OptionSSLRootCert   = "databricks.ssl_root_cert"
+	OptionSSLClientCert = "databricks.ssl_client_cert"
+	OptionSSLClientKey  = "databricks.ssl_client_key"
+	// Name sent as SNI and checked against the workspace certificate
+	// instead of the hostname, for private DNS setups reaching the
+	// workspace through an internal load balancer or by IP address. A
+	// warning is logged when set, as a wrong name weakens the check.
+	OptionSSLServerName = "databricks.ssl_server_name"
+
+	// Values for OptionSSLMode. require (the default) and verify-full check
+	// the server certificate chain and host name, verify-ca checks only the
+	// chain, and insecure disables verification for test environments.
+	// system checks the chain and host name against the OS trust store:
+	// the certificate store on Windows and the keychain on macOS, so
+	// enterprise CAs deployed there are trusted without a PEM bundle.
+	// With system, OptionSSLRootCert adds to the OS roots instead of
+	// replacing them. On other platforms the OS roots are the system
+	// bundle files, as with require.
+	OptionValueSSLModeRequire    = "require"
+	OptionValueSSLModeVerifyCA   = "verify-ca"
+	OptionValueSSLModeVerifyFull = "verify-full"
+	OptionValueSSLModeInsecure   = "insecure"
+	OptionValueSSLModeSystem     = "system"
+
+	// Proxy for requests to the workspace, as an http, https or socks5 URL.
+	// Hosts listed in the NO_PROXY environment variable bypass it. When no
+	// URL is set, HTTP_PROXY and HTTPS_PROXY from the environment are used.
+	// As CloudFetch downloads from cloud storage cannot use a proxy URL,
+	// setting one fetches results through the workspace instead.
+	OptionProxyURL      = "databricks.proxy.url"
+	OptionProxyUser     = "databricks.proxy.user"
+	OptionProxyPassword = "databricks.proxy.password"
+	// Options with this prefix add the HTTP header named by the rest of the
+	// key to every request to the workspace, such as cost attribution or
+	// gateway headers. They are not sent to cloud storage with CloudFetch.
+	OptionHTTPHeaderPrefix = "databricks.http.header."
+
+	// Authentication options
+	OptionAuthType = "databricks.auth_type"
+	// Database options with this prefix that the driver does not recognize
+	// are passed to auth types added with RegisterAuthType
+	OptionAuthPrefix = "databricks.auth."
+
+	// OAuth options
+	OptionOAuthClientID      = "databricks.oauth.client_id"
+	OptionOAuthClientSecret  = "databricks.oauth.client_secret"
+	OptionOAuthRefreshToken  = "databricks.oauth.refresh_token"
+	OptionOAuthTokenEndpoint = "databricks.oauth.token_endpoint"
+	OptionOAuthRedirectPort  = "databricks.oauth.redirect_port"
+	// How long before expiry OAuth tokens are renewed, as a Go duration
+	// (default 5m). Raise it when long result downloads outlive tokens.
+	OptionOAuthRefreshBeforeExpiry = "databricks.oauth.refresh_before_expiry"
+	// An OAuth access token obtained by the application. Setting it again
+	// on the database replaces the token used by open connections; a
+	// request rejected with 401 is retried once if a new token was set.
+	OptionOAuthExternalToken = "databricks.oauth.external_token"
+	// Workload identity federation: a file holding the workload's OIDC
+	// token (re-read on every exchange), and the audience requested for
+	// GitHub Actions tokens (default: the token endpoint URL)
+	OptionOAuthIdentityTokenFile     = "databricks.oauth.identity_token_file"
+	OptionOAuthIdentityTokenAudience = "databricks.oauth.identity_token_audience"
+	// Google service account key for OptionValueAuthTypeGoogleCredentials,
+	// either a path to the JSON key file or the JSON itself
+	OptionGoogleCredentials = "databricks.google.credentials"
+
+	// Values for OptionAuthType
+	OptionValueAuthTypePAT      = "pat"
+	OptionValueAuthTypeOAuthM2M = "oauth-m2m"
+	OptionValueAuthTypeOAuthU2M = "oauth-u2m"
+	// Azure managed identity; OptionOAuthClientID selects a user-assigned
+	// identity and OptionOAuthTokenEndpoint overrides the IMDS endpoint
+	OptionValueAuthTypeAzureMSI = "azure-msi"
+	// Use OptionOAuthExternalToken as-is
+	OptionValueAuthTypeOAuthExternal = "oauth-external"
+	// Exchange the workload's OIDC token for a Databricks token; set
+	// OptionOAuthClientID to the service principal of the federation policy
+	OptionValueAuthTypeOAuthFederation = "oauth-federation"
+	// Google service account on GCP workspaces, from OptionGoogleCredentials
+	// or Application Default Credentials: GOOGLE_APPLICATION_CREDENTIALS,
+	// then the metadata server. OptionOAuthTokenEndpoint overrides the
+	// Google token endpoint or metadata server URL.
+	OptionValueAuthTypeGoogleCredentials = "google-credentials"
+
+	// Statement options for bulk ingestion, in addition to the standard
+	// ADBC ingest options. When enabled, null values bound for columns
+	// that declare a DEFAULT are written as DEFAULT rather than NULL.
+	OptionStatementIngestNullAsDefault = "databricks.statement.ingest.null_as_default"
+	// Comma-separated columns declared NOT NULL when the ingest creates
+	// the target table, even if their Arrow fields are nullable
+	OptionStatementIngestNotNullColumns = "databricks.statement.ingest.not_null_columns"
+	// Key identifying one logical ingest, kept the same when it is retried.
+	// Each row is stored with "<key>/<batch>/<row>" in the idempotency
+	// column (default "_adbc_idempotency_key"), and rows whose key is
+	// already in the table are skipped, so retrying after an ambiguous
+	// failure does not duplicate rows as long as the same data is bound.
+	// Tables created by the ingest include the column; existing target
+	// tables must already have it as a STRING column.
+	OptionStatementIngestIdempotencyKey    = "databricks.statement.ingest.idempotency_key"
+	OptionStatementIngestIdempotencyColumn = "databricks.statement.ingest.idempotency_column"
+	// Shadow table also written with the bound data by each ingest, in the
+	// same ingest mode, for validating a new table before switching to it.
+	// The catalog and schema resolve like those of the target table. A
+	// failure writing the shadow table stops writing it but not the target
+	// table, and is reported by OptionStatementIngestShadowError, along
+	// with the rows written in OptionStatementIngestShadowRowCount, rather
+	// than by the ingest.
+	OptionStatementIngestShadowTargetTable    = "databricks.statement.ingest.shadow.target_table"
+	OptionStatementIngestShadowTargetCatalog  = "databricks.statement.ingest.shadow.target_catalog"
+	OptionStatementIngestShadowTargetDbSchema = "databricks.statement.ingest.shadow.target_db_schema"
+	OptionStatementIngestShadowRowCount       = "databricks.statement.ingest.shadow.row_count"
+	OptionStatementIngestShadowError          = "databricks.statement.ingest.shadow.error"
+	// How an ingest handles batches of the bound stream that add fields
+	// after those of the batches before, as upstream CDC schema changes
+	// do: fail (the default) fails the ingest before writing the batch;
+	// add_columns adds the fields to the table as nullable columns, left
+	// NULL in rows already written; ignore writes the batches without
+	// them. Batches that drop, reorder or retype fields always fail. The
+	// fields added by the last ingest are reported, comma-separated, by
+	// OptionStatementIngestAddedColumns.
+	OptionStatementIngestSchemaEvolution = "databricks.statement.ingest.schema_evolution"
+	OptionStatementIngestAddedColumns    = "databricks.statement.ingest.added_columns"
+
+	// Values for OptionStatementIngestSchemaEvolution
+	OptionValueSchemaEvolutionFail       = "fail"
+	OptionValueSchemaEvolutionAddColumns = "add_columns"
+	OptionValueSchemaEvolutionIgnore     = "ignore"
+	// Column of the bound data making the ingest apply a change feed, as
+	// replicated from OLTP sources, rather than append rows. Each row is an
+	// insert, update or delete ("I", "U" or "D", in any case) of the row
+	// of the target table with the same values in the comma-separated key
+	// columns. The rows are written to a staging table next to the target
+	// and applied with one MERGE, which for each key applies the last
+	// change in the feed, so a feed may be replayed. The ingest mode must
+	// be append, or create_append, which creates the table without the
+	// operation column. The ingest returns the rows the MERGE changed.
+	OptionStatementIngestCDCOperationColumn = "databricks.statement.ingest.cdc.operation_column"
+	OptionStatementIngestCDCKeyColumns      = "databricks.statement.ingest.cdc.key_columns"
+	// What an ExecuteUpdate ingesting data already ingested by the one
+	// before does: error (the default) fails with StatusInvalidState
+	// until data is bound again; reingest keeps the batches of the bound
+	// data in memory as they are read, so that each ExecuteUpdate, such
+	// as a retry after a failure, ingests all of it again, with the ingest
+	// options set at the time. Binding data discards the batches kept.
+	OptionStatementIngestRepeat = "databricks.statement.ingest.repeat"
+
+	// Values for OptionStatementIngestRepeat
+	OptionValueIngestRepeatError    = "error"
+	OptionValueIngestRepeatReingest = "reingest"
+
+	// Read-only state of the data bound to a statement: none; bound, to
+	// be used by the next execution; or consumed by the execution before,
+	// and to be bound again before the next
+	OptionStatementBindState = "databricks.statement.bind_state"
+
+	// Values for OptionStatementBindState
+	OptionValueBindStateNone     = "none"
+	OptionValueBindStateBound    = "bound"
+	OptionValueBindStateConsumed = "consumed"
+
+	// Statement options for deleting rows by bound keys
+	OptionStatementDeleteTargetTable    = "databricks.statement.delete.target_table"
+	OptionStatementDeleteTargetCatalog  = "databricks.statement.delete.target_catalog"
+	OptionStatementDeleteTargetDbSchema = "databricks.statement.delete.target_db_schema"
+	OptionStatementDeleteBatchSize      = "databricks.statement.delete.batch_size"
+
+	// Statement options loading files from cloud storage, or a volume, into
+	// an existing table with COPY INTO, run by ExecuteQuery when the target
+	// table is set. The source is the directory holding the files, and the
+	// file format one of AVRO, BINARYFILE, CSV, JSON, ORC, PARQUET or TEXT.
+	// The files to load are the comma-separated files, relative to the
+	// source, or those matching the glob pattern (*, ?, [abc], {a,b})
+	// within one directory, or else every file of the source, found with
+	// LIST. Each file is loaded by its own COPY INTO, so that a failure
+	// does not keep the others from loading; files that COPY INTO loaded
+	// before are skipped. Validate is "all" or a number of rows to parse
+	// and check instead of loading them. Options with the format option
+	// and copy option prefixes add FORMAT_OPTIONS and COPY_OPTIONS named by
+	// the rest of the key, such as header or mergeSchema; an empty value
+	// removes one. Not available while autocommit is disabled on a
+	// warehouse without transactions.
+	//
+	// The result has one row per file: file_path; status, which is LOADED,
+	// SKIPPED when no rows were loaded, VALIDATED, or FAILED; num_rows, the
+	// rows loaded or validated, if known; and error, why it failed.
+	OptionStatementCopyIntoTargetTable        = "databricks.statement.copy_into.target_table"
+	OptionStatementCopyIntoTargetCatalog      = "databricks.statement.copy_into.target_catalog"
+	OptionStatementCopyIntoTargetDbSchema     = "databricks.statement.copy_into.target_db_schema"
+	OptionStatementCopyIntoSource             = "databricks.statement.copy_into.source"
+	OptionStatementCopyIntoFileFormat         = "databricks.statement.copy_into.file_format"
+	OptionStatementCopyIntoFiles              = "databricks.statement.copy_into.files"
+	OptionStatementCopyIntoPattern            = "databricks.statement.copy_into.pattern"
+	OptionStatementCopyIntoValidate           = "databricks.statement.copy_into.validate"
+	OptionStatementCopyIntoFormatOptionPrefix = "databricks.statement.copy_into.format_option."
+	OptionStatementCopyIntoCopyOptionPrefix   = "databricks.statement.copy_into.copy_option."
+
+	// Statement options counting the chunks, batches, rows and bytes of
+	// the most recent result set fetched so far. The counts grow as the
+	// result is read and are its totals once OptionStatementResultComplete
+	// is "true".
+	OptionStatementResultFetchedChunks  = "databricks.statement.result.fetched_chunks"
+	OptionStatementResultFetchedBatches = "databricks.statement.result.fetched_batches"
+	OptionStatementResultFetchedRows    = "databricks.statement.result.fetched_rows"
+	OptionStatementResultFetchedBytes   = "databricks.statement.result.fetched_bytes"
+	OptionStatementResultComplete       = "databricks.statement.result.complete"
+
+	// Statement options giving the chunks, rows and bytes of the whole of
+	// the most recent result set, or -1 while they are not known: for a
+	// submitted statement, as its manifest reports them, and otherwise
+	// only once the result is complete.
+	OptionStatementResultTotalChunks = "databricks.statement.result.total_chunks"
+	OptionStatementResultTotalRows   = "databricks.statement.result.total_rows"
+	OptionStatementResultTotalBytes  = "databricks.statement.result.total_bytes"
+
+	// Statement options reporting, in bytes, the Arrow memory currently
+	// retained by the most recent result reader or ingest, and its peak.
+	// Result batches are allocated from the driver's allocator; for an
+	// ingest, the bound batch being written is counted.
+	OptionStatementMemoryCurrentBytes = "databricks.statement.memory.current_bytes"
+	OptionStatementMemoryPeakBytes    = "databricks.statement.memory.peak_bytes"
 
-	// TLS/SSL options
-	OptionSSLMode     = "databricks.ssl_mode"
-	OptionSSLRootCert = "databricks.ssl_root_cert"
+	// When "true", ExecuteUpdate submits the statement through the
+	// Statement Execution API of the warehouse, outside of the session,
+	// and returns -1 without waiting for it to finish, for queue-style
+	// INSERT and UPDATE workloads and for running many long queries at
+	// once without a goroutine blocked on each. Its ID is then in
+	// OptionStatementSubmittedID, which may also be set to follow a
+	// statement submitted elsewhere; setting a new query clears it.
+	// Reading OptionStatementSubmittedState asks for its current state:
+	// PENDING, RUNNING, SUCCEEDED, FAILED, CANCELED or CLOSED;
+	// OptionStatementSubmittedError is why it failed. Setting
+	// OptionStatementSubmittedCancel, to any value, asks for it to be
+	// canceled. With the option, ExecuteQuery waits for the submitted
+	// statement to finish and reads its results, submitting the query
+	// first if it has not been, in which case it is canceled if the
+	// context ends while waiting. The statement runs outside of the
+	// session, in its current catalog and schema, and is not reported to
+	// a QueryLogger. Since it would not have the other settings of the
+	// session, it is refused once OptionInitSQL, OptionSessionANSIMode,
+	// OptionSessionDefaultCollation, OptionUseCachedResult or a SET
+	// statement changed them. Not available while autocommit is disabled.
+	// Requires the HTTP path of a SQL warehouse; not supported with
+	// adbc.OptionKeyURI.
+	//
+	// ExecuteQuery on a statement with OptionStatementSubmittedID set and
+	// no query waits for that statement to finish and reads its results,
+	// such as those of a query submitted before a process restarted. Only
+	// results in the ARROW_STREAM format can be read, as are those of
+	// statements submitted by the driver.
+	OptionStatementSubmitAsync     = "databricks.statement.submit_async"
+	OptionStatementSubmittedID     = "databricks.statement.submitted.id"
+	OptionStatementSubmittedState  = "databricks.statement.submitted.state"
+	OptionStatementSubmittedError  = "databricks.statement.submitted.error"
+	OptionStatementSubmittedCancel = "databricks.statement.submitted.cancel"
 
-	// OAuth options (for future expansion)
-	OptionOAuthClientID     = "databricks.oauth.client_id"
-	OptionOAuthClientSecret = "databricks.oauth.client_secret"
-	OptionOAuthRefreshToken = "databricks.oauth.refresh_token"
+	// OptionStatementResultFormat selects how ExecuteQuery and
+	// ExecutePartitions return results: OptionValueResultFormatArrow, the
+	// default, in the columns of the query, or
+	// OptionValueResultFormatJSONLines, in a single string column, "json",
+	// holding each row as a JSON object converted by the server with
+	// to_json, for consumers such as document stores that take rows
+	// without mapping their types. The query must be one that can be
+	// nested as a subquery, such as a SELECT.
+	OptionStatementResultFormat      = "databricks.statement.result_format"
+	OptionValueResultFormatArrow     = "arrow"
+	OptionValueResultFormatJSONLines = "json_lines"
+
+	// Keys of the adbc.Error details locating the bound row that a bulk
+	// ingest or delete failed on: the index of its record batch in the
+	// bound stream and its index in the batch. The column and a truncated
+	// rendering of its value are added when the failure concerns one
+	// value, or the server error names the column.
+	ErrorDetailBatchIndex = "databricks.batch_index"
+	ErrorDetailRowIndex   = "databricks.row_index"
+	ErrorDetailColumn     = "databricks.column"
+	ErrorDetailValue      = "databricks.value"
+
+	// Keys of the schema metadata added by OptionResultProvenance: the
+	// server query ID, when the query started running (RFC 3339, UTC), the
+	// ID of the warehouse or cluster it ran on, and the driver's version
+	MetadataKeyQueryID       = "databricks.query_id"
+	MetadataKeyExecutedAt    = "databricks.executed_at"
+	MetadataKeyWarehouseID   = "databricks.warehouse_id"
+	MetadataKeyClusterID     = "databricks.cluster_id"
+	MetadataKeyDriverVersion = "databricks.driver_version"
 
 	// Default values
-	DefaultPort    = 443
-	DefaultSSLMode = "require"
+	DefaultPort            = 443
+	DefaultSSLMode         = OptionValueSSLModeRequire
+	DefaultDeleteBatchSize = 256
+	DefaultMaxSQLLength    = 16 << 20
+)
+
+// Driver-specific GetInfo codes describing the compute that the HTTP path
+// connects to, from the form of the path. With OptionFailoverHTTPPaths,
+// they describe the primary path.
+const (
+	// The kind of compute: warehouse for a SQL warehouse
+	// (/sql/1.0/warehouses/...), cluster for an all-purpose cluster
+	// (/sql/protocolv1/o/...), or unknown (string)
+	InfoComputeType adbc.InfoCode = 10_000 + iota
+	// The ID of the warehouse or cluster; null if unknown (string)
+	InfoComputeID
+	// Whether OptionStatementSubmitAsync is supported (bool)
+	InfoSupportsSubmitAsync
+	// Whether the connection usage options report metrics (bool)
+	InfoSupportsUsageMetrics
+	// Whether OptionWarehouseWaitForStart is supported (bool)
+	InfoSupportsWaitForStart
+	// Whether disabling autocommit can begin a transaction of the session,
+	// rather than staging changes until commit; null until autocommit is
+	// first disabled on a warehouse (bool)
+	InfoSupportsTransactions
+	// The type of the SQL warehouse, as OptionWarehouseType (string)
+	InfoWarehouseType
+)
+
+// Values of InfoComputeType
+const (
+	InfoValueComputeWarehouse = "warehouse"
+	InfoValueComputeCluster   = "cluster"
+	InfoValueComputeUnknown   = "unknown"
 )
 
 func init() {
@@ -83,15 +674,72 @@ type driverImpl struct {
 
 // NewDriver creates a new Databricks driver using the given Arrow allocator.
 func NewDriver(alloc memory.Allocator) adbc.Driver {
+	return driverbase.NewDriver(newDriverImpl(alloc))
+}
+
+// NewDatabaseWithTokenProvider creates a database that authenticates every
+// connection with access tokens obtained from provider, instead of the
+// credentials configured through options.
+func NewDatabaseWithTokenProvider(ctx context.Context, alloc memory.Allocator, opts map[string]string, provider TokenProvider) (adbc.Database, error) {
+	if provider == nil {
+		return nil, adbc.Error{
+			Code: adbc.StatusInvalidArgument,
+			Msg:  "token provider is required",
+		}
+	}
+	return NewDatabaseWithOptions(ctx, alloc, opts, WithTokenProvider(provider))
+}
+
+// DatabaseOption configures a database created with NewDatabaseWithOptions
+// with Go values that cannot be given as string options
+type DatabaseOption func(*databaseImpl)
+
+// WithTokenProvider authenticates every connection with access tokens
+// obtained from provider, instead of the credentials configured through
+// options
+func WithTokenProvider(provider TokenProvider) DatabaseOption {
+	return func(d *databaseImpl) { d.tokenProvider = provider }
+}
+
+// WithQueryLogger reports every statement run by the database's
+// connections to logger, with parameter values redacted or hashed as set
+// by OptionQueryLogParameters. Not supported when connecting with
+// adbc.uri.
+func WithQueryLogger(logger QueryLogger) DatabaseOption {
+	return func(d *databaseImpl) { d.queryLogger = logger }
+}
+
+// WithOAuthLoginHandler presents the login URL of the oauth-u2m auth type
+// by calling handler, for example to show it in the application's own
+// window, instead of opening it in the default browser. The login waits
+// for the browser to return to the driver's callback listener as before.
+func WithOAuthLoginHandler(handler func(loginURL string) error) DatabaseOption {
+	return func(d *databaseImpl) { d.oauthLoginHandler = handler }
+}
+
+// NewDatabaseWithOptions creates a database from string options, as
+// adbc.Driver.NewDatabase does, and the given Go options.
+func NewDatabaseWithOptions(ctx context.Context, alloc memory.Allocator, opts map[string]string, options ...DatabaseOption) (adbc.Database, error) {
+	return newDriverImpl(alloc).newDatabase(ctx, opts, options...)
+}
+
+func newDriverImpl(alloc memory.Allocator) *driverImpl {
 	info := driverbase.DefaultDriverInfo("Databricks")
 
 	if err := info.RegisterInfoCode(adbc.InfoDriverName, "ADBC Driver Foundry Driver for Databricks"); err != nil {
 		panic(err)
 	}
+	// Set from the connection's HTTP path when info is requested
+	for _, code := range []adbc.InfoCode{InfoComputeType, InfoComputeID, InfoSupportsSubmitAsync,
+		InfoSupportsUsageMetrics, InfoSupportsWaitForStart, InfoSupportsTransactions, InfoWarehouseType} {
+		if err := info.RegisterInfoCode(code, nil); err != nil {
+			panic(err)
+		}
+	}
 
-	return driverbase.NewDriver(&driverImpl{
+	return &driverImpl{
 		DriverImplBase: driverbase.NewDriverImplBase(info, alloc),
-	})
+	}
 }
 
 func (d *driverImpl) NewDatabase(opts map[string]string) (adbc.Database, error) {
@@ -99,6 +747,10 @@ func (d *driverImpl) NewDatabase(opts map[string]string) (adbc.Database, error)
 }
 
 func (d *driverImpl) NewDatabaseWithContext(ctx context.Context, opts map[string]string) (adbc.Database, error) {
+	return d.newDatabase(ctx, opts)
+}
+
+func (d *driverImpl) newDatabase(ctx context.Context, opts map[string]string, options ...DatabaseOption) (adbc.Database, error) {
 	dbBase, err := driverbase.NewDatabaseImplBase(ctx, &d.DriverImplBase)
 	if err != nil {
 		return nil, err
@@ -108,6 +760,10 @@ func (d *driverImpl) NewDatabaseWithContext(ctx context.Context, opts map[string
 		DatabaseImplBase: dbBase,
 		port:             DefaultPort,
 		sslMode:          DefaultSSLMode,
+		maxSQLLength:     DefaultMaxSQLLength,
+	}
+	for _, option := range options {
+		option(db)
 	}
 
 	if err := db.SetOptions(opts); err != nil {
@@ -785,7 +785,9 @@ func (suite *DatabricksTests) TestDatabaseOptions() {
 		{"SSLMode", databricks.OptionSSLMode, "require"},
 		{"QueryTimeout", databricks.OptionQueryTimeout, "1m0s"},
 		{"MaxRows", databricks.OptionMaxRows, "5000"},
+		{"FetchSize", databricks.OptionFetchSize, "1000"},
 		{"QueryRetryCount", databricks.OptionQueryRetryCount, "5"},
+		{"CloudFetchMaxParallelDownloads", databricks.OptionCloudFetchMaxParallelDownloads, "8"},
 		{"DownloadThreadCount", databricks.OptionDownloadThreadCount, "8"},
 	}
 
@@ -0,0 +1,101 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+)
+
+// ParseDSN splits a connection string of the form
+//
+//	databricks://token:<token>@<host>:<port>/<http path>?catalog=<catalog>&schema=<schema>
+//
+// into database options. The port and every query parameter are optional.
+// Query parameters other than catalog and schema must be full option
+// names, such as databricks.query.timeout. Characters of the token that
+// are not allowed in a URL must be percent-encoded.
+func ParseDSN(dsn string) (map[string]string, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		// The url.Error message would include the token
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return nil, dsnError("%v", err)
+	}
+	if u.Scheme != "databricks" {
+		return nil, dsnError("expected scheme 'databricks', got '%s'", u.Scheme)
+	}
+	if u.Hostname() == "" {
+		return nil, dsnError("missing host")
+	}
+
+	opts := map[string]string{OptionServerHostname: u.Hostname()}
+	if port := u.Port(); port != "" {
+		if _, err := strconv.Atoi(port); err != nil {
+			return nil, dsnError("invalid port '%s'", port)
+		}
+		opts[OptionPort] = port
+	}
+	if u.Path != "" && u.Path != "/" {
+		opts[OptionHTTPPath] = u.Path
+	}
+	if u.User != nil {
+		token, ok := u.User.Password()
+		if u.User.Username() != "token" || !ok || token == "" {
+			return nil, dsnError("credentials must be given as token:<personal access token>")
+		}
+		opts[OptionAccessToken] = token
+	}
+
+	query, err := url.ParseQuery(u.RawQuery)
+	if err != nil {
+		return nil, dsnError("invalid query parameters: %v", err)
+	}
+	for key, values := range query {
+		if len(values) != 1 {
+			return nil, dsnError("query parameter '%s' given more than once", key)
+		}
+		switch {
+		case key == "catalog":
+			key = OptionCatalog
+		case key == "schema":
+			key = OptionSchema
+		case key == OptionURI || key == adbc.OptionKeyURI:
+			return nil, dsnError("query parameter '%s' is not allowed", key)
+		case !strings.HasPrefix(key, "databricks.") && !strings.HasPrefix(key, "adbc."):
+			return nil, dsnError("unknown query parameter '%s'", key)
+		}
+		if _, ok := opts[key]; ok {
+			return nil, dsnError("option '%s' given more than once", key)
+		}
+		opts[key] = values[0]
+	}
+	return opts, nil
+}
+
+func dsnError(format string, args ...any) error {
+	return adbc.Error{
+		Code: adbc.StatusInvalidArgument,
+		Msg:  "invalid Databricks URI: " + fmt.Sprintf(format, args...),
+	}
+}
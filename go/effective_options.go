@@ -0,0 +1,201 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"encoding/json"
+	"maps"
+	"net/url"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+)
+
+// Replaces secret values in OptionEffectiveOptions
+const redactedOptionValue = "<redacted>"
+
+// Database options listed by OptionEffectiveOptions, besides HTTP header
+// and auth type options. OptionURI is left out as the options it sets are
+// listed themselves.
+var effectiveDatabaseOptions = []string{
+	adbc.OptionKeyURI,
+	OptionServerHostname,
+	OptionHTTPPath,
+	OptionFailoverHTTPPaths,
+	OptionAccessToken,
+	OptionProxyURL,
+	OptionProxyUser,
+	OptionProxyPassword,
+	OptionPort,
+	OptionCatalog,
+	OptionSchema,
+	OptionConnectTimeout,
+	OptionHTTPRequestTimeout,
+	OptionHTTP2,
+	OptionMaxIdleConnsPerHost,
+	OptionTCPKeepAlive,
+	OptionQueryTimeout,
+	OptionQueryPollInterval,
+	OptionSlowQueryThreshold,
+	OptionRetryBudgetMaxRetries,
+	OptionRetryBudgetMaxTime,
+	OptionWarehouseWaitForStart,
+	OptionUseCachedResult,
+	OptionSessionANSIMode,
+	OptionSessionDefaultCollation,
+	OptionMetadataCacheTTL,
+	OptionMetadataMaxConcurrency,
+	OptionMetadataMaxRetries,
+	OptionMetadataBackoff,
+	OptionPoolMaxOpenConnections,
+	OptionPoolMaxIdleConnections,
+	OptionPoolIdleTimeout,
+	OptionPoolMaxLifetime,
+	OptionLazyConnect,
+	OptionAutoReconnect,
+	OptionInitSQL,
+	OptionQueryTags,
+	OptionImpersonateUser,
+	OptionMaxSQLLength,
+	OptionQueryLogParameters,
+	OptionStrictConversions,
+	OptionResultProvenance,
+	OptionGetObjectsFilterCase,
+	OptionMemoryLimitBytes,
+	OptionMemoryLimitWait,
+	OptionKeepAliveInterval,
+	OptionIdleCloseAfter,
+	OptionMaxRows,
+	OptionFetchSize,
+	OptionQueryRetryCount,
+	OptionCloudFetchMaxParallelDownloads,
+	OptionSSLMode,
+	OptionSSLRootCert,
+	OptionSSLClientCert,
+	OptionSSLClientKey,
+	OptionSSLServerName,
+	OptionOAuthClientID,
+	OptionOAuthClientSecret,
+	OptionOAuthRefreshToken,
+	OptionOAuthTokenEndpoint,
+	OptionOAuthExternalToken,
+	OptionOAuthRefreshBeforeExpiry,
+	OptionOAuthIdentityTokenFile,
+	OptionOAuthIdentityTokenAudience,
+	OptionGoogleCredentials,
+	OptionOAuthRedirectPort,
+	OptionAuthType,
+}
+
+// Options whose values are always secret
+var secretOptions = map[string]bool{
+	OptionAccessToken:        true,
+	OptionProxyPassword:      true,
+	OptionOAuthClientSecret:  true,
+	OptionOAuthRefreshToken:  true,
+	OptionOAuthExternalToken: true,
+}
+
+// Options that take a file path, or the secret itself
+var secretFileOptions = map[string]bool{
+	OptionSSLClientKey:      true,
+	OptionGoogleCredentials: true,
+}
+
+// secretNameRe matches the names of auth type options, HTTP headers and
+// databricks-sql-go DSN parameters that likely hold secrets
+var secretNameRe = regexp.MustCompile(`(?i)token|secret|password|credential|authorization|cookie|api[-_]?key|private`)
+
+// effectiveOptions renders the options in effect on the database as a JSON
+// object, leaving out unset options and redacting secrets
+func (d *databaseImpl) effectiveOptions() (string, error) {
+	options := map[string]string{}
+	for _, key := range effectiveDatabaseOptions {
+		value, err := d.GetOption(key)
+		if err != nil {
+			return "", err
+		}
+		if value != "" {
+			options[key] = redactOption(key, value)
+		}
+	}
+	for key, value := range d.authOptions {
+		options[key] = redactOption(key, value)
+	}
+	for name := range d.httpHeaders {
+		key := OptionHTTPHeaderPrefix + name
+		options[key] = redactOption(key, d.httpHeaders.Get(name))
+	}
+
+	data, err := json.Marshal(options)
+	if err != nil {
+		return "", d.ErrorHelper.Errorf(adbc.StatusInternal, "failed to render options: %v", err)
+	}
+	return string(data), nil
+}
+
+// redactOption returns value with any secrets in it redacted
+func redactOption(key, value string) string {
+	switch {
+	case key == adbc.OptionKeyURI:
+		return redactDSN(value)
+	case secretOptions[key]:
+		return redactedOptionValue
+	case secretFileOptions[key]:
+		if info, err := os.Stat(value); err == nil && info.Mode().IsRegular() {
+			return value
+		}
+		return redactedOptionValue
+	case strings.HasPrefix(key, OptionAuthPrefix), strings.HasPrefix(key, OptionHTTPHeaderPrefix):
+		if secretNameRe.MatchString(key) {
+			return redactedOptionValue
+		}
+	}
+	return value
+}
+
+// redactDSN redacts the password and secret parameters of a
+// databricks-sql-go DSN, such as token:<token>@host:443/path?k=v
+func redactDSN(dsn string) string {
+	rest, query, hasQuery := strings.Cut(dsn, "?")
+	if at := strings.LastIndex(rest, "@"); at >= 0 {
+		if user, _, ok := strings.Cut(rest[:at], ":"); ok {
+			rest = user + ":" + redactedOptionValue + rest[at:]
+		}
+	}
+	if !hasQuery {
+		return rest
+	}
+
+	params, err := url.ParseQuery(query)
+	if err != nil {
+		return rest + "?" + redactedOptionValue
+	}
+	var parts []string
+	for _, name := range slices.Sorted(maps.Keys(params)) {
+		for _, value := range params[name] {
+			if secretNameRe.MatchString(name) {
+				value = redactedOptionValue
+			} else {
+				value = url.QueryEscape(value)
+			}
+			parts = append(parts, url.QueryEscape(name)+"="+value)
+		}
+	}
+	return rest + "?" + strings.Join(parts, "&")
+}
@@ -0,0 +1,164 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+)
+
+// hostnameRe matches DNS names, allowing the underscores some private DNS
+// zones use
+var hostnameRe = regexp.MustCompile(`^[A-Za-z0-9_-]+(\.[A-Za-z0-9_-]+)*\.?$`)
+
+// parseServerHostname splits a value of OptionServerHostname, which may
+// start with https:// and end with a port, into the host and the port, or
+// 0 if it has none. IPv6 literals may be given with or without brackets,
+// and are returned in brackets as they appear in URLs.
+func parseServerHostname(value string) (host string, port int, err error) {
+	invalid := func(reason string) (string, int, error) {
+		return "", 0, adbc.Error{
+			Code: adbc.StatusInvalidArgument,
+			Msg:  fmt.Sprintf("invalid %s '%s': %s", OptionServerHostname, value, reason),
+		}
+	}
+	if value == "" {
+		return "", 0, nil
+	}
+
+	host = value
+	if scheme, rest, ok := strings.Cut(host, "://"); ok {
+		if !strings.EqualFold(scheme, "https") {
+			return invalid("only https is supported")
+		}
+		host = rest
+	}
+	host = strings.TrimSuffix(host, "/")
+	if strings.ContainsAny(host, "/?#") {
+		return invalid(fmt.Sprintf("it must not contain a path, which is set with %s", OptionHTTPPath))
+	}
+
+	// Only IPv6 literals are bracketed
+	bracketed := strings.HasPrefix(host, "[")
+	switch {
+	case net.ParseIP(host) != nil && strings.Contains(host, ":"):
+		// An IPv6 literal without brackets cannot have a port
+	case bracketed && strings.HasSuffix(host, "]"):
+		host = host[1 : len(host)-1]
+	case strings.Contains(host, ":"):
+		var portValue string
+		if host, portValue, err = net.SplitHostPort(host); err != nil {
+			return invalid(err.Error())
+		}
+		if port, err = strconv.Atoi(portValue); err != nil || port < 1 || port > 65535 {
+			return invalid(fmt.Sprintf("invalid port '%s'", portValue))
+		}
+	}
+	if bracketed || strings.Contains(host, ":") {
+		if net.ParseIP(host) == nil {
+			return invalid("not a valid IPv6 address")
+		}
+		return "[" + host + "]", port, nil
+	}
+	if !hostnameRe.MatchString(host) {
+		return invalid("not a valid hostname")
+	}
+	return host, port, nil
+}
+
+// validateHTTPPath checks that a value of OptionHTTPPath is a path,
+// returning it with a leading slash
+func validateHTTPPath(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+	reason := ""
+	switch {
+	case strings.Contains(value, "://"):
+		reason = fmt.Sprintf("it must be a path, with the host set by %s and the port by %s", OptionServerHostname, OptionPort)
+	case strings.ContainsAny(value, "?# \t\r\n"):
+		reason = "it must not contain a query, fragment or whitespace"
+	}
+	if reason != "" {
+		return "", adbc.Error{
+			Code: adbc.StatusInvalidArgument,
+			Msg:  fmt.Sprintf("invalid %s '%s': %s", OptionHTTPPath, value, reason),
+		}
+	}
+	if !strings.HasPrefix(value, "/") {
+		value = "/" + value
+	}
+	return value, nil
+}
+
+// endpointPort returns the port to connect to: OptionPort, or else the
+// port given with the hostname, or else 443
+func (d *databaseImpl) endpointPort() (int, error) {
+	switch {
+	case d.port != 0 && d.hostnamePort != 0 && d.port != d.hostnamePort:
+		return 0, adbc.Error{
+			Code: adbc.StatusInvalidArgument,
+			Msg:  fmt.Sprintf("%s has port %d, but %s is %d", OptionServerHostname, d.hostnamePort, OptionPort, d.port),
+		}
+	case d.port != 0:
+		return d.port, nil
+	case d.hostnamePort != 0:
+		return d.hostnamePort, nil
+	}
+	return DEFAULT_PORT, nil
+}
+
+// workspaceURL returns the URL of a path on the workspace endpoint, giving
+// the port unless it is 443. Conflicting ports were already rejected when
+// resolving the connection options, so OptionPort is taken then.
+func (d *databaseImpl) workspaceURL(path string) string {
+	port, err := d.endpointPort()
+	if err != nil {
+		port = d.port
+	}
+	host := d.serverHostname
+	if port != DEFAULT_PORT {
+		host = net.JoinHostPort(strings.Trim(host, "[]"), strconv.Itoa(port))
+	}
+	u := url.URL{Scheme: "https", Host: host, Path: path}
+	return u.String()
+}
+
+// warnEndpointTLS logs the ways the endpoint's certificate check differs
+// from the usual check against the hostname
+func (d *databaseImpl) warnEndpointTLS() {
+	if d.Logger == nil {
+		return
+	}
+	if d.sslServerName != "" {
+		d.Logger.Warn("TLS server name overridden; the workspace certificate is checked against it instead of the hostname",
+			"server_name", d.sslServerName, "hostname", d.serverHostname)
+		if d.sslMode == OptionValueSSLModeInsecure || d.sslMode == OptionValueSSLModeVerifyCA {
+			d.Logger.Warn("TLS server name only sets SNI, as the SSL mode does not check host names", "ssl_mode", d.sslMode)
+		}
+		return
+	}
+	host := strings.Trim(d.serverHostname, "[]")
+	if net.ParseIP(host) != nil && d.sslMode != OptionValueSSLModeInsecure && d.sslMode != OptionValueSSLModeVerifyCA {
+		d.Logger.Warn("connecting to an IP address, which workspace certificates do not usually name; set "+OptionSSLServerName+" to the name they do",
+			"hostname", d.serverHostname)
+	}
+}
@@ -0,0 +1,174 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/adbc-drivers/driverbase-go/driverbase"
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseServerHostname(t *testing.T) {
+	for value, want := range map[string]struct {
+		host string
+		port int
+	}{
+		"example.cloud.databricks.com":          {"example.cloud.databricks.com", 0},
+		"https://example.cloud.databricks.com/": {"example.cloud.databricks.com", 0},
+		"gateway.internal:8443":                 {"gateway.internal", 8443},
+		"HTTPS://gateway.internal:8443":         {"gateway.internal", 8443},
+		"dbx_gw.corp.":                          {"dbx_gw.corp.", 0},
+		"10.0.0.5:8443":                         {"10.0.0.5", 8443},
+		"fd00::10":                              {"[fd00::10]", 0},
+		"[fd00::10]":                            {"[fd00::10]", 0},
+		"https://[fd00::10]:8443":               {"[fd00::10]", 8443},
+		"":                                      {"", 0},
+	} {
+		host, port, err := parseServerHostname(value)
+		require.NoError(t, err, value)
+		assert.Equal(t, want.host, host, value)
+		assert.Equal(t, want.port, port, value)
+	}
+
+	for _, value := range []string{
+		"http://example.com",
+		"example.com/sql/1.0/warehouses/abc",
+		"example.com:0",
+		"example.com:https",
+		"example.com:70000",
+		"exa mple.com",
+		"token@example.com",
+		"[fd00::zz]:8443",
+		"[example.com]",
+	} {
+		_, _, err := parseServerHostname(value)
+		var adbcErr adbc.Error
+		require.ErrorAs(t, err, &adbcErr, value)
+		assert.Equal(t, adbc.StatusInvalidArgument, adbcErr.Code, value)
+	}
+}
+
+func TestEndpointOptions(t *testing.T) {
+	d := &databaseImpl{}
+	require.NoError(t, d.SetOption(OptionServerHostname, "gateway.internal:8443"))
+	require.NoError(t, d.SetOption(OptionHTTPPath, "gw/sql/1.0/warehouses/abc"))
+
+	hostname, err := d.GetOption(OptionServerHostname)
+	require.NoError(t, err)
+	assert.Equal(t, "gateway.internal:8443", hostname)
+	path, err := d.GetOption(OptionHTTPPath)
+	require.NoError(t, err)
+	assert.Equal(t, "/gw/sql/1.0/warehouses/abc", path)
+	port, err := d.endpointPort()
+	require.NoError(t, err)
+	assert.Equal(t, 8443, port)
+
+	// A port set separately must agree with the hostname's
+	require.NoError(t, d.SetOption(OptionPort, "443"))
+	_, err = d.endpointPort()
+	assert.ErrorContains(t, err, "has port 8443, but databricks.port is 443")
+	require.NoError(t, d.SetOption(OptionServerHostname, "gateway.internal"))
+	port, err = d.endpointPort()
+	require.NoError(t, err)
+	assert.Equal(t, 443, port)
+
+	for key, value := range map[string]string{
+		OptionHTTPPath:          "https://gateway.internal/sql/1.0/warehouses/abc",
+		OptionFailoverHTTPPaths: "/sql/1.0/warehouses/def,/sql/1.0/warehouses/ghi?o=1",
+	} {
+		err := d.SetOption(key, value)
+		var adbcErr adbc.Error
+		require.ErrorAs(t, err, &adbcErr, key)
+		assert.Equal(t, adbc.StatusInvalidArgument, adbcErr.Code, key)
+	}
+}
+
+func TestOIDCEndpoints(t *testing.T) {
+	for hostname, want := range map[string]string{
+		"example.cloud.databricks.com":     "https://example.cloud.databricks.com/oidc/v1/token",
+		"example.cloud.databricks.com:443": "https://example.cloud.databricks.com/oidc/v1/token",
+		"gateway.internal:8443":            "https://gateway.internal:8443/oidc/v1/token",
+		"fd00::10":                         "https://[fd00::10]/oidc/v1/token",
+		"[fd00::10]:8443":                  "https://[fd00::10]:8443/oidc/v1/token",
+	} {
+		d := &databaseImpl{}
+		require.NoError(t, d.SetOption(OptionServerHostname, hostname))
+		assert.Equal(t, want, d.oidcTokenEndpoint(), hostname)
+	}
+
+	d := &databaseImpl{}
+	require.NoError(t, d.SetOption(OptionServerHostname, "fd00::10"))
+	require.NoError(t, d.SetOption(OptionPort, "8443"))
+	assert.Equal(t, "https://[fd00::10]:8443/oidc/v1/authorize", d.oidcAuthorizationEndpoint())
+}
+
+func TestSSLServerName(t *testing.T) {
+	// The test server's certificate names example.com and its IP address
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"state": "RUNNING"}`))
+	}))
+	defer srv.Close()
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+	port, err := strconv.Atoi(u.Port())
+	require.NoError(t, err)
+	pool := x509.NewCertPool()
+	pool.AddCert(srv.Certificate())
+
+	connect := func(serverName string, buf *bytes.Buffer) error {
+		d := &databaseImpl{
+			DatabaseImplBase: driverbase.DatabaseImplBase{Logger: slog.New(slog.NewTextHandler(buf, nil))},
+			serverHostname:   u.Hostname(),
+			port:             port,
+			httpPath:         "/sql/1.0/warehouses/abc",
+			accessToken:      "dapi-token",
+			sslCertPool:      pool,
+		}
+		require.NoError(t, d.SetOption(OptionSSLServerName, serverName))
+		_, err := d.resolveConnectionOptions()
+		require.NoError(t, err)
+		_, err = d.warehouse.state(context.Background())
+		return err
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, connect("example.com", &buf))
+	assert.Contains(t, buf.String(), "TLS server name overridden")
+	assert.Contains(t, buf.String(), "server_name=example.com")
+
+	// Without an override, connecting by IP address is warned about
+	buf.Reset()
+	require.NoError(t, connect("", &buf))
+	assert.Contains(t, buf.String(), "connecting to an IP address")
+
+	err = connect("other.example", &bytes.Buffer{})
+	assert.ErrorContains(t, err, "certificate is valid for")
+
+	err = (&databaseImpl{}).SetOption(OptionSSLServerName, "https://example.com")
+	var adbcErr adbc.Error
+	require.ErrorAs(t, err, &adbcErr)
+	assert.Equal(t, adbc.StatusInvalidArgument, adbcErr.Code)
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+)
+
+// Longest rendering of a value in an error, in bytes
+const maxErrorValueLen = 64
+
+// rowPosition locates a bound row, and optionally one of its values
+type rowPosition struct {
+	batch, row int
+	column     string
+	value      string
+}
+
+// newRowPosition locates row of the batch'th record batch rec, and its
+// value in column col if col is not negative
+func newRowPosition(rec arrow.RecordBatch, batch, row, col int) rowPosition {
+	pos := rowPosition{batch: batch, row: row}
+	if col >= 0 {
+		pos.column = rec.Schema().Field(col).Name
+		pos.value = renderErrorValue(rec.Column(col), row)
+	}
+	return pos
+}
+
+func (p rowPosition) String() string {
+	s := fmt.Sprintf("batch %d, row %d", p.batch, p.row)
+	if p.column != "" {
+		s += fmt.Sprintf(", column %s (value %s)", quoteIdentifier(p.column), p.value)
+	}
+	return s
+}
+
+func (p rowPosition) details() []adbc.ErrorDetail {
+	details := []adbc.ErrorDetail{
+		&adbc.TextErrorDetail{Name: ErrorDetailBatchIndex, Detail: strconv.Itoa(p.batch)},
+		&adbc.TextErrorDetail{Name: ErrorDetailRowIndex, Detail: strconv.Itoa(p.row)},
+	}
+	if p.column != "" {
+		details = append(details,
+			&adbc.TextErrorDetail{Name: ErrorDetailColumn, Detail: p.column},
+			&adbc.TextErrorDetail{Name: ErrorDetailValue, Detail: p.value})
+	}
+	return details
+}
+
+// rowErrorf formats an error about the bound row at pos, naming it in the
+// message and the error details
+func (s *statementImpl) rowErrorf(pos rowPosition, code adbc.Status, format string, args ...any) error {
+	err := s.ErrorHelper.Errorf(code, "%s at %s", fmt.Sprintf(format, args...), pos).(adbc.Error)
+	err.Details = pos.details()
+	return err
+}
+
+// renderErrorValue renders a value for an error message, truncated to
+// maxErrorValueLen bytes
+func renderErrorValue(arr arrow.Array, idx int) string {
+	if arr.IsNull(idx) {
+		return "NULL"
+	}
+	var s string
+	switch arr := arr.(type) {
+	case *array.Decimal128:
+		s = formatDecimal128(arr.Value(idx), arr.DataType().(*arrow.Decimal128Type).Scale)
+	default:
+		s = arr.ValueStr(idx)
+	}
+	if len(s) > maxErrorValueLen {
+		cut := maxErrorValueLen
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		s = s[:cut] + "..."
+	}
+	return strconv.Quote(s)
+}
+
+// failedColumn returns the index of the field of schema that a server
+// error names, as Databricks does for casts and constraint violations
+// when writing a column, or -1 if it names none
+func failedColumn(schema *arrow.Schema, err error) int {
+	msg := identifierKey(err.Error())
+	for i, field := range schema.Fields() {
+		name := identifierKey(field.Name)
+		if strings.Contains(msg, quoteIdentifier(name)) || strings.Contains(msg, "column: "+name) {
+			return i
+		}
+	}
+	return -1
+}
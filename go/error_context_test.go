@@ -0,0 +1,116 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/adbc-drivers/driverbase-go/driverbase"
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func errorDetails(t *testing.T, err error) map[string]string {
+	var adbcErr adbc.Error
+	require.ErrorAs(t, err, &adbcErr)
+	details := map[string]string{}
+	for _, detail := range adbcErr.Details {
+		value, err := detail.Serialize()
+		require.NoError(t, err)
+		details[detail.Key()] = string(value)
+	}
+	return details
+}
+
+func TestIngestErrorContext(t *testing.T) {
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "name", Type: arrow.BinaryTypes.String},
+	}, nil)
+	var batches []arrow.RecordBatch
+	for _, ids := range [][]int64{{1, 2}, {3, 4}} {
+		bldr := array.NewRecordBuilder(memory.DefaultAllocator, schema)
+		bldr.Field(0).(*array.Int64Builder).AppendValues(ids, nil)
+		bldr.Field(1).(*array.StringBuilder).AppendValues([]string{"a", strings.Repeat("x", 100)}, nil)
+		batches = append(batches, bldr.NewRecordBatch())
+		bldr.Release()
+	}
+	defer func() {
+		for _, rec := range batches {
+			rec.Release()
+		}
+	}()
+
+	drv := &fakeDriver{execErr: func(args []any) error {
+		if args[0] == "4" {
+			return errors.New("[DELTA_NOT_NULL_CONSTRAINT_VIOLATED] NOT NULL constraint violated for column: name.")
+		}
+		return nil
+	}}
+	db := sql.OpenDB(drv)
+	defer func() { _ = db.Close() }()
+	conn, err := db.Conn(context.Background())
+	require.NoError(t, err)
+
+	stream, err := array.NewRecordReader(schema, batches)
+	require.NoError(t, err)
+	s := &statementImpl{
+		conn:              &connectionImpl{conn: conn},
+		bulkIngestOptions: driverbase.NewBulkIngestOptions(),
+		boundStream:       stream,
+	}
+	s.bulkIngestOptions.TableName = "events"
+	s.bulkIngestOptions.Mode = adbc.OptionValueIngestModeAppend
+
+	rows, err := s.executeIngest(context.Background())
+	assert.Equal(t, int64(3), rows)
+	assert.ErrorContains(t, err, "at batch 1, row 1, column `name` (value \"xxxx")
+	assert.Equal(t, map[string]string{
+		ErrorDetailBatchIndex: "1",
+		ErrorDetailRowIndex:   "1",
+		ErrorDetailColumn:     "name",
+		ErrorDetailValue:      `"` + strings.Repeat("x", maxErrorValueLen) + `..."`,
+	}, errorDetails(t, err))
+
+	// Without a column named by the server, only the row is located
+	drv.execErr = func([]any) error { return errors.New("connection reset") }
+	stream, err = array.NewRecordReader(schema, batches)
+	require.NoError(t, err)
+	s.boundStream = stream
+	_, err = s.executeIngest(context.Background())
+	assert.ErrorContains(t, err, "failed to execute the query: connection reset at batch 0, row 0")
+	assert.Equal(t, map[string]string{ErrorDetailBatchIndex: "0", ErrorDetailRowIndex: "0"}, errorDetails(t, err))
+}
+
+func TestRenderErrorValue(t *testing.T) {
+	bldr := array.NewStringBuilder(memory.DefaultAllocator)
+	defer bldr.Release()
+	bldr.Append(strings.Repeat("a", maxErrorValueLen-1) + "é")
+	bldr.AppendNull()
+	arr := bldr.NewArray()
+	defer arr.Release()
+
+	// Truncation does not split a character
+	assert.Equal(t, `"`+strings.Repeat("a", maxErrorValueLen-1)+`..."`, renderErrorValue(arr, 0))
+	assert.Equal(t, "NULL", renderErrorValue(arr, 1))
+}
@@ -0,0 +1,82 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"time"
+)
+
+const (
+	// Thrift method starting a query
+	executeStatementMethod = "ExecuteStatement"
+
+	// Bytes of a Thrift request searched for its method name, which
+	// follows a few bytes of header in both the binary and compact
+	// protocols
+	thriftMethodPrefixLength = 64
+
+	// How long a query is still waited for once its context has ended
+	executeStatementGracePeriod = 30 * time.Second
+)
+
+// executeTransport lets a request starting a query finish even once its
+// context has ended, for up to executeStatementGracePeriod. Dropping the
+// request would leave the query running on the server without its
+// handle, while with the response databricks-sql-go cancels it.
+type executeTransport struct {
+	base http.RoundTripper
+}
+
+func (t *executeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !isThriftCall(req, executeStatementMethod) {
+		return t.base.RoundTrip(req)
+	}
+
+	ctx, cancel := context.WithCancel(context.WithoutCancel(req.Context()))
+	stop := context.AfterFunc(req.Context(), func() {
+		time.AfterFunc(executeStatementGracePeriod, cancel)
+	})
+	done := func() {
+		stop()
+		cancel()
+	}
+	resp, err := t.base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		done()
+		return nil, err
+	}
+	resp.Body = &cancelOnCloseBody{ReadCloser: resp.Body, cancel: done}
+	return resp, nil
+}
+
+// isThriftCall reports whether req calls the Thrift method, reading the
+// start of a copy of its body
+func isThriftCall(req *http.Request, method string) bool {
+	if req.Method != http.MethodPost || req.GetBody == nil {
+		return false
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return false
+	}
+	defer func() { _ = body.Close() }()
+	prefix := make([]byte, thriftMethodPrefixLength)
+	n, _ := io.ReadFull(body, prefix)
+	return bytes.Contains(prefix[:n], []byte(method))
+}
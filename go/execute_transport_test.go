@@ -0,0 +1,60 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecuteTransport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+		_, _ = io.WriteString(w, "handle")
+	}))
+	defer srv.Close()
+	client := &http.Client{Transport: &executeTransport{base: http.DefaultTransport}}
+	call := func(method string) (*http.Response, error) {
+		// A Thrift binary protocol call header: version, name, sequence ID
+		body := append([]byte{0x80, 0x01, 0x00, 0x01, 0x00, 0x00, 0x00, byte(len(method))}, method...)
+		body = append(body, 0x00, 0x00, 0x00, 0x01)
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(10*time.Millisecond, cancel)
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, srv.URL, bytes.NewReader(body))
+		require.NoError(t, err)
+		return client.Do(req)
+	}
+
+	// Starting a query is waited for after the context ends, so its
+	// handle is there to cancel it with
+	resp, err := call(executeStatementMethod)
+	require.NoError(t, err)
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, "handle", string(data))
+
+	// Other calls end with the context
+	_, err = call("GetOperationStatus")
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,112 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+	"sync/atomic"
+)
+
+// nonFailoverErrorRe matches errors that every endpoint of the workspace
+// would return alike: rejected credentials and untrusted certificates.
+// databricks-sql-go does not type these, so the message is matched.
+var nonFailoverErrorRe = regexp.MustCompile(`unexpected HTTP status (401|403)\b|x509: |certificate is not trusted`)
+
+// isFailoverError returns whether a session that could not be opened or
+// queried on one endpoint may work on another
+func isFailoverError(ctx context.Context, err error) bool {
+	if ctx.Err() != nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	return !nonFailoverErrorRe.MatchString(err.Error())
+}
+
+// parseHTTPPaths splits a comma-separated list of HTTP paths
+func parseHTTPPaths(value string) []string {
+	var paths []string
+	for path := range strings.SplitSeq(value, ",") {
+		if path = strings.TrimSpace(path); path != "" {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// failoverConnector opens sessions on the first of several endpoints
+// that accepts them and answers a trivial query. The endpoint that last
+// worked is tried first, so a session is opened on the primary again only
+// once a standby fails in turn.
+type failoverConnector struct {
+	connectors []driver.Connector
+	paths      []string
+	logger     *slog.Logger
+
+	// Index of the endpoint tried first
+	active atomic.Int32
+}
+
+func (c *failoverConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	start := int(c.active.Load())
+	var errs []error
+	for i := range c.connectors {
+		idx := (start + i) % len(c.connectors)
+		conn, err := c.connect(ctx, c.connectors[idx])
+		if err == nil {
+			if idx != start {
+				c.active.Store(int32(idx))
+				if c.logger != nil {
+					c.logger.Warn("failed over to another endpoint", "http_path", c.paths[idx], "failed_http_path", c.paths[start])
+				}
+			}
+			return conn, nil
+		}
+
+		errs = append(errs, fmt.Errorf("%s: %w", c.paths[idx], err))
+		if !isFailoverError(ctx, err) {
+			break
+		}
+	}
+	if len(errs) == 1 {
+		return nil, errors.Unwrap(errs[0])
+	}
+	return nil, fmt.Errorf("no endpoint accepted the session: %w", errors.Join(errs...))
+}
+
+// connect opens a session and checks that it can run a query, as a
+// warehouse may accept sessions before it can run statements
+func (c *failoverConnector) connect(ctx context.Context, connector driver.Connector) (driver.Conn, error) {
+	conn, err := connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if pinger, ok := conn.(driver.Pinger); ok {
+		if err := pinger.Ping(ctx); err != nil {
+			return nil, errors.Join(err, conn.Close())
+		}
+	}
+	return conn, nil
+}
+
+func (c *failoverConnector) Driver() driver.Driver {
+	return c.connectors[0].Driver()
+}
+
+var _ driver.Connector = (*failoverConnector)(nil)
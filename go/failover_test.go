@@ -0,0 +1,123 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// endpointConnector stands for a warehouse endpoint that fails to open
+// sessions with connectErr, or opens sessions failing pings with pingErr
+type endpointConnector struct {
+	connectErr error
+	pingErr    error
+	connects   int
+	closed     int
+}
+
+type endpointConn struct {
+	driver.Conn
+	c *endpointConnector
+}
+
+func (c *endpointConnector) Connect(context.Context) (driver.Conn, error) {
+	c.connects++
+	if c.connectErr != nil {
+		return nil, c.connectErr
+	}
+	return &endpointConn{c: c}, nil
+}
+
+func (c *endpointConnector) Driver() driver.Driver { return nil }
+
+func (c *endpointConn) Ping(context.Context) error { return c.c.pingErr }
+
+func (c *endpointConn) Close() error {
+	c.c.closed++
+	return nil
+}
+
+func newTestFailoverConnector(connectors ...*endpointConnector) *failoverConnector {
+	f := &failoverConnector{}
+	for i, c := range connectors {
+		f.connectors = append(f.connectors, c)
+		f.paths = append(f.paths, []string{"/sql/1.0/warehouses/primary", "/sql/1.0/warehouses/standby"}[i])
+	}
+	return f
+}
+
+func TestFailoverConnector(t *testing.T) {
+	ctx := context.Background()
+	primary := &endpointConnector{connectErr: errors.New("request error after 5 attempt(s): unexpected HTTP status 503 Service Unavailable")}
+	standby := &endpointConnector{}
+	f := newTestFailoverConnector(primary, standby)
+
+	conn, err := f.Connect(ctx)
+	require.NoError(t, err)
+	assert.Same(t, standby, conn.(*endpointConn).c)
+
+	// Sessions stay on the standby until it fails in turn
+	_, err = f.Connect(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, primary.connects)
+	assert.Equal(t, 2, standby.connects)
+
+	primary.connectErr = nil
+	standby.pingErr = errors.New("dial tcp: connection refused")
+	conn, err = f.Connect(ctx)
+	require.NoError(t, err)
+	assert.Same(t, primary, conn.(*endpointConn).c)
+	assert.Equal(t, 1, standby.closed)
+}
+
+func TestFailoverConnectorErrors(t *testing.T) {
+	ctx := context.Background()
+
+	// The standby would reject the same credentials
+	primary := &endpointConnector{connectErr: errors.New("request error after 1 attempt(s): unexpected HTTP status 401 Unauthorized")}
+	standby := &endpointConnector{}
+	_, err := newTestFailoverConnector(primary, standby).Connect(ctx)
+	assert.Equal(t, primary.connectErr, err)
+	assert.Equal(t, 0, standby.connects)
+
+	primary.connectErr = errors.New("dial tcp: i/o timeout")
+	standby.connectErr = errors.New("unexpected HTTP status 502 Bad Gateway")
+	_, err = newTestFailoverConnector(primary, standby).Connect(ctx)
+	assert.ErrorIs(t, err, primary.connectErr)
+	assert.ErrorIs(t, err, standby.connectErr)
+	assert.ErrorContains(t, err, "/sql/1.0/warehouses/standby")
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	standby.connects = 0
+	_, err = newTestFailoverConnector(primary, standby).Connect(canceled)
+	assert.Error(t, err)
+	assert.Equal(t, 0, standby.connects)
+}
+
+func TestFailoverHTTPPathsOption(t *testing.T) {
+	d := &databaseImpl{}
+	require.NoError(t, d.SetOption(OptionFailoverHTTPPaths, " /sql/1.0/warehouses/b, ,/sql/1.0/warehouses/c"))
+	assert.Equal(t, []string{"/sql/1.0/warehouses/b", "/sql/1.0/warehouses/c"}, d.failoverHTTPPaths)
+	val, err := d.GetOption(OptionFailoverHTTPPaths)
+	require.NoError(t, err)
+	assert.Equal(t, "/sql/1.0/warehouses/b,/sql/1.0/warehouses/c", val)
+}
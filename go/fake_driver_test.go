@@ -0,0 +1,176 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"sync"
+)
+
+// fakeDriver is a database/sql driver and connector whose connections each
+// stand for a session, numbered from 0 in the order opened. It records the
+// statements run, and the arguments of the updates that succeeded, and
+// answers queries with the given keys, in a column named keyColumn if set.
+// Its hooks, all optional, change how statements are answered.
+type fakeDriver struct {
+	mu        sync.Mutex
+	sessions  int
+	keys      []string
+	keyColumn string
+	execs     []string
+	args      [][]any
+
+	// Fails updates with the error returned
+	execErr func(args []any) error
+	// Answers queries instead of the keys
+	query func(query string, args []driver.NamedValue) (driver.Rows, error)
+	// Runs before each statement of a session, failing it with the error
+	// returned; it may block to hold the statement running
+	before func(ctx context.Context, session int, query string) error
+	// Answers pings
+	ping func() error
+	// Called as sessions are closed
+	onClose func(session int)
+}
+
+type fakeConn struct {
+	d       *fakeDriver
+	session int
+}
+
+// fakeRows are the rows of a query result
+type fakeRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+// blockUntil returns a hook for fakeDriver.before signaling started once a
+// statement runs, then holding it until release is closed, or failing it
+// once its context is done
+func blockUntil(started chan<- struct{}, release <-chan struct{}) func(context.Context, int, string) error {
+	return func(ctx context.Context, _ int, _ string) error {
+		started <- struct{}{}
+		select {
+		case <-release:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (d *fakeDriver) Open(string) (driver.Conn, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.sessions++
+	return &fakeConn{d: d, session: d.sessions - 1}, nil
+}
+
+func (d *fakeDriver) Connect(context.Context) (driver.Conn, error) { return d.Open("") }
+func (d *fakeDriver) Driver() driver.Driver                        { return d }
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("not supported")
+}
+func (c *fakeConn) Begin() (driver.Tx, error) { return nil, errors.New("not supported") }
+
+func (c *fakeConn) Close() error {
+	if c.d.onClose != nil {
+		c.d.onClose(c.session)
+	}
+	return nil
+}
+
+func (c *fakeConn) Ping(context.Context) error {
+	if c.d.ping != nil {
+		return c.d.ping()
+	}
+	return nil
+}
+
+// start records a statement once the before hook lets it run
+func (c *fakeConn) start(ctx context.Context, query string) error {
+	if c.d.before != nil {
+		if err := c.d.before(ctx, c.session, query); err != nil {
+			return err
+		}
+	}
+	c.d.mu.Lock()
+	defer c.d.mu.Unlock()
+	c.d.execs = append(c.d.execs, query)
+	return nil
+}
+
+func (c *fakeConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if err := c.start(ctx, query); err != nil {
+		return nil, err
+	}
+	values := namedValues(args)
+	if c.d.execErr != nil {
+		if err := c.d.execErr(values); err != nil {
+			return nil, err
+		}
+	}
+	c.d.mu.Lock()
+	defer c.d.mu.Unlock()
+	c.d.args = append(c.d.args, values)
+	return driver.RowsAffected(1), nil
+}
+
+func (c *fakeConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if err := c.start(ctx, query); err != nil {
+		return nil, err
+	}
+	if c.d.query != nil {
+		return c.d.query(query, args)
+	}
+	return keyRows(c.d.keyColumn, c.d.keys...), nil
+}
+
+// namedValues returns the values of args
+func namedValues(args []driver.NamedValue) []any {
+	values := make([]any, len(args))
+	for i, arg := range args {
+		values[i] = arg.Value
+	}
+	return values
+}
+
+// keyRows returns rows of one column named column ("key" if empty)
+// holding keys
+func keyRows(column string, keys ...string) *fakeRows {
+	rows := &fakeRows{columns: []string{column}}
+	if column == "" {
+		rows.columns[0] = "key"
+	}
+	for _, key := range keys {
+		rows.rows = append(rows.rows, []driver.Value{key})
+	}
+	return rows
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+func (r *fakeRows) Close() error      { return nil }
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+)
+
+// parseFetchSize parses a value of OptionFetchSize, empty for no limit
+func parseFetchSize(value string) (int64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	size, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	if size < 0 {
+		return 0, errors.New("must not be negative")
+	}
+	return size, nil
+}
+
+// withFetchSize returns a reader of the batches of reader split into
+// batches of at most size rows, see OptionFetchSize, or reader itself if
+// size is 0
+func withFetchSize(reader array.RecordReader, size int64) array.RecordReader {
+	if reader == nil || size <= 0 {
+		return reader
+	}
+	return &fetchSizeReader{RecordReader: reader, size: size}
+}
+
+// fetchSizeReader splits batches larger than size into slices of them,
+// which share their buffers and so take no more memory
+type fetchSizeReader struct {
+	array.RecordReader
+	size int64
+	// Batch of the reader being split, and the offset of its next slice
+	src    arrow.RecordBatch
+	offset int64
+	cur    arrow.RecordBatch
+}
+
+func (r *fetchSizeReader) Next() bool {
+	if r.cur != nil {
+		r.cur.Release()
+		r.cur = nil
+	}
+	for {
+		if r.src != nil && r.offset < r.src.NumRows() {
+			end := min(r.offset+r.size, r.src.NumRows())
+			r.cur = r.src.NewSlice(r.offset, end)
+			r.offset = end
+			return true
+		}
+		if !r.RecordReader.Next() {
+			r.src = nil
+			return false
+		}
+		r.src, r.offset = r.RecordReader.RecordBatch(), 0
+		if r.src.NumRows() <= r.size {
+			// Passed through as is, including empty batches
+			r.src.Retain()
+			r.cur, r.offset = r.src, r.src.NumRows()
+			return true
+		}
+	}
+}
+
+func (r *fetchSizeReader) RecordBatch() arrow.RecordBatch { return r.cur }
+
+func (r *fetchSizeReader) Record() arrow.RecordBatch { return r.cur }
+
+func (r *fetchSizeReader) Release() {
+	if r.cur != nil {
+		r.cur.Release()
+		r.cur = nil
+	}
+	r.src = nil
+	r.RecordReader.Release()
+}
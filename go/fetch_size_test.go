@@ -0,0 +1,74 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"testing"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetchSize(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.DefaultAllocator)
+	defer mem.AssertSize(t, 0)
+	schema := arrow.NewSchema([]arrow.Field{{Name: "id", Type: arrow.PrimitiveTypes.Int64}}, nil)
+	bldr := array.NewRecordBuilder(mem, schema)
+	defer bldr.Release()
+	var batches []arrow.RecordBatch
+	for _, n := range []int{5, 0, 2} {
+		for i := range n {
+			bldr.Field(0).(*array.Int64Builder).Append(int64(i))
+		}
+		batches = append(batches, bldr.NewRecordBatch())
+	}
+	read := func(size int64) []int64 {
+		stream, err := array.NewRecordReader(schema, batches)
+		require.NoError(t, err)
+		reader := withFetchSize(stream, size)
+		defer reader.Release()
+		var rows []int64
+		for reader.Next() {
+			rows = append(rows, reader.RecordBatch().NumRows())
+		}
+		require.NoError(t, reader.Err())
+		return rows
+	}
+
+	// Larger batches are split, others returned as they are
+	assert.Equal(t, []int64{5, 0, 2}, read(0))
+	assert.Equal(t, []int64{2, 2, 1, 0, 2}, read(2))
+	assert.Equal(t, []int64{5, 0, 2}, read(5))
+	for _, rec := range batches {
+		rec.Release()
+	}
+
+	s := &statementImpl{conn: &connectionImpl{}, fetchSize: 1000}
+	val, err := s.GetOption(OptionFetchSize)
+	require.NoError(t, err)
+	assert.Equal(t, "1000", val)
+	require.NoError(t, s.SetOptionInt(OptionFetchSize, 0))
+	val, err = s.GetOption(OptionFetchSize)
+	require.NoError(t, err)
+	assert.Empty(t, val)
+
+	var adbcErr adbc.Error
+	require.ErrorAs(t, s.SetOption(OptionFetchSize, "-1"), &adbcErr)
+	assert.Equal(t, adbc.StatusInvalidArgument, adbcErr.Code)
+}
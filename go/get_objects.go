@@ -0,0 +1,217 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"context"
+	"encoding/json"
+	"sync/atomic"
+
+	"github.com/adbc-drivers/driverbase-go/driverbase"
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+// Most rows fetched by one information_schema query; larger results are
+// read in several pages. A variable so tests can lower it.
+var metadataPageSize = 10000
+
+// objectsEnumerator is the subset of the connection used to look up
+// objects for GetObjects
+type objectsEnumerator interface {
+	GetDBSchemasForCatalog(ctx context.Context, catalog string, schemaFilter *string) ([]string, error)
+	GetTablesForDBSchema(ctx context.Context, catalog string, schema string, tableFilter *string, columnFilter *string, includeColumns bool) ([]driverbase.TableInfo, error)
+}
+
+// GetObjects implements adbc.Connection. Rather than building the whole
+// hierarchy before returning, as the driverbase implementation does, the
+// result is streamed with one record batch per catalog, each looked up
+// when the reader reaches it. Workspaces with thousands of schemas and
+// tables then only hold one catalog in memory at a time.
+func (c *connectionImpl) GetObjects(ctx context.Context, depth adbc.ObjectDepth, catalog *string, dbSchema *string, tableName *string, columnName *string, tableType []string) (array.RecordReader, error) {
+	catalogs, err := c.GetCatalogs(ctx, catalog)
+	if err != nil {
+		return nil, err
+	}
+
+	reader := &getObjectsReader{
+		ctx:        ctx,
+		mem:        c.Alloc,
+		objects:    c,
+		depth:      depth,
+		catalogs:   catalogs,
+		dbSchema:   dbSchema,
+		tableName:  tableName,
+		columnName: columnName,
+	}
+	reader.refCount.Store(1)
+	return reader, nil
+}
+
+// getObjectsReader produces the GetObjects result one catalog at a time
+type getObjectsReader struct {
+	refCount atomic.Int64
+
+	ctx        context.Context
+	mem        memory.Allocator
+	objects    objectsEnumerator
+	depth      adbc.ObjectDepth
+	catalogs   []string
+	dbSchema   *string
+	tableName  *string
+	columnName *string
+
+	next int
+	cur  arrow.RecordBatch
+	err  error
+}
+
+// lookup collects the objects of a catalog down to the requested depth
+func (r *getObjectsReader) lookup(catalog string) (driverbase.GetObjectsInfo, error) {
+	info := driverbase.GetObjectsInfo{CatalogName: driverbase.Nullable(catalog)}
+	if r.depth == adbc.ObjectDepthCatalogs {
+		return info, nil
+	}
+
+	dbSchemas, err := r.objects.GetDBSchemasForCatalog(r.ctx, catalog, r.dbSchema)
+	if err != nil {
+		return info, err
+	}
+	info.CatalogDbSchemas = make([]driverbase.DBSchemaInfo, len(dbSchemas))
+	for i, dbSchema := range dbSchemas {
+		info.CatalogDbSchemas[i] = driverbase.DBSchemaInfo{DbSchemaName: driverbase.Nullable(dbSchema)}
+		if r.depth == adbc.ObjectDepthDBSchemas {
+			continue
+		}
+
+		includeColumns := r.depth == adbc.ObjectDepthColumns
+		tables, err := r.objects.GetTablesForDBSchema(r.ctx, catalog, dbSchema, r.tableName, r.columnName, includeColumns)
+		if err != nil {
+			return info, err
+		}
+		info.CatalogDbSchemas[i].DbSchemaTables = tables
+	}
+	return info, nil
+}
+
+func (r *getObjectsReader) Next() bool {
+	if r.cur != nil {
+		r.cur.Release()
+		r.cur = nil
+	}
+	if r.err != nil || r.next >= len(r.catalogs) {
+		return false
+	}
+
+	info, err := r.lookup(r.catalogs[r.next])
+	r.next++
+	if err != nil {
+		r.err = err
+		return false
+	}
+
+	// Encode the same way as driverbase.BuildGetObjectsRecordReader
+	data, err := json.Marshal(info)
+	if err != nil {
+		r.err = err
+		return false
+	}
+	bldr := array.NewRecordBuilder(r.mem, adbc.GetObjectsSchema)
+	defer bldr.Release()
+	if err := json.Unmarshal(data, bldr); err != nil {
+		r.err = err
+		return false
+	}
+	r.cur = bldr.NewRecordBatch()
+	return true
+}
+
+func (r *getObjectsReader) Schema() *arrow.Schema {
+	return adbc.GetObjectsSchema
+}
+
+func (r *getObjectsReader) Record() arrow.RecordBatch {
+	return r.cur
+}
+
+func (r *getObjectsReader) RecordBatch() arrow.RecordBatch {
+	return r.cur
+}
+
+func (r *getObjectsReader) Err() error {
+	return r.err
+}
+
+func (r *getObjectsReader) Retain() {
+	r.refCount.Add(1)
+}
+
+func (r *getObjectsReader) Release() {
+	if r.refCount.Add(-1) == 0 && r.cur != nil {
+		r.cur.Release()
+		r.cur = nil
+	}
+}
+
+// filterExcludes returns whether name fails a GetObjects filter that the
+// server matched ignoring case, when filters must match exactly
+func (c *connectionImpl) filterExcludes(filter *string, name string) bool {
+	return c.exactFilters && filter != nil && !matchLike(*filter, name)
+}
+
+// matchLike matches s against a SQL LIKE pattern, case-sensitively: '%'
+// matches any run of characters, '_' any single character, and '\'
+// escapes the character after it
+func matchLike(pattern, s string) bool {
+	p, str := []rune(normalizeIdentifier(pattern)), []rune(normalizeIdentifier(s))
+	// Where the last '%' was seen, to backtrack to when a match fails
+	star, starStr := -1, 0
+	i, j := 0, 0
+	for j < len(str) {
+		if i < len(p) {
+			switch c := p[i]; {
+			case c == '%':
+				star, starStr = i, j
+				i++
+				continue
+			case c == '_':
+				i++
+				j++
+				continue
+			case c == '\\' && i+1 < len(p):
+				if p[i+1] == str[j] {
+					i += 2
+					j++
+					continue
+				}
+			case c == str[j]:
+				i++
+				j++
+				continue
+			}
+		}
+		if star < 0 {
+			return false
+		}
+		starStr++
+		i, j = star+1, starStr
+	}
+	for i < len(p) && p[i] == '%' {
+		i++
+	}
+	return i == len(p)
+}
@@ -0,0 +1,187 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/adbc-drivers/driverbase-go/driverbase"
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeObjects struct {
+	lookups []string
+}
+
+func (f *fakeObjects) GetDBSchemasForCatalog(ctx context.Context, catalog string, schemaFilter *string) ([]string, error) {
+	f.lookups = append(f.lookups, catalog)
+	return []string{"default", "sales"}, nil
+}
+
+func (f *fakeObjects) GetTablesForDBSchema(ctx context.Context, catalog string, schema string, tableFilter *string, columnFilter *string, includeColumns bool) ([]driverbase.TableInfo, error) {
+	return []driverbase.TableInfo{{TableName: schema + "_orders", TableType: "TABLE"}}, nil
+}
+
+func TestGetObjectsStreamsCatalogs(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.DefaultAllocator)
+	defer mem.AssertSize(t, 0)
+
+	objects := &fakeObjects{}
+	rdr := &getObjectsReader{
+		ctx:      context.Background(),
+		mem:      mem,
+		objects:  objects,
+		depth:    adbc.ObjectDepthTables,
+		catalogs: []string{"main", "samples"},
+	}
+	rdr.refCount.Store(1)
+	defer rdr.Release()
+
+	var catalogs []string
+	for rdr.Next() {
+		// Each catalog is looked up only when the reader reaches it
+		assert.Len(t, objects.lookups, len(catalogs)+1)
+
+		rec := rdr.RecordBatch()
+		require.EqualValues(t, 1, rec.NumRows())
+		catalogs = append(catalogs, rec.Column(0).(*array.String).Value(0))
+
+		dbSchemas := rec.Column(1).(*array.List)
+		assert.Equal(t, 2, dbSchemas.ListValues().Len())
+		tables := dbSchemas.ListValues().(*array.Struct).Field(1).(*array.List)
+		tableNames := tables.ListValues().(*array.Struct).Field(0).(*array.String)
+		assert.Equal(t, "sales_orders", tableNames.Value(1))
+	}
+	require.NoError(t, rdr.Err())
+	assert.Equal(t, []string{"main", "samples"}, catalogs)
+}
+
+// newPagedDriver returns a fake driver answering queries with the given
+// pages of columns in turn
+func newPagedDriver(pages ...[][]driver.Value) *fakeDriver {
+	return &fakeDriver{query: func(string, []driver.NamedValue) (driver.Rows, error) {
+		rows := &fakeRows{columns: []string{"TABLE_NAME", "ordinal_position", "COLUMN_NAME", "DATA_TYPE", "IS_NULLABLE"}}
+		if len(pages) > 0 {
+			rows.rows, pages = pages[0], pages[1:]
+		}
+		return rows, nil
+	}}
+}
+
+func TestGetTablesWithColumnsPaging(t *testing.T) {
+	pageSize := metadataPageSize
+	metadataPageSize = 2
+	defer func() { metadataPageSize = pageSize }()
+
+	column := func(table string, pos int64, name string) []driver.Value {
+		return []driver.Value{table, pos, name, "INT", "YES"}
+	}
+	drv := newPagedDriver(
+		[][]driver.Value{column("a", 0, "id"), column("a", 1, "value")},
+		[][]driver.Value{column("a", 2, "extra"), column("b", 0, "id")},
+		[][]driver.Value{column("b", 1, "value")},
+	)
+	db := sql.OpenDB(drv)
+	defer func() { _ = db.Close() }()
+	conn, err := db.Conn(context.Background())
+	require.NoError(t, err)
+	c := &connectionImpl{conn: conn}
+
+	tables, err := c.GetTablesForDBSchema(context.Background(), "main", "default", nil, nil, true)
+	require.NoError(t, err)
+	require.Len(t, tables, 2)
+	assert.Equal(t, "a", tables[0].TableName)
+	assert.Len(t, tables[0].TableColumns, 3)
+	assert.Equal(t, "b", tables[1].TableName)
+	assert.Len(t, tables[1].TableColumns, 2)
+
+	require.Len(t, drv.execs, 3)
+	assert.Contains(t, drv.execs[0], "LIMIT 2")
+	assert.NotContains(t, drv.execs[0], "c.TABLE_NAME >")
+	assert.Contains(t, drv.execs[1], "(c.TABLE_NAME > 'a' OR (c.TABLE_NAME = 'a' AND c.ordinal_position > 1))")
+	assert.Contains(t, drv.execs[2], "(c.TABLE_NAME > 'b' OR (c.TABLE_NAME = 'b' AND c.ordinal_position > 0))")
+}
+
+func TestMatchLike(t *testing.T) {
+	for _, tc := range []struct {
+		pattern, s string
+		match      bool
+	}{
+		{"orders", "orders", true},
+		{"orders", "Orders", false},
+		{"ord%", "orders", true},
+		{"%ers", "orders", true},
+		{"o%r%s", "orders", true},
+		{"%", "", true},
+		{"ord_rs", "orders", true},
+		{"ord_rs", "ordrs", false},
+		{"my\\_table", "my_table", true},
+		{"my\\_table", "myxtable", false},
+		{"%d%x", "orders", false},
+		{"ö%", "öl", true},
+	} {
+		assert.Equal(t, tc.match, matchLike(tc.pattern, tc.s), "%q LIKE %q", tc.s, tc.pattern)
+	}
+}
+
+func TestGetObjectsFilterCase(t *testing.T) {
+	d := &databaseImpl{}
+	require.NoError(t, d.SetOption(OptionGetObjectsFilterCase, "EXACT"))
+	assert.True(t, d.exactFilters)
+	var adbcErr adbc.Error
+	require.ErrorAs(t, d.SetOption(OptionGetObjectsFilterCase, "upper"), &adbcErr)
+	assert.Equal(t, adbc.StatusInvalidArgument, adbcErr.Code)
+
+	// SHOW matches ignoring case, so exact matching filters its results
+	drv := &fakeDriver{keys: []string{"Sales", "sales"}}
+	db := sql.OpenDB(drv)
+	defer func() { _ = db.Close() }()
+	conn, err := db.Conn(context.Background())
+	require.NoError(t, err)
+	filter := "sales"
+	for _, exact := range []bool{false, true} {
+		c := &connectionImpl{conn: conn, exactFilters: exact}
+		catalogs, err := c.GetCatalogs(context.Background(), &filter)
+		require.NoError(t, err)
+		if exact {
+			assert.Equal(t, []string{"sales"}, catalogs)
+		} else {
+			assert.Equal(t, []string{"Sales", "sales"}, catalogs)
+		}
+	}
+
+	// information_schema is queried with the matching operator
+	paged := newPagedDriver()
+	pagedDB := sql.OpenDB(paged)
+	defer func() { _ = pagedDB.Close() }()
+	pagedConn, err := pagedDB.Conn(context.Background())
+	require.NoError(t, err)
+	table := "Orders"
+	for _, exact := range []bool{false, true} {
+		c := &connectionImpl{conn: pagedConn, exactFilters: exact}
+		_, err := c.GetTablesForDBSchema(context.Background(), "main", "default", &table, nil, true)
+		require.NoError(t, err)
+	}
+	require.Len(t, paged.execs, 2)
+	assert.Contains(t, paged.execs[0], "c.TABLE_NAME ILIKE 'Orders'")
+	assert.Contains(t, paged.execs[1], "c.TABLE_NAME LIKE 'Orders'")
+}
@@ -0,0 +1,332 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+)
+
+// Ping checks that the connection's session is usable by running a
+// trivial query. With OptionAutoReconnect enabled, a session the server
+// no longer knows is replaced rather than reported.
+func Ping(ctx context.Context, cnxn adbc.Connection) (err error) {
+	stmt, err := cnxn.NewStatement()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errors.Join(err, stmt.Close())
+	}()
+
+	if err := stmt.SetSqlQuery("SELECT 1"); err != nil {
+		return err
+	}
+	reader, _, err := stmt.ExecuteQuery(ctx)
+	if err != nil {
+		return err
+	}
+	defer reader.Release()
+	for reader.Next() {
+	}
+	return reader.Err()
+}
+
+// sessionStatementRe matches statements changing session state, which are
+// replayed when a session is replaced
+var sessionStatementRe = regexp.MustCompile(`(?i)^\s*(SET|USE)\s`)
+
+// temporaryStateRe matches statements creating session state that is not
+// replayed: temporary views and functions, and session variables
+var temporaryStateRe = regexp.MustCompile(`(?i)^\s*(CREATE\s+(OR\s+REPLACE\s+)?(GLOBAL\s+)?TEMP(ORARY)?\s+(VIEW|FUNCTION)|DECLARE)\s`)
+
+var (
+	useCatalogRe  = regexp.MustCompile(`(?i)^\s*USE\s+CATALOG\s`)
+	setTimeZoneRe = regexp.MustCompile(`(?i)^\s*SET\s+TIME\s+ZONE\s`)
+	setPrefixRe   = regexp.MustCompile(`(?i)^\s*SET\s+`)
+)
+
+// Keys of the session state set by USE statements
+const (
+	sessionKeyCatalog = "use catalog"
+	sessionKeySchema  = "use schema"
+)
+
+// sessionStatementKey returns the session state a SET or USE statement
+// changes: the catalog, the schema, the time zone, or the key of a SET
+// statement, ignoring case and spacing
+func sessionStatementKey(query string) string {
+	switch {
+	case useCatalogRe.MatchString(query):
+		return sessionKeyCatalog
+	case !setPrefixRe.MatchString(query):
+		return sessionKeySchema
+	case setTimeZoneRe.MatchString(query):
+		return "set time zone"
+	}
+	key := setPrefixRe.ReplaceAllString(query, "")
+	if i := strings.Index(key, "="); i >= 0 {
+		key = key[:i]
+	}
+	return "set " + strings.ToLower(strings.Join(strings.Fields(key), " "))
+}
+
+// reconnectingConnector creates connections that replace their session
+// when the server reports it invalid, as it does after a warehouse
+// restart or once an idle session expires, and that can close their
+// session while idle, reopening it on next use.
+type reconnectingConnector struct {
+	driver.Connector
+	logger *slog.Logger
+	// Report sessions the server no longer knows instead of replacing
+	// them, only reopening sessions closed by suspend
+	reportLost bool
+}
+
+func (c *reconnectingConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &reconnectingConn{connector: c, conn: conn}, nil
+}
+
+// reconnectingConn forwards to the connection of the current session.
+// database/sql never uses a connection concurrently, so it is unlocked.
+type reconnectingConn struct {
+	connector *reconnectingConnector
+	// nil while suspended
+	conn driver.Conn
+
+	// SET and USE statements run on the session, in order, without those
+	// a later one overrode
+	sessionStatements []string
+	// Whether the session has temporary state that a new one would lack,
+	// so it is not closed while idle
+	temporaryState bool
+}
+
+// withSession runs fn, and if the session was lost before it ran,
+// replaces the session and runs fn again. databricks-sql-go reports an
+// invalid session handle as driver.ErrBadConn, and the server rejects
+// such requests without running them, so running fn again is safe.
+func (r *reconnectingConn) withSession(ctx context.Context, fn func() error) error {
+	if err := r.resume(ctx); err != nil {
+		return err
+	}
+	err := fn()
+	if !errors.Is(err, driver.ErrBadConn) || r.connector.reportLost {
+		return err
+	}
+	if reconnectErr := r.reconnect(ctx); reconnectErr != nil {
+		return errors.Join(err, reconnectErr)
+	}
+	return fn()
+}
+
+// reconnect opens a new session and restores the state of the lost one
+func (r *reconnectingConn) reconnect(ctx context.Context) error {
+	conn, err := r.open(ctx)
+	if err != nil {
+		return err
+	}
+
+	// The old session is gone, so closing it is expected to fail
+	_ = r.conn.Close()
+	r.conn = conn
+	if logger := r.connector.logger; logger != nil {
+		logger.Info("replaced lost session", "restored_statements", len(r.sessionStatements))
+	}
+	return nil
+}
+
+// open opens a new session with the state of the current one
+func (r *reconnectingConn) open(ctx context.Context) (driver.Conn, error) {
+	conn, err := r.connector.Connector.Connect(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open a new session: %w", err)
+	}
+	for _, query := range r.sessionStatements {
+		if _, err := conn.(driver.ExecerContext).ExecContext(ctx, query, nil); err != nil {
+			return nil, errors.Join(fmt.Errorf("failed to restore session state with %q: %w", query, err), conn.Close())
+		}
+	}
+	return conn, nil
+}
+
+// suspend closes the session, which is reopened with its state restored
+// when the connection is next used
+func (r *reconnectingConn) suspend() error {
+	if conn := r.detach(); conn != nil {
+		return conn.Close()
+	}
+	return nil
+}
+
+// detach suspends the session without closing it, returning it for the
+// caller to close, or nil if already suspended
+func (r *reconnectingConn) detach() driver.Conn {
+	conn := r.conn
+	r.conn = nil
+	return conn
+}
+
+// resume reopens a suspended session
+func (r *reconnectingConn) resume(ctx context.Context) error {
+	if r.conn != nil {
+		return nil
+	}
+	conn, err := r.open(ctx)
+	if err != nil {
+		return err
+	}
+	r.conn = conn
+	if logger := r.connector.logger; logger != nil {
+		logger.Debug("reopened idle session", "restored_statements", len(r.sessionStatements))
+	}
+	return nil
+}
+
+// record remembers a successful statement if it changes session state,
+// forgetting those setting the same state before it. Setting the catalog
+// also resets the schema, so it overrides both. Statements creating
+// temporary state are noted but not remembered.
+func (r *reconnectingConn) record(query string) {
+	if temporaryStateRe.MatchString(query) {
+		r.temporaryState = true
+	}
+	if !sessionStatementRe.MatchString(query) {
+		return
+	}
+	key := sessionStatementKey(query)
+	r.sessionStatements = slices.DeleteFunc(r.sessionStatements, func(prev string) bool {
+		prevKey := sessionStatementKey(prev)
+		return prevKey == key || (key == sessionKeyCatalog && prevKey == sessionKeySchema)
+	})
+	r.sessionStatements = append(r.sessionStatements, query)
+}
+
+func (r *reconnectingConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (result driver.Result, err error) {
+	err = r.withSession(ctx, func() error {
+		execer, ok := r.conn.(driver.ExecerContext)
+		if !ok {
+			return driver.ErrSkip
+		}
+		result, err = execer.ExecContext(ctx, query, args)
+		return err
+	})
+	if err == nil {
+		r.record(query)
+	}
+	return result, err
+}
+
+func (r *reconnectingConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (rows driver.Rows, err error) {
+	err = r.withSession(ctx, func() error {
+		queryer, ok := r.conn.(driver.QueryerContext)
+		if !ok {
+			return driver.ErrSkip
+		}
+		rows, err = queryer.QueryContext(ctx, query, args)
+		return err
+	})
+	if err == nil {
+		r.record(query)
+	}
+	return rows, err
+}
+
+func (r *reconnectingConn) Ping(ctx context.Context) error {
+	// A suspended session is not reopened just to be pinged
+	if _, ok := r.conn.(driver.Pinger); !ok {
+		return nil
+	}
+	return r.withSession(ctx, func() error {
+		return r.conn.(driver.Pinger).Ping(ctx)
+	})
+}
+
+func (r *reconnectingConn) Prepare(query string) (driver.Stmt, error) {
+	return r.PrepareContext(context.Background(), query)
+}
+
+func (r *reconnectingConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	if err := r.resume(ctx); err != nil {
+		return nil, err
+	}
+	if preparer, ok := r.conn.(driver.ConnPrepareContext); ok {
+		return preparer.PrepareContext(ctx, query)
+	}
+	return r.conn.Prepare(query)
+}
+
+func (r *reconnectingConn) Begin() (driver.Tx, error) {
+	return r.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (r *reconnectingConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if err := r.resume(ctx); err != nil {
+		return nil, err
+	}
+	if beginner, ok := r.conn.(driver.ConnBeginTx); ok {
+		return beginner.BeginTx(ctx, opts)
+	}
+	return nil, errors.New("transactions are not supported")
+}
+
+func (r *reconnectingConn) ResetSession(ctx context.Context) error {
+	if resetter, ok := r.conn.(driver.SessionResetter); ok {
+		return resetter.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (r *reconnectingConn) IsValid() bool {
+	if validator, ok := r.conn.(driver.Validator); ok {
+		return validator.IsValid()
+	}
+	return true
+}
+
+func (r *reconnectingConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if checker, ok := r.conn.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+func (r *reconnectingConn) Close() error {
+	return r.suspend()
+}
+
+var (
+	_ driver.Connector          = (*reconnectingConnector)(nil)
+	_ driver.Pinger             = (*reconnectingConn)(nil)
+	_ driver.SessionResetter    = (*reconnectingConn)(nil)
+	_ driver.Validator          = (*reconnectingConn)(nil)
+	_ driver.ExecerContext      = (*reconnectingConn)(nil)
+	_ driver.QueryerContext     = (*reconnectingConn)(nil)
+	_ driver.ConnPrepareContext = (*reconnectingConn)(nil)
+	_ driver.ConnBeginTx        = (*reconnectingConn)(nil)
+	_ driver.NamedValueChecker  = (*reconnectingConn)(nil)
+)
@@ -0,0 +1,125 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sessionConnector is a fake driver whose sessions record the statements
+// run in them, until the test expires them or they are closed
+type sessionConnector struct {
+	*fakeDriver
+	mu       sync.Mutex
+	sessions [][]string
+	expired  map[int]bool
+	closed   map[int]bool
+	// Called as sessions are closed, if set
+	onClose func(id int)
+}
+
+func newSessionConnector() *sessionConnector {
+	c := &sessionConnector{expired: map[int]bool{}, closed: map[int]bool{}}
+	c.fakeDriver = &fakeDriver{
+		before: func(_ context.Context, id int, query string) error {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			if c.expired[id] {
+				// As reported by databricks-sql-go
+				return fmt.Errorf("Invalid SessionHandle: %w", driver.ErrBadConn)
+			}
+			c.sessions[id] = append(c.sessions[id], query)
+			return nil
+		},
+		onClose: func(id int) {
+			if c.onClose != nil {
+				c.onClose(id)
+			}
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			c.closed[id] = true
+		},
+	}
+	return c
+}
+
+func (c *sessionConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sessions = append(c.sessions, nil)
+	return c.fakeDriver.Connect(ctx)
+}
+
+// isClosed reports whether the session was closed
+func (c *sessionConnector) isClosed(id int) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.closed[id]
+}
+
+func TestAutoReconnect(t *testing.T) {
+	connector := newSessionConnector()
+	db := sql.OpenDB(&reconnectingConnector{Connector: connector})
+	defer func() { _ = db.Close() }()
+
+	conn, err := db.Conn(context.Background())
+	require.NoError(t, err)
+	defer func() { _ = conn.Close() }()
+
+	ctx := context.Background()
+	for _, query := range []string{"USE CATALOG `main`", "INSERT INTO t VALUES (1)", "set ansi_mode = false"} {
+		_, err = conn.ExecContext(ctx, query)
+		require.NoError(t, err)
+	}
+
+	// After the warehouse restarts, the next statement runs in a new
+	// session with the session state restored
+	connector.expired[0] = true
+	_, err = conn.ExecContext(ctx, "INSERT INTO t VALUES (2)")
+	require.NoError(t, err)
+	require.Len(t, connector.sessions, 2)
+	assert.Equal(t, []string{"USE CATALOG `main`", "set ansi_mode = false", "INSERT INTO t VALUES (2)"}, connector.sessions[1])
+
+	// Only the statements still in effect are restored
+	for _, query := range []string{
+		"USE SCHEMA sales", "SET ansi_mode = true", "SET TIME ZONE 'UTC'", "SET spark.sql.x = 'a=b'",
+		"USE CATALOG `dev`", "USE finance", "SET TIME ZONE LOCAL", "set  ANSI_MODE=false",
+	} {
+		_, err = conn.ExecContext(ctx, query)
+		require.NoError(t, err)
+	}
+	connector.expired[1] = true
+	_, err = conn.ExecContext(ctx, "INSERT INTO t VALUES (3)")
+	require.NoError(t, err)
+	require.Len(t, connector.sessions, 3)
+	assert.Equal(t, []string{
+		"SET spark.sql.x = 'a=b'", "USE CATALOG `dev`", "USE finance", "SET TIME ZONE LOCAL", "set  ANSI_MODE=false",
+		"INSERT INTO t VALUES (3)",
+	}, connector.sessions[2])
+
+	// The error is returned if the new session cannot be used either
+	connector.expired[2] = true
+	connector.expired[3] = true
+	_, err = conn.ExecContext(ctx, "INSERT INTO t VALUES (4)")
+	require.ErrorIs(t, err, driver.ErrBadConn)
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"golang.org/x/net/http/httpguts"
+)
+
+// Headers managed by the driver that OptionHTTPHeaderPrefix cannot set
+var reservedHTTPHeaders = []string{
+	"Authorization",
+	"Content-Length",
+	"Content-Type",
+	"Host",
+	"Transfer-Encoding",
+}
+
+// setHTTPHeader handles an option with OptionHTTPHeaderPrefix, adding the
+// header or removing it if the value is empty
+func (d *databaseImpl) setHTTPHeader(key, value string) error {
+	name := http.CanonicalHeaderKey(strings.TrimPrefix(key, OptionHTTPHeaderPrefix))
+	if !httpguts.ValidHeaderFieldName(name) {
+		return adbc.Error{
+			Code: adbc.StatusInvalidArgument,
+			Msg:  fmt.Sprintf("invalid HTTP header name in option %s", key),
+		}
+	}
+	if slices.Contains(reservedHTTPHeaders, name) {
+		return adbc.Error{
+			Code: adbc.StatusInvalidArgument,
+			Msg:  fmt.Sprintf("HTTP header %s is set by the driver and cannot be overridden", name),
+		}
+	}
+	if !httpguts.ValidHeaderFieldValue(value) {
+		return adbc.Error{
+			Code: adbc.StatusInvalidArgument,
+			Msg:  fmt.Sprintf("invalid value for HTTP header %s", name),
+		}
+	}
+
+	if value == "" {
+		d.httpHeaders.Del(name)
+		return nil
+	}
+	if d.httpHeaders == nil {
+		d.httpHeaders = http.Header{}
+	}
+	d.httpHeaders.Set(name, value)
+	return nil
+}
+
+// headerTransport adds the configured headers to every request sent to the
+// workspace
+type headerTransport struct {
+	base    http.RoundTripper
+	headers http.Header
+}
+
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// Requests must not be modified by a RoundTripper
+	req = req.Clone(req.Context())
+	for name, values := range t.headers {
+		req.Header[name] = values
+	}
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPHeaderOptions(t *testing.T) {
+	var received http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+	}))
+	defer srv.Close()
+
+	d := &databaseImpl{}
+	require.NoError(t, d.SetOption(OptionHTTPHeaderPrefix+"x-cost-center", "analytics"))
+	require.NoError(t, d.SetOption(OptionHTTPHeaderPrefix+"X-Gateway-Token", "secret"))
+	require.NoError(t, d.SetOption(OptionHTTPHeaderPrefix+"X-Removed", "value"))
+	require.NoError(t, d.SetOption(OptionHTTPHeaderPrefix+"X-Removed", ""))
+
+	val, err := d.GetOption(OptionHTTPHeaderPrefix + "X-Cost-Center")
+	require.NoError(t, err)
+	assert.Equal(t, "analytics", val)
+
+	client := &http.Client{Transport: &headerTransport{base: http.DefaultTransport, headers: d.httpHeaders}}
+	req, err := http.NewRequest(http.MethodPost, srv.URL, nil)
+	require.NoError(t, err)
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+
+	assert.Equal(t, "analytics", received.Get("X-Cost-Center"))
+	assert.Equal(t, "secret", received.Get("X-Gateway-Token"))
+	assert.Empty(t, received.Get("X-Removed"))
+	assert.Empty(t, req.Header, "the caller's request should not be modified")
+
+	for _, key := range []string{
+		OptionHTTPHeaderPrefix + "Authorization",
+		OptionHTTPHeaderPrefix + "bad header",
+	} {
+		var adbcErr adbc.Error
+		require.ErrorAs(t, d.SetOption(key, "value"), &adbcErr, key)
+		assert.Equal(t, adbc.StatusInvalidArgument, adbcErr.Code)
+	}
+}
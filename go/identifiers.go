@@ -0,0 +1,72 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Longest name of a Unity Catalog object, in characters
+const maxIdentifierLen = 255
+
+// normalizeIdentifier returns name in Unicode normalization form C, so
+// that names typed with combining characters, as macOS input produces,
+// name the same objects as their precomposed forms
+func normalizeIdentifier(name string) string {
+	return norm.NFC.String(name)
+}
+
+// identifierKey returns the form in which Databricks compares names,
+// which ignores case
+func identifierKey(name string) string {
+	return strings.ToLower(normalizeIdentifier(name))
+}
+
+// validateNewIdentifier checks that a table or column about to be created
+// has a name Databricks accepts. Unity Catalog also rejects periods,
+// spaces and slashes in table names, which columns may contain.
+func validateNewIdentifier(kind, name string) error {
+	invalid := func(reason string) error {
+		return adbc.Error{
+			Code: adbc.StatusInvalidArgument,
+			Msg:  fmt.Sprintf("invalid %s name %q: %s", kind, name, reason),
+		}
+	}
+	if name == "" {
+		return adbc.Error{
+			Code: adbc.StatusInvalidArgument,
+			Msg:  fmt.Sprintf("%s name cannot be empty", kind),
+		}
+	}
+	if !utf8.ValidString(name) {
+		return invalid("it is not valid UTF-8")
+	}
+	if n := utf8.RuneCountInString(normalizeIdentifier(name)); n > maxIdentifierLen {
+		return invalid(fmt.Sprintf("it has %d characters, more than %d", n, maxIdentifierLen))
+	}
+	if strings.ContainsFunc(name, unicode.IsControl) {
+		return invalid("it contains control characters")
+	}
+	if kind == "table" && strings.ContainsAny(name, "./ ") {
+		return invalid("it contains a period, slash or space")
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// "café" with the accent as a combining character
+const decomposedCafe = "cafe\u0301"
+
+func TestQuoteUnicodeIdentifiers(t *testing.T) {
+	for name, want := range map[string]string{
+		"café":           "`café`",
+		decomposedCafe:   "`café`",
+		"売上":             "`売上`",
+		"👩‍💻 team":       "`👩‍💻 team`",
+		"`":              "````",
+		"é`":             "`é```",
+		"`é`":            "```é```",
+		"naïve``quoted`": "`naïve````quoted```",
+	} {
+		assert.Equal(t, want, quoteIdentifier(name), name)
+	}
+
+	assert.Equal(t, `'C:\\data\\''s'`, quoteString(`C:\data\'s`))
+	assert.Equal(t, identifierKey("CAFÉ"), identifierKey(decomposedCafe))
+}
+
+func TestValidateNewIdentifier(t *testing.T) {
+	for _, name := range []string{"café", "売上", "👩‍💻", "order`s", strings.Repeat("é", maxIdentifierLen)} {
+		assert.NoError(t, validateNewIdentifier("table", name), name)
+	}
+	// Columns, unlike tables, may contain periods and spaces
+	assert.NoError(t, validateNewIdentifier("column", "unit price.usd"))
+
+	for kind, names := range map[string][]string{
+		"table":  {"", "sales.events", "a b", "a/b", "tab\tle", "\xff", strings.Repeat("é", maxIdentifierLen+1)},
+		"column": {"", "line\nbreak", "nul\x00"},
+	} {
+		for _, name := range names {
+			err := validateNewIdentifier(kind, name)
+			var adbcErr adbc.Error
+			require.ErrorAs(t, err, &adbcErr, "%s %q", kind, name)
+			assert.Equal(t, adbc.StatusInvalidArgument, adbcErr.Code)
+		}
+	}
+
+	// Control characters are reported escaped
+	schema := arrow.NewSchema([]arrow.Field{{Name: "bad\x1bname", Type: arrow.PrimitiveTypes.Int64}}, nil)
+	_, err := buildCreateTableSQL("`events`", schema, false, nil)
+	assert.ErrorContains(t, err, `invalid column name "bad\x1bname": it contains control characters`)
+}
+
+func TestUnicodeCreateTable(t *testing.T) {
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: decomposedCafe, Type: arrow.PrimitiveTypes.Int64},
+		{Name: "a`b", Type: arrow.BinaryTypes.String, Nullable: true},
+	}, nil)
+	sql, err := buildCreateTableSQL(quoteIdentifier("売上"), schema, false, []string{"CAFÉ"})
+	require.NoError(t, err)
+	assert.Equal(t, "CREATE TABLE `売上` (`café` BIGINT NOT NULL, `a``b` STRING)", sql)
+}
+
+func TestUnicodeGetObjectsFilters(t *testing.T) {
+	assert.True(t, matchLike(decomposedCafe+"%", "café_orders"))
+	assert.True(t, matchLike("café", decomposedCafe))
+	assert.False(t, matchLike("cafe_", decomposedCafe+"s"))
+
+	drv := &fakeDriver{}
+	db := sql.OpenDB(drv)
+	defer func() { _ = db.Close() }()
+	conn, err := db.Conn(context.Background())
+	require.NoError(t, err)
+	c := &connectionImpl{conn: conn}
+	filter := decomposedCafe + `\_%`
+	_, err = c.GetCatalogs(context.Background(), &filter)
+	require.NoError(t, err)
+	_, err = c.getDBSchemasForCatalog(context.Background(), "売上`", &filter)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		`SHOW CATALOGS LIKE 'café\\_%'`,
+		"SHOW SCHEMAS IN `売上``` LIKE 'café\\\\_%'",
+	}, drv.execs)
+}
+
+func TestUnicodeFailedColumn(t *testing.T) {
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "Prénom`", Type: arrow.BinaryTypes.String},
+	}, nil)
+	err := errors.New("[CAST_INVALID_INPUT] cannot cast value in column `pre\u0301nom```")
+	assert.Equal(t, 1, failedColumn(schema, err))
+	assert.Equal(t, -1, failedColumn(schema, errors.New("[CAST_INVALID_INPUT] column `prenom`")))
+}
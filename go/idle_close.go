@@ -0,0 +1,137 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"context"
+	"database/sql/driver"
+	"sync/atomic"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow/array"
+)
+
+// minIdleCloseAfter is the shortest OptionIdleCloseAfter, as sessions are
+// checked four times as often
+const minIdleCloseAfter = time.Second
+
+// idleClose closes a connection's session once it has been idle for a
+// while, so that it does not keep the warehouse from suspending. The
+// session is reopened by reconnectingConn when the connection is next
+// used.
+type idleClose struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func startIdleClose(c *connectionImpl, after time.Duration) *idleClose {
+	ctx, cancel := context.WithCancel(context.Background())
+	ic := &idleClose{cancel: cancel, done: make(chan struct{})}
+	go func() {
+		defer close(ic.done)
+		// The session is closed between after and 1.25 times after
+		ticker := time.NewTicker(after / 4)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				c.closeIdleSession(after)
+			}
+		}
+	}()
+	return ic
+}
+
+// stop ends the checks, waiting for one in progress
+func (ic *idleClose) stop() {
+	if ic == nil {
+		return
+	}
+	ic.cancel()
+	<-ic.done
+}
+
+// closeIdleSession suspends the session if the connection has not been
+// used for idle. A session with operations in progress, result readers
+// still open, changes not yet committed, or temporary state that cannot
+// be restored is left alone, as is a lazily connected connection without
+// a session.
+//
+// The session is detached while no operation can start, and closed
+// after, so that operations starting during the round trip reopen a
+// session rather than fail.
+func (c *connectionImpl) closeIdleSession(idle time.Duration) {
+	if time.Since(time.Unix(0, c.lastUsed.Load())) < idle {
+		return
+	}
+
+	// Operations are counted under connectMu as they start
+	c.connectMu.Lock()
+	if c.conn == nil || c.active.Load() > 0 || c.openReaders.Load() > 0 || c.txMode != transactionNone {
+		c.connectMu.Unlock()
+		return
+	}
+	var session driver.Conn
+	err := c.conn.Raw(func(driverConn any) error {
+		if r, ok := driverConn.(*reconnectingConn); ok && !r.temporaryState {
+			session = r.detach()
+		}
+		return nil
+	})
+	c.connectMu.Unlock()
+	if err == nil && session != nil {
+		err = session.Close()
+	}
+
+	if c.Logger == nil {
+		return
+	}
+	if err != nil {
+		c.Logger.Warn("failed to close idle session", "error", err)
+	} else if session != nil {
+		c.Logger.Debug("closed idle session", "idle", idle)
+	}
+}
+
+// sessionReader is a result reader reading from the connection's
+// session, which is not closed for idleness until its last reference is
+// released
+type sessionReader struct {
+	array.RecordReader
+	conn *connectionImpl
+	refs atomic.Int64
+}
+
+func newSessionReader(reader array.RecordReader, conn *connectionImpl) *sessionReader {
+	conn.openReaders.Add(1)
+	r := &sessionReader{RecordReader: reader, conn: conn}
+	r.refs.Store(1)
+	return r
+}
+
+func (r *sessionReader) Retain() {
+	r.refs.Add(1)
+	r.RecordReader.Retain()
+}
+
+func (r *sessionReader) Release() {
+	r.RecordReader.Release()
+	if r.refs.Add(-1) == 0 {
+		r.conn.lastUsed.Store(time.Now().UnixNano())
+		r.conn.openReaders.Add(-1)
+	}
+}
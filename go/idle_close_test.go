@@ -0,0 +1,166 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdleClose(t *testing.T) {
+	connector := newSessionConnector()
+	db := sql.OpenDB(&reconnectingConnector{Connector: connector, reportLost: true})
+	defer func() { _ = db.Close() }()
+	conn, err := db.Conn(context.Background())
+	require.NoError(t, err)
+	c := &connectionImpl{conn: conn}
+
+	ctx := context.Background()
+	_, err = conn.ExecContext(ctx, "USE CATALOG `main`")
+	require.NoError(t, err)
+
+	// Recently used, with a reader open, or in a transaction, the session
+	// is kept
+	c.lastUsed.Store(time.Now().UnixNano())
+	c.closeIdleSession(time.Minute)
+	c.lastUsed.Store(time.Now().Add(-time.Hour).UnixNano())
+	results, err := array.NewRecordReader(arrow.NewSchema(nil, nil), nil)
+	require.NoError(t, err)
+	reader := newSessionReader(results, c)
+	c.closeIdleSession(time.Minute)
+	// Nor while a reference retained by the caller is left
+	reader.Retain()
+	reader.Release()
+	assert.Equal(t, int64(1), c.openReaders.Load())
+	c.closeIdleSession(time.Minute)
+	reader.Release()
+	assert.Zero(t, c.openReaders.Load())
+	c.lastUsed.Store(time.Now().Add(-time.Hour).UnixNano())
+	c.txMode = transactionNative
+	c.closeIdleSession(time.Minute)
+	assert.Empty(t, connector.closed)
+
+	// Idle, it is closed, and reopened with its state on next use
+	c.txMode = transactionNone
+	c.lastUsed.Store(time.Now().Add(-time.Hour).UnixNano())
+	c.closeIdleSession(time.Minute)
+	assert.Equal(t, map[int]bool{0: true}, connector.closed)
+	_, err = conn.ExecContext(ctx, "INSERT INTO t VALUES (1)")
+	require.NoError(t, err)
+	require.Len(t, connector.sessions, 2)
+	assert.Equal(t, []string{"USE CATALOG `main`", "INSERT INTO t VALUES (1)"}, connector.sessions[1])
+
+	// Sessions with temporary state are kept, as it would be lost
+	_, err = conn.ExecContext(ctx, "CREATE OR REPLACE TEMPORARY VIEW recent AS SELECT 1")
+	require.NoError(t, err)
+	c.lastUsed.Store(time.Now().Add(-time.Hour).UnixNano())
+	c.closeIdleSession(time.Minute)
+	assert.False(t, connector.isClosed(1))
+
+	// Without OptionAutoReconnect, lost sessions are still reported
+	connector.expired[1] = true
+	_, err = conn.ExecContext(ctx, "INSERT INTO t VALUES (2)")
+	require.ErrorIs(t, err, driver.ErrBadConn)
+	assert.Len(t, connector.sessions, 2)
+}
+
+func TestIdleCloseAfterOption(t *testing.T) {
+	d := &databaseImpl{}
+	require.NoError(t, d.SetOption(OptionIdleCloseAfter, "10m"))
+	val, err := d.GetOption(OptionIdleCloseAfter)
+	require.NoError(t, err)
+	assert.Equal(t, "10m0s", val)
+	require.NoError(t, d.SetOption(OptionIdleCloseAfter, "0"))
+	assert.Zero(t, d.idleCloseAfter)
+
+	// Sessions are checked at a quarter of the time, which must be
+	// positive
+	for _, value := range []string{"-1s", "3ns", "999ms", "soon"} {
+		var adbcErr adbc.Error
+		require.ErrorAs(t, d.SetOption(OptionIdleCloseAfter, value), &adbcErr, value)
+		assert.Equal(t, adbc.StatusInvalidArgument, adbcErr.Code, value)
+	}
+}
+
+func TestIdleCloseDuringOperation(t *testing.T) {
+	connector := newSessionConnector()
+	closing, closed := make(chan struct{}), make(chan struct{})
+	connector.onClose = func(id int) {
+		if id == 0 {
+			close(closing)
+			<-closed
+		}
+	}
+	db := sql.OpenDB(&reconnectingConnector{Connector: connector})
+	defer func() { _ = db.Close() }()
+	conn, err := db.Conn(context.Background())
+	require.NoError(t, err)
+	c := &connectionImpl{conn: conn}
+	_, err = conn.ExecContext(context.Background(), "USE CATALOG `main`")
+	require.NoError(t, err)
+
+	c.lastUsed.Store(time.Now().Add(-time.Hour).UnixNano())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		c.closeIdleSession(time.Minute)
+	}()
+	<-closing
+
+	// While the server closes the idle session, statements run in a new
+	// one with its state
+	require.NoError(t, c.acquire())
+	_, err = c.conn.ExecContext(context.Background(), "INSERT INTO t VALUES (1)")
+	c.release()
+	require.NoError(t, err)
+	close(closed)
+	<-done
+	assert.True(t, connector.isClosed(0))
+	require.Len(t, connector.sessions, 2)
+	assert.Equal(t, []string{"USE CATALOG `main`", "INSERT INTO t VALUES (1)"}, connector.sessions[1])
+
+	// An operation in progress keeps the session
+	c.lastUsed.Store(time.Now().Add(-time.Hour).UnixNano())
+	require.NoError(t, c.acquire())
+	c.lastUsed.Store(time.Now().Add(-time.Hour).UnixNano())
+	c.closeIdleSession(time.Minute)
+	c.release()
+	assert.False(t, connector.isClosed(1))
+}
+
+func TestIdleCloseBackground(t *testing.T) {
+	connector := newSessionConnector()
+	db := sql.OpenDB(&reconnectingConnector{Connector: connector})
+	defer func() { _ = db.Close() }()
+	conn, err := db.Conn(context.Background())
+	require.NoError(t, err)
+
+	c := &connectionImpl{conn: conn}
+	c.lastUsed.Store(time.Now().UnixNano())
+	c.idleClose = startIdleClose(c, 20*time.Millisecond)
+	require.Eventually(t, func() bool {
+		return connector.isClosed(0)
+	}, 5*time.Second, 5*time.Millisecond)
+	require.NoError(t, c.Close())
+}
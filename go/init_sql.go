@@ -0,0 +1,91 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// splitSQLStatements splits SQL text at semicolons outside of quoted
+// strings, identifiers and comments, dropping empty statements
+func splitSQLStatements(text string) []string {
+	var statements []string
+	start := 0
+	add := func(end int) {
+		if stmt := strings.TrimSpace(text[start:end]); stmt != "" {
+			statements = append(statements, stmt)
+		}
+	}
+
+	for i := 0; i < len(text); i++ {
+		switch c := text[i]; {
+		case c == '\'' || c == '"' || c == '`':
+			// Skip to the closing quote; backslashes escape in strings
+			for i++; i < len(text) && text[i] != c; i++ {
+				if text[i] == '\\' && c != '`' {
+					i++
+				}
+			}
+		case c == '-' && strings.HasPrefix(text[i:], "--"):
+			if end := strings.IndexByte(text[i:], '\n'); end >= 0 {
+				i += end
+			} else {
+				i = len(text)
+			}
+		case c == '/' && strings.HasPrefix(text[i:], "/*"):
+			if end := strings.Index(text[i+2:], "*/"); end >= 0 {
+				i += end + 3
+			} else {
+				i = len(text)
+			}
+		case c == ';':
+			add(i)
+			start = i + 1
+		}
+	}
+	if start < len(text) {
+		add(len(text))
+	}
+	return statements
+}
+
+// initConnector runs statements on each session it opens before handing
+// it out, so that sessions opened later, as by the pool or to replace a
+// lost session, are set up like the first
+type initConnector struct {
+	driver.Connector
+	statements []string
+}
+
+func (c *initConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	execer, ok := conn.(driver.ExecerContext)
+	if !ok {
+		return nil, errors.Join(errors.New("the driver connection cannot execute statements"), conn.Close())
+	}
+	for _, stmt := range c.statements {
+		if _, err := execer.ExecContext(ctx, stmt, nil); err != nil {
+			return nil, errors.Join(fmt.Errorf("%s statement %q failed: %w", OptionInitSQL, stmt, err), conn.Close())
+		}
+	}
+	return conn, nil
+}
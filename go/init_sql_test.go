@@ -0,0 +1,62 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitSQLStatements(t *testing.T) {
+	for _, tc := range []struct {
+		text string
+		want []string
+	}{
+		{"", nil},
+		{" ; ;", nil},
+		{"USE CATALOG main", []string{"USE CATALOG main"}},
+		{"USE CATALOG main; SET query_tags = 'a;b';\n", []string{"USE CATALOG main", "SET query_tags = 'a;b'"}},
+		{"SELECT 'it\\'s;'; SELECT `a;b`", []string{"SELECT 'it\\'s;'", "SELECT `a;b`"}},
+		{"SELECT 1 -- one; two\n; /* three; */ SELECT 2", []string{"SELECT 1 -- one; two", "/* three; */ SELECT 2"}},
+		{"CREATE TEMPORARY FUNCTION f(x INT) RETURNS INT RETURN x + 1", []string{"CREATE TEMPORARY FUNCTION f(x INT) RETURNS INT RETURN x + 1"}},
+	} {
+		assert.Equal(t, tc.want, splitSQLStatements(tc.text), tc.text)
+	}
+}
+
+func TestInitConnector(t *testing.T) {
+	sessions := newSessionConnector()
+	connector := &initConnector{Connector: sessions, statements: []string{"USE CATALOG main", "SET query_tags = 'etl'"}}
+
+	for range 2 {
+		conn, err := connector.Connect(context.Background())
+		require.NoError(t, err)
+		require.NoError(t, conn.Close())
+	}
+	assert.Equal(t, [][]string{
+		{"USE CATALOG main", "SET query_tags = 'etl'"},
+		{"USE CATALOG main", "SET query_tags = 'etl'"},
+	}, sessions.sessions)
+
+	// A session whose statements fail is not handed out
+	sessions.expired[2] = true
+	_, err := connector.Connect(context.Background())
+	assert.ErrorIs(t, err, driver.ErrBadConn)
+	assert.ErrorContains(t, err, `databricks.init_sql statement "USE CATALOG main" failed`)
+}
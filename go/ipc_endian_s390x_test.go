@@ -0,0 +1,32 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build s390x
+
+package databricks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIPCReaderAdapterSwapsByteOrder(t *testing.T) {
+	_, rec := readLittleEndianFixture(t)
+
+	// The value 1 as stored in the result is big-endian, not the
+	// little-endian bytes received
+	values := rec.Column(1).Data().Buffers()[1].Bytes()
+	assert.Equal(t, []byte{0, 0, 0, 0, 0, 0, 0, 1}, values[:8])
+}
@@ -0,0 +1,125 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"context"
+	"math"
+	"os"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/decimal128"
+	"github.com/apache/arrow-go/v18/arrow/endian"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readLittleEndianFixture reads testdata/little_endian.arrows, an IPC
+// stream written on a little-endian platform as Databricks sends them,
+// through the reader adapter
+func readLittleEndianFixture(t *testing.T) (*arrow.Schema, arrow.RecordBatch) {
+	data, err := os.ReadFile("testdata/little_endian.arrows")
+	require.NoError(t, err)
+
+	rows := &mockRows{iterator: &mockIPCStreamIterator{streams: [][]byte{data}, schema: data}}
+	reader, err := newIPCReaderAdapter(context.Background(), rows, &resultStats{}, nil)
+	require.NoError(t, err)
+	t.Cleanup(reader.Release)
+
+	require.True(t, reader.Next())
+	rec := reader.RecordBatch()
+	rec.Retain()
+	t.Cleanup(rec.Release)
+	require.False(t, reader.Next())
+	require.NoError(t, reader.Err())
+	return reader.Schema(), rec
+}
+
+func TestIPCReaderAdapterLittleEndianStream(t *testing.T) {
+	schema, rec := readLittleEndianFixture(t)
+
+	// Buffers are converted to the platform's byte order on big-endian
+	// platforms, so values read the same everywhere
+	assert.Equal(t, endian.NativeEndian, schema.Endianness())
+	assert.Equal(t, []int32{1, -2, 0x01020304}, rec.Column(0).(*array.Int32).Int32Values())
+	assert.Equal(t, []int64{1, -2, 0x0102030405060708}, rec.Column(1).(*array.Int64).Int64Values())
+	assert.Equal(t, []float64{1.5, -2.25, 1e300}, rec.Column(2).(*array.Float64).Float64Values())
+
+	for row, expected := range []any{"1", "-2", "72623859790382856"} {
+		val, err := extractGoValue(rec.Column(1), row)
+		require.NoError(t, err)
+		assert.Equal(t, expected, val)
+	}
+}
+
+func TestExtractGoValueRanges(t *testing.T) {
+	mem := memory.DefaultAllocator
+
+	int64s := array.NewInt64Builder(mem)
+	int64s.AppendValues([]int64{math.MinInt64, math.MaxInt64}, nil)
+	int64Arr := int64s.NewArray()
+	defer int64Arr.Release()
+
+	uint32s := array.NewUint32Builder(mem)
+	uint32s.AppendValues([]uint32{math.MaxUint32}, nil)
+	uint32Arr := uint32s.NewArray()
+	defer uint32Arr.Release()
+
+	uint64s := array.NewUint64Builder(mem)
+	uint64s.AppendValues([]uint64{math.MaxUint64}, nil)
+	uint64Arr := uint64s.NewArray()
+	defer uint64Arr.Release()
+
+	int32s := array.NewInt32Builder(mem)
+	int32s.AppendValues([]int32{math.MinInt32}, nil)
+	int32Arr := int32s.NewArray()
+	defer int32Arr.Release()
+
+	fixed := array.NewFixedSizeBinaryBuilder(mem, &arrow.FixedSizeBinaryType{ByteWidth: 4})
+	fixed.Append([]byte{0x01, 0x02, 0x03, 0xff})
+	fixedArr := fixed.NewArray()
+	defer fixedArr.Release()
+
+	decs := array.NewDecimal128Builder(mem, &arrow.Decimal128Type{Precision: 38, Scale: 2})
+	decs.Append(decimal128.New(-1, math.MaxUint64)) // -1 as two's complement
+	decs.Append(decimal128.FromI64(math.MaxInt64))
+	decs.Append(decimal128.FromI64(5))
+	decArr := decs.NewArray()
+	defer decArr.Release()
+
+	for _, tc := range []struct {
+		arr      arrow.Array
+		idx      int
+		expected any
+	}{
+		{int64Arr, 0, "-9223372036854775808"},
+		{int64Arr, 1, "9223372036854775807"},
+		{uint32Arr, 0, "4294967295"},
+		{uint64Arr, 0, "18446744073709551615"},
+		{int32Arr, 0, int64(math.MinInt32)},
+		// Bytes are written in order regardless of platform byte order
+		{fixedArr, 0, "010203ff"},
+		{decArr, 0, "-0.01"},
+		{decArr, 1, "92233720368547758.07"},
+		{decArr, 2, "0.05"},
+	} {
+		val, err := extractGoValue(tc.arr, tc.idx)
+		require.NoError(t, err)
+		assert.Equal(t, tc.expected, val, "%s[%d]", tc.arr.DataType(), tc.idx)
+	}
+}
@@ -35,23 +35,70 @@ import (
 	"github.com/apache/arrow-go/v18/arrow"
 	"github.com/apache/arrow-go/v18/arrow/array"
 	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/apache/arrow-go/v18/arrow/memory"
 	dbsqlrows "github.com/databricks/databricks-sql-go/rows"
 )
 
 // ipcReaderAdapter uses the new IPC stream interface for Arrow access
 type ipcReaderAdapter struct {
+	ctx           context.Context
 	rows          driver.Rows
 	ipcIterator   dbsqlrows.ArrowIPCStreamIterator
 	currentReader *ipc.Reader
 	currentRecord arrow.RecordBatch
 	schema        *arrow.Schema
 	closed        bool
-	refCount      int64
+	refCount      atomic.Int64
 	err           error
+	stats         *resultStats
+	mem           memory.Allocator
 }
 
-// newIPCReaderAdapter creates a RecordReader using direct IPC stream access
-func newIPCReaderAdapter(ctx context.Context, rows driver.Rows) (array.RecordReader, error) {
+// resultStats counts what has been fetched of a result set
+type resultStats struct {
+	chunks   atomic.Int64
+	batches  atomic.Int64
+	rows     atomic.Int64
+	bytes    atomic.Int64
+	complete atomic.Bool
+
+	// manifest describes the whole result, if known before it is read
+	manifest *submittedManifest
+}
+
+// total returns the size of the whole result from the manifest if there is
+// one, or else the count fetched once the result is read in full, or else
+// -1 as it is not known
+func (r *resultStats) total(fetched *atomic.Int64, fromManifest func(submittedManifest) int64) int64 {
+	switch {
+	case r.manifest != nil:
+		return fromManifest(*r.manifest)
+	case r.complete.Load():
+		return fetched.Load()
+	}
+	return -1
+}
+
+// countingReader counts the bytes read from the wrapped reader
+type countingReader struct {
+	r io.Reader
+	n *atomic.Int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n.Add(int64(n))
+	return n, err
+}
+
+// newIPCReaderAdapter creates a RecordReader using direct IPC stream access,
+// counting what is fetched in stats as streams are consumed. Record
+// batches are allocated from mem, or the default allocator if it is nil.
+func newIPCReaderAdapter(ctx context.Context, rows driver.Rows, stats *resultStats, mem memory.Allocator) (array.RecordReader, error) {
+	if mem == nil {
+		mem = memory.DefaultAllocator
+	}
+
 	ipcRows, ok := rows.(dbsqlrows.Rows)
 	if !ok {
 		return nil, adbc.Error{
@@ -69,18 +116,28 @@ func newIPCReaderAdapter(ctx context.Context, rows driver.Rows) (array.RecordRea
 		}
 	}
 
+	return newIPCStreamReader(ctx, ipcIterator, rows, stats, mem)
+}
+
+// newIPCStreamReader creates a RecordReader over the streams of
+// ipcIterator, closing rows, if not nil, when released or once reading
+// fails after ctx has ended
+func newIPCStreamReader(ctx context.Context, ipcIterator dbsqlrows.ArrowIPCStreamIterator, rows driver.Rows, stats *resultStats, mem memory.Allocator) (array.RecordReader, error) {
 	adapter := &ipcReaderAdapter{
+		ctx:         ctx,
 		rows:        rows,
-		refCount:    1,
 		ipcIterator: ipcIterator,
+		stats:       stats,
+		mem:         mem,
 	}
+	adapter.refCount.Store(1)
 
 	// Load the first IPC stream to get the schema.
 	// Note: SchemaBytes() may return empty bytes if no direct results were
 	// returned with the query response. The schema is populated lazily
 	// during the first data fetch in databricks-sql-go. By loading the
 	// first reader, we ensure the schema is available.
-	err = adapter.loadNextReader()
+	err := adapter.loadNextReader()
 	if err != nil && err != io.EOF {
 		return nil, adbc.Error{
 			Code: adbc.StatusInternal,
@@ -138,6 +195,7 @@ func (r *ipcReaderAdapter) loadNextReader() error {
 
 	// Get next IPC stream
 	if !r.ipcIterator.HasNext() {
+		r.stats.complete.Store(true)
 		return io.EOF
 	}
 
@@ -145,10 +203,14 @@ func (r *ipcReaderAdapter) loadNextReader() error {
 	if err != nil {
 		return err
 	}
+	r.stats.chunks.Add(1)
 
 	// Create IPC reader from stream
-	reader, err := ipc.NewReader(ipcStream)
+	reader, err := ipc.NewReader(&countingReader{r: ipcStream, n: &r.stats.bytes}, ipc.WithAllocator(r.mem))
 	if err != nil {
+		if limitErr := memoryLimitError(err); limitErr != err {
+			return limitErr
+		}
 		return adbc.Error{
 			Code: adbc.StatusInternal,
 			Msg:  fmt.Sprintf("failed to create IPC reader: %v", err),
@@ -178,30 +240,71 @@ func (r *ipcReaderAdapter) Next() bool {
 
 	// Try to get next record from current reader
 	if r.currentReader != nil && r.currentReader.Next() {
-		r.currentRecord = r.currentReader.RecordBatch()
-		r.currentRecord.Retain()
+		r.setCurrentRecord(r.currentReader.RecordBatch())
 		return true
 	}
+	if r.readerFailed() {
+		return false
+	}
 
 	// Need to load next IPC stream
 	err := r.loadNextReader()
 	if err == io.EOF {
 		return false
 	} else if err != nil {
-		r.err = err
+		r.fail(memoryLimitError(err))
 		return false
 	}
 
 	// Try again with new reader
 	if r.currentReader != nil && r.currentReader.Next() {
-		r.currentRecord = r.currentReader.RecordBatch()
-		r.currentRecord.Retain()
+		r.setCurrentRecord(r.currentReader.RecordBatch())
 		return true
 	}
+	r.readerFailed()
 
 	return false
 }
 
+// readerFailed records the error of the current IPC stream, if reading it
+// failed rather than ended
+func (r *ipcReaderAdapter) readerFailed() bool {
+	if r.currentReader == nil || r.currentReader.Err() == nil {
+		return false
+	}
+	r.fail(memoryLimitError(r.currentReader.Err()))
+	return true
+}
+
+// fail records the error reading results failed with. If the context has
+// ended, the results are closed on the server right away rather than once
+// the reader is released, which callers giving up may take long to do.
+func (r *ipcReaderAdapter) fail(err error) {
+	r.err = err
+	if r.ctx != nil && r.ctx.Err() != nil {
+		r.closeResults()
+	}
+}
+
+// closeResults stops fetching results and closes them on the server
+func (r *ipcReaderAdapter) closeResults() {
+	if r.ipcIterator != nil {
+		r.ipcIterator.Close()
+		r.ipcIterator = nil
+	}
+	if r.rows != nil {
+		r.err = errors.Join(r.err, r.rows.Close())
+		r.rows = nil
+	}
+}
+
+func (r *ipcReaderAdapter) setCurrentRecord(rec arrow.RecordBatch) {
+	rec.Retain()
+	r.currentRecord = rec
+	r.stats.batches.Add(1)
+	r.stats.rows.Add(rec.NumRows())
+}
+
 func (r *ipcReaderAdapter) Record() arrow.RecordBatch {
 	return r.currentRecord
 }
@@ -211,7 +314,7 @@ func (r *ipcReaderAdapter) RecordBatch() arrow.RecordBatch {
 }
 
 func (r *ipcReaderAdapter) Release() {
-	if atomic.AddInt64(&r.refCount, -1) <= 0 {
+	if r.refCount.Add(-1) <= 0 {
 		if r.closed {
 			panic("Double cleanup on ipc_reader_adapter - was Release() called with a closed reader?")
 		}
@@ -231,17 +334,12 @@ func (r *ipcReaderAdapter) Release() {
 			r.schema = nil
 		}
 
-		r.ipcIterator.Close()
-
-		if r.rows != nil {
-			r.err = errors.Join(r.err, r.rows.Close())
-			r.rows = nil
-		}
+		r.closeResults()
 	}
 }
 
 func (r *ipcReaderAdapter) Retain() {
-	atomic.AddInt64(&r.refCount, 1)
+	r.refCount.Add(1)
 }
 
 func (r *ipcReaderAdapter) Err() error {
@@ -73,6 +73,7 @@ func (m *mockIPCStreamIterator) SchemaBytes() ([]byte, error) {
 // mockRows implements the subset of dbsqlrows.Rows needed for testing
 type mockRows struct {
 	iterator dbsqlrows.ArrowIPCStreamIterator
+	closed   int
 }
 
 func (m *mockRows) GetArrowIPCStreams(ctx context.Context) (dbsqlrows.ArrowIPCStreamIterator, error) {
@@ -92,6 +93,7 @@ func (m *mockRows) Next(dest []driver.Value) error {
 }
 
 func (m *mockRows) Close() error {
+	m.closed++
 	return nil
 }
 
@@ -148,7 +150,7 @@ func TestIPCReaderAdapter(t *testing.T) {
 
 	// Test the IPC reader adapter
 	ctx := context.Background()
-	reader, err := newIPCReaderAdapter(ctx, mockRows)
+	reader, err := newIPCReaderAdapter(ctx, mockRows, &resultStats{}, nil)
 	require.NoError(t, err)
 	defer reader.Release()
 
@@ -245,10 +247,15 @@ func TestIPCReaderAdapterMultipleStreams(t *testing.T) {
 
 	// Test the adapter
 	ctx := context.Background()
-	reader, err := newIPCReaderAdapter(ctx, mockRows)
+	stats := &resultStats{}
+	reader, err := newIPCReaderAdapter(ctx, mockRows, stats, nil)
 	require.NoError(t, err)
 	defer reader.Release()
 
+	// Only the first stream is loaded up front
+	assert.EqualValues(t, 1, stats.chunks.Load())
+	assert.False(t, stats.complete.Load())
+
 	// Read all batches
 	rowCount := 0
 	batchCount := 0
@@ -266,4 +273,43 @@ func TestIPCReaderAdapterMultipleStreams(t *testing.T) {
 
 	assert.Equal(t, 3, batchCount)
 	assert.Equal(t, 300, rowCount)
+
+	assert.True(t, stats.complete.Load())
+	assert.EqualValues(t, 3, stats.chunks.Load())
+	assert.EqualValues(t, 3, stats.batches.Load())
+	assert.EqualValues(t, 300, stats.rows.Load())
+	var totalBytes int
+	for _, stream := range streams {
+		totalBytes += len(stream)
+	}
+	assert.EqualValues(t, totalBytes, stats.bytes.Load())
+}
+
+// TestIPCReaderAdapterClosesCanceledResults tests that results are closed
+// on the server as soon as reading them fails after the context ended
+func TestIPCReaderAdapterClosesCanceledResults(t *testing.T) {
+	for _, canceled := range []bool{false, true} {
+		rows := newInt64Rows(t, 1)
+		iterator := rows.iterator.(*mockIPCStreamIterator)
+		iterator.streams = append(iterator.streams, []byte("not an IPC stream"))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		reader, err := newIPCReaderAdapter(ctx, rows, &resultStats{}, nil)
+		require.NoError(t, err)
+		require.True(t, reader.Next())
+		if canceled {
+			cancel()
+		}
+		assert.False(t, reader.Next())
+		assert.Error(t, reader.Err())
+
+		if canceled {
+			assert.Equal(t, 1, rows.closed)
+		} else {
+			assert.Equal(t, 0, rows.closed)
+		}
+		reader.Release()
+		assert.Equal(t, 1, rows.closed)
+		cancel()
+	}
 }
@@ -0,0 +1,80 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"context"
+	"time"
+)
+
+// keepAlive pings a connection's session whenever it has been idle for an
+// interval, so that the server does not expire it
+type keepAlive struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func startKeepAlive(c *connectionImpl, interval time.Duration) *keepAlive {
+	ctx, cancel := context.WithCancel(context.Background())
+	k := &keepAlive{cancel: cancel, done: make(chan struct{})}
+	go func() {
+		defer close(k.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				c.heartbeat(ctx, interval)
+			}
+		}
+	}()
+	return k
+}
+
+// stop ends the pings, waiting for one in progress to be cancelled
+func (k *keepAlive) stop() {
+	if k == nil {
+		return
+	}
+	k.cancel()
+	<-k.done
+}
+
+// heartbeat pings the session if the connection has not been used for
+// idle. A session with operations in progress or result readers still
+// open is in use, and a lazily connected connection without a session is
+// left alone.
+func (c *connectionImpl) heartbeat(ctx context.Context, idle time.Duration) {
+	if time.Since(time.Unix(0, c.lastUsed.Load())) < idle {
+		return
+	}
+	if c.active.Load() > 0 || c.openReaders.Load() > 0 {
+		return
+	}
+
+	c.connectMu.Lock()
+	conn := c.conn
+	c.connectMu.Unlock()
+	if conn == nil {
+		return
+	}
+
+	// Runs "SELECT 1"; with OptionAutoReconnect, a lost session is replaced
+	if err := conn.PingContext(ctx); err != nil && ctx.Err() == nil && c.Logger != nil {
+		c.Logger.Warn("keep-alive ping failed", "error", err)
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"context"
+	"database/sql"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeepAlive(t *testing.T) {
+	var pings atomic.Int32
+	db := sql.OpenDB(&fakeDriver{ping: func() error {
+		pings.Add(1)
+		return nil
+	}})
+	defer func() { _ = db.Close() }()
+	conn, err := db.Conn(context.Background())
+	require.NoError(t, err)
+
+	c := &connectionImpl{conn: conn}
+	c.lastUsed.Store(time.Now().UnixNano())
+	c.keepAlive = startKeepAlive(c, 10*time.Millisecond)
+
+	require.Eventually(t, func() bool { return pings.Load() >= 2 }, 5*time.Second, 5*time.Millisecond)
+
+	// No pings are sent while the connection is being used
+	c.lastUsed.Store(time.Now().Add(time.Hour).UnixNano())
+	before := pings.Load()
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, before, pings.Load())
+
+	// Or while results are read from the session
+	c.lastUsed.Store(time.Now().Add(-time.Hour).UnixNano())
+	c.openReaders.Add(1)
+	// A ping already past the check may still land
+	time.Sleep(20 * time.Millisecond)
+	before = pings.Load()
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, before, pings.Load())
+	c.openReaders.Add(-1)
+
+	// Or once it is closed
+	require.NoError(t, c.Close())
+	before = pings.Load()
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, before, pings.Load())
+}
+
+func TestKeepAliveLazyConnection(t *testing.T) {
+	var pings atomic.Int32
+	db := sql.OpenDB(&fakeDriver{ping: func() error {
+		pings.Add(1)
+		return nil
+	}})
+	defer func() { _ = db.Close() }()
+
+	// A connection without a session has nothing to keep alive
+	c := &connectionImpl{pool: db}
+	c.heartbeat(context.Background(), 0)
+	assert.Zero(t, pings.Load())
+
+	require.NoError(t, c.connect())
+	c.heartbeat(context.Background(), 0)
+	assert.Equal(t, int32(1), pings.Load())
+	require.NoError(t, c.Close())
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+)
+
+// MaterializedQuery is the result of a query stored in a temporary table
+// by MaterializeQuery. The table belongs to the session of the connection
+// that created it, so only statements on that connection can read it.
+type MaterializedQuery struct {
+	// Table is the quoted table name, for use in later queries
+	Table string
+
+	cnxn adbc.Connection
+}
+
+// MaterializeQuery runs query once and stores its result in a temporary
+// table, so algorithms making several passes over an expensive result can
+// read the table instead of running the query again. The table is dropped
+// by Drop, or when the connection is closed.
+func MaterializeQuery(ctx context.Context, cnxn adbc.Connection, query string) (*MaterializedQuery, error) {
+	if strings.TrimSpace(query) == "" {
+		return nil, adbc.Error{
+			Code: adbc.StatusInvalidArgument,
+			Msg:  "query is required",
+		}
+	}
+
+	nameBytes := make([]byte, 8)
+	if _, err := rand.Read(nameBytes); err != nil {
+		return nil, adbc.Error{
+			Code: adbc.StatusInternal,
+			Msg:  fmt.Sprintf("failed to generate table name: %v", err),
+		}
+	}
+	m := &MaterializedQuery{
+		Table: quoteIdentifier("adbc_materialized_" + hex.EncodeToString(nameBytes)),
+		cnxn:  cnxn,
+	}
+
+	if err := m.exec(ctx, fmt.Sprintf("CREATE TEMPORARY TABLE %s AS %s", m.Table, query)); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Query returns a query selecting the whole materialized result
+func (m *MaterializedQuery) Query() string {
+	return "SELECT * FROM " + m.Table
+}
+
+// Drop drops the temporary table. It is not an error to drop it twice.
+func (m *MaterializedQuery) Drop(ctx context.Context) error {
+	return m.exec(ctx, "DROP TEMPORARY TABLE IF EXISTS "+m.Table)
+}
+
+func (m *MaterializedQuery) exec(ctx context.Context, query string) (err error) {
+	stmt, err := m.cnxn.NewStatement()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errors.Join(err, stmt.Close())
+	}()
+
+	if err := stmt.SetSqlQuery(query); err != nil {
+		return err
+	}
+	_, err = stmt.ExecuteUpdate(ctx)
+	return err
+}
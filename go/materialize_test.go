@@ -0,0 +1,87 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingConnection records the updates executed by its statements
+type recordingConnection struct {
+	adbc.Connection
+	executed []string
+	closed   int
+	err      error
+}
+
+type recordingStatement struct {
+	adbc.Statement
+	cnxn  *recordingConnection
+	query string
+}
+
+func (c *recordingConnection) NewStatement() (adbc.Statement, error) {
+	return &recordingStatement{cnxn: c}, nil
+}
+
+func (s *recordingStatement) SetSqlQuery(query string) error {
+	s.query = query
+	return nil
+}
+
+func (s *recordingStatement) ExecuteUpdate(context.Context) (int64, error) {
+	s.cnxn.executed = append(s.cnxn.executed, s.query)
+	return 0, s.cnxn.err
+}
+
+func (s *recordingStatement) Close() error {
+	s.cnxn.closed++
+	return nil
+}
+
+func TestMaterializeQuery(t *testing.T) {
+	cnxn := &recordingConnection{}
+	m, err := MaterializeQuery(context.Background(), cnxn, "SELECT * FROM sales WHERE year = 2025")
+	require.NoError(t, err)
+	assert.Regexp(t, regexp.MustCompile("^`adbc_materialized_[0-9a-f]{16}`$"), m.Table)
+	assert.Equal(t, "SELECT * FROM "+m.Table, m.Query())
+
+	require.NoError(t, m.Drop(context.Background()))
+	assert.Equal(t, []string{
+		"CREATE TEMPORARY TABLE " + m.Table + " AS SELECT * FROM sales WHERE year = 2025",
+		"DROP TEMPORARY TABLE IF EXISTS " + m.Table,
+	}, cnxn.executed)
+	assert.Equal(t, 2, cnxn.closed)
+
+	other, err := MaterializeQuery(context.Background(), cnxn, "SELECT 1")
+	require.NoError(t, err)
+	assert.NotEqual(t, m.Table, other.Table)
+
+	cnxn.err = errors.New("TABLE_OR_VIEW_NOT_FOUND")
+	_, err = MaterializeQuery(context.Background(), cnxn, "SELECT * FROM missing")
+	require.ErrorIs(t, err, cnxn.err)
+
+	var adbcErr adbc.Error
+	_, err = MaterializeQuery(context.Background(), cnxn, " ")
+	require.ErrorAs(t, err, &adbcErr)
+	assert.Equal(t, adbc.StatusInvalidArgument, adbcErr.Code)
+}
@@ -0,0 +1,209 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+// memoryStats tracks the Arrow memory retained by a statement's result
+// reader or ingest, and its peak
+type memoryStats struct {
+	current atomic.Int64
+	peak    atomic.Int64
+}
+
+// add records n more bytes retained, or fewer if n is negative
+func (m *memoryStats) add(n int64) {
+	current := m.current.Add(n)
+	for {
+		peak := m.peak.Load()
+		if current <= peak || m.peak.CompareAndSwap(peak, current) {
+			return
+		}
+	}
+}
+
+// SQLSTATE of errors for exceeding OptionMemoryLimitBytes, the class of
+// insufficient resources
+var memoryLimitSQLState = [5]byte{'5', '3', '2', '0', '0'}
+
+// memoryBudget limits the Arrow memory retained by the result readers of
+// a connection together
+type memoryBudget struct {
+	limit int64
+	// How long an allocation waits for memory to be released before
+	// failing
+	wait time.Duration
+
+	mu   sync.Mutex
+	used int64
+	// Closed and replaced whenever memory is released
+	released chan struct{}
+}
+
+// newMemoryBudget returns a budget of limit bytes, or nil if limit is 0
+func newMemoryBudget(limit int64, wait time.Duration) *memoryBudget {
+	if limit <= 0 {
+		return nil
+	}
+	return &memoryBudget{limit: limit, wait: wait, released: make(chan struct{})}
+}
+
+// reserve accounts for n more bytes, waiting for other readers to release
+// memory while they would exceed the limit
+func (b *memoryBudget) reserve(n int64) error {
+	var deadline <-chan time.Time
+	for {
+		b.mu.Lock()
+		if b.used+n <= b.limit {
+			b.used += n
+			b.mu.Unlock()
+			return nil
+		}
+		used, released := b.used, b.released
+		b.mu.Unlock()
+
+		if n > b.limit || b.wait <= 0 {
+			return b.exceeded(used, n)
+		}
+		if deadline == nil {
+			timer := time.NewTimer(b.wait)
+			defer timer.Stop()
+			deadline = timer.C
+		}
+		select {
+		case <-released:
+		case <-deadline:
+			return b.exceeded(used, n)
+		}
+	}
+}
+
+// release returns n bytes to the budget, waking waiting allocations
+func (b *memoryBudget) release(n int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.used -= n
+	close(b.released)
+	b.released = make(chan struct{})
+}
+
+func (b *memoryBudget) exceeded(used, n int64) error {
+	return adbc.Error{
+		Code: adbc.StatusInternal,
+		Msg: fmt.Sprintf("result batches would exceed %s of %d bytes: %d bytes retained, %d more needed; release batches sooner or raise the limit",
+			OptionMemoryLimitBytes, b.limit, used, n),
+		SqlState: memoryLimitSQLState,
+	}
+}
+
+// memoryLimitError returns the error for exceeding OptionMemoryLimitBytes
+// wrapped in err, as Arrow wraps errors of allocators, or else err
+func memoryLimitError(err error) error {
+	var adbcErr adbc.Error
+	if errors.As(err, &adbcErr) && adbcErr.SqlState == memoryLimitSQLState {
+		return adbcErr
+	}
+	return err
+}
+
+// trackingAllocator records the memory allocated through it in stats,
+// and counts it against budget if set. Allocations over the budget panic
+// with its error, as Arrow's readers recover panics of allocators into
+// errors.
+type trackingAllocator struct {
+	memory.Allocator
+	stats  *memoryStats
+	budget *memoryBudget
+}
+
+// newTrackingAllocator wraps mem, or the default allocator if mem is nil
+func newTrackingAllocator(mem memory.Allocator, stats *memoryStats, budget *memoryBudget) *trackingAllocator {
+	if mem == nil {
+		mem = memory.DefaultAllocator
+	}
+	return &trackingAllocator{Allocator: mem, stats: stats, budget: budget}
+}
+
+func (a *trackingAllocator) Allocate(size int) []byte {
+	a.reserve(int64(size))
+	b := a.Allocator.Allocate(size)
+	a.stats.add(int64(len(b)))
+	return b
+}
+
+func (a *trackingAllocator) Reallocate(size int, b []byte) []byte {
+	old := len(b)
+	if size > old {
+		a.reserve(int64(size - old))
+	}
+	b = a.Allocator.Reallocate(size, b)
+	if a.budget != nil && len(b) < old {
+		a.budget.release(int64(old - len(b)))
+	}
+	a.stats.add(int64(len(b) - old))
+	return b
+}
+
+func (a *trackingAllocator) Free(b []byte) {
+	if a.budget != nil {
+		a.budget.release(int64(len(b)))
+	}
+	a.stats.add(-int64(len(b)))
+	a.Allocator.Free(b)
+}
+
+func (a *trackingAllocator) reserve(n int64) {
+	if a.budget == nil {
+		return
+	}
+	if err := a.budget.reserve(n); err != nil {
+		panic(err)
+	}
+}
+
+// recordBufferSize returns the size of the buffers backing rec
+func recordBufferSize(rec arrow.RecordBatch) int64 {
+	var size int64
+	for _, col := range rec.Columns() {
+		size += arrayDataBufferSize(col.Data())
+	}
+	return size
+}
+
+func arrayDataBufferSize(data arrow.ArrayData) int64 {
+	var size int64
+	for _, buf := range data.Buffers() {
+		if buf != nil {
+			size += int64(buf.Len())
+		}
+	}
+	for _, child := range data.Children() {
+		size += arrayDataBufferSize(child)
+	}
+	if data.DataType().ID() == arrow.DICTIONARY {
+		size += arrayDataBufferSize(data.Dictionary())
+	}
+	return size
+}
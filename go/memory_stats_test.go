@@ -0,0 +1,188 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/adbc-drivers/driverbase-go/driverbase"
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrackingAllocator(t *testing.T) {
+	checked := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer checked.AssertSize(t, 0)
+
+	stats := &memoryStats{}
+	mem := newTrackingAllocator(checked, stats, nil)
+
+	a := mem.Allocate(64)
+	b := mem.Allocate(32)
+	assert.Equal(t, int64(96), stats.current.Load())
+
+	a = mem.Reallocate(128, a)
+	assert.Equal(t, int64(160), stats.current.Load())
+
+	mem.Free(a)
+	mem.Free(b)
+	assert.Equal(t, int64(0), stats.current.Load())
+	assert.Equal(t, int64(160), stats.peak.Load())
+}
+
+// newInt64Rows returns rows of streams, each a batch of 1000 int64s
+func newInt64Rows(t *testing.T, streams int) *mockRows {
+	schema := arrow.NewSchema([]arrow.Field{{Name: "id", Type: arrow.PrimitiveTypes.Int64}}, nil)
+
+	iterator := &mockIPCStreamIterator{}
+	for i := 0; i < streams; i++ {
+		builder := array.NewRecordBuilder(memory.DefaultAllocator, schema)
+		builder.Field(0).(*array.Int64Builder).AppendValues(make([]int64, 1000), nil)
+		record := builder.NewRecordBatch()
+
+		var buf bytes.Buffer
+		writer := ipc.NewWriter(&buf, ipc.WithSchema(schema))
+		require.NoError(t, writer.Write(record))
+		require.NoError(t, writer.Close())
+		iterator.streams = append(iterator.streams, buf.Bytes())
+
+		record.Release()
+		builder.Release()
+	}
+
+	var schemaBuf bytes.Buffer
+	require.NoError(t, ipc.NewWriter(&schemaBuf, ipc.WithSchema(schema)).Close())
+	iterator.schema = schemaBuf.Bytes()
+	return &mockRows{iterator: iterator}
+}
+
+func TestIPCReaderAdapterMemoryStats(t *testing.T) {
+	rows := newInt64Rows(t, 2)
+	stats := &memoryStats{}
+	reader, err := newIPCReaderAdapter(context.Background(), rows, &resultStats{}, newTrackingAllocator(nil, stats, nil))
+	require.NoError(t, err)
+
+	for reader.Next() {
+		assert.Positive(t, stats.current.Load())
+	}
+	require.NoError(t, reader.Err())
+	reader.Release()
+
+	// Batches are released as the reader moves on, so the peak is that of
+	// a single batch rather than the whole result
+	assert.Equal(t, int64(0), stats.current.Load())
+	assert.GreaterOrEqual(t, stats.peak.Load(), int64(8000))
+	assert.Less(t, stats.peak.Load(), int64(16000))
+}
+
+func TestIngestMemoryStats(t *testing.T) {
+	schema := arrow.NewSchema([]arrow.Field{{Name: "id", Type: arrow.PrimitiveTypes.Int64}}, nil)
+	bldr := array.NewRecordBuilder(memory.DefaultAllocator, schema)
+	defer bldr.Release()
+	bldr.Field(0).(*array.Int64Builder).AppendValues([]int64{10, 11}, nil)
+	rec := bldr.NewRecordBatch()
+	defer rec.Release()
+
+	db := sql.OpenDB(&fakeDriver{})
+	defer func() { _ = db.Close() }()
+	conn, err := db.Conn(context.Background())
+	require.NoError(t, err)
+
+	stream, err := array.NewRecordReader(schema, []arrow.RecordBatch{rec})
+	require.NoError(t, err)
+	s := &statementImpl{
+		conn:              &connectionImpl{conn: conn},
+		bulkIngestOptions: driverbase.NewBulkIngestOptions(),
+		boundStream:       stream,
+	}
+	s.bulkIngestOptions.TableName = "events"
+	s.bulkIngestOptions.Mode = adbc.OptionValueIngestModeAppend
+
+	_, err = s.executeIngest(context.Background())
+	require.NoError(t, err)
+
+	peak, err := s.getOptionInt(OptionStatementMemoryPeakBytes)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, peak, int64(16))
+	current, err := s.getOptionInt(OptionStatementMemoryCurrentBytes)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), current)
+}
+
+func TestMemoryBudget(t *testing.T) {
+	newReader := func(budget *memoryBudget) array.RecordReader {
+		mem := newTrackingAllocator(nil, &memoryStats{}, budget)
+		reader, err := newIPCReaderAdapter(context.Background(), newInt64Rows(t, 1), &resultStats{}, mem)
+		require.NoError(t, err)
+		t.Cleanup(reader.Release)
+		return reader
+	}
+	requireLimitError := func(err error) {
+		var adbcErr adbc.Error
+		require.ErrorAs(t, err, &adbcErr)
+		assert.Equal(t, adbc.StatusInternal, adbcErr.Code)
+		assert.Equal(t, "53200", string(adbcErr.SqlState[:]))
+		assert.Contains(t, adbcErr.Msg, OptionMemoryLimitBytes)
+	}
+
+	d := &databaseImpl{}
+	require.NoError(t, d.SetOption(OptionMemoryLimitBytes, "268435456"))
+	require.NoError(t, d.SetOption(OptionMemoryLimitWait, "30s"))
+	limit, err := d.GetOption(OptionMemoryLimitBytes)
+	require.NoError(t, err)
+	assert.Equal(t, "268435456", limit)
+	var adbcErr adbc.Error
+	require.ErrorAs(t, d.SetOption(OptionMemoryLimitBytes, "256MB"), &adbcErr)
+	assert.Equal(t, adbc.StatusInvalidArgument, adbcErr.Code)
+	assert.Nil(t, newMemoryBudget(0, 0))
+
+	// A batch larger than the whole budget fails without waiting
+	reader := newReader(newMemoryBudget(4000, time.Minute))
+	assert.False(t, reader.Next())
+	requireLimitError(reader.Err())
+
+	// A batch waits for other readers of the budget to release theirs
+	budget := newMemoryBudget(12000, time.Minute)
+	first, second := newReader(budget), newReader(budget)
+	require.True(t, first.Next())
+	read := make(chan bool)
+	go func() { read <- second.Next() }()
+	select {
+	case <-read:
+		t.Fatal("second batch read while the first is retained")
+	case <-time.After(50 * time.Millisecond):
+	}
+	assert.False(t, first.Next())
+	assert.True(t, <-read)
+	assert.False(t, second.Next())
+	require.NoError(t, second.Err())
+	assert.Equal(t, int64(0), budget.used)
+
+	// Until the wait runs out
+	budget = newMemoryBudget(12000, 10*time.Millisecond)
+	first, second = newReader(budget), newReader(budget)
+	require.True(t, first.Next())
+	assert.False(t, second.Next())
+	requireLimitError(second.Err())
+}
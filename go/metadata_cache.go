@@ -0,0 +1,131 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Expired entries are swept when the cache grows past this many entries
+const metadataCacheSweepSize = 1024
+
+// metadataCache holds metadata query results for a database, shared by
+// its connections, so repeated lookups within the TTL skip the warehouse.
+// A nil cache caches nothing.
+type metadataCache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]metadataCacheEntry
+}
+
+type metadataCacheEntry struct {
+	value   any
+	expires time.Time
+}
+
+// newMetadataCache creates a cache keeping results for ttl, or returns nil
+// if ttl is not positive
+func newMetadataCache(ttl time.Duration) *metadataCache {
+	if ttl <= 0 {
+		return nil
+	}
+	return &metadataCache{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: map[string]metadataCacheEntry{},
+	}
+}
+
+func (m *metadataCache) get(key string) (any, bool) {
+	if m == nil {
+		return nil, false
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if !m.now().Before(entry.expires) {
+		delete(m.entries, key)
+		return nil, false
+	}
+	return entry.value, true
+}
+
+func (m *metadataCache) put(key string, value any) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	if len(m.entries) >= metadataCacheSweepSize {
+		for k, entry := range m.entries {
+			if !now.Before(entry.expires) {
+				delete(m.entries, k)
+			}
+		}
+	}
+	m.entries[key] = metadataCacheEntry{value: value, expires: now.Add(m.ttl)}
+}
+
+// invalidate drops every cached result
+func (m *metadataCache) invalidate() {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	clear(m.entries)
+}
+
+// cachedMetadata returns the cached result for key, or calls load and
+// caches its result if it succeeds
+func cachedMetadata[T any](m *metadataCache, key string, load func() (T, error)) (T, error) {
+	if value, ok := m.get(key); ok {
+		return value.(T), nil
+	}
+	value, err := load()
+	if err != nil {
+		return value, err
+	}
+	m.put(key, value)
+	return value, nil
+}
+
+// metadataCacheKey builds a cache key from the kind of lookup and its
+// arguments, distinguishing nil arguments from empty ones
+func metadataCacheKey(kind string, args ...*string) string {
+	var key strings.Builder
+	key.WriteString(kind)
+	for _, arg := range args {
+		key.WriteByte(0)
+		if arg == nil {
+			key.WriteByte('-')
+			continue
+		}
+		// Quote each argument so separators inside values are unambiguous
+		key.WriteString(strconv.Quote(*arg))
+	}
+	return key.String()
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetadataCacheTTL(t *testing.T) {
+	now := time.Now()
+	cache := newMetadataCache(time.Minute)
+	cache.now = func() time.Time { return now }
+
+	loads := 0
+	load := func() ([]string, error) {
+		loads++
+		return []string{"main"}, nil
+	}
+
+	for range 2 {
+		catalogs, err := cachedMetadata(cache, metadataCacheKey("catalogs", nil), load)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"main"}, catalogs)
+	}
+	assert.Equal(t, 1, loads)
+
+	now = now.Add(time.Minute)
+	_, err := cachedMetadata(cache, metadataCacheKey("catalogs", nil), load)
+	require.NoError(t, err)
+	assert.Equal(t, 2, loads, "expired results should be loaded again")
+
+	// Errors are not cached
+	_, err = cachedMetadata(cache, "failing", func() ([]string, error) { return nil, errors.New("boom") })
+	require.Error(t, err)
+	_, ok := cache.get("failing")
+	assert.False(t, ok)
+
+	empty := ""
+	assert.NotEqual(t, metadataCacheKey("tables", nil), metadataCacheKey("tables", &empty))
+	assert.Nil(t, newMetadataCache(0))
+}
+
+func TestMetadataCacheConnection(t *testing.T) {
+	d := &databaseImpl{}
+	require.NoError(t, d.SetOption(OptionMetadataCacheTTL, "5m"))
+	val, err := d.GetOption(OptionMetadataCacheTTL)
+	require.NoError(t, err)
+	assert.Equal(t, "5m0s", val)
+
+	drv := newPagedDriver(
+		[][]driver.Value{{"orders", int64(0), "id", "INT", "NO"}},
+		[][]driver.Value{{"orders", int64(0), "id", "INT", "NO"}},
+	)
+	db := sql.OpenDB(drv)
+	defer func() { _ = db.Close() }()
+	conn, err := db.Conn(context.Background())
+	require.NoError(t, err)
+	c := &connectionImpl{conn: conn, metadataCache: d.metadataCache}
+
+	lookup := func() {
+		tables, err := c.GetTablesForDBSchema(context.Background(), "main", "default", nil, nil, true)
+		require.NoError(t, err)
+		require.Len(t, tables, 1)
+		assert.Equal(t, "orders", tables[0].TableName)
+	}
+
+	lookup()
+	lookup()
+	assert.Len(t, drv.execs, 1)
+
+	require.NoError(t, c.SetOption(OptionMetadataCacheInvalidate, "true"))
+	lookup()
+	assert.Len(t, drv.execs, 2)
+
+	// Invalidating through the database affects its connections too
+	require.NoError(t, d.SetOption(OptionMetadataCacheInvalidate, "true"))
+	catalog, schema := "main", "default"
+	_, ok := d.metadataCache.get(metadataCacheKey("columns", &catalog, &schema, nil, nil))
+	assert.False(t, ok)
+
+	var adbcErr adbc.Error
+	require.ErrorAs(t, d.SetOption(OptionMetadataCacheTTL, "soon"), &adbcErr)
+	assert.Equal(t, adbc.StatusInvalidArgument, adbcErr.Code)
+	require.NoError(t, d.SetOption(OptionMetadataCacheTTL, ""))
+	assert.Nil(t, d.metadataCache)
+}
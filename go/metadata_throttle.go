@@ -0,0 +1,196 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Default wait before the first retry of a rate-limited metadata query
+const defaultMetadataBackoff = time.Second
+
+// metadataThrottle limits the metadata queries of a database's
+// connections. At most limit queries run at once; the limit is halved
+// whenever the workspace rate limits a query, and grows back by one with
+// each query that is not, up to maxConcurrency. Queries failed by rate
+// limiting are run again after a backoff. A nil throttle does nothing.
+type metadataThrottle struct {
+	// 0 if unlimited
+	maxConcurrency int
+	maxRetries     int
+	backoff        time.Duration
+	logger         *slog.Logger
+	sleep          func(ctx context.Context, d time.Duration) error
+
+	mu       sync.Mutex
+	limit    int
+	inFlight int
+	// Closed when a query ends, to wake those waiting for a slot
+	released chan struct{}
+}
+
+// newMetadataThrottle returns a throttle with the given limits, or nil if
+// neither concurrency nor retries are limited
+func newMetadataThrottle(maxConcurrency, maxRetries int, backoff time.Duration, logger *slog.Logger) *metadataThrottle {
+	if maxConcurrency <= 0 && maxRetries <= 0 {
+		return nil
+	}
+	if backoff <= 0 {
+		backoff = defaultMetadataBackoff
+	}
+	return &metadataThrottle{
+		maxConcurrency: maxConcurrency,
+		maxRetries:     maxRetries,
+		backoff:        backoff,
+		logger:         logger,
+		sleep:          sleepContext,
+		limit:          maxConcurrency,
+		released:       make(chan struct{}),
+	}
+}
+
+// acquire waits for a slot to run a query in
+func (t *metadataThrottle) acquire(ctx context.Context) error {
+	for {
+		t.mu.Lock()
+		if t.maxConcurrency <= 0 || t.inFlight < t.limit {
+			t.inFlight++
+			t.mu.Unlock()
+			return nil
+		}
+		released := t.released
+		t.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-released:
+		}
+	}
+}
+
+// release frees the slot of a query, adjusting the limit by whether it
+// was rate limited
+func (t *metadataThrottle) release(rateLimited bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.inFlight--
+	if t.maxConcurrency > 0 {
+		if rateLimited {
+			t.limit = max(1, t.limit/2)
+		} else if t.limit < t.maxConcurrency {
+			t.limit++
+		}
+	}
+	close(t.released)
+	t.released = make(chan struct{})
+}
+
+type metadataCallKey struct{}
+
+// metadataCall records whether the requests of a metadata query were
+// rate limited, and for how long the workspace asked to wait
+type metadataCall struct {
+	mu          sync.Mutex
+	rateLimited bool
+	retryAfter  time.Duration
+}
+
+func (c *metadataCall) reset() (rateLimited bool, retryAfter time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	rateLimited, retryAfter = c.rateLimited, c.retryAfter
+	c.rateLimited, c.retryAfter = false, 0
+	return rateLimited, retryAfter
+}
+
+// throttleMetadata runs a metadata query under the throttle, running it
+// again while it fails after being rate limited
+func throttleMetadata[T any](ctx context.Context, t *metadataThrottle, query func(context.Context) (T, error)) (T, error) {
+	if t == nil {
+		return query(ctx)
+	}
+
+	call := &metadataCall{}
+	ctx = context.WithValue(ctx, metadataCallKey{}, call)
+	for attempt := 0; ; attempt++ {
+		if err := t.acquire(ctx); err != nil {
+			var zero T
+			return zero, err
+		}
+		value, err := query(ctx)
+		rateLimited, retryAfter := call.reset()
+		t.release(rateLimited)
+		if err == nil || !rateLimited || attempt >= t.maxRetries {
+			return value, err
+		}
+
+		wait := max(min(t.backoff<<min(attempt, 16), DEFAULT_RETRY_WAIT_MAX), retryAfter)
+		if t.logger != nil {
+			t.logger.Info("metadata query rate limited, retrying", "attempt", attempt+1, "wait", wait, "error", err)
+		}
+		if sleepErr := t.sleep(ctx, wait); sleepErr != nil {
+			return value, err
+		}
+	}
+}
+
+// loadMetadata returns the cached result for key, or runs query under the
+// connection's metadata throttle and caches its result
+func loadMetadata[T any](ctx context.Context, c *connectionImpl, key string, query func(context.Context) (T, error)) (T, error) {
+	return cachedMetadata(c.metadataCache, key, func() (T, error) {
+		return throttleMetadata(ctx, c.metadataThrottle, query)
+	})
+}
+
+// metadataThrottleTransport reports the requests of metadata queries
+// that the workspace rate limited. It sits beneath the retry loop of
+// databricks-sql-go, so it sees every attempt.
+type metadataThrottleTransport struct {
+	base http.RoundTripper
+}
+
+func (t *metadataThrottleTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	call, ok := req.Context().Value(metadataCallKey{}).(*metadataCall)
+	if !ok || err != nil || resp.StatusCode != http.StatusTooManyRequests {
+		return resp, err
+	}
+
+	call.mu.Lock()
+	defer call.mu.Unlock()
+	call.rateLimited = true
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+		call.retryAfter = max(call.retryAfter, time.Duration(seconds)*time.Second)
+	}
+	return resp, nil
+}
+
+// sleepContext waits for d, or until ctx is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetadataThrottleConcurrency(t *testing.T) {
+	assert.Nil(t, newMetadataThrottle(0, 0, 0, nil))
+
+	throttle := newMetadataThrottle(2, 0, 0, nil)
+	var running, peak atomic.Int32
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := throttleMetadata(context.Background(), throttle, func(context.Context) (int, error) {
+				n := running.Add(1)
+				for {
+					p := peak.Load()
+					if n <= p || peak.CompareAndSwap(p, n) {
+						break
+					}
+				}
+				time.Sleep(5 * time.Millisecond)
+				running.Add(-1)
+				return 0, nil
+			})
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(2), peak.Load())
+
+	// Rate limiting halves the limit, which grows back with each query
+	// that is not
+	throttle = newMetadataThrottle(8, 0, 0, nil)
+	for _, rateLimited := range []bool{true, true, false} {
+		require.NoError(t, throttle.acquire(context.Background()))
+		throttle.release(rateLimited)
+	}
+	assert.Equal(t, 3, throttle.limit)
+
+	// Waiting for a slot ends with the context
+	throttle = newMetadataThrottle(1, 0, 0, nil)
+	require.NoError(t, throttle.acquire(context.Background()))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, throttle.acquire(ctx), context.DeadlineExceeded)
+}
+
+func TestMetadataThrottleRetries(t *testing.T) {
+	var requests atomic.Int32
+	limited := 2
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if int(requests.Add(1)) <= limited {
+			w.Header().Set("Retry-After", "3")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+	client := &http.Client{Transport: &metadataThrottleTransport{base: http.DefaultTransport}}
+	query := func(ctx context.Context) (int, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, srv.URL, nil)
+		require.NoError(t, err)
+		resp, err := client.Do(req)
+		if err != nil {
+			return 0, err
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return 0, fmt.Errorf("request error after 1 attempt(s): %s", resp.Status)
+		}
+		return resp.StatusCode, nil
+	}
+
+	throttle := newMetadataThrottle(4, 2, 0, nil)
+	var waits []time.Duration
+	throttle.sleep = func(ctx context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+
+	// Rate limited queries run again, waiting as long as asked
+	status, err := throttleMetadata(context.Background(), throttle, query)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, []time.Duration{3 * time.Second, 3 * time.Second}, waits)
+	assert.Equal(t, 2, throttle.limit)
+
+	// Until the retries run out
+	requests.Store(0)
+	limited = 5
+	_, err = throttleMetadata(context.Background(), throttle, query)
+	assert.ErrorContains(t, err, "429")
+	assert.Equal(t, int32(3), requests.Load())
+
+	// Other failures are not retried
+	requests.Store(0)
+	_, err = throttleMetadata(context.Background(), throttle, func(context.Context) (int, error) {
+		requests.Add(1)
+		return 0, errors.New("[TABLE_OR_VIEW_NOT_FOUND] t")
+	})
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), requests.Load())
+}
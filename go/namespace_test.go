@@ -0,0 +1,146 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"github.com/adbc-drivers/driverbase-go/driverbase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// namespaceConnector is a fake driver whose sessions are in the given
+// catalog and schema
+type namespaceConnector struct {
+	*fakeDriver
+	catalog, schema string
+}
+
+func newNamespaceConnector(catalog, schema string) *namespaceConnector {
+	c := &namespaceConnector{catalog: catalog, schema: schema}
+	c.fakeDriver = &fakeDriver{query: func(query string, _ []driver.NamedValue) (driver.Rows, error) {
+		if query != "SELECT current_catalog(), current_schema()" {
+			return nil, errors.New("unexpected query")
+		}
+		return &fakeRows{
+			columns: []string{"catalog", "schema"},
+			rows:    [][]driver.Value{{c.catalog, c.schema}},
+		}, nil
+	}}
+	return c
+}
+
+func TestSessionDefaultNamespace(t *testing.T) {
+	connector := newNamespaceConnector("hive_metastore", "default")
+	db := sql.OpenDB(connector)
+	defer func() { _ = db.Close() }()
+	conn, err := db.Conn(context.Background())
+	require.NoError(t, err)
+
+	// The schema is given as an option, the catalog is the warehouse's
+	c := &connectionImpl{conn: conn, dbSchema: "sales"}
+	defer func() { _ = c.Close() }()
+
+	catalog, err := c.GetCurrentCatalog()
+	require.NoError(t, err)
+	assert.Equal(t, "hive_metastore", catalog)
+	dbSchema, err := c.GetCurrentDbSchema()
+	require.NoError(t, err)
+	assert.Equal(t, "sales", dbSchema)
+	assert.Len(t, connector.execs, 1, "the session is only asked once")
+
+	require.NoError(t, c.SetCurrentCatalog("main"))
+	catalog, err = c.GetCurrentCatalog()
+	require.NoError(t, err)
+	assert.Equal(t, "main", catalog)
+
+	// The defaults are still those the session started with
+	val, err := c.GetOption(OptionSessionDefaultCatalog)
+	require.NoError(t, err)
+	assert.Equal(t, "hive_metastore", val)
+	val, err = c.GetOption(OptionSessionDefaultDbSchema)
+	require.NoError(t, err)
+	assert.Equal(t, "sales", val)
+	assert.Equal(t, []string{"SELECT current_catalog(), current_schema()", "USE CATALOG `main`"}, connector.execs)
+}
+
+func TestSessionDefaultNamespaceFromOptions(t *testing.T) {
+	connector := newNamespaceConnector("", "")
+	db := sql.OpenDB(connector)
+	defer func() { _ = db.Close() }()
+	conn, err := db.Conn(context.Background())
+	require.NoError(t, err)
+
+	c := &connectionImpl{conn: conn, catalog: "main", dbSchema: "sales"}
+	defer func() { _ = c.Close() }()
+
+	val, err := c.GetOption(OptionSessionDefaultCatalog)
+	require.NoError(t, err)
+	assert.Equal(t, "main", val)
+	assert.Empty(t, connector.execs)
+}
+
+func TestNamespaceFollowsUseStatements(t *testing.T) {
+	connector := newNamespaceConnector("hive_metastore", "default")
+	db := sql.OpenDB(connector)
+	defer func() { _ = db.Close() }()
+	conn, err := db.Conn(context.Background())
+	require.NoError(t, err)
+
+	c := &connectionImpl{
+		ConnectionImplBase: driverbase.ConnectionImplBase{
+			ErrorHelper: driverbase.ErrorHelper{DriverName: "databricks"},
+		},
+		conn: conn,
+	}
+	defer func() { _ = c.Close() }()
+	stmt, err := c.NewStatement()
+	require.NoError(t, err)
+	defer func() { _ = stmt.Close() }()
+
+	// The session defaults are recorded before the statement leaves them
+	require.NoError(t, stmt.SetSqlQuery("use main.sales"))
+	_, err = stmt.ExecuteUpdate(context.Background())
+	require.NoError(t, err)
+	connector.catalog, connector.schema = "main", "sales"
+
+	catalog, err := c.GetCurrentCatalog()
+	require.NoError(t, err)
+	assert.Equal(t, "main", catalog)
+	dbSchema, err := c.GetCurrentDbSchema()
+	require.NoError(t, err)
+	assert.Equal(t, "sales", dbSchema)
+	val, err := c.GetOption(OptionSessionDefaultCatalog)
+	require.NoError(t, err)
+	assert.Equal(t, "hive_metastore", val)
+
+	// Other statements keep the cached values
+	require.NoError(t, stmt.SetSqlQuery("SET ansi_mode = true"))
+	_, err = stmt.ExecuteUpdate(context.Background())
+	require.NoError(t, err)
+	_, err = c.GetCurrentCatalog()
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"SELECT current_catalog(), current_schema()",
+		"use main.sales",
+		"SELECT current_catalog(), current_schema()",
+		"SET ansi_mode = true",
+	}, connector.execs)
+}
@@ -0,0 +1,41 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+// Former names of renamed options, which keep working as aliases of the
+// new ones. Setting one logs a deprecation warning.
+var optionAliases = map[string]string{
+	OptionDownloadThreadCount:       OptionCloudFetchMaxParallelDownloads,
+	OptionWarehouseWaitStartTimeout: OptionWarehouseWaitForStart,
+}
+
+// canonicalOption returns the current name of an option, given either it
+// or a deprecated alias of it
+func canonicalOption(key string) string {
+	if name, ok := optionAliases[key]; ok {
+		return name
+	}
+	return key
+}
+
+// resolveOptionAlias returns the current name of an option being set,
+// warning if it was set by a deprecated alias
+func (d *databaseImpl) resolveOptionAlias(key string) string {
+	name := canonicalOption(key)
+	if name != key && d.Logger != nil {
+		d.Logger.Warn("option is deprecated and will be removed in a future release", "option", key, "replacement", name)
+	}
+	return name
+}
@@ -0,0 +1,62 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/adbc-drivers/driverbase-go/driverbase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptionAliases(t *testing.T) {
+	var buf bytes.Buffer
+	d := &databaseImpl{DatabaseImplBase: driverbase.DatabaseImplBase{Logger: slog.New(slog.NewTextHandler(&buf, nil))}}
+
+	// The new name is set without a warning
+	require.NoError(t, d.SetOption(OptionCloudFetchMaxParallelDownloads, "4"))
+	assert.Equal(t, 4, d.downloadThreadCount)
+	assert.Empty(t, buf.String())
+
+	// The former one sets the same option, with a warning
+	require.NoError(t, d.SetOptionInt(OptionDownloadThreadCount, 8))
+	assert.Equal(t, 8, d.downloadThreadCount)
+	assert.Contains(t, buf.String(), "option is deprecated")
+	assert.Contains(t, buf.String(), "option="+OptionDownloadThreadCount)
+	assert.Contains(t, buf.String(), "replacement="+OptionCloudFetchMaxParallelDownloads)
+
+	// Either name reads it
+	for _, key := range []string{OptionCloudFetchMaxParallelDownloads, OptionDownloadThreadCount} {
+		val, err := d.GetOption(key)
+		require.NoError(t, err)
+		assert.Equal(t, "8", val)
+	}
+
+	require.NoError(t, d.SetOption(OptionWarehouseWaitStartTimeout, "2m"))
+	assert.Equal(t, 2*time.Minute, d.warehouseWaitForStart)
+	val, err := d.GetOption(OptionWarehouseWaitForStart)
+	require.NoError(t, err)
+	assert.Equal(t, "2m0s", val)
+
+	// Aliases name options that exist
+	for alias, name := range optionAliases {
+		assert.NotEqual(t, alias, name)
+		assert.Contains(t, effectiveDatabaseOptions, name)
+	}
+}
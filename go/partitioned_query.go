@@ -0,0 +1,451 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+)
+
+// Splits executed at once by ExecutePartitionedQuery by default
+const DefaultPartitionedQueryParallelism = 4
+
+// PartitionedQuery describes a query that ExecutePartitionedQuery splits
+// by the values of one of its columns
+type PartitionedQuery struct {
+	// Query to split. It is nested in the split queries as a subquery.
+	Query string
+	// Column of the result to split by
+	Column string
+	// Number of ranges to split an integer or date column into, between
+	// its smallest and largest values, with NULLs in a split of their own.
+	// If 0, each distinct value of the column is a split.
+	Ranges int
+	// Number of splits executed at once, each on a connection of its own.
+	// Defaults to DefaultPartitionedQueryParallelism.
+	Parallelism int
+}
+
+// ExecutePartitionedQuery splits a large query by a column, executes the
+// splits concurrently on separate connections of db, and returns their
+// results merged in a single reader. The splits are found by querying the
+// column first, with a connection that is closed before the splits run.
+// Batches are returned in the order the splits produce them, not in the
+// order of the query. Releasing the reader early cancels the splits.
+func ExecutePartitionedQuery(ctx context.Context, db adbc.Database, q PartitionedQuery) (array.RecordReader, error) {
+	if strings.TrimSpace(q.Query) == "" || q.Column == "" {
+		return nil, adbc.Error{
+			Code: adbc.StatusInvalidArgument,
+			Msg:  "query and column are required",
+		}
+	}
+	if q.Ranges < 0 || q.Parallelism < 0 {
+		return nil, adbc.Error{
+			Code: adbc.StatusInvalidArgument,
+			Msg:  fmt.Sprintf("invalid ranges or parallelism: %d, %d", q.Ranges, q.Parallelism),
+		}
+	}
+	if q.Parallelism == 0 {
+		q.Parallelism = DefaultPartitionedQueryParallelism
+	}
+
+	predicates, err := discoverSplits(ctx, db, q)
+	if err != nil {
+		return nil, err
+	}
+	queries := make([]string, len(predicates))
+	for i, pred := range predicates {
+		queries[i] = fmt.Sprintf("SELECT * FROM (%s) AS adbc_partitioned WHERE %s", q.Query, pred)
+	}
+	return newMergedReader(ctx, db, queries, min(q.Parallelism, len(queries)))
+}
+
+// discoverSplits returns the predicates selecting each split of q
+func discoverSplits(ctx context.Context, db adbc.Database, q PartitionedQuery) ([]string, error) {
+	column := quoteIdentifier(q.Column)
+	var query string
+	if q.Ranges > 0 {
+		query = fmt.Sprintf("SELECT min(%s), max(%s) FROM (%s) AS adbc_partitioned", column, column, q.Query)
+	} else {
+		query = fmt.Sprintf("SELECT DISTINCT %s FROM (%s) AS adbc_partitioned", column, q.Query)
+	}
+
+	var predicates []string
+	err := queryPartitionColumn(ctx, db, query, func(rec arrow.RecordBatch) error {
+		if q.Ranges > 0 {
+			var err error
+			predicates, err = rangePredicates(column, rec, q.Ranges)
+			return err
+		}
+		for i := range int(rec.NumRows()) {
+			pred, err := valuePredicate(column, rec.Column(0), i)
+			if err != nil {
+				return err
+			}
+			predicates = append(predicates, pred)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(predicates) == 0 {
+		// The result is empty, but its schema is still needed
+		predicates = []string{"FALSE"}
+	}
+	return predicates, nil
+}
+
+// queryPartitionColumn runs query on a new connection, passing each batch
+// of its result to fn
+func queryPartitionColumn(ctx context.Context, db adbc.Database, query string, fn func(arrow.RecordBatch) error) (err error) {
+	cnxn, err := db.Open(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errors.Join(err, cnxn.Close())
+	}()
+	stmt, err := cnxn.NewStatement()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errors.Join(err, stmt.Close())
+	}()
+
+	if err := stmt.SetSqlQuery(query); err != nil {
+		return err
+	}
+	reader, _, err := stmt.ExecuteQuery(ctx)
+	if err != nil {
+		return err
+	}
+	defer reader.Release()
+	for reader.Next() {
+		if err := fn(reader.RecordBatch()); err != nil {
+			return err
+		}
+	}
+	return reader.Err()
+}
+
+// rangePredicates splits the range between the smallest and largest
+// values of an integer or date column, given in a row of rec, into up to
+// n ranges. The first and last ranges are open, so that no rows are lost
+// should the column change before the splits run.
+func rangePredicates(column string, rec arrow.RecordBatch, n int) ([]string, error) {
+	nullSplit := column + " IS NULL"
+	if rec.NumRows() == 0 || rec.Column(0).IsNull(0) {
+		return []string{nullSplit}, nil
+	}
+
+	lo, render, err := rangeValue(rec.Column(0), 0)
+	if err != nil {
+		return nil, err
+	}
+	hi, _, err := rangeValue(rec.Column(1), 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// Computed in uint64 so that spans wider than int64 do not overflow
+	span := uint64(hi) - uint64(lo)
+	step := span/uint64(n) + 1
+	var bounds []string
+	for i := uint64(1); i < uint64(n) && i*step <= span; i++ {
+		bounds = append(bounds, render(int64(uint64(lo)+i*step)))
+	}
+
+	if len(bounds) == 0 {
+		return []string{column + " IS NOT NULL", nullSplit}, nil
+	}
+	predicates := []string{fmt.Sprintf("%s < %s", column, bounds[0])}
+	for i := 1; i < len(bounds); i++ {
+		predicates = append(predicates, fmt.Sprintf("%s >= %s AND %s < %s", column, bounds[i-1], column, bounds[i]))
+	}
+	predicates = append(predicates, fmt.Sprintf("%s >= %s", column, bounds[len(bounds)-1]), nullSplit)
+	return predicates, nil
+}
+
+// rangeValue returns a value of an integer or date column as an integer,
+// and a function rendering such integers as literals of the column's type
+func rangeValue(arr arrow.Array, idx int) (int64, func(int64) string, error) {
+	renderInt := func(v int64) string { return strconv.FormatInt(v, 10) }
+	switch arr := arr.(type) {
+	case *array.Int8:
+		return int64(arr.Value(idx)), renderInt, nil
+	case *array.Int16:
+		return int64(arr.Value(idx)), renderInt, nil
+	case *array.Int32:
+		return int64(arr.Value(idx)), renderInt, nil
+	case *array.Int64:
+		return arr.Value(idx), renderInt, nil
+	case *array.Date32:
+		return int64(arr.Value(idx)), func(v int64) string {
+			return fmt.Sprintf("DATE'%s'", arrow.Date32(v).ToTime().Format(time.DateOnly))
+		}, nil
+	}
+	return 0, nil, adbc.Error{
+		Code: adbc.StatusInvalidArgument,
+		Msg:  fmt.Sprintf("cannot split a column of type %s into ranges", arr.DataType()),
+	}
+}
+
+// valuePredicate returns the predicate selecting the rows whose column
+// has the value at idx of arr
+func valuePredicate(column string, arr arrow.Array, idx int) (string, error) {
+	if arr.IsNull(idx) {
+		return column + " IS NULL", nil
+	}
+
+	var literal string
+	switch arr := arr.(type) {
+	case *array.Int8, *array.Int16, *array.Int32, *array.Int64:
+		literal = arr.ValueStr(idx)
+	case *array.Boolean:
+		literal = strings.ToUpper(arr.ValueStr(idx))
+	case *array.Decimal128:
+		literal = formatDecimal128(arr.Value(idx), arr.DataType().(*arrow.Decimal128Type).Scale)
+	case *array.String:
+		literal = stringLiteral(arr.Value(idx))
+	case *array.LargeString:
+		literal = stringLiteral(arr.Value(idx))
+	case *array.Date32:
+		literal = fmt.Sprintf("DATE'%s'", arr.Value(idx).ToTime().Format(time.DateOnly))
+	case *array.Timestamp:
+		typ := arr.DataType().(*arrow.TimestampType)
+		ts := arr.Value(idx).ToTime(typ.Unit)
+		if typ.TimeZone == "" {
+			literal = fmt.Sprintf("TIMESTAMP_NTZ'%s'", ts.Format("2006-01-02 15:04:05.999999999"))
+		} else {
+			literal = fmt.Sprintf("TIMESTAMP'%s'", ts.UTC().Format("2006-01-02T15:04:05.999999999Z"))
+		}
+	default:
+		return "", adbc.Error{
+			Code: adbc.StatusInvalidArgument,
+			Msg:  fmt.Sprintf("cannot split a column of type %s by value", arr.DataType()),
+		}
+	}
+	return fmt.Sprintf("%s = %s", column, literal), nil
+}
+
+// stringLiteral quotes a string as a Databricks SQL literal, in which
+// backslashes are escapes
+func stringLiteral(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	return "'" + strings.ReplaceAll(value, "'", `\'`) + "'"
+}
+
+// mergedReader reads the results of queries executed concurrently on
+// connections of their own
+type mergedReader struct {
+	refCount atomic.Int64
+	schema   *arrow.Schema
+	batches  chan arrow.RecordBatch
+	cur      arrow.RecordBatch
+	cancel   context.CancelFunc
+	done     chan struct{}
+
+	mu  sync.Mutex
+	err error
+}
+
+// newMergedReader executes queries with parallelism workers, returning
+// once the first of them has a schema
+func newMergedReader(ctx context.Context, db adbc.Database, queries []string, parallelism int) (*mergedReader, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	r := &mergedReader{
+		batches: make(chan arrow.RecordBatch, parallelism),
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+	r.refCount.Store(1)
+
+	pending := make(chan string, len(queries))
+	for _, query := range queries {
+		pending <- query
+	}
+	close(pending)
+
+	schemaReady := make(chan struct{})
+	var schemaOnce sync.Once
+	setSchema := func(schema *arrow.Schema) error {
+		schemaOnce.Do(func() {
+			r.schema = schema
+			close(schemaReady)
+		})
+		<-schemaReady
+		if !r.schema.Equal(schema) {
+			return fmt.Errorf("splits returned different schemas: %s and %s", r.schema, schema)
+		}
+		return nil
+	}
+
+	var wg sync.WaitGroup
+	for range parallelism {
+		wg.Go(func() {
+			if err := r.work(ctx, db, pending, setSchema); err != nil {
+				r.fail(err)
+			}
+		})
+	}
+	go func() {
+		wg.Wait()
+		close(r.batches)
+		close(r.done)
+	}()
+
+	select {
+	case <-schemaReady:
+		return r, nil
+	case <-r.done:
+		if r.schema != nil {
+			// The splits finished before the schema was noticed
+			return r, nil
+		}
+		// Every worker failed before any split had a result
+		r.Release()
+		return nil, r.Err()
+	}
+}
+
+// work executes queries from pending on a connection of its own until
+// none are left
+func (r *mergedReader) work(ctx context.Context, db adbc.Database, pending <-chan string, setSchema func(*arrow.Schema) error) (err error) {
+	cnxn, err := db.Open(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errors.Join(err, cnxn.Close())
+	}()
+
+	for query := range pending {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := r.executeSplit(ctx, cnxn, query, setSchema); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *mergedReader) executeSplit(ctx context.Context, cnxn adbc.Connection, query string, setSchema func(*arrow.Schema) error) (err error) {
+	stmt, err := cnxn.NewStatement()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errors.Join(err, stmt.Close())
+	}()
+
+	if err := stmt.SetSqlQuery(query); err != nil {
+		return err
+	}
+	reader, _, err := stmt.ExecuteQuery(ctx)
+	if err != nil {
+		return err
+	}
+	defer reader.Release()
+	if err := setSchema(reader.Schema()); err != nil {
+		return err
+	}
+
+	for reader.Next() {
+		rec := reader.RecordBatch()
+		rec.Retain()
+		select {
+		case r.batches <- rec:
+		case <-ctx.Done():
+			rec.Release()
+			return ctx.Err()
+		}
+	}
+	return reader.Err()
+}
+
+// fail records the first error and cancels the other splits
+func (r *mergedReader) fail(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err == nil {
+		r.err = err
+	}
+	r.cancel()
+}
+
+func (r *mergedReader) Retain() {
+	r.refCount.Add(1)
+}
+
+func (r *mergedReader) Release() {
+	if r.refCount.Add(-1) != 0 {
+		return
+	}
+	r.cancel()
+	for rec := range r.batches {
+		rec.Release()
+	}
+	<-r.done
+	if r.cur != nil {
+		r.cur.Release()
+		r.cur = nil
+	}
+}
+
+func (r *mergedReader) Schema() *arrow.Schema {
+	return r.schema
+}
+
+func (r *mergedReader) Next() bool {
+	if r.cur != nil {
+		r.cur.Release()
+		r.cur = nil
+	}
+	rec, ok := <-r.batches
+	if !ok {
+		return false
+	}
+	r.cur = rec
+	return true
+}
+
+func (r *mergedReader) RecordBatch() arrow.RecordBatch {
+	return r.cur
+}
+
+func (r *mergedReader) Record() arrow.RecordBatch {
+	return r.cur
+}
+
+func (r *mergedReader) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+var _ array.RecordReader = (*mergedReader)(nil)
@@ -0,0 +1,208 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// splitDatabase answers queries with the JSON rows results has for them
+type splitDatabase struct {
+	adbc.Database
+	schema  *arrow.Schema
+	results map[string]string
+	failOn  string
+
+	mu      sync.Mutex
+	queries []string
+	opened  int
+	closed  int
+}
+
+type splitConnection struct {
+	adbc.Connection
+	db *splitDatabase
+}
+
+type splitStatement struct {
+	adbc.Statement
+	db    *splitDatabase
+	query string
+}
+
+func (d *splitDatabase) Open(context.Context) (adbc.Connection, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.opened++
+	return &splitConnection{db: d}, nil
+}
+
+func (c *splitConnection) NewStatement() (adbc.Statement, error) {
+	return &splitStatement{db: c.db}, nil
+}
+
+func (c *splitConnection) Close() error {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+	c.db.closed++
+	return nil
+}
+
+func (s *splitStatement) SetSqlQuery(query string) error {
+	s.query = query
+	return nil
+}
+
+func (s *splitStatement) ExecuteQuery(context.Context) (array.RecordReader, int64, error) {
+	s.db.mu.Lock()
+	s.db.queries = append(s.db.queries, s.query)
+	s.db.mu.Unlock()
+	if s.db.failOn != "" && strings.Contains(s.query, s.db.failOn) {
+		return nil, -1, errors.New("[INSUFFICIENT_PERMISSIONS] access denied")
+	}
+
+	schema := s.db.schema
+	rows, ok := s.db.results[s.query]
+	if !ok {
+		rows = "[]"
+	}
+	if strings.HasPrefix(s.query, "SELECT min(") {
+		schema = arrow.NewSchema([]arrow.Field{
+			{Name: "min", Type: schema.Field(0).Type, Nullable: true},
+			{Name: "max", Type: schema.Field(0).Type, Nullable: true},
+		}, nil)
+	} else if strings.HasPrefix(s.query, "SELECT DISTINCT") {
+		schema = arrow.NewSchema(schema.Fields()[:1], nil)
+	}
+	rec, _, err := array.RecordFromJSON(memory.DefaultAllocator, schema, strings.NewReader(rows))
+	if err != nil {
+		return nil, -1, err
+	}
+	defer rec.Release()
+	reader, err := array.NewRecordReader(schema, []arrow.RecordBatch{rec})
+	return reader, -1, err
+}
+
+func (s *splitStatement) Close() error { return nil }
+
+func readPartitionedIDs(t *testing.T, reader array.RecordReader) []int64 {
+	t.Helper()
+	defer reader.Release()
+	var ids []int64
+	for reader.Next() {
+		ids = append(ids, reader.RecordBatch().Column(0).(*array.Int64).Int64Values()...)
+	}
+	require.NoError(t, reader.Err())
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+func TestExecutePartitionedQueryRanges(t *testing.T) {
+	split := func(pred string) string {
+		return "SELECT * FROM (SELECT * FROM events) AS adbc_partitioned WHERE " + pred
+	}
+	db := &splitDatabase{
+		schema: arrow.NewSchema([]arrow.Field{{Name: "id", Type: arrow.PrimitiveTypes.Int64, Nullable: true}}, nil),
+		results: map[string]string{
+			"SELECT min(`id`), max(`id`) FROM (SELECT * FROM events) AS adbc_partitioned": `[{"min": 1, "max": 9}]`,
+			split("`id` < 4"):               `[{"id": 1}, {"id": 3}]`,
+			split("`id` >= 4 AND `id` < 7"): `[{"id": 4}, {"id": 6}]`,
+			split("`id` >= 7"):              `[{"id": 9}]`,
+			split("`id` IS NULL"):           `[{"id": null}]`,
+		},
+	}
+
+	reader, err := ExecutePartitionedQuery(context.Background(), db, PartitionedQuery{
+		Query:       "SELECT * FROM events",
+		Column:      "id",
+		Ranges:      3,
+		Parallelism: 2,
+	})
+	require.NoError(t, err)
+	assert.True(t, db.schema.Equal(reader.Schema()))
+	assert.Equal(t, []int64{0, 1, 3, 4, 6, 9}, readPartitionedIDs(t, reader))
+
+	assert.Len(t, db.queries, 5)
+	for pred := range db.results {
+		assert.Contains(t, db.queries, pred)
+	}
+	// One connection finds the splits, and one runs each worker
+	assert.Equal(t, 3, db.opened)
+	assert.Equal(t, 3, db.closed)
+}
+
+func TestExecutePartitionedQueryValues(t *testing.T) {
+	db := &splitDatabase{
+		schema: arrow.NewSchema([]arrow.Field{
+			{Name: "region", Type: arrow.BinaryTypes.String, Nullable: true},
+			{Name: "amount", Type: arrow.PrimitiveTypes.Int64},
+		}, nil),
+		results: map[string]string{
+			"SELECT DISTINCT `region` FROM (SELECT * FROM sales) AS adbc_partitioned": `[{"region": "emea"}, {"region": "o'hare"}, {"region": null}]`,
+		},
+		failOn: "'emea'",
+	}
+
+	_, err := ExecutePartitionedQuery(context.Background(), db, PartitionedQuery{
+		Query:       "SELECT * FROM sales",
+		Column:      "region",
+		Parallelism: 1,
+	})
+	assert.ErrorContains(t, err, "INSUFFICIENT_PERMISSIONS")
+	assert.Contains(t, db.queries, "SELECT * FROM (SELECT * FROM sales) AS adbc_partitioned WHERE `region` = 'emea'")
+	assert.Equal(t, db.opened, db.closed)
+
+	db.failOn = ""
+	db.queries = nil
+	reader, err := ExecutePartitionedQuery(context.Background(), db, PartitionedQuery{
+		Query:  "SELECT * FROM sales",
+		Column: "region",
+	})
+	require.NoError(t, err)
+	reader.Release()
+	assert.ElementsMatch(t, []string{
+		"SELECT DISTINCT `region` FROM (SELECT * FROM sales) AS adbc_partitioned",
+		"SELECT * FROM (SELECT * FROM sales) AS adbc_partitioned WHERE `region` = 'emea'",
+		`SELECT * FROM (SELECT * FROM sales) AS adbc_partitioned WHERE ` + "`region`" + ` = 'o\'hare'`,
+		"SELECT * FROM (SELECT * FROM sales) AS adbc_partitioned WHERE `region` IS NULL",
+	}, db.queries)
+}
+
+func TestRangePredicates(t *testing.T) {
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "min", Type: arrow.FixedWidthTypes.Date32, Nullable: true},
+		{Name: "max", Type: arrow.FixedWidthTypes.Date32, Nullable: true},
+	}, nil)
+	rec, _, err := array.RecordFromJSON(memory.DefaultAllocator, schema, strings.NewReader(`[{"min": "2026-01-01", "max": "2026-01-02"}]`))
+	require.NoError(t, err)
+	defer rec.Release()
+
+	// There are fewer values than ranges
+	predicates, err := rangePredicates("`day`", rec, 4)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"`day` < DATE'2026-01-02'", "`day` >= DATE'2026-01-02'", "`day` IS NULL"}, predicates)
+}
@@ -0,0 +1,228 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+)
+
+// Largest result set, as serialized Arrow IPC, that ExecutePartitions
+// embeds in a single partition
+const maxInlinePartitionBytes = 16 << 20
+
+// inlinePartitionPrefix marks a partition descriptor that carries the
+// result set itself as an Arrow IPC stream following the prefix
+var inlinePartitionPrefix = []byte("databricks-inline-ipc:")
+
+// chunkPartitionPrefix marks a partition descriptor naming a chunk of the
+// results of a statement run with the Statement Execution API, as JSON
+// following the prefix
+var chunkPartitionPrefix = []byte("databricks-chunk:")
+
+// chunkPartition is the descriptor of a partition that is a chunk of the
+// results of a statement
+type chunkPartition struct {
+	StatementID string `json:"statement_id"`
+	Chunk       int    `json:"chunk"`
+}
+
+// ExecutePartitions executes the query and returns its results as
+// partitions that ReadPartition reads, possibly on other connections or
+// in other processes.
+//
+// On a SQL warehouse, the query is run with the Statement Execution API,
+// the same as with OptionStatementSubmitAsync, and each chunk of its
+// results is a partition. Reading one downloads the chunk from cloud
+// storage by a link fresh from the warehouse, so partitions can be read
+// as long as the warehouse keeps the results, and only by connections
+// whose identity may read them.
+//
+// Otherwise, or while autocommit is disabled, impersonating a user, with
+// bound parameters, or once the settings of the session were changed (see
+// OptionStatementSubmitAsync), the query runs in the session and the
+// results are returned as a single self-contained partition, and those
+// larger than 16 MiB must be read with ExecuteQuery.
+func (s *statementImpl) ExecutePartitions(ctx context.Context) (*arrow.Schema, adbc.Partitions, int64, error) {
+	if err := s.acquire(); err != nil {
+		return nil, adbc.Partitions{}, -1, err
+	}
+	defer s.mu.Unlock()
+
+	// Chunks of results are read outside of the session, which is not
+	// established for them
+	if err := s.conn.acquireOpen(); err != nil {
+		return nil, adbc.Partitions{}, -1, err
+	}
+	if s.chunkPartitionsAvailable() {
+		defer s.conn.release()
+		ctx, op := s.startOperation(ctx)
+		ctx, timedOut := s.startQueryTimeout(ctx)
+		schema, partitions, numRows, err := s.executeChunkPartitions(ctx)
+		return schema, partitions, numRows, op.finish(timedOut(err))
+	}
+	s.conn.release()
+
+	if err := s.conn.acquire(); err != nil {
+		return nil, adbc.Partitions{}, -1, err
+	}
+	defer s.conn.release()
+
+	ctx, op := s.startOperation(ctx)
+	ctx, timedOut := s.startQueryTimeout(ctx)
+	reader, _, err := s.executeQuery(ctx)
+	reader, err = op.results(reader, timedOut(err))
+	if err != nil {
+		return nil, adbc.Partitions{}, -1, err
+	}
+	defer reader.Release()
+
+	partition, numRows, err := encodeInlinePartition(reader, maxInlinePartitionBytes)
+	if err != nil {
+		if canceled := op.err(err); canceled != err {
+			return nil, adbc.Partitions{}, -1, canceled
+		}
+		return nil, adbc.Partitions{}, -1, s.ErrorHelper.Errorf(adbc.StatusNotImplemented, "%v", err)
+	}
+
+	return reader.Schema(), adbc.Partitions{
+		NumPartitions: 1,
+		PartitionIDs:  [][]byte{partition},
+	}, numRows, nil
+}
+
+// chunkPartitionsAvailable reports whether the statement's query can be
+// run with the Statement Execution API and its results partitioned by
+// chunk
+func (s *statementImpl) chunkPartitionsAvailable() bool {
+	return s.conn.warehouse != nil && s.query != "" && s.boundStream == nil && !s.copyIntoOptions.IsSet() &&
+		s.conn.txMode == transactionNone && s.conn.impersonateUser == "" && s.conn.sessionState() == ""
+}
+
+// executeChunkPartitions submits the query, waits for it to finish and
+// returns a partition for each chunk of its results
+func (s *statementImpl) executeChunkPartitions(ctx context.Context) (*arrow.Schema, adbc.Partitions, int64, error) {
+	query := s.resultQuery()
+	if err := s.conn.checkSQLLength(query); err != nil {
+		return nil, adbc.Partitions{}, -1, err
+	}
+	if err := s.submit(ctx, query); err != nil {
+		return nil, adbc.Partitions{}, -1, err
+	}
+	manifest, err := s.waitSubmitted(ctx, s.conn.warehouse)
+	if err != nil {
+		if ctx.Err() != nil {
+			_ = s.cancelSubmitted()
+		}
+		return nil, adbc.Partitions{}, -1, err
+	}
+
+	partitions := adbc.Partitions{NumPartitions: uint64(manifest.TotalChunkCount)}
+	for chunk := range manifest.TotalChunkCount {
+		descriptor, err := json.Marshal(chunkPartition{StatementID: s.submittedID, Chunk: chunk})
+		if err != nil {
+			return nil, adbc.Partitions{}, -1, err
+		}
+		partitions.PartitionIDs = append(partitions.PartitionIDs, append(bytes.Clone(chunkPartitionPrefix), descriptor...))
+	}
+	return manifest.arrowSchema(), partitions, manifest.TotalRowCount, nil
+}
+
+// encodeInlinePartition serializes a result set into an inline partition
+// descriptor, failing once it exceeds maxBytes.
+func encodeInlinePartition(reader array.RecordReader, maxBytes int) ([]byte, int64, error) {
+	var buf bytes.Buffer
+	buf.Write(inlinePartitionPrefix)
+
+	writer := ipc.NewWriter(&buf, ipc.WithSchema(reader.Schema()))
+	numRows := int64(0)
+	for reader.Next() {
+		rec := reader.RecordBatch()
+		if err := writer.Write(rec); err != nil {
+			return nil, -1, fmt.Errorf("failed to serialize result set: %w", err)
+		}
+		numRows += rec.NumRows()
+		if buf.Len()-len(inlinePartitionPrefix) > maxBytes {
+			return nil, -1, fmt.Errorf("result set exceeds %d bytes and cannot be returned as an inline partition; use ExecuteQuery instead", maxBytes)
+		}
+	}
+	if err := reader.Err(); err != nil {
+		return nil, -1, fmt.Errorf("failed to read result set: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return nil, -1, fmt.Errorf("failed to serialize result set: %w", err)
+	}
+	return buf.Bytes(), numRows, nil
+}
+
+// ReadPartition reads a partition returned by ExecutePartitions
+func (c *connectionImpl) ReadPartition(ctx context.Context, serializedPartition []byte) (array.RecordReader, error) {
+	// Partitions are read outside of the session, which is not
+	// established for them
+	if err := c.acquireOpen(); err != nil {
+		return nil, err
+	}
+	defer c.release()
+
+	if descriptor, ok := bytes.CutPrefix(serializedPartition, chunkPartitionPrefix); ok {
+		reader, err := c.readChunkPartition(ctx, descriptor)
+		return withFetchSize(reader, c.fetchSize), err
+	}
+
+	data, ok := bytes.CutPrefix(serializedPartition, inlinePartitionPrefix)
+	if !ok {
+		return nil, c.ErrorHelper.Errorf(adbc.StatusInvalidArgument, "unrecognized partition descriptor")
+	}
+
+	mem := newTrackingAllocator(c.Alloc, &memoryStats{}, c.memoryBudget)
+	reader, err := ipc.NewReader(bytes.NewReader(data), ipc.WithAllocator(mem))
+	if err != nil {
+		return nil, c.ErrorHelper.Errorf(adbc.StatusInvalidData, "failed to read inline partition: %v", err)
+	}
+	return withFetchSize(reader, c.fetchSize), nil
+}
+
+// readChunkPartition downloads the chunk of results of a partition
+func (c *connectionImpl) readChunkPartition(ctx context.Context, descriptor []byte) (array.RecordReader, error) {
+	var partition chunkPartition
+	if err := json.Unmarshal(descriptor, &partition); err != nil || partition.StatementID == "" || partition.Chunk < 0 {
+		return nil, c.ErrorHelper.Errorf(adbc.StatusInvalidArgument, "invalid partition descriptor")
+	}
+	if c.warehouse == nil {
+		return nil, c.warehouseRequired("reading a partition of chunked results")
+	}
+
+	iterator := &submittedResultIterator{
+		ctx:      ctx,
+		api:      c.warehouse,
+		id:       partition.StatementID,
+		manifest: submittedManifest{TotalChunkCount: partition.Chunk + 1},
+		chunk:    partition.Chunk,
+	}
+	mem := newTrackingAllocator(c.Alloc, &memoryStats{}, c.memoryBudget)
+	reader, err := newIPCStreamReader(ctx, iterator, nil, &resultStats{}, mem)
+	if err != nil {
+		iterator.Close()
+		return nil, c.ErrorHelper.Errorf(adbc.StatusIO, "failed to read chunk %d of statement %s: %v", partition.Chunk, partition.StatementID, err)
+	}
+	return reader, nil
+}
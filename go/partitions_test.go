@@ -0,0 +1,189 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/adbc-drivers/driverbase-go/driverbase"
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRecordReader(t *testing.T, mem memory.Allocator, rows int) array.RecordReader {
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+	}, nil)
+	builder := array.NewRecordBuilder(mem, schema)
+	defer builder.Release()
+	for i := range rows {
+		builder.Field(0).(*array.Int64Builder).Append(int64(i))
+	}
+	record := builder.NewRecordBatch()
+	defer record.Release()
+
+	reader, err := array.NewRecordReader(schema, []arrow.RecordBatch{record})
+	require.NoError(t, err)
+	return reader
+}
+
+func TestInlinePartitionRoundTrip(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	reader := newTestRecordReader(t, mem, 3)
+	defer reader.Release()
+
+	partition, numRows, err := encodeInlinePartition(reader, maxInlinePartitionBytes)
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, numRows)
+
+	pool := sql.OpenDB(&fakeDriver{})
+	defer func() { _ = pool.Close() }()
+	conn := &connectionImpl{ConnectionImplBase: driverbase.ConnectionImplBase{Alloc: mem}, pool: pool}
+	result, err := conn.ReadPartition(context.Background(), partition)
+	require.NoError(t, err)
+	defer result.Release()
+
+	require.True(t, result.Next())
+	ids := result.RecordBatch().Column(0).(*array.Int64)
+	assert.Equal(t, []int64{0, 1, 2}, ids.Int64Values())
+	assert.False(t, result.Next())
+	require.NoError(t, result.Err())
+}
+
+func TestInlinePartitionTooLarge(t *testing.T) {
+	reader := newTestRecordReader(t, memory.NewGoAllocator(), 1000)
+	defer reader.Release()
+
+	_, _, err := encodeInlinePartition(reader, 1024)
+	assert.ErrorContains(t, err, "use ExecuteQuery")
+}
+
+func TestReadPartitionRejectsUnknownDescriptor(t *testing.T) {
+	pool := sql.OpenDB(&fakeDriver{})
+	defer func() { _ = pool.Close() }()
+	conn := &connectionImpl{ConnectionImplBase: driverbase.ConnectionImplBase{
+		ErrorHelper: driverbase.ErrorHelper{DriverName: "databricks"},
+	}, pool: pool}
+	_, err := conn.ReadPartition(context.Background(), []byte("s3://bucket/chunk"))
+	var adbcErr adbc.Error
+	require.ErrorAs(t, err, &adbcErr)
+	assert.Equal(t, adbc.StatusInvalidArgument, adbcErr.Code)
+}
+
+func TestChunkPartitions(t *testing.T) {
+	schema := arrow.NewSchema([]arrow.Field{{Name: "id", Type: arrow.PrimitiveTypes.Int64}}, nil)
+	chunk := func(ids ...int64) []byte {
+		bldr := array.NewRecordBuilder(memory.DefaultAllocator, schema)
+		defer bldr.Release()
+		bldr.Field(0).(*array.Int64Builder).AppendValues(ids, nil)
+		rec := bldr.NewRecordBatch()
+		defer rec.Release()
+		var buf bytes.Buffer
+		w := ipc.NewWriter(&buf, ipc.WithSchema(schema))
+		require.NoError(t, w.Write(rec))
+		require.NoError(t, w.Close())
+		return buf.Bytes()
+	}
+	chunks := [][]byte{chunk(1, 2), chunk(3)}
+
+	var srv *httptest.Server
+	srv = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var index int
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/api/2.0/sql/statements/":
+			_ = json.NewEncoder(w).Encode(map[string]any{"statement_id": "01ef-stmt"})
+		case r.URL.Path == "/api/2.0/sql/statements/01ef-stmt":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"status": map[string]any{"state": "SUCCEEDED"},
+				"manifest": map[string]any{
+					"format":            "ARROW_STREAM",
+					"total_chunk_count": len(chunks),
+					"total_row_count":   3,
+					"schema":            map[string]any{"columns": []map[string]any{{"name": "id", "type_name": "LONG"}}},
+				},
+			})
+		case fmtSscanf(r.URL.Path, "/api/2.0/sql/statements/01ef-stmt/result/chunks/%d", &index):
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"external_links": []map[string]any{{"external_link": fmt.Sprintf("%s/download/%d", srv.URL, index)}},
+			})
+		case fmtSscanf(r.URL.Path, "/download/%d", &index):
+			_, _ = w.Write(chunks[index])
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	d := newWarehouseTestDatabase(t, srv, "")
+	pool := sql.OpenDB(&fakeDriver{})
+	defer func() { _ = pool.Close() }()
+	s := &statementImpl{conn: &connectionImpl{warehouse: d.warehouse, pool: pool}}
+	s.conn.Alloc = memory.DefaultAllocator
+	require.NoError(t, s.SetSqlQuery("SELECT id FROM t"))
+
+	// Each chunk of the results is a partition
+	resultSchema, partitions, numRows, err := s.ExecutePartitions(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, resultSchema.NumFields())
+	assert.Equal(t, arrow.PrimitiveTypes.Int64, resultSchema.Field(0).Type)
+	assert.EqualValues(t, 3, numRows)
+	require.EqualValues(t, 2, partitions.NumPartitions)
+
+	// Which other connections to the warehouse download
+	otherPool := sql.OpenDB(&fakeDriver{})
+	defer func() { _ = otherPool.Close() }()
+	conn := &connectionImpl{ConnectionImplBase: driverbase.ConnectionImplBase{Alloc: memory.DefaultAllocator}, warehouse: d.warehouse, pool: otherPool}
+	var ids [][]int64
+	for _, partition := range partitions.PartitionIDs {
+		rdr, err := conn.ReadPartition(context.Background(), partition)
+		require.NoError(t, err)
+		var chunkIDs []int64
+		for rdr.Next() {
+			chunkIDs = append(chunkIDs, rdr.RecordBatch().Column(0).(*array.Int64).Int64Values()...)
+		}
+		require.NoError(t, rdr.Err())
+		rdr.Release()
+		ids = append(ids, chunkIDs)
+	}
+	assert.Equal(t, [][]int64{{1, 2}, {3}}, ids)
+
+	// Once they are closed, they cannot
+	require.NoError(t, conn.Close())
+	_, err = conn.ReadPartition(context.Background(), partitions.PartitionIDs[0])
+	requireInvalidState(t, err)
+
+	// Connections to other compute cannot
+	other := &connectionImpl{ConnectionImplBase: driverbase.ConnectionImplBase{
+		ErrorHelper: driverbase.ErrorHelper{DriverName: "databricks"},
+	}, pool: otherPool}
+	_, err = other.ReadPartition(context.Background(), partitions.PartitionIDs[0])
+	var adbcErr adbc.Error
+	require.ErrorAs(t, err, &adbcErr)
+	assert.Equal(t, adbc.StatusNotImplemented, adbcErr.Code)
+}
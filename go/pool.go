@@ -0,0 +1,32 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"database/sql"
+)
+
+// configurePool applies the connection pool options to db. Each ADBC
+// connection holds one pooled connection, and so one warehouse session,
+// from Open until Close returns it to the pool.
+func (d *databaseImpl) configurePool(db *sql.DB) {
+	db.SetMaxOpenConns(d.poolMaxOpen)
+	if d.poolMaxIdleSet {
+		// database/sql keeps no idle connections for values <= 0
+		db.SetMaxIdleConns(d.poolMaxIdle)
+	}
+	db.SetConnMaxIdleTime(d.poolIdleTimeout)
+	db.SetConnMaxLifetime(d.poolMaxLifetime)
+}
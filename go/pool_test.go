@@ -0,0 +1,69 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPoolOptions(t *testing.T) {
+	d := &databaseImpl{}
+	require.NoError(t, d.SetOptions(map[string]string{
+		OptionPoolMaxOpenConnections: "1",
+		OptionPoolMaxIdleConnections: "0",
+		OptionPoolIdleTimeout:        "1m",
+		OptionPoolMaxLifetime:        "1h",
+	}))
+	val, err := d.GetOption(OptionPoolMaxIdleConnections)
+	require.NoError(t, err)
+	assert.Equal(t, "0", val)
+	val, err = d.GetOption(OptionPoolMaxLifetime)
+	require.NoError(t, err)
+	assert.Equal(t, "1h0m0s", val)
+
+	db := sql.OpenDB(&fakeDriver{})
+	defer func() { _ = db.Close() }()
+	d.configurePool(db)
+	assert.Equal(t, 1, db.Stats().MaxOpenConnections)
+
+	// A second connection waits for the first to be closed
+	conn, err := db.Conn(context.Background())
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = db.Conn(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// With no idle connections kept, closing ends the session
+	require.NoError(t, conn.Close())
+	assert.Equal(t, 0, db.Stats().Idle)
+	assert.Equal(t, 0, db.Stats().OpenConnections)
+
+	var adbcErr adbc.Error
+	require.ErrorAs(t, d.SetOption(OptionPoolMaxOpenConnections, "-1"), &adbcErr)
+	assert.Equal(t, adbc.StatusInvalidArgument, adbcErr.Code)
+	require.ErrorAs(t, d.SetOption(OptionPoolIdleTimeout, "forever"), &adbcErr)
+	assert.Equal(t, adbc.StatusInvalidArgument, adbcErr.Code)
+
+	require.NoError(t, d.SetOption(OptionPoolMaxIdleConnections, ""))
+	assert.False(t, d.poolMaxIdleSet)
+}
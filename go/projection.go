@@ -0,0 +1,69 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"strings"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-go/v18/arrow"
+)
+
+// BuildSelectQuery builds a SELECT statement reading the given fields from
+// a table, typically a subset of the schema returned by GetTableSchema.
+// Identifiers are quoted, and each column with a direct Databricks
+// equivalent is cast to the type of its Arrow field so the result schema
+// matches the selection. The catalog and db schema may be empty.
+func BuildSelectQuery(catalog, dbSchema, table string, fields []arrow.Field) (string, error) {
+	if table == "" {
+		return "", adbc.Error{
+			Code: adbc.StatusInvalidArgument,
+			Msg:  "table name is required",
+		}
+	}
+	if len(fields) == 0 {
+		return "", adbc.Error{
+			Code: adbc.StatusInvalidArgument,
+			Msg:  "at least one field must be selected",
+		}
+	}
+
+	var sql strings.Builder
+	sql.WriteString("SELECT ")
+
+	for i, field := range fields {
+		if i > 0 {
+			sql.WriteString(", ")
+		}
+
+		column := quoteIdentifier(field.Name)
+		if typ, ok := lookupDatabricksType(field.Type); ok {
+			sql.WriteString("CAST(")
+			sql.WriteString(column)
+			sql.WriteString(" AS ")
+			sql.WriteString(typ)
+			sql.WriteString(") AS ")
+			sql.WriteString(column)
+		} else {
+			// Nested and other types without a direct equivalent are
+			// selected as-is
+			sql.WriteString(column)
+		}
+	}
+
+	sql.WriteString(" FROM ")
+	sql.WriteString(buildTableName(catalog, dbSchema, table))
+	return sql.String(), nil
+}
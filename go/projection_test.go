@@ -0,0 +1,56 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"testing"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildSelectQuery(t *testing.T) {
+	fields := []arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "amount", Type: &arrow.Decimal128Type{Precision: 10, Scale: 2}},
+		{Name: "we`ird", Type: arrow.BinaryTypes.String},
+		{Name: "tags", Type: arrow.ListOf(arrow.BinaryTypes.String)},
+	}
+
+	query, err := BuildSelectQuery("main", "sales", "orders", fields)
+	require.NoError(t, err)
+	assert.Equal(t,
+		"SELECT CAST(`id` AS BIGINT) AS `id`, CAST(`amount` AS DECIMAL(10, 2)) AS `amount`, "+
+			"CAST(`we``ird` AS STRING) AS `we``ird`, `tags` FROM `main`.`sales`.`orders`",
+		query)
+
+	query, err = BuildSelectQuery("", "", "orders", fields[:1])
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT CAST(`id` AS BIGINT) AS `id` FROM `orders`", query)
+}
+
+func TestBuildSelectQueryErrors(t *testing.T) {
+	var adbcErr adbc.Error
+
+	_, err := BuildSelectQuery("main", "sales", "orders", nil)
+	require.ErrorAs(t, err, &adbcErr)
+	assert.Equal(t, adbc.StatusInvalidArgument, adbcErr.Code)
+
+	_, err = BuildSelectQuery("main", "sales", "", []arrow.Field{{Name: "id", Type: arrow.PrimitiveTypes.Int64}})
+	require.ErrorAs(t, err, &adbcErr)
+	assert.Equal(t, adbc.StatusInvalidArgument, adbcErr.Code)
+}
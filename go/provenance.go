@@ -0,0 +1,119 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/databricks/databricks-sql-go/driverctx"
+)
+
+// queryProvenance records where the results of a query came from, for
+// OptionResultProvenance. A nil provenance is valid and does nothing.
+type queryProvenance struct {
+	executedAt time.Time
+	compute    computeInfo
+	version    string
+
+	mu      sync.Mutex
+	queryID string
+}
+
+// startProvenance begins recording the provenance of a query if
+// OptionResultProvenance is enabled, returning a context that captures
+// the server query ID along with any callback already set on ctx.
+func (c *connectionImpl) startProvenance(ctx context.Context) (context.Context, *queryProvenance) {
+	if !c.resultProvenance {
+		return ctx, nil
+	}
+
+	p := &queryProvenance{executedAt: time.Now().UTC(), compute: c.compute}
+	if c.DriverInfo != nil {
+		if version, ok := c.DriverInfo.GetInfoForInfoCode(adbc.InfoDriverVersion); ok {
+			p.version, _ = version.(string)
+		}
+	}
+	prev, _ := ctx.Value(driverctx.QueryIdCallbackKey).(driverctx.IdCallbackFunc)
+	ctx = driverctx.NewContextWithQueryIdCallback(ctx, func(id string) {
+		p.mu.Lock()
+		p.queryID = id
+		p.mu.Unlock()
+		if prev != nil {
+			prev(id)
+		}
+	})
+	return ctx, p
+}
+
+// metadata returns the schema metadata recording the provenance
+func (p *queryProvenance) metadata() arrow.Metadata {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var keys, values []string
+	add := func(key, value string) {
+		if value != "" {
+			keys = append(keys, key)
+			values = append(values, value)
+		}
+	}
+	add(MetadataKeyQueryID, p.queryID)
+	add(MetadataKeyExecutedAt, p.executedAt.Format(time.RFC3339Nano))
+	switch p.compute.kind {
+	case InfoValueComputeWarehouse:
+		add(MetadataKeyWarehouseID, p.compute.id)
+	case InfoValueComputeCluster:
+		add(MetadataKeyClusterID, p.compute.id)
+	}
+	add(MetadataKeyDriverVersion, p.version)
+	return arrow.NewMetadata(keys, values)
+}
+
+// wrap returns reader with the provenance added to the metadata of its
+// schema, keeping any the schema already has
+func (p *queryProvenance) wrap(reader array.RecordReader) array.RecordReader {
+	if p == nil {
+		return reader
+	}
+	schema := reader.Schema()
+	md := p.metadata()
+	keys, values := md.Keys(), md.Values()
+	existing := schema.Metadata()
+	for i, key := range existing.Keys() {
+		if md.FindKey(key) < 0 {
+			keys = append(keys, key)
+			values = append(values, existing.Values()[i])
+		}
+	}
+	metadata := arrow.NewMetadata(keys, values)
+	return &provenanceReader{RecordReader: reader, schema: arrow.NewSchema(schema.Fields(), &metadata)}
+}
+
+// provenanceReader is a reader whose schema carries the provenance of its
+// results. Arrow schema equality ignores schema metadata, so its batches
+// keep the schema they were read with.
+type provenanceReader struct {
+	array.RecordReader
+	schema *arrow.Schema
+}
+
+func (r *provenanceReader) Schema() *arrow.Schema {
+	return r.schema
+}
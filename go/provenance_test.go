@@ -0,0 +1,81 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/databricks/databricks-sql-go/driverctx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResultProvenance(t *testing.T) {
+	d := &databaseImpl{}
+	require.NoError(t, d.SetOption(OptionResultProvenance, "true"))
+	var adbcErr adbc.Error
+	require.ErrorAs(t, d.SetOption(OptionResultProvenance, "sometimes"), &adbcErr)
+	assert.Equal(t, adbc.StatusInvalidArgument, adbcErr.Code)
+
+	metadata := arrow.NewMetadata([]string{"origin"}, []string{"upstream"})
+	schema := arrow.NewSchema([]arrow.Field{{Name: "id", Type: arrow.PrimitiveTypes.Int64}}, &metadata)
+	newReader := func() array.RecordReader {
+		reader, err := array.NewRecordReader(schema, nil)
+		require.NoError(t, err)
+		return reader
+	}
+
+	// Disabled, results are returned as they are
+	c := &connectionImpl{compute: httpPathCompute("/sql/1.0/warehouses/abc")}
+	ctx, p := c.startProvenance(context.Background())
+	assert.Nil(t, p)
+	reader := newReader()
+	assert.Same(t, reader, p.wrap(reader))
+	reader.Release()
+
+	// Enabled, the query ID reported by the server is recorded along with
+	// any callback already set
+	var reported string
+	ctx = driverctx.NewContextWithQueryIdCallback(ctx, func(id string) { reported = id })
+	c.resultProvenance = d.resultProvenance
+	before := time.Now().UTC()
+	ctx, p = c.startProvenance(ctx)
+	ctx.Value(driverctx.QueryIdCallbackKey).(driverctx.IdCallbackFunc)("01ef-query")
+	assert.Equal(t, "01ef-query", reported)
+
+	reader = p.wrap(newReader())
+	defer reader.Release()
+	md := reader.Schema().Metadata()
+	value := func(key string) string {
+		idx := md.FindKey(key)
+		if idx < 0 {
+			return ""
+		}
+		return md.Values()[idx]
+	}
+	assert.Equal(t, "01ef-query", value(MetadataKeyQueryID))
+	assert.Equal(t, "abc", value(MetadataKeyWarehouseID))
+	assert.Empty(t, value(MetadataKeyClusterID))
+	assert.Equal(t, "upstream", value("origin"))
+	executedAt, err := time.Parse(time.RFC3339Nano, value(MetadataKeyExecutedAt))
+	require.NoError(t, err)
+	assert.False(t, executedAt.Before(before))
+	assert.True(t, reader.Schema().Equal(schema))
+}
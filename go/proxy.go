@@ -0,0 +1,62 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+// parseProxyURL validates a value for OptionProxyURL
+func parseProxyURL(value string) (*url.URL, error) {
+	proxyURL, err := url.Parse(value)
+	if err != nil {
+		return nil, err
+	}
+	switch proxyURL.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("unsupported proxy scheme '%s' (supported: 'http', 'https', 'socks5')", proxyURL.Scheme)
+	}
+	if proxyURL.Host == "" {
+		return nil, fmt.Errorf("proxy URL has no host")
+	}
+	return proxyURL, nil
+}
+
+// proxyFunc returns the proxy selection for HTTP transports: the proxy in
+// OptionProxyURL, except for hosts excluded by the NO_PROXY environment
+// variable, or otherwise the HTTP_PROXY and HTTPS_PROXY variables.
+func (d *databaseImpl) proxyFunc() func(*http.Request) (*url.URL, error) {
+	if d.proxyURL == nil {
+		return http.ProxyFromEnvironment
+	}
+
+	proxyURL := *d.proxyURL
+	if d.proxyUser != "" {
+		proxyURL.User = url.UserPassword(d.proxyUser, d.proxyPassword)
+	}
+
+	config := httpproxy.FromEnvironment()
+	config.HTTPProxy = proxyURL.String()
+	config.HTTPSProxy = proxyURL.String()
+	proxy := config.ProxyFunc()
+	return func(req *http.Request) (*url.URL, error) {
+		return proxy(req.URL)
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProxyOptions(t *testing.T) {
+	var proxied []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, _ := parseBasicAuth(r.Header.Get("Proxy-Authorization"))
+		assert.Equal(t, "alice", user)
+		assert.Equal(t, "s3cret", password)
+		proxied = append(proxied, r.URL.String())
+	}))
+	defer proxy.Close()
+
+	t.Setenv("NO_PROXY", "internal.example.com")
+	d := &databaseImpl{}
+	require.NoError(t, d.SetOption(OptionProxyURL, proxy.URL))
+	require.NoError(t, d.SetOption(OptionProxyUser, "alice"))
+	require.NoError(t, d.SetOption(OptionProxyPassword, "s3cret"))
+
+	client := &http.Client{Transport: d.newPooledTransport(nil, d.proxyFunc())}
+	resp, err := client.Get("http://workspace.example.com/sql/1.0/warehouses/abc")
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, []string{"http://workspace.example.com/sql/1.0/warehouses/abc"}, proxied)
+
+	req := httptest.NewRequest(http.MethodGet, "https://internal.example.com/", nil)
+	proxyURL, err := d.proxyFunc()(req)
+	require.NoError(t, err)
+	assert.Nil(t, proxyURL, "hosts in NO_PROXY should bypass the proxy")
+
+	val, err := d.GetOption(OptionProxyURL)
+	require.NoError(t, err)
+	assert.Equal(t, proxy.URL, val)
+
+	// Downloads from cloud storage would bypass the proxy
+	d.serverHostname = "example.cloud.databricks.com"
+	d.httpPath = "/sql/1.0/warehouses/abc"
+	d.accessToken = "dapi"
+	assert.False(t, usesCloudFetch(t, d))
+
+	var adbcErr adbc.Error
+	require.ErrorAs(t, d.SetOption(OptionProxyURL, "ftp://proxy:21"), &adbcErr)
+	assert.Equal(t, adbc.StatusInvalidArgument, adbcErr.Code)
+	require.NoError(t, d.SetOption(OptionProxyURL, "socks5://proxy:1080"))
+}
+
+// parseBasicAuth decodes a Basic credential from a Proxy-Authorization header
+func parseBasicAuth(header string) (user, password string, ok bool) {
+	req := &http.Request{Header: http.Header{"Authorization": {header}}}
+	return req.BasicAuth()
+}
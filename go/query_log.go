@@ -0,0 +1,155 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/databricks/databricks-sql-go/driverctx"
+)
+
+// Longest prefix of the SQL text included in slow query log entries, whose
+// string literals are redacted or hashed as set by
+// OptionQueryLogParameters
+const slowQueryLogMaxSQLLength = 256
+
+// queryTimer measures one statement execution for the slow query log. A
+// nil timer is valid and does nothing, so callers need not check whether
+// the log is enabled.
+type queryTimer struct {
+	logger    *slog.Logger
+	threshold time.Duration
+	query     string
+	// Hash rather than redact the string literals of the query
+	hashLiterals bool
+
+	mu       sync.Mutex
+	queryID  string
+	start    time.Time
+	executed time.Time
+	done     bool
+}
+
+// startQueryTimer begins timing a statement if the slow query log is
+// enabled, returning a context that captures the server query ID along
+// with any callback already set on ctx.
+func (c *connectionImpl) startQueryTimer(ctx context.Context, query string) (context.Context, *queryTimer) {
+	if c.slowQueryThreshold <= 0 || c.Logger == nil {
+		return ctx, nil
+	}
+
+	t := &queryTimer{
+		logger:       c.Logger,
+		threshold:    c.slowQueryThreshold,
+		query:        query,
+		start:        time.Now(),
+		hashLiterals: c.queryLogHashParams,
+	}
+	prev, _ := ctx.Value(driverctx.QueryIdCallbackKey).(driverctx.IdCallbackFunc)
+	ctx = driverctx.NewContextWithQueryIdCallback(ctx, func(id string) {
+		t.mu.Lock()
+		t.queryID = id
+		t.mu.Unlock()
+		if prev != nil {
+			prev(id)
+		}
+	})
+	return ctx, t
+}
+
+// markExecuted records that the server finished executing the statement
+// and results, if any, are about to be fetched.
+func (t *queryTimer) markExecuted() {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.executed = time.Now()
+}
+
+// finish logs the statement if it ran for at least the threshold. Only
+// the first call has an effect.
+func (t *queryTimer) finish(rows int64, err error) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.done {
+		return
+	}
+	t.done = true
+
+	end := time.Now()
+	total := end.Sub(t.start)
+	if total < t.threshold {
+		return
+	}
+
+	executed := t.executed
+	if executed.IsZero() {
+		executed = end
+	}
+
+	query := redactSQLLiterals(t.query, t.hashLiterals)
+	if len(query) > slowQueryLogMaxSQLLength {
+		// Cut on a character boundary
+		end := slowQueryLogMaxSQLLength
+		for end > 0 && !utf8.RuneStart(query[end]) {
+			end--
+		}
+		query = query[:end] + "..."
+	}
+
+	attrs := []any{
+		"query_id", t.queryID,
+		"duration", total,
+		"execute_duration", executed.Sub(t.start),
+		"fetch_duration", end.Sub(executed),
+		"rows", rows,
+		"sql", query,
+	}
+	if err != nil {
+		attrs = append(attrs, "error", err)
+	}
+	t.logger.Warn("slow query", attrs...)
+}
+
+// timedRecordReader reports a query to the slow query log once its result
+// set has been read to the end or released.
+type timedRecordReader struct {
+	array.RecordReader
+	timer *queryTimer
+	stats *resultStats
+}
+
+func (r *timedRecordReader) Next() bool {
+	if r.RecordReader.Next() {
+		return true
+	}
+	r.timer.finish(r.stats.rows.Load(), r.RecordReader.Err())
+	return false
+}
+
+func (r *timedRecordReader) Release() {
+	r.timer.finish(r.stats.rows.Load(), r.RecordReader.Err())
+	r.RecordReader.Release()
+}
@@ -0,0 +1,121 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adbc-drivers/driverbase-go/driverbase"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/databricks/databricks-sql-go/driverctx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSlowQueryTestConn(buf *bytes.Buffer, threshold time.Duration) *connectionImpl {
+	return &connectionImpl{
+		ConnectionImplBase: driverbase.ConnectionImplBase{
+			Logger: slog.New(slog.NewTextHandler(buf, nil)),
+		},
+		slowQueryThreshold: threshold,
+	}
+}
+
+func TestSlowQueryLog(t *testing.T) {
+	var buf bytes.Buffer
+	conn := newSlowQueryTestConn(&buf, time.Nanosecond)
+
+	ctx, timer := conn.startQueryTimer(context.Background(), "SELECT 1")
+	require.NotNil(t, timer)
+	driverctx.NewContextWithQueryId(ctx, "01ef-query")
+	timer.markExecuted()
+	timer.finish(42, nil)
+	// Later calls are ignored
+	timer.finish(0, errors.New("ignored"))
+
+	out := buf.String()
+	assert.Contains(t, out, "slow query")
+	assert.Contains(t, out, "query_id=01ef-query")
+	assert.Contains(t, out, "rows=42")
+	assert.Contains(t, out, `sql="SELECT 1"`)
+	assert.NotContains(t, out, "ignored")
+}
+
+func TestSlowQueryLogRedaction(t *testing.T) {
+	var buf bytes.Buffer
+	conn := newSlowQueryTestConn(&buf, time.Nanosecond)
+
+	// String literals are redacted, but not quoted identifiers or comments
+	_, timer := conn.startQueryTimer(context.Background(),
+		"SELECT `it's` FROM users -- don't\nWHERE ssn = '123-45-6789' AND note = \"a \\\" b\" /* 'x' */")
+	timer.finish(0, nil)
+	assert.Contains(t, buf.String(), `sql="SELECT `+"`it's`"+` FROM users -- don't\nWHERE ssn = '<redacted>' AND note = \"<redacted>\" /* 'x' */"`)
+	assert.NotContains(t, buf.String(), "6789")
+
+	// or hashed
+	buf.Reset()
+	conn.queryLogHashParams = true
+	_, timer = conn.startQueryTimer(context.Background(), "SELECT 'secret'")
+	timer.finish(0, nil)
+	assert.Contains(t, buf.String(), "SELECT 'sha256:2bb80d537b1da3e38bd30361aa855686bde0eacd7162fef6a25fe97bf527a25b'")
+
+	// Long statements are cut on a character boundary
+	buf.Reset()
+	query := "SELECT " + strings.Repeat("é", slowQueryLogMaxSQLLength)
+	_, timer = conn.startQueryTimer(context.Background(), query)
+	timer.finish(0, nil)
+	assert.Contains(t, buf.String(), `sql="`+query[:slowQueryLogMaxSQLLength-1]+`..."`)
+}
+
+func TestSlowQueryLogBelowThreshold(t *testing.T) {
+	var buf bytes.Buffer
+	conn := newSlowQueryTestConn(&buf, time.Hour)
+
+	_, timer := conn.startQueryTimer(context.Background(), "SELECT 1")
+	timer.finish(1, nil)
+	assert.Empty(t, buf.String())
+
+	conn.slowQueryThreshold = 0
+	_, timer = conn.startQueryTimer(context.Background(), "SELECT 1")
+	assert.Nil(t, timer)
+	// A nil timer is safe to use
+	timer.markExecuted()
+	timer.finish(1, nil)
+}
+
+func TestTimedRecordReader(t *testing.T) {
+	var buf bytes.Buffer
+	conn := newSlowQueryTestConn(&buf, time.Nanosecond)
+	_, timer := conn.startQueryTimer(context.Background(), "SELECT id FROM range(3)")
+
+	stats := &resultStats{}
+	stats.rows.Store(3)
+	reader := &timedRecordReader{
+		RecordReader: newTestRecordReader(t, memory.NewGoAllocator(), 3),
+		timer:        timer,
+		stats:        stats,
+	}
+	defer reader.Release()
+
+	for reader.Next() {
+	}
+	assert.Contains(t, buf.String(), "rows=3")
+}
@@ -0,0 +1,288 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql/driver"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/databricks/databricks-sql-go/driverctx"
+)
+
+// QueryLogger receives every SQL statement the driver runs on a session:
+// queries, updates, metadata lookups, the statements of bulk ingestion and
+// deletion, and OptionInitSQL. See NewDatabaseWithOptions. LogQuery is
+// called once the statement has executed, before its results are read,
+// and may be called concurrently by different connections.
+type QueryLogger interface {
+	LogQuery(ctx context.Context, entry QueryLogEntry)
+}
+
+// QueryLogEntry describes one executed statement
+type QueryLogEntry struct {
+	// SQL text, with parameter markers in place of values
+	SQL string
+	// Parameter values in order, redacted or hashed as configured with
+	// OptionQueryLogParameters; "NULL" for null values
+	Parameters []string
+	// Server query ID, if the statement reached the server
+	QueryID string
+	// When the statement started, and how long it took to execute
+	Start    time.Time
+	Duration time.Duration
+	// Why the statement failed, or nil
+	Err error
+}
+
+// renderLoggedParameters renders parameter values for a QueryLogEntry,
+// never including the values themselves
+func renderLoggedParameters(args []driver.NamedValue, hash bool) []string {
+	if len(args) == 0 {
+		return nil
+	}
+	params := make([]string, len(args))
+	for i, arg := range args {
+		params[i] = renderLoggedValue(arg.Value, hash)
+	}
+	return params
+}
+
+// renderLoggedValue renders a value for a log as "<redacted>", or its
+// SHA-256 if hash is set; "NULL" for a null value
+func renderLoggedValue(value any, hash bool) string {
+	switch {
+	case value == nil:
+		return "NULL"
+	case hash:
+		sum := sha256.Sum256(fmt.Appendf(nil, "%v", value))
+		return "sha256:" + hex.EncodeToString(sum[:])
+	default:
+		return "<redacted>"
+	}
+}
+
+// redactSQLLiterals replaces the contents of the string literals of query
+// with their renderings by renderLoggedValue. Quoted identifiers and
+// comments are left as they are.
+func redactSQLLiterals(query string, hash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(query); {
+		switch c := query[i]; {
+		case c == '\'' || c == '"':
+			// Backslashes escape the next character, and a doubled quote
+			// is an escaped one
+			end := i + 1
+			for end < len(query) {
+				if query[end] == '\\' {
+					end += 2
+					continue
+				}
+				if query[end] == c {
+					if end+1 < len(query) && query[end+1] == c {
+						end += 2
+						continue
+					}
+					break
+				}
+				end++
+			}
+			end = min(end, len(query))
+			b.WriteByte(c)
+			b.WriteString(renderLoggedValue(query[i+1:end], hash))
+			b.WriteByte(c)
+			i = end + 1
+		case c == '`':
+			end := len(query)
+			if j := strings.IndexByte(query[i+1:], '`'); j >= 0 {
+				end = i + j + 2
+			}
+			b.WriteString(query[i:end])
+			i = end
+		case strings.HasPrefix(query[i:], "--"):
+			end := len(query)
+			if j := strings.IndexByte(query[i:], '\n'); j >= 0 {
+				end = i + j
+			}
+			b.WriteString(query[i:end])
+			i = end
+		case strings.HasPrefix(query[i:], "/*"):
+			end := len(query)
+			if j := strings.Index(query[i+2:], "*/"); j >= 0 {
+				end = i + j + 4
+			}
+			b.WriteString(query[i:end])
+			i = end
+		default:
+			b.WriteByte(c)
+			i++
+		}
+	}
+	return b.String()
+}
+
+// queryLoggingConnector reports the statements run on its sessions to a
+// QueryLogger
+type queryLoggingConnector struct {
+	driver.Connector
+	logger     QueryLogger
+	hashParams bool
+}
+
+func (c *queryLoggingConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &queryLoggingConn{connector: c, conn: conn}, nil
+}
+
+// queryLoggingConn forwards to a session, reporting statements run on it
+type queryLoggingConn struct {
+	connector *queryLoggingConnector
+	conn      driver.Conn
+}
+
+// log runs fn, reporting query and its parameters. The server query ID is
+// captured alongside any callback already set on ctx.
+func (l *queryLoggingConn) log(ctx context.Context, query string, args []driver.NamedValue, fn func(context.Context) error) error {
+	var mu sync.Mutex
+	var queryID string
+	prev, _ := ctx.Value(driverctx.QueryIdCallbackKey).(driverctx.IdCallbackFunc)
+	ctx = driverctx.NewContextWithQueryIdCallback(ctx, func(id string) {
+		mu.Lock()
+		queryID = id
+		mu.Unlock()
+		if prev != nil {
+			prev(id)
+		}
+	})
+
+	start := time.Now()
+	err := fn(ctx)
+	if errors.Is(err, driver.ErrSkip) {
+		// database/sql runs the statement another way
+		return err
+	}
+
+	mu.Lock()
+	id := queryID
+	mu.Unlock()
+	l.connector.logger.LogQuery(ctx, QueryLogEntry{
+		SQL:        query,
+		Parameters: renderLoggedParameters(args, l.connector.hashParams),
+		QueryID:    id,
+		Start:      start,
+		Duration:   time.Since(start),
+		Err:        err,
+	})
+	return err
+}
+
+func (l *queryLoggingConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (result driver.Result, err error) {
+	execer, ok := l.conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	err = l.log(ctx, query, args, func(ctx context.Context) error {
+		result, err = execer.ExecContext(ctx, query, args)
+		return err
+	})
+	return result, err
+}
+
+func (l *queryLoggingConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (rows driver.Rows, err error) {
+	queryer, ok := l.conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	err = l.log(ctx, query, args, func(ctx context.Context) error {
+		rows, err = queryer.QueryContext(ctx, query, args)
+		return err
+	})
+	return rows, err
+}
+
+func (l *queryLoggingConn) Ping(ctx context.Context) error {
+	if pinger, ok := l.conn.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+func (l *queryLoggingConn) Prepare(query string) (driver.Stmt, error) {
+	return l.conn.Prepare(query)
+}
+
+func (l *queryLoggingConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	if preparer, ok := l.conn.(driver.ConnPrepareContext); ok {
+		return preparer.PrepareContext(ctx, query)
+	}
+	return l.conn.Prepare(query)
+}
+
+func (l *queryLoggingConn) Begin() (driver.Tx, error) {
+	return l.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (l *queryLoggingConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if beginner, ok := l.conn.(driver.ConnBeginTx); ok {
+		return beginner.BeginTx(ctx, opts)
+	}
+	return nil, errors.New("transactions are not supported")
+}
+
+func (l *queryLoggingConn) ResetSession(ctx context.Context) error {
+	if resetter, ok := l.conn.(driver.SessionResetter); ok {
+		return resetter.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (l *queryLoggingConn) IsValid() bool {
+	if validator, ok := l.conn.(driver.Validator); ok {
+		return validator.IsValid()
+	}
+	return true
+}
+
+func (l *queryLoggingConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if checker, ok := l.conn.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+func (l *queryLoggingConn) Close() error {
+	return l.conn.Close()
+}
+
+var (
+	_ driver.Connector          = (*queryLoggingConnector)(nil)
+	_ driver.ExecerContext      = (*queryLoggingConn)(nil)
+	_ driver.QueryerContext     = (*queryLoggingConn)(nil)
+	_ driver.Pinger             = (*queryLoggingConn)(nil)
+	_ driver.SessionResetter    = (*queryLoggingConn)(nil)
+	_ driver.Validator          = (*queryLoggingConn)(nil)
+	_ driver.NamedValueChecker  = (*queryLoggingConn)(nil)
+	_ driver.ConnPrepareContext = (*queryLoggingConn)(nil)
+	_ driver.ConnBeginTx        = (*queryLoggingConn)(nil)
+)
@@ -0,0 +1,91 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingQueryLogger struct {
+	mu      sync.Mutex
+	entries []QueryLogEntry
+}
+
+func (l *recordingQueryLogger) LogQuery(ctx context.Context, entry QueryLogEntry) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = append(l.entries, entry)
+}
+
+func TestQueryLoggingConnector(t *testing.T) {
+	for _, hash := range []bool{false, true} {
+		logger := &recordingQueryLogger{}
+		drv := &fakeDriver{keys: []string{"k"}}
+		db := sql.OpenDB(&queryLoggingConnector{Connector: drv, logger: logger, hashParams: hash})
+
+		ctx := context.Background()
+		_, err := db.ExecContext(ctx, "INSERT INTO t VALUES (?, ?)", "secret", nil)
+		require.NoError(t, err)
+		rows, err := db.QueryContext(ctx, "SELECT key FROM t")
+		require.NoError(t, err)
+		require.NoError(t, rows.Close())
+		require.NoError(t, db.Close())
+
+		require.Len(t, logger.entries, 2)
+		insert := logger.entries[0]
+		assert.Equal(t, "INSERT INTO t VALUES (?, ?)", insert.SQL)
+		require.Len(t, insert.Parameters, 2)
+		assert.Equal(t, "NULL", insert.Parameters[1])
+		if hash {
+			assert.True(t, strings.HasPrefix(insert.Parameters[0], "sha256:"))
+			assert.Len(t, insert.Parameters[0], len("sha256:")+64)
+		} else {
+			assert.Equal(t, "<redacted>", insert.Parameters[0])
+		}
+		assert.NotContains(t, insert.Parameters[0], "secret")
+		assert.False(t, insert.Start.IsZero())
+		assert.NoError(t, insert.Err)
+
+		assert.Equal(t, "SELECT key FROM t", logger.entries[1].SQL)
+		assert.Empty(t, logger.entries[1].Parameters)
+	}
+}
+
+func TestNewDatabaseWithOptions(t *testing.T) {
+	logger := &recordingQueryLogger{}
+	db, err := NewDatabaseWithOptions(context.Background(), memory.DefaultAllocator, map[string]string{
+		OptionQueryLogParameters: "HASH",
+	}, WithQueryLogger(logger))
+	require.NoError(t, err)
+	defer func() { _ = db.Close() }()
+
+	value, err := db.(adbc.GetSetOptions).GetOption(OptionQueryLogParameters)
+	require.NoError(t, err)
+	assert.Equal(t, OptionValueQueryLogParametersHash, value)
+
+	_, err = NewDatabaseWithOptions(context.Background(), memory.DefaultAllocator, map[string]string{
+		OptionQueryLogParameters: "plain",
+	})
+	assert.Error(t, err)
+}
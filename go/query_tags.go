@@ -0,0 +1,85 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+)
+
+// Session configuration attributing the statements of a session
+const queryTagsSessionParam = "QUERY_TAGS"
+
+// queryTag is a key, with an optional value, attributing statements, see
+// OptionQueryTags
+type queryTag struct {
+	Key   string `json:"key"`
+	Value string `json:"value,omitempty"`
+}
+
+// parseQueryTags parses a value of OptionQueryTags
+func parseQueryTags(value string) ([]queryTag, error) {
+	var tags []queryTag
+	for _, pair := range strings.Split(value, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		key, val, _ := strings.Cut(pair, ":")
+		key, val = strings.TrimSpace(key), strings.TrimSpace(val)
+		if key == "" {
+			return nil, adbc.Error{
+				Code: adbc.StatusInvalidArgument,
+				Msg:  fmt.Sprintf("invalid %s '%s': tag '%s' has no key", OptionQueryTags, value, pair),
+			}
+		}
+		tags = append(tags, queryTag{Key: key, Value: val})
+	}
+	return tags, nil
+}
+
+// formatQueryTags renders tags as the QUERY_TAGS session configuration
+// does: key:value pairs separated by commas
+func formatQueryTags(tags []queryTag) string {
+	pairs := make([]string, len(tags))
+	for i, tag := range tags {
+		pairs[i] = tag.Key
+		if tag.Value != "" {
+			pairs[i] += ":" + tag.Value
+		}
+	}
+	return strings.Join(pairs, ",")
+}
+
+// setQueryTags replaces the query tags of the connection's session
+func (c *connectionImpl) setQueryTags(value string) error {
+	tags, err := parseQueryTags(value)
+	if err != nil {
+		return err
+	}
+	if err := c.acquireExclusive(); err != nil {
+		return err
+	}
+	defer c.releaseExclusive()
+
+	query := fmt.Sprintf("SET %s = %s", queryTagsSessionParam, quoteString(formatQueryTags(tags)))
+	if _, err := c.conn.ExecContext(context.Background(), query); err != nil {
+		return c.ErrorHelper.Errorf(adbc.StatusIO, "failed to set query tags: %v", err)
+	}
+	c.queryTags = tags
+	return nil
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryTags(t *testing.T) {
+	tags, err := parseQueryTags(" team : data ,, app,cost-center:42 ")
+	require.NoError(t, err)
+	assert.Equal(t, []queryTag{{Key: "team", Value: "data"}, {Key: "app"}, {Key: "cost-center", Value: "42"}}, tags)
+	assert.Equal(t, "team:data,app,cost-center:42", formatQueryTags(tags))
+
+	_, err = parseQueryTags("team:data,:orphan")
+	var adbcErr adbc.Error
+	require.ErrorAs(t, err, &adbcErr)
+	assert.Equal(t, adbc.StatusInvalidArgument, adbcErr.Code)
+
+	// Database tags are session configuration of every connection
+	d := &databaseImpl{
+		serverHostname: "example.cloud.databricks.com",
+		httpPath:       "/sql/1.0/warehouses/abc",
+		accessToken:    "dapi-token",
+	}
+	require.NoError(t, d.SetOption(OptionQueryTags, "team:data, app"))
+	val, err := d.GetOption(OptionQueryTags)
+	require.NoError(t, err)
+	assert.Equal(t, "team:data,app", val)
+	_, err = d.resolveConnectionOptions()
+	require.NoError(t, err)
+	require.ErrorAs(t, d.SetOption(OptionQueryTags, ":x"), &adbcErr)
+
+	// Connection tags replace those of its session
+	drv := &fakeDriver{}
+	db := sql.OpenDB(drv)
+	defer func() { _ = db.Close() }()
+	conn, err := db.Conn(context.Background())
+	require.NoError(t, err)
+	c := &connectionImpl{conn: conn, queryTags: d.queryTags}
+	val, err = c.GetOption(OptionQueryTags)
+	require.NoError(t, err)
+	assert.Equal(t, "team:data,app", val)
+	require.NoError(t, c.SetOption(OptionQueryTags, "team:it's"))
+	assert.Equal(t, []string{"SET QUERY_TAGS = 'team:it''s'"}, drv.execs)
+	val, err = c.GetOption(OptionQueryTags)
+	require.NoError(t, err)
+	assert.Equal(t, "team:it's", val)
+
+	drv.execErr = func([]any) error { return executionError{msg: "[INVALID_PARAMETER_VALUE] QUERY_TAGS"} }
+	assert.Error(t, c.SetOption(OptionQueryTags, "team:ops"))
+	assert.Equal(t, []queryTag{{Key: "team", Value: "it's"}}, c.queryTags)
+}
+
+func TestSubmitAsyncQueryTags(t *testing.T) {
+	var submitted map[string]any
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&submitted))
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"statement_id": "01ef-stmt",
+			"status":       map[string]string{"state": "PENDING"},
+		})
+	}))
+	defer srv.Close()
+
+	pool := sql.OpenDB(&fakeDriver{})
+	defer func() { _ = pool.Close() }()
+	d := newWarehouseTestDatabase(t, srv, "")
+	s := &statementImpl{conn: &connectionImpl{
+		warehouse: d.warehouse,
+		pool:      pool,
+		queryTags: []queryTag{{Key: "team", Value: "data"}, {Key: "app"}},
+	}}
+	require.NoError(t, s.SetOption(OptionStatementSubmitAsync, adbc.OptionValueEnabled))
+	require.NoError(t, s.SetSqlQuery("INSERT INTO events VALUES (1)"))
+	_, err := s.ExecuteUpdate(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []any{
+		map[string]any{"key": "team", "value": "data"},
+		map[string]any{"key": "app"},
+	}, submitted["query_tags"])
+}
@@ -0,0 +1,68 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+)
+
+// errQueryTimeout is the cause of the contexts ended by OptionQueryTimeout
+var errQueryTimeout = errors.New("query timed out")
+
+// State of the operations the server stopped for running longer than the
+// server-side query timeout
+const serverTimedOutState = "TIMEDOUT_STATE"
+
+// startQueryTimeout returns a context that ends once the statement's
+// query has run for longer than OptionQueryTimeout, and a function to
+// call with the error of the query once it has run, which stops the timer
+// and reports a timeout as adbc.StatusTimeout. Results are read after the
+// timer is stopped, so reading them takes as long as it needs.
+func (s *statementImpl) startQueryTimeout(ctx context.Context) (context.Context, func(error) error) {
+	timeout := s.queryTimeout
+	if timeout <= 0 {
+		return ctx, s.timeoutErr(ctx, timeout)
+	}
+	ctx, cancel := context.WithCancelCause(ctx)
+	timer := time.AfterFunc(timeout, func() { cancel(errQueryTimeout) })
+	done := s.timeoutErr(ctx, timeout)
+	return ctx, func(err error) error {
+		timer.Stop()
+		return done(err)
+	}
+}
+
+// timeoutErr returns a function reporting err as adbc.StatusTimeout if
+// the query failed by running for longer than timeout, whether ctx ended
+// or the server stopped it
+func (s *statementImpl) timeoutErr(ctx context.Context, timeout time.Duration) func(error) error {
+	return func(err error) error {
+		if err == nil {
+			return nil
+		}
+		if errors.Is(context.Cause(ctx), errQueryTimeout) {
+			return s.ErrorHelper.Errorf(adbc.StatusTimeout, "query did not finish within %s (%s) and was canceled", timeout, OptionQueryTimeout)
+		}
+		if strings.Contains(err.Error(), serverTimedOutState) {
+			return s.ErrorHelper.Errorf(adbc.StatusTimeout, "query exceeded the server-side timeout: %v", err)
+		}
+		return err
+	}
+}
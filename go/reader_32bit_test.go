@@ -0,0 +1,57 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build 386 || arm || mips || mipsle
+
+package databricks
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 64-bit atomic operations panic on 32-bit platforms unless their operand
+// is 8-byte aligned, which Go only guarantees for atomic.Int64 and the
+// like, so reference counts of readers must use them
+func TestReaderReferenceCounts(t *testing.T) {
+	require.Equal(t, 32, strconv.IntSize)
+
+	data, err := os.ReadFile("testdata/little_endian.arrows")
+	require.NoError(t, err)
+	rows := &mockRows{iterator: &mockIPCStreamIterator{streams: [][]byte{data}, schema: data}}
+	reader, err := newIPCReaderAdapter(context.Background(), rows, &resultStats{}, nil)
+	require.NoError(t, err)
+	reader.Retain()
+	reader.Release()
+	reader.Release()
+
+	objects := &getObjectsReader{
+		ctx:     context.Background(),
+		mem:     memory.DefaultAllocator,
+		objects: &fakeObjects{},
+		depth:   adbc.ObjectDepthCatalogs,
+	}
+	objects.refCount.Store(1)
+	objects.Retain()
+	objects.Release()
+	objects.Release()
+	assert.Zero(t, objects.refCount.Load())
+}
@@ -0,0 +1,35 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Column of the results in OptionValueResultFormatJSONLines
+const jsonLinesColumn = "json"
+
+// resultQuery returns the query run for the results of ExecuteQuery and
+// ExecutePartitions, which converts the rows of the statement's query to
+// JSON in OptionValueResultFormatJSONLines. The query is nested on lines
+// of its own, so that a trailing comment does not swallow the rest.
+func (s *statementImpl) resultQuery() string {
+	if !s.jsonLines || s.query == "" {
+		return s.query
+	}
+	query := strings.TrimRight(strings.TrimSpace(s.query), "; \t\r\n")
+	return fmt.Sprintf("SELECT to_json(struct(*)) AS %s FROM (\n%s\n) AS adbc_json_lines", jsonLinesColumn, query)
+}
@@ -0,0 +1,49 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"testing"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResultFormat(t *testing.T) {
+	s := &statementImpl{conn: &connectionImpl{}}
+	require.NoError(t, s.SetSqlQuery("SELECT * FROM orders -- all of them;\n;"))
+
+	// Results are Arrow columns by default
+	val, err := s.GetOption(OptionStatementResultFormat)
+	require.NoError(t, err)
+	assert.Equal(t, OptionValueResultFormatArrow, val)
+	assert.Equal(t, "SELECT * FROM orders -- all of them;\n;", s.resultQuery())
+
+	// Or JSON lines converted by the server
+	require.NoError(t, s.SetOption(OptionStatementResultFormat, OptionValueResultFormatJSONLines))
+	val, err = s.GetOption(OptionStatementResultFormat)
+	require.NoError(t, err)
+	assert.Equal(t, OptionValueResultFormatJSONLines, val)
+	assert.Equal(t, "SELECT to_json(struct(*)) AS json FROM (\nSELECT * FROM orders -- all of them\n) AS adbc_json_lines", s.resultQuery())
+
+	// Without a query, there is nothing to wrap
+	require.NoError(t, s.SetSqlQuery(""))
+	assert.Empty(t, s.resultQuery())
+
+	var adbcErr adbc.Error
+	require.ErrorAs(t, s.SetOption(OptionStatementResultFormat, "csv"), &adbcErr)
+	assert.Equal(t, adbc.StatusInvalidArgument, adbcErr.Code)
+}
@@ -0,0 +1,146 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// errRetryBudgetExhausted is the cause of statements failed because their
+// connection used up its retry budget
+var errRetryBudgetExhausted = errors.New("retry budget exhausted")
+
+// retryBudget limits the retries made by all statements on a connection,
+// counting both the number of retries and the time spent waiting between a
+// failed request and its retry. Once either limit is reached, requests that
+// would be retried fail immediately instead. Any successful request ends
+// the outage and restores the budget.
+type retryBudget struct {
+	maxRetries int
+	maxTime    time.Duration
+
+	mu      sync.Mutex
+	retries int
+	elapsed time.Duration
+}
+
+// newRetryBudget returns a budget with the given limits, or nil if neither
+// limit is set
+func newRetryBudget(maxRetries int, maxTime time.Duration) *retryBudget {
+	if maxRetries <= 0 && maxTime <= 0 {
+		return nil
+	}
+	return &retryBudget{maxRetries: maxRetries, maxTime: maxTime}
+}
+
+// spend records a retry made after waiting for wait, failing if the
+// budget does not allow it
+func (b *retryBudget) spend(wait time.Duration) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if (b.maxRetries > 0 && b.retries >= b.maxRetries) || (b.maxTime > 0 && b.elapsed >= b.maxTime) {
+		return fmt.Errorf("%w: %d retries over %s", errRetryBudgetExhausted, b.retries, b.elapsed.Round(time.Millisecond))
+	}
+	b.retries++
+	b.elapsed += wait
+	return nil
+}
+
+// reset restores the full budget
+func (b *retryBudget) reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.retries = 0
+	b.elapsed = 0
+}
+
+type retryCallKey struct{}
+
+// retryCall tracks the requests made by one statement execution, so that
+// repeated attempts after a failure are recognized as retries
+type retryCall struct {
+	budget *retryBudget
+	cancel context.CancelCauseFunc
+
+	mu          sync.Mutex
+	lastFailure time.Time
+}
+
+// withRetryBudget returns a context charging retries of requests made with
+// it to the connection's retry budget. When the budget is exhausted the
+// context is canceled, which stops the driver's own retry loop.
+func (c *connectionImpl) withRetryBudget(ctx context.Context) context.Context {
+	if c.retryBudget == nil {
+		return ctx
+	}
+	ctx, cancel := context.WithCancelCause(ctx)
+	return context.WithValue(ctx, retryCallKey{}, &retryCall{budget: c.retryBudget, cancel: cancel})
+}
+
+// retryBudgetCause returns the budget error if it is what made err occur
+func retryBudgetCause(ctx context.Context, err error) error {
+	if cause := context.Cause(ctx); errors.Is(cause, errRetryBudgetExhausted) {
+		return cause
+	}
+	return err
+}
+
+// retryBudgetTransport charges retried requests to the retry budget of the
+// connection that made them. It sits beneath the retry loop of
+// databricks-sql-go, so it sees every attempt.
+type retryBudgetTransport struct {
+	base http.RoundTripper
+}
+
+func (t *retryBudgetTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	call, ok := req.Context().Value(retryCallKey{}).(*retryCall)
+	if !ok {
+		return t.base.RoundTrip(req)
+	}
+
+	call.mu.Lock()
+	lastFailure := call.lastFailure
+	call.mu.Unlock()
+	if !lastFailure.IsZero() {
+		if err := call.budget.spend(time.Since(lastFailure)); err != nil {
+			if req.Body != nil {
+				_ = req.Body.Close()
+			}
+			call.cancel(err)
+			return nil, err
+		}
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	failed := err != nil || resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError
+
+	call.mu.Lock()
+	if failed {
+		call.lastFailure = time.Now()
+	} else {
+		call.lastFailure = time.Time{}
+	}
+	call.mu.Unlock()
+	if !failed {
+		call.budget.reset()
+	}
+	return resp, err
+}
@@ -0,0 +1,96 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryBudgetTransport(t *testing.T) {
+	var requests atomic.Int32
+	var healthy atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	conn := &connectionImpl{retryBudget: newRetryBudget(3, 0)}
+	client := &http.Client{Transport: &retryBudgetTransport{base: http.DefaultTransport}}
+
+	// attempt sends a request as one try of a retry loop
+	attempt := func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, srv.URL, nil)
+		require.NoError(t, err)
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		_ = resp.Body.Close()
+		return nil
+	}
+
+	// Two statements retrying during an outage share the budget
+	first := conn.withRetryBudget(context.Background())
+	for range 3 {
+		require.NoError(t, attempt(first))
+	}
+	second := conn.withRetryBudget(context.Background())
+	require.NoError(t, attempt(second))
+	require.NoError(t, attempt(second))
+	assert.EqualValues(t, 5, requests.Load())
+
+	// The budget is spent, so the next retry fails without a request and
+	// cancels the statement
+	err := attempt(second)
+	assert.ErrorIs(t, err, errRetryBudgetExhausted)
+	assert.ErrorIs(t, retryBudgetCause(second, errors.New("context canceled")), errRetryBudgetExhausted)
+	assert.EqualValues(t, 5, requests.Load())
+
+	// First attempts are still made, and a success restores the budget
+	healthy.Store(true)
+	third := conn.withRetryBudget(context.Background())
+	require.NoError(t, attempt(third))
+	assert.Equal(t, 0, conn.retryBudget.retries)
+}
+
+func TestRetryBudgetOptions(t *testing.T) {
+	d := &databaseImpl{}
+	require.NoError(t, d.SetOption(OptionRetryBudgetMaxRetries, "10"))
+	require.NoError(t, d.SetOption(OptionRetryBudgetMaxTime, "2m"))
+	val, err := d.GetOption(OptionRetryBudgetMaxTime)
+	require.NoError(t, err)
+	assert.Equal(t, "2m0s", val)
+
+	budget := newRetryBudget(d.retryBudgetRetries, d.retryBudgetTime)
+	require.NotNil(t, budget)
+	assert.Equal(t, 10, budget.maxRetries)
+	assert.Nil(t, newRetryBudget(0, 0))
+
+	var adbcErr adbc.Error
+	require.ErrorAs(t, d.SetOption(OptionRetryBudgetMaxRetries, "-1"), &adbcErr)
+	assert.Equal(t, adbc.StatusInvalidArgument, adbcErr.Code)
+}
@@ -0,0 +1,131 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-go/v18/arrow"
+)
+
+// ingestTableSchema returns the columns an ingest writes for the bound
+// fields of schema: those fields, then the idempotency key column if
+// enabled
+func (s *statementImpl) ingestTableSchema(schema *arrow.Schema) *arrow.Schema {
+	if s.ingestOptions.IdempotencyKey == "" {
+		return schema
+	}
+	keyField := arrow.Field{Name: s.ingestOptions.idempotencyColumn(), Type: arrow.BinaryTypes.String, Nullable: true}
+	return arrow.NewSchema(append(slices.Clone(schema.Fields()), keyField), nil)
+}
+
+// batchAddedFields compares the schema of a batch of the bound stream
+// with the fields written so far, returning the fields it adds after
+// them. It fails if the batch drops, reorders or retypes any of them, or
+// adds fields while the schema evolution policy is to fail. The added
+// fields are recorded for OptionStatementIngestAddedColumns.
+func (s *statementImpl) batchAddedFields(batchIdx int, schema, batchSchema *arrow.Schema) ([]arrow.Field, error) {
+	fields := batchSchema.Fields()
+	for i, field := range schema.Fields() {
+		if i >= len(fields) {
+			return nil, s.ErrorHelper.Errorf(adbc.StatusInvalidArgument,
+				"batch %d of the bound data lacks field %s", batchIdx, quoteIdentifier(field.Name))
+		}
+		if identifierKey(fields[i].Name) != identifierKey(field.Name) || !arrow.TypeEqual(fields[i].Type, field.Type) {
+			return nil, s.ErrorHelper.Errorf(adbc.StatusInvalidArgument,
+				"field %d of batch %d of the bound data is %s %s, but was %s %s; only fields added after the others can be reconciled",
+				i, batchIdx, quoteIdentifier(fields[i].Name), fields[i].Type, quoteIdentifier(field.Name), field.Type)
+		}
+	}
+
+	added := fields[schema.NumFields():]
+	if len(added) == 0 {
+		return nil, nil
+	}
+	columns := map[string]bool{}
+	for _, field := range s.ingestTableSchema(schema).Fields() {
+		columns[identifierKey(field.Name)] = true
+	}
+	names := make([]string, len(added))
+	for i, field := range added {
+		names[i] = field.Name
+		if columns[identifierKey(field.Name)] {
+			return nil, s.ErrorHelper.Errorf(adbc.StatusInvalidArgument,
+				"batch %d of the bound data adds field %s, which is already a column", batchIdx, quoteIdentifier(field.Name))
+		}
+		columns[identifierKey(field.Name)] = true
+	}
+
+	switch s.ingestOptions.SchemaEvolution {
+	case OptionValueSchemaEvolutionAddColumns, OptionValueSchemaEvolutionIgnore:
+	default:
+		return nil, s.ErrorHelper.Errorf(adbc.StatusInvalidArgument,
+			"batch %d of the bound data adds fields %s; set %s to %s or %s to reconcile them",
+			batchIdx, strings.Join(names, ", "), OptionStatementIngestSchemaEvolution,
+			OptionValueSchemaEvolutionAddColumns, OptionValueSchemaEvolutionIgnore)
+	}
+
+	// Fields are reported once, though ignored ones recur in later batches
+	for _, name := range names {
+		if !slices.Contains(s.addedColumns, name) {
+			s.addedColumns = append(s.addedColumns, name)
+			if s.conn.Logger != nil {
+				s.conn.Logger.Info("bound data added a field during ingest",
+					"table", s.bulkIngestOptions.TableName, "field", name, "batch", batchIdx, "policy", s.ingestOptions.SchemaEvolution)
+			}
+		}
+	}
+	return added, nil
+}
+
+// addIngestColumns adds fields that batches of the bound stream added to
+// the table of t, as nullable columns, and prepares writing them. schema
+// and tableSchema are the bound fields and the columns written from then
+// on.
+func (s *statementImpl) addIngestColumns(ctx context.Context, t *ingestTarget, schema, tableSchema *arrow.Schema, added []arrow.Field) error {
+	if s.conn.txMode == transactionStaged && !t.opts.Temporary {
+		return s.ErrorHelper.Errorf(adbc.StatusNotImplemented,
+			"columns cannot be added during an ingest while changes are staged for commit")
+	}
+
+	columns := make([]string, len(added))
+	for i, field := range added {
+		if err := validateNewIdentifier("column", field.Name); err != nil {
+			return err
+		}
+		columns[i] = quoteIdentifier(field.Name) + " " + arrowTypeToDatabricksType(field.Type)
+	}
+	query := fmt.Sprintf("ALTER TABLE %s ADD COLUMNS (%s)", t.tableName, strings.Join(columns, ", "))
+	if _, err := s.conn.conn.ExecContext(ctx, query); err != nil {
+		return s.ErrorHelper.Errorf(adbc.StatusInternal, "failed to add columns %s to %s: %v",
+			strings.Join(columns, ", "), t.tableName, err)
+	}
+
+	// Added columns declare no defaults
+	if t.hasDefault != nil {
+		t.hasDefault = append(t.hasDefault, make([]bool, len(added))...)
+	}
+	t.defaultInsertSQL = map[string]string{}
+	var err error
+	if t.checkConversions, err = s.ingestConversionCheck(ctx, schema, t.opts); err != nil {
+		return err
+	}
+	t.insertSQL, err = buildInsertSQL(t.tableName, tableSchema, nil)
+	return err
+}
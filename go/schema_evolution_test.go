@@ -0,0 +1,148 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/adbc-drivers/driverbase-go/driverbase"
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// driftingReader is a record reader whose batches may have schemas other
+// than its own, as streams relaying upstream schema changes produce
+type driftingReader struct {
+	array.RecordReader
+	batches []arrow.RecordBatch
+	current arrow.RecordBatch
+}
+
+func (r *driftingReader) Next() bool {
+	if len(r.batches) == 0 {
+		r.current = nil
+		return false
+	}
+	r.current, r.batches = r.batches[0], r.batches[1:]
+	return true
+}
+
+func (r *driftingReader) RecordBatch() arrow.RecordBatch { return r.current }
+func (r *driftingReader) Err() error                     { return nil }
+
+// driftingBatches returns a batch of id, then one of id and email, then
+// one of id, email and score
+func driftingBatches(t *testing.T) []arrow.RecordBatch {
+	fields := []arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "email", Type: arrow.BinaryTypes.String, Nullable: true},
+		{Name: "score", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+	}
+	var batches []arrow.RecordBatch
+	for n := 1; n <= len(fields); n++ {
+		bldr := array.NewRecordBuilder(memory.DefaultAllocator, arrow.NewSchema(fields[:n], nil))
+		bldr.Field(0).(*array.Int64Builder).Append(int64(n))
+		if n > 1 {
+			bldr.Field(1).(*array.StringBuilder).Append("a@example.com")
+		}
+		if n > 2 {
+			bldr.Field(2).(*array.Float64Builder).Append(0.5)
+		}
+		rec := bldr.NewRecordBatch()
+		bldr.Release()
+		t.Cleanup(rec.Release)
+		batches = append(batches, rec)
+	}
+	return batches
+}
+
+func TestIngestSchemaEvolution(t *testing.T) {
+	ingest := func(policy string, batches []arrow.RecordBatch) (*statementImpl, *fakeDriver, int64, error) {
+		drv := &fakeDriver{}
+		db := sql.OpenDB(drv)
+		t.Cleanup(func() { _ = db.Close() })
+		conn, err := db.Conn(context.Background())
+		require.NoError(t, err)
+
+		base, err := array.NewRecordReader(batches[0].Schema(), nil)
+		require.NoError(t, err)
+		s := &statementImpl{
+			conn:              &connectionImpl{conn: conn},
+			bulkIngestOptions: driverbase.NewBulkIngestOptions(),
+			boundStream:       &driftingReader{RecordReader: base, batches: batches},
+		}
+		s.bulkIngestOptions.TableName = "events"
+		s.bulkIngestOptions.Mode = adbc.OptionValueIngestModeCreate
+		_, err = s.ingestOptions.SetOption(&s.ErrorHelper, OptionStatementIngestSchemaEvolution, policy)
+		require.NoError(t, err)
+		rows, err := s.executeIngest(context.Background())
+		return s, drv, rows, err
+	}
+
+	// Added fields become columns, written from the batch adding them
+	s, drv, rows, err := ingest(OptionValueSchemaEvolutionAddColumns, driftingBatches(t))
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), rows)
+	assert.Equal(t, []string{
+		"CREATE TABLE `events` (`id` BIGINT NOT NULL)",
+		"INSERT INTO `events` (`id`) VALUES (?)",
+		"ALTER TABLE `events` ADD COLUMNS (`email` STRING)",
+		"INSERT INTO `events` (`id`, `email`) VALUES (?, ?)",
+		"ALTER TABLE `events` ADD COLUMNS (`score` DOUBLE)",
+		"INSERT INTO `events` (`id`, `email`, `score`) VALUES (?, ?, ?)",
+	}, drv.execs)
+	assert.Equal(t, []any{"3", "a@example.com", "0.5"}, drv.args[len(drv.args)-1])
+	added, err := s.getOption(OptionStatementIngestAddedColumns)
+	require.NoError(t, err)
+	assert.Equal(t, "email,score", added)
+
+	// Ignored fields are not written, but still reported
+	s, drv, _, err = ingest(OptionValueSchemaEvolutionIgnore, driftingBatches(t))
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"CREATE TABLE `events` (`id` BIGINT NOT NULL)",
+		"INSERT INTO `events` (`id`) VALUES (?)",
+		"INSERT INTO `events` (`id`) VALUES (?)",
+		"INSERT INTO `events` (`id`) VALUES (?)",
+	}, drv.execs)
+	added, err = s.getOption(OptionStatementIngestAddedColumns)
+	require.NoError(t, err)
+	assert.Equal(t, "email,score", added)
+
+	// By default, the batch adding fields fails before any of its rows
+	// is written
+	_, drv, rows, err = ingest("", driftingBatches(t))
+	var adbcErr adbc.Error
+	require.ErrorAs(t, err, &adbcErr)
+	assert.Equal(t, adbc.StatusInvalidArgument, adbcErr.Code)
+	assert.Contains(t, adbcErr.Msg, "batch 1 of the bound data adds fields email")
+	assert.Equal(t, int64(1), rows)
+	assert.Len(t, drv.execs, 2)
+
+	// Fields cannot be dropped, whatever the policy
+	batches := driftingBatches(t)
+	_, _, _, err = ingest(OptionValueSchemaEvolutionAddColumns, []arrow.RecordBatch{batches[1], batches[0]})
+	assert.ErrorContains(t, err, "batch 1 of the bound data lacks field `email`")
+
+	_, err = (&ingestOptions{}).SetOption(&driverbase.ErrorHelper{}, OptionStatementIngestSchemaEvolution, "merge")
+	require.ErrorAs(t, err, &adbcErr)
+	assert.Equal(t, adbc.StatusInvalidArgument, adbcErr.Code)
+}
@@ -0,0 +1,47 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-go/v18/arrow"
+)
+
+// checkSQLLength fails if query is longer than the connection's
+// OptionMaxSQLLength, before it is sent to the warehouse
+func (c *connectionImpl) checkSQLLength(query string) error {
+	if c.maxSQLLength <= 0 || len(query) <= c.maxSQLLength {
+		return nil
+	}
+	return c.ErrorHelper.Errorf(adbc.StatusInvalidArgument,
+		"statement of %d bytes exceeds the maximum SQL length of %d bytes set by %s: %.64s...",
+		len(query), c.maxSQLLength, OptionMaxSQLLength, query)
+}
+
+// deleteRowsPerStatement returns how many of the batchSize keys a DELETE
+// of buildDeleteByKeysSQL can match within the connection's
+// OptionMaxSQLLength, failing if not even one fits
+func (c *connectionImpl) deleteRowsPerStatement(tableName string, schema *arrow.Schema, batchSize int) (int, error) {
+	if c.maxSQLLength <= 0 {
+		return batchSize, nil
+	}
+	single := buildDeleteByKeysSQL(tableName, schema, 1)
+	if err := c.checkSQLLength(single); err != nil {
+		return 0, err
+	}
+	// Each further key adds a separator and its placeholders
+	perRow := len(buildDeleteByKeysSQL(tableName, schema, 2)) - len(single)
+	return min(batchSize, 1+(c.maxSQLLength-len(single))/perRow), nil
+}
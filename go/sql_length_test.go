@@ -0,0 +1,83 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaxSQLLength(t *testing.T) {
+	d := &databaseImpl{maxSQLLength: DefaultMaxSQLLength}
+	val, err := d.GetOption(OptionMaxSQLLength)
+	require.NoError(t, err)
+	assert.Equal(t, "16777216", val)
+	require.NoError(t, d.SetOption(OptionMaxSQLLength, "40"))
+	var adbcErr adbc.Error
+	require.ErrorAs(t, d.SetOption(OptionMaxSQLLength, "-1"), &adbcErr)
+	assert.Equal(t, adbc.StatusInvalidArgument, adbcErr.Code)
+
+	drv := &fakeDriver{}
+	db := sql.OpenDB(drv)
+	defer func() { _ = db.Close() }()
+	conn, err := db.Conn(context.Background())
+	require.NoError(t, err)
+	s := &statementImpl{conn: &connectionImpl{conn: conn, maxSQLLength: d.maxSQLLength}}
+
+	// Statements that are too long are not sent
+	require.NoError(t, s.SetSqlQuery("UPDATE t SET v = 1 WHERE id IN ("+strings.Repeat("1, ", 20)+"1)"))
+	_, err = s.ExecuteUpdate(context.Background())
+	require.ErrorAs(t, err, &adbcErr)
+	assert.Equal(t, adbc.StatusInvalidArgument, adbcErr.Code)
+	assert.Contains(t, adbcErr.Msg, "statement of 94 bytes exceeds the maximum SQL length of 40 bytes")
+	assert.Empty(t, drv.execs)
+
+	// Deletes match fewer keys per statement to fit
+	schema := arrow.NewSchema([]arrow.Field{{Name: "id", Type: arrow.PrimitiveTypes.Int64}}, nil)
+	bldr := array.NewRecordBuilder(memory.DefaultAllocator, schema)
+	defer bldr.Release()
+	bldr.Field(0).(*array.Int64Builder).AppendValues([]int64{1, 2, 3, 4, 5, 6, 7}, nil)
+	rec := bldr.NewRecordBatch()
+	defer rec.Release()
+	s.deleteOptions = newDeleteByKeysOptions()
+	s.deleteOptions.TableName = "t"
+	require.NoError(t, s.Bind(context.Background(), rec))
+	_, err = s.executeDeleteByKeys(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"DELETE FROM `t` WHERE `id` IN (?, ?, ?)",
+		"DELETE FROM `t` WHERE `id` IN (?, ?, ?)",
+		"DELETE FROM `t` WHERE `id` IN (?)",
+	}, drv.execs)
+
+	// Unless not even a single key fits
+	drv.execs = nil
+	s.conn.maxSQLLength = 20
+	require.NoError(t, s.Bind(context.Background(), rec))
+	_, err = s.executeDeleteByKeys(context.Background())
+	require.ErrorAs(t, err, &adbcErr)
+	assert.Equal(t, adbc.StatusInvalidArgument, adbcErr.Code)
+	assert.Contains(t, adbcErr.Msg, "maximum SQL length of 20 bytes")
+	assert.Empty(t, drv.execs)
+}
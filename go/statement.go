@@ -27,6 +27,10 @@ import (
 	"database/sql"
 	"database/sql/driver"
 	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/adbc-drivers/driverbase-go/driverbase"
 	"github.com/apache/arrow-adbc/go/adbc"
@@ -41,12 +45,85 @@ type statementImpl struct {
 	prepared          *sql.Stmt
 	boundStream       array.RecordReader
 	bulkIngestOptions driverbase.BulkIngestOptions
+	ingestOptions     ingestOptions
+	deleteOptions     deleteByKeysOptions
+	copyIntoOptions   copyIntoOptions
+	resultStats       *resultStats
+	memoryStats       *memoryStats
+	shadowIngest      *shadowIngestResult
+	// Fields that batches of the last ingest added
+	addedColumns []string
+	// Whether the last execution consumed the bound data, see
+	// OptionStatementBindState
+	boundConsumed bool
+
+	// Submit updates with the Statement Execution API, and the ID of the
+	// statement last submitted
+	submitAsync bool
+	submittedID string
+
+	// Held for the duration of every call. Locks are only ever tried, so
+	// a call made while another is in progress (e.g. SetSqlQuery during
+	// ExecuteQuery) fails with StatusInvalidState instead of racing.
+	mu sync.Mutex
+	// The call, or results, that Cancel stops; not held for the duration
+	// of calls, so that Cancel can be called during one
+	cancelMu  sync.Mutex
+	operation *operation
+	// Longest each query may run, see OptionQueryTimeout; 0 for no limit
+	queryTimeout time.Duration
+	// Most rows in each batch of results, see OptionFetchSize; 0 for no
+	// limit
+	fetchSize int64
+	// Return results as JSON lines, see OptionStatementResultFormat
+	jsonLines bool
+	// Readers of results not yet released, which Close releases
+	readers map[*operationReader]struct{}
+}
+
+// acquire marks the statement busy for the duration of a call, failing if
+// another call is in progress or the statement is closed.
+func (s *statementImpl) acquire() error {
+	if !s.mu.TryLock() {
+		return s.ErrorHelper.Errorf(adbc.StatusInvalidState, "statement is in use by another call")
+	}
+	if s.conn == nil {
+		s.mu.Unlock()
+		return s.ErrorHelper.Errorf(adbc.StatusInvalidState, "statement is closed")
+	}
+	return nil
+}
+
+// acquireWithConn is like acquire for calls that execute on the
+// connection, which is also held so it cannot be closed meanwhile.
+func (s *statementImpl) acquireWithConn() error {
+	if err := s.acquire(); err != nil {
+		return err
+	}
+	if err := s.conn.acquire(); err != nil {
+		s.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+func (s *statementImpl) releaseWithConn() {
+	s.conn.release()
+	s.mu.Unlock()
 }
 
 func (s *statementImpl) Close() error {
+	if !s.mu.TryLock() {
+		return s.ErrorHelper.Errorf(adbc.StatusInvalidState, "statement is in use by another call")
+	}
+	defer s.mu.Unlock()
+
 	if s.conn == nil {
 		return s.ErrorHelper.Errorf(adbc.StatusInvalidState, "statement already closed")
 	}
+	// Results abandoned without being read would otherwise hold their
+	// server operation until the session ends
+	s.closeReaders()
 	if s.boundStream != nil {
 		s.boundStream.Release()
 		s.boundStream = nil
@@ -62,17 +139,233 @@ func (s *statementImpl) Close() error {
 }
 
 func (s *statementImpl) SetOption(key, val string) error {
+	if err := s.acquire(); err != nil {
+		return err
+	}
+	defer s.mu.Unlock()
+
 	if handled, err := s.bulkIngestOptions.SetOption(&s.ErrorHelper, key, val); err != nil {
 		return err
 	} else if handled {
 		return nil
 	}
 
+	if handled, err := s.ingestOptions.SetOption(&s.ErrorHelper, key, val); err != nil {
+		return err
+	} else if handled {
+		return nil
+	}
+
+	if handled, err := s.deleteOptions.SetOption(&s.ErrorHelper, key, val); err != nil {
+		return err
+	} else if handled {
+		return nil
+	}
+
+	if handled, err := s.copyIntoOptions.SetOption(&s.ErrorHelper, key, val); err != nil {
+		return err
+	} else if handled {
+		return nil
+	}
+
+	switch key {
+	case OptionStatementSubmitAsync:
+		enabled, err := strconv.ParseBool(val)
+		if err != nil {
+			return s.ErrorHelper.Errorf(adbc.StatusInvalidArgument, "invalid statement option %s=%s", key, val)
+		}
+		s.submitAsync = enabled
+		return nil
+	case OptionStatementSubmittedID:
+		s.submittedID = val
+		return nil
+	case OptionStatementSubmittedCancel:
+		return s.cancelSubmitted()
+	case OptionStatementResultFormat:
+		switch val {
+		case OptionValueResultFormatArrow:
+			s.jsonLines = false
+		case OptionValueResultFormatJSONLines:
+			s.jsonLines = true
+		default:
+			return s.ErrorHelper.Errorf(adbc.StatusInvalidArgument, "invalid statement option %s=%s", key, val)
+		}
+		return nil
+	case OptionQueryTimeout:
+		timeout := time.Duration(0)
+		if val != "" {
+			var err error
+			timeout, err = time.ParseDuration(val)
+			if err != nil || timeout < 0 {
+				return s.ErrorHelper.Errorf(adbc.StatusInvalidArgument, "invalid statement option %s=%s", key, val)
+			}
+		}
+		s.queryTimeout = timeout
+		return nil
+	case OptionFetchSize:
+		fetchSize, err := parseFetchSize(val)
+		if err != nil {
+			return s.ErrorHelper.Errorf(adbc.StatusInvalidArgument, "invalid statement option %s=%s", key, val)
+		}
+		s.fetchSize = fetchSize
+		return nil
+	}
+
 	return s.ErrorHelper.Errorf(adbc.StatusNotImplemented, "unsupported statement option: %s=%s", key, val)
 }
 
+func (s *statementImpl) GetOption(key string) (string, error) {
+	if err := s.acquire(); err != nil {
+		return "", err
+	}
+	defer s.mu.Unlock()
+
+	return s.getOption(key)
+}
+
+func (s *statementImpl) getOption(key string) (string, error) {
+	if val, ok := bulkIngestOption(&s.bulkIngestOptions, key); ok {
+		return val, nil
+	}
+	if val, ok := s.ingestOptions.GetOption(key); ok {
+		return val, nil
+	}
+	if val, ok := s.deleteOptions.GetOption(key); ok {
+		return val, nil
+	}
+	if val, ok := s.copyIntoOptions.GetOption(key); ok {
+		return val, nil
+	}
+
+	switch key {
+	case OptionStatementResultFetchedChunks, OptionStatementResultFetchedBatches, OptionStatementResultFetchedRows, OptionStatementResultFetchedBytes,
+		OptionStatementResultTotalChunks, OptionStatementResultTotalRows, OptionStatementResultTotalBytes,
+		OptionStatementMemoryCurrentBytes, OptionStatementMemoryPeakBytes, OptionStatementIngestShadowRowCount:
+		val, err := s.getOptionInt(key)
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatInt(val, 10), nil
+	case OptionStatementIngestAddedColumns:
+		return strings.Join(s.addedColumns, ","), nil
+	case OptionStatementBindState:
+		return s.bindState(), nil
+	case OptionStatementIngestShadowError:
+		if s.shadowIngest == nil {
+			return "", s.ErrorHelper.Errorf(adbc.StatusInvalidState, "no ingest into a shadow table")
+		}
+		if s.shadowIngest.err != nil {
+			return s.shadowIngest.err.Error(), nil
+		}
+		return "", nil
+	case OptionStatementSubmitAsync:
+		if s.submitAsync {
+			return adbc.OptionValueEnabled, nil
+		}
+		return adbc.OptionValueDisabled, nil
+	case OptionStatementSubmittedID:
+		return s.submittedID, nil
+	case OptionStatementResultFormat:
+		if s.jsonLines {
+			return OptionValueResultFormatJSONLines, nil
+		}
+		return OptionValueResultFormatArrow, nil
+	case OptionQueryTimeout:
+		if s.queryTimeout > 0 {
+			return s.queryTimeout.String(), nil
+		}
+		return "", nil
+	case OptionFetchSize:
+		if s.fetchSize > 0 {
+			return strconv.FormatInt(s.fetchSize, 10), nil
+		}
+		return "", nil
+	case OptionStatementSubmittedState, OptionStatementSubmittedError:
+		status, err := s.submittedStatus()
+		if err != nil {
+			return "", err
+		}
+		if key == OptionStatementSubmittedState {
+			return status.State, nil
+		}
+		return status.Error, nil
+	case OptionStatementResultComplete:
+		if s.resultStats == nil {
+			return "", s.ErrorHelper.Errorf(adbc.StatusInvalidState, "no result set available")
+		}
+		if s.resultStats.complete.Load() {
+			return adbc.OptionValueEnabled, nil
+		}
+		return adbc.OptionValueDisabled, nil
+	}
+
+	return s.StatementImplBase.GetOption(key)
+}
+
+func (s *statementImpl) GetOptionInt(key string) (int64, error) {
+	if err := s.acquire(); err != nil {
+		return 0, err
+	}
+	defer s.mu.Unlock()
+
+	return s.getOptionInt(key)
+}
+
+func (s *statementImpl) getOptionInt(key string) (int64, error) {
+	switch key {
+	case OptionStatementResultFetchedChunks, OptionStatementResultFetchedBatches, OptionStatementResultFetchedRows, OptionStatementResultFetchedBytes,
+		OptionStatementResultTotalChunks, OptionStatementResultTotalRows, OptionStatementResultTotalBytes:
+		if s.resultStats == nil {
+			return 0, s.ErrorHelper.Errorf(adbc.StatusInvalidState, "no result set available")
+		}
+	case OptionStatementMemoryCurrentBytes, OptionStatementMemoryPeakBytes:
+		if s.memoryStats == nil {
+			return 0, s.ErrorHelper.Errorf(adbc.StatusInvalidState, "no result set or ingest available")
+		}
+	case OptionStatementIngestShadowRowCount:
+		if s.shadowIngest == nil {
+			return 0, s.ErrorHelper.Errorf(adbc.StatusInvalidState, "no ingest into a shadow table")
+		}
+		return s.shadowIngest.rows, nil
+	}
+
+	switch key {
+	case OptionStatementMemoryCurrentBytes:
+		return s.memoryStats.current.Load(), nil
+	case OptionStatementMemoryPeakBytes:
+		return s.memoryStats.peak.Load(), nil
+	case OptionStatementResultFetchedChunks:
+		return s.resultStats.chunks.Load(), nil
+	case OptionStatementResultFetchedBatches:
+		return s.resultStats.batches.Load(), nil
+	case OptionStatementResultFetchedRows:
+		return s.resultStats.rows.Load(), nil
+	case OptionStatementResultFetchedBytes:
+		return s.resultStats.bytes.Load(), nil
+	case OptionStatementResultTotalChunks:
+		return s.resultStats.total(&s.resultStats.chunks, func(m submittedManifest) int64 { return int64(m.TotalChunkCount) }), nil
+	case OptionStatementResultTotalRows:
+		return s.resultStats.total(&s.resultStats.rows, func(m submittedManifest) int64 { return m.TotalRowCount }), nil
+	case OptionStatementResultTotalBytes:
+		return s.resultStats.total(&s.resultStats.bytes, func(m submittedManifest) int64 { return m.TotalByteCount }), nil
+	}
+
+	value, err := s.getOption(key)
+	if err != nil {
+		return 0, err
+	}
+	return parseIntOption(&s.ErrorHelper, key, value)
+}
+
 func (s *statementImpl) SetSqlQuery(query string) error {
+	if err := s.acquire(); err != nil {
+		return err
+	}
+	defer s.mu.Unlock()
+
 	s.query = query
+	// The new query has not been submitted
+	s.submittedID = ""
 	// Reset prepared statement if query changes
 	if s.prepared != nil {
 		if err := s.prepared.Close(); err != nil {
@@ -84,6 +377,11 @@ func (s *statementImpl) SetSqlQuery(query string) error {
 }
 
 func (s *statementImpl) Prepare(ctx context.Context) error {
+	if err := s.acquireWithConn(); err != nil {
+		return err
+	}
+	defer s.releaseWithConn()
+
 	if s.query == "" {
 		return s.ErrorHelper.Errorf(adbc.StatusInvalidState, "no query set")
 	}
@@ -98,29 +396,110 @@ func (s *statementImpl) Prepare(ctx context.Context) error {
 }
 
 func (s *statementImpl) ExecuteQuery(ctx context.Context) (array.RecordReader, int64, error) {
-	if s.boundStream != nil {
-		return nil, -1, s.ErrorHelper.Errorf(adbc.StatusNotImplemented, "parameterized queries not yet implemented")
+	if err := s.acquire(); err != nil {
+		return nil, -1, err
+	}
+	defer s.mu.Unlock()
+
+	ctx, op := s.startOperation(ctx)
+	ctx, timedOut := s.startQueryTimeout(ctx)
+	reader, rowsAffected, err := s.queryResults(ctx)
+	reader, err = op.results(withFetchSize(reader, s.fetchSize), timedOut(err))
+	return reader, rowsAffected, err
+}
+
+// queryResults runs the query, or reads the results of the submitted
+// statement, for ExecuteQuery
+func (s *statementImpl) queryResults(ctx context.Context) (array.RecordReader, int64, error) {
+	if !s.copyIntoOptions.IsSet() && (s.submitAsync || (s.query == "" && s.submittedID != "")) {
+		// Submitted statements run, and their results are read, outside
+		// of the session, which is not established for them
+		if err := s.conn.acquireOpen(); err != nil {
+			return nil, -1, err
+		}
+		defer s.conn.release()
+		submitted := s.submittedID == ""
+		if submitted {
+			if err := s.submit(ctx, s.resultQuery()); err != nil {
+				return nil, -1, err
+			}
+		}
+		reader, err := s.fetchSubmittedResults(ctx)
+		if err != nil && submitted && ctx.Err() != nil {
+			// Nobody is left to read the results of the statement
+			// submitted for this call
+			_ = s.cancelSubmitted()
+		}
+		return reader, -1, err
+	}
+
+	if err := s.conn.acquire(); err != nil {
+		return nil, -1, err
+	}
+	defer s.conn.release()
+
+	return s.executeQuery(ctx)
+}
+
+func (s *statementImpl) executeQuery(ctx context.Context) (array.RecordReader, int64, error) {
+	if s.copyIntoOptions.IsSet() {
+		reader, err := s.executeCopyInto(ctx)
+		return reader, -1, err
 	}
 
 	if s.query == "" {
 		return nil, -1, s.ErrorHelper.Errorf(adbc.StatusInvalidState, "no query set")
 	}
 
+	if s.boundStream != nil {
+		return s.executeBoundQuery(ctx)
+	}
+
+	reader, err := s.runQuery(ctx, nil)
+	if err != nil {
+		return nil, -1, err
+	}
+	// Return -1 for rowsAffected (unknown) since we can't count without consuming
+	// The ADBC spec allows -1 to indicate "unknown number of rows affected"
+	return reader, -1, nil
+}
+
+// runQuery runs the query with the given parameters, returning a reader
+// of its results
+func (s *statementImpl) runQuery(ctx context.Context, args []driver.NamedValue) (reader array.RecordReader, err error) {
+	query := s.resultQuery()
+	if err := s.conn.checkSQLLength(query); err != nil {
+		return nil, err
+	}
+	changesNamespace, err := s.conn.beforeStatement(ctx, s.query)
+	if err != nil {
+		return nil, err
+	}
+
 	// Execute query using raw driver interface to get Arrow batches
 	// This works for both prepared and unprepared statements since
 	// databricks-sql-go doesn't do server-side preparation
+	ctx = s.conn.withRetryBudget(ctx)
+	ctx = s.conn.withUsageTracking(ctx)
+	ctx, timer := s.conn.startQueryTimer(ctx, query)
+	ctx, provenance := s.conn.startProvenance(ctx)
+
 	var driverRows driver.Rows
-	var err error
 	err = s.conn.conn.Raw(func(driverConn interface{}) error {
 		// Use raw driver interface for direct Arrow access
 		queryerCtx := driverConn.(driver.QueryerContext)
-		var driverArgs []driver.NamedValue
-		driverRows, err = queryerCtx.QueryContext(ctx, s.query, driverArgs)
+		driverRows, err = queryerCtx.QueryContext(ctx, query, args)
 		return err
 	})
 
 	if err != nil {
-		return nil, -1, s.ErrorHelper.Errorf(adbc.StatusInternal, "failed to execute query: %v", err)
+		err = retryBudgetCause(ctx, err)
+		timer.finish(-1, err)
+		return nil, s.ErrorHelper.Errorf(adbc.StatusInternal, "failed to execute query: %v", err)
+	}
+	timer.markExecuted()
+	if changesNamespace {
+		s.conn.afterNamespaceChange()
 	}
 
 	defer func() {
@@ -133,28 +512,95 @@ func (s *statementImpl) ExecuteQuery(ctx context.Context) (array.RecordReader, i
 	}()
 
 	// Use the IPC stream interface (zero-copy)
-	reader, err := newIPCReaderAdapter(ctx, driverRows)
+	stats := &resultStats{}
+	memStats := &memoryStats{}
+	reader, err = newIPCReaderAdapter(ctx, driverRows, stats, newTrackingAllocator(s.conn.Alloc, memStats, s.conn.memoryBudget))
 	if err != nil {
-		return nil, -1, s.ErrorHelper.Errorf(adbc.StatusInternal, "failed to create IPC reader adapter: %v", err)
+		timer.finish(-1, err)
+		return nil, s.ErrorHelper.Errorf(adbc.StatusInternal, "failed to create IPC reader adapter: %v", err)
 	}
 	driverRows = nil // Prevent double close in defer
+	s.resultStats = stats
+	s.memoryStats = memStats
 
-	// Return -1 for rowsAffected (unknown) since we can't count without consuming
-	// The ADBC spec allows -1 to indicate "unknown number of rows affected"
-	return reader, -1, nil
+	if timer != nil {
+		reader = &timedRecordReader{RecordReader: reader, timer: timer, stats: stats}
+	}
+	if s.conn.idleClose != nil {
+		reader = newSessionReader(reader, s.conn)
+	}
+	return provenance.wrap(reader), nil
 }
 
 func (s *statementImpl) ExecuteUpdate(ctx context.Context) (int64, error) {
+	if err := s.acquire(); err != nil {
+		return -1, err
+	}
+	defer s.mu.Unlock()
+
+	ctx, op := s.startOperation(ctx)
+	ctx, timedOut := s.startQueryTimeout(ctx)
+	rowsAffected, err := s.executeUpdate(ctx)
+	return rowsAffected, op.finish(timedOut(err))
+}
+
+func (s *statementImpl) executeUpdate(ctx context.Context) (int64, error) {
+	if s.submitAsync && !s.bulkIngestOptions.IsSet() && !s.deleteOptions.IsSet() {
+		// Submitted statements run outside of the session, which is not
+		// established for them
+		if err := s.conn.acquireOpen(); err != nil {
+			return -1, err
+		}
+		defer s.conn.release()
+		return s.submitUpdate(ctx)
+	}
+
+	if err := s.conn.acquire(); err != nil {
+		return -1, err
+	}
+	defer s.conn.release()
+
+	ctx = s.conn.withRetryBudget(ctx)
+	ctx = s.conn.withUsageTracking(ctx)
+
+	if s.bulkIngestOptions.IsSet() && s.deleteOptions.IsSet() {
+		return -1, s.ErrorHelper.Errorf(adbc.StatusInvalidState, "cannot set both an ingest target and a delete target")
+	}
+
+	if s.copyIntoOptions.IsSet() {
+		return -1, s.ErrorHelper.Errorf(adbc.StatusInvalidState, "the results of %s are returned by ExecuteQuery", OptionStatementCopyIntoTargetTable)
+	}
+
 	if s.bulkIngestOptions.IsSet() {
 		return s.executeIngest(ctx)
 	}
 
+	if s.deleteOptions.IsSet() {
+		if s.conn.txMode == transactionStaged {
+			return -1, s.ErrorHelper.Errorf(adbc.StatusNotImplemented,
+				"deleting by keys while autocommit is disabled requires a warehouse supporting transactions")
+		}
+		return s.executeDeleteByKeys(ctx)
+	}
+
 	if s.boundStream != nil {
-		return -1, s.ErrorHelper.Errorf(adbc.StatusInvalidState, "bound data provided but no ingest target set")
+		return s.executeBoundUpdate(ctx)
+	}
+
+	if err := s.conn.checkSQLLength(s.query); err != nil {
+		return -1, err
+	}
+	if s.conn.txMode == transactionStaged {
+		return s.bufferUpdate(ctx)
+	}
+
+	changesNamespace, err := s.conn.beforeStatement(ctx, s.query)
+	if err != nil {
+		return -1, err
 	}
 
 	var result sql.Result
-	var err error
+	ctx, timer := s.conn.startQueryTimer(ctx, s.query)
 
 	if s.prepared != nil {
 		result, err = s.prepared.ExecContext(ctx)
@@ -165,10 +611,17 @@ func (s *statementImpl) ExecuteUpdate(ctx context.Context) (int64, error) {
 	}
 
 	if err != nil {
+		err = retryBudgetCause(ctx, err)
+		timer.finish(-1, err)
 		return -1, s.ErrorHelper.Errorf(adbc.StatusInternal, "failed to execute update: %v", err)
 	}
+	timer.markExecuted()
+	if changesNamespace {
+		s.conn.afterNamespaceChange()
+	}
 
 	rowsAffected, err := result.RowsAffected()
+	timer.finish(rowsAffected, err)
 	if err != nil {
 		return -1, s.ErrorHelper.Errorf(adbc.StatusInternal, "failed to get rows affected: %v", err)
 	}
@@ -177,23 +630,27 @@ func (s *statementImpl) ExecuteUpdate(ctx context.Context) (int64, error) {
 }
 
 func (s *statementImpl) Bind(ctx context.Context, values arrow.RecordBatch) error {
-	if s.boundStream != nil {
-		s.boundStream.Release()
+	if err := s.acquire(); err != nil {
+		return err
 	}
+	defer s.mu.Unlock()
+
 	stream, err := array.NewRecordReader(values.Schema(), []arrow.RecordBatch{values})
 	if err != nil {
 		return s.ErrorHelper.Errorf(adbc.StatusInternal, "failed to create record reader")
 	}
-	s.boundStream = stream
+	s.bind(stream)
 	return nil
 }
 
 func (s *statementImpl) BindStream(ctx context.Context, stream array.RecordReader) error {
-	if s.boundStream != nil {
-		s.boundStream.Release()
+	if err := s.acquire(); err != nil {
+		return err
 	}
+	defer s.mu.Unlock()
+
 	stream.Retain()
-	s.boundStream = stream
+	s.bind(stream)
 	return nil
 }
 
@@ -207,8 +664,3 @@ func (s *statementImpl) SetSubstraitPlan(plan []byte) error {
 	// Databricks SQL doesn't support Substrait plans
 	return s.ErrorHelper.Errorf(adbc.StatusNotImplemented, "Substrait plans not supported")
 }
-
-func (s *statementImpl) ExecutePartitions(ctx context.Context) (*arrow.Schema, adbc.Partitions, int64, error) {
-	// Databricks SQL doesn't support partitioned result sets
-	return nil, adbc.Partitions{}, -1, s.ErrorHelper.Errorf(adbc.StatusNotImplemented, "partitioned result sets not supported")
-}
@@ -0,0 +1,229 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+)
+
+// submittedStatus is the state of a statement submitted to the Statement
+// Execution API, why it failed if it did, and the description of its
+// results once it has succeeded
+type submittedStatus struct {
+	State    string
+	Error    string
+	Manifest submittedManifest
+}
+
+// submitStatement submits query to the Statement Execution API, returning
+// its statement ID without waiting for it to run
+func (w *warehouseClient) submitStatement(ctx context.Context, query, catalog, schema string, tags []queryTag) (string, error) {
+	req := struct {
+		WarehouseID   string     `json:"warehouse_id"`
+		Statement     string     `json:"statement"`
+		Catalog       string     `json:"catalog,omitempty"`
+		Schema        string     `json:"schema,omitempty"`
+		QueryTags     []queryTag `json:"query_tags,omitempty"`
+		Format        string     `json:"format"`
+		Disposition   string     `json:"disposition"`
+		WaitTimeout   string     `json:"wait_timeout"`
+		OnWaitTimeout string     `json:"on_wait_timeout"`
+	}{
+		WarehouseID: w.warehouseID,
+		Statement:   query,
+		Catalog:     catalog,
+		Schema:      schema,
+		QueryTags:   tags,
+		// Results, if any, can then be read by ExecuteQuery
+		Format:      submittedFormatArrow,
+		Disposition: "EXTERNAL_LINKS",
+		// Return at once, leaving the statement running
+		WaitTimeout:   "0s",
+		OnWaitTimeout: "CONTINUE",
+	}
+	var resp struct {
+		StatementID string `json:"statement_id"`
+	}
+	if err := w.do(ctx, http.MethodPost, "/api/2.0/sql/statements/", &req, &resp); err != nil {
+		return "", err
+	}
+	return resp.StatementID, nil
+}
+
+// statementStatus returns the current state of a submitted statement
+func (w *warehouseClient) statementStatus(ctx context.Context, id string) (submittedStatus, error) {
+	var resp struct {
+		Status struct {
+			State string `json:"state"`
+			Error struct {
+				ErrorCode string `json:"error_code"`
+				Message   string `json:"message"`
+			} `json:"error"`
+		} `json:"status"`
+		Manifest submittedManifest `json:"manifest"`
+	}
+	if err := w.do(ctx, http.MethodGet, "/api/2.0/sql/statements/"+url.PathEscape(id), nil, &resp); err != nil {
+		return submittedStatus{}, err
+	}
+	status := submittedStatus{State: resp.Status.State, Manifest: resp.Manifest}
+	if e := resp.Status.Error; e.ErrorCode != "" || e.Message != "" {
+		status.Error = strings.TrimSpace(e.ErrorCode + " " + e.Message)
+	}
+	return status, nil
+}
+
+// cancelStatement asks for a submitted statement to be canceled. The
+// request returns at once; the statement is CANCELED once it stops.
+func (w *warehouseClient) cancelStatement(ctx context.Context, id string) error {
+	return w.do(ctx, http.MethodPost, "/api/2.0/sql/statements/"+url.PathEscape(id)+"/cancel", nil, nil)
+}
+
+// warehouseAPI returns the REST client of the connection's warehouse
+func (s *statementImpl) warehouseAPI() (*warehouseClient, error) {
+	if s.conn.warehouse == nil {
+		return nil, s.conn.warehouseRequired(OptionStatementSubmitAsync)
+	}
+	return s.conn.warehouse, nil
+}
+
+// submitUpdate submits the query for OptionStatementSubmitAsync
+func (s *statementImpl) submitUpdate(ctx context.Context) (int64, error) {
+	return -1, s.submit(ctx, s.query)
+}
+
+// submit submits query, the statement's or one wrapping it, to the
+// Statement Execution API
+func (s *statementImpl) submit(ctx context.Context, query string) error {
+	if query == "" {
+		return s.ErrorHelper.Errorf(adbc.StatusInvalidState, "no query set")
+	}
+	if s.boundStream != nil {
+		return s.ErrorHelper.Errorf(adbc.StatusInvalidState, "bound data cannot be submitted with %s", OptionStatementSubmitAsync)
+	}
+	if s.conn.txMode != transactionNone {
+		return s.ErrorHelper.Errorf(adbc.StatusInvalidState, "statements cannot be submitted with %s while autocommit is disabled", OptionStatementSubmitAsync)
+	}
+	if s.conn.impersonateUser != "" {
+		return s.ErrorHelper.Errorf(adbc.StatusNotImplemented, "statements cannot be submitted with %s while impersonating a user with %s", OptionStatementSubmitAsync, OptionImpersonateUser)
+	}
+	if setting := s.conn.sessionState(); setting != "" {
+		return s.ErrorHelper.Errorf(adbc.StatusInvalidState,
+			"statements cannot be submitted with %s after %s changed the session, as they run outside of it", OptionStatementSubmitAsync, setting)
+	}
+	api, err := s.warehouseAPI()
+	if err != nil {
+		return err
+	}
+
+	catalog, schema, err := s.conn.restNamespace(ctx)
+	if err != nil {
+		return err
+	}
+	id, err := api.submitStatement(ctx, query, catalog, schema, s.conn.queryTags)
+	if err != nil {
+		return s.ErrorHelper.Errorf(adbc.StatusIO, "failed to submit statement: %v", err)
+	}
+	s.submittedID = id
+	if s.conn.usage != nil {
+		s.conn.usage.add(id)
+	}
+	return nil
+}
+
+// cancelSubmitted cancels the submitted statement, for
+// OptionStatementSubmittedCancel
+func (s *statementImpl) cancelSubmitted() error {
+	if s.submittedID == "" {
+		return s.ErrorHelper.Errorf(adbc.StatusInvalidState, "no statement submitted")
+	}
+	api, err := s.warehouseAPI()
+	if err != nil {
+		return err
+	}
+	if err := api.cancelStatement(context.Background(), s.submittedID); err != nil {
+		return s.ErrorHelper.Errorf(adbc.StatusIO, "failed to cancel statement %s: %v", s.submittedID, err)
+	}
+	return nil
+}
+
+// submittedStatus asks for the state of the submitted statement
+func (s *statementImpl) submittedStatus() (submittedStatus, error) {
+	if s.submittedID == "" {
+		return submittedStatus{}, s.ErrorHelper.Errorf(adbc.StatusInvalidState, "no statement submitted")
+	}
+	api, err := s.warehouseAPI()
+	if err != nil {
+		return submittedStatus{}, err
+	}
+	status, err := api.statementStatus(context.Background(), s.submittedID)
+	if err != nil {
+		return submittedStatus{}, s.ErrorHelper.Errorf(adbc.StatusIO, "failed to get state of statement %s: %v", s.submittedID, err)
+	}
+	return status, nil
+}
+
+// sessionSettingStatementRe matches statements that change settings of
+// the session other than its catalog and schema
+var sessionSettingStatementRe = regexp.MustCompile(`(?i)^\s*SET\s`)
+
+// sessionSettings returns the options changing the settings of sessions,
+// which statements run with the Statement Execution API do not have
+func (d *databaseImpl) sessionSettings() []string {
+	var settings []string
+	for _, setting := range [][2]string{
+		{OptionInitSQL, d.initSQL},
+		{OptionSessionANSIMode, d.ansiMode},
+		{OptionSessionDefaultCollation, d.defaultCollation},
+		{OptionUseCachedResult, d.useCachedResult},
+	} {
+		if setting[1] != "" {
+			settings = append(settings, setting[0])
+		}
+	}
+	return settings
+}
+
+// sessionState returns what changed the settings of the session, which
+// statements run outside of it would not have, or "" if nothing did
+func (c *connectionImpl) sessionState() string {
+	if len(c.sessionSettings) > 0 {
+		return c.sessionSettings[0]
+	}
+	if c.sessionChanged.Load() {
+		return "a SET statement"
+	}
+	return ""
+}
+
+// restNamespace returns the catalog and schema the statements of the
+// session run in, for those run outside of it. A session not yet
+// established has not left those of the options.
+func (c *connectionImpl) restNamespace(ctx context.Context) (catalog, dbSchema string, err error) {
+	c.connectMu.Lock()
+	connected := c.conn != nil
+	c.connectMu.Unlock()
+	if connected {
+		return c.resolveNamespace(ctx)
+	}
+	c.namespaceMu.Lock()
+	defer c.namespaceMu.Unlock()
+	return c.catalog, c.dbSchema, nil
+}
@@ -0,0 +1,384 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubmitAsync(t *testing.T) {
+	var submitted map[string]string
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer dapi-token", r.Header.Get("Authorization"))
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/api/2.0/sql/statements/":
+			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&submitted))
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"statement_id": "01ef-stmt",
+				"status":       map[string]string{"state": "PENDING"},
+			})
+		case r.Method == http.MethodGet && r.URL.Path == "/api/2.0/sql/statements/01ef-stmt":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"statement_id": "01ef-stmt",
+				"status": map[string]any{
+					"state": "FAILED",
+					"error": map[string]string{"error_code": "BAD_REQUEST", "message": "[TABLE_OR_VIEW_NOT_FOUND] events"},
+				},
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	// A lazy connection, whose session is not established to submit
+	pool := sql.OpenDB(&fakeDriver{})
+	defer func() { _ = pool.Close() }()
+	d := newWarehouseTestDatabase(t, srv, "")
+	s := &statementImpl{conn: &connectionImpl{warehouse: d.warehouse, catalog: "main", pool: pool}}
+	require.NoError(t, s.SetOption(OptionStatementSubmitAsync, adbc.OptionValueEnabled))
+	require.NoError(t, s.SetSqlQuery("INSERT INTO events VALUES (1)"))
+
+	rows, err := s.ExecuteUpdate(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(-1), rows)
+	assert.Equal(t, map[string]string{
+		"warehouse_id":    "abc",
+		"statement":       "INSERT INTO events VALUES (1)",
+		"catalog":         "main",
+		"format":          "ARROW_STREAM",
+		"disposition":     "EXTERNAL_LINKS",
+		"wait_timeout":    "0s",
+		"on_wait_timeout": "CONTINUE",
+	}, submitted)
+
+	id, err := s.GetOption(OptionStatementSubmittedID)
+	require.NoError(t, err)
+	assert.Equal(t, "01ef-stmt", id)
+	state, err := s.GetOption(OptionStatementSubmittedState)
+	require.NoError(t, err)
+	assert.Equal(t, "FAILED", state)
+	reason, err := s.GetOption(OptionStatementSubmittedError)
+	require.NoError(t, err)
+	assert.Equal(t, "BAD_REQUEST [TABLE_OR_VIEW_NOT_FOUND] events", reason)
+
+	// A statement submitted elsewhere can be followed by its ID
+	other := &statementImpl{conn: &connectionImpl{warehouse: d.warehouse}}
+	_, err = other.GetOption(OptionStatementSubmittedState)
+	requireInvalidState(t, err)
+	require.NoError(t, other.SetOption(OptionStatementSubmittedID, "missing"))
+	_, err = other.GetOption(OptionStatementSubmittedState)
+	assert.ErrorContains(t, err, "status 404")
+}
+
+func TestSubmitAsyncRequiresWarehouse(t *testing.T) {
+	pool := sql.OpenDB(&fakeDriver{})
+	defer func() { _ = pool.Close() }()
+	s := &statementImpl{conn: &connectionImpl{pool: pool}}
+	require.NoError(t, s.SetOption(OptionStatementSubmitAsync, adbc.OptionValueEnabled))
+	require.NoError(t, s.SetSqlQuery("INSERT INTO events VALUES (1)"))
+	_, err := s.ExecuteUpdate(context.Background())
+	var adbcErr adbc.Error
+	require.ErrorAs(t, err, &adbcErr)
+	assert.Equal(t, adbc.StatusNotImplemented, adbcErr.Code)
+}
+
+func TestSubmittedResults(t *testing.T) {
+	schema := arrow.NewSchema([]arrow.Field{{Name: "id", Type: arrow.PrimitiveTypes.Int64}}, nil)
+	chunk := func(ids ...int64) []byte {
+		bldr := array.NewRecordBuilder(memory.DefaultAllocator, schema)
+		defer bldr.Release()
+		bldr.Field(0).(*array.Int64Builder).AppendValues(ids, nil)
+		rec := bldr.NewRecordBatch()
+		defer rec.Release()
+		var buf bytes.Buffer
+		w := ipc.NewWriter(&buf, ipc.WithSchema(schema))
+		require.NoError(t, w.Write(rec))
+		require.NoError(t, w.Close())
+		return buf.Bytes()
+	}
+	chunks := [][]byte{chunk(1, 2), chunk(3)}
+
+	polls := 0
+	state, format := "SUCCEEDED", "ARROW_STREAM"
+	var srv *httptest.Server
+	srv = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var index int
+		switch {
+		case r.URL.Path == "/api/2.0/sql/statements/01ef-stmt":
+			assert.Equal(t, "Bearer dapi-token", r.Header.Get("Authorization"))
+			polls++
+			current := state
+			if polls == 1 {
+				current = "RUNNING"
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"statement_id": "01ef-stmt",
+				"status":       map[string]any{"state": current, "error": map[string]string{"message": "[DIVIDE_BY_ZERO]"}},
+				"manifest": map[string]any{
+					"format":            format,
+					"total_chunk_count": len(chunks),
+					"total_row_count":   3,
+					"total_byte_count":  1024,
+					"schema":            map[string]any{"columns": []map[string]any{{"name": "id", "type_name": "LONG"}}},
+				},
+			})
+		case fmtSscanf(r.URL.Path, "/api/2.0/sql/statements/01ef-stmt/result/chunks/%d", &index):
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"external_links": []map[string]any{{
+					"external_link": fmt.Sprintf("%s/download/%d", srv.URL, index),
+					"http_headers":  map[string]string{"x-amz-server-side-encryption": "AES256"},
+				}},
+			})
+		case fmtSscanf(r.URL.Path, "/download/%d", &index):
+			// Links are presigned
+			assert.Empty(t, r.Header.Get("Authorization"))
+			assert.Equal(t, "AES256", r.Header.Get("x-amz-server-side-encryption"))
+			_, _ = w.Write(chunks[index])
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	d := newWarehouseTestDatabase(t, srv, "")
+	pool := sql.OpenDB(&fakeDriver{})
+	defer func() { _ = pool.Close() }()
+	s := &statementImpl{conn: &connectionImpl{warehouse: d.warehouse, pool: pool}}
+	s.conn.Alloc = memory.DefaultAllocator
+	require.NoError(t, s.SetOption(OptionStatementSubmittedID, "01ef-stmt"))
+	read := func() ([]int64, error) {
+		rdr, _, err := s.ExecuteQuery(context.Background())
+		if err != nil {
+			return nil, err
+		}
+		defer rdr.Release()
+		var ids []int64
+		for rdr.Next() {
+			ids = append(ids, rdr.RecordBatch().Column(0).(*array.Int64).Int64Values()...)
+		}
+		return ids, rdr.Err()
+	}
+
+	// The results are read once the statement finishes, chunk by chunk
+	ids, err := read()
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 2, 3}, ids)
+	assert.Equal(t, 2, polls)
+	val, err := s.GetOption(OptionStatementResultFetchedChunks)
+	require.NoError(t, err)
+	assert.Equal(t, "2", val)
+	// The totals are those of the manifest
+	for key, want := range map[string]int64{
+		OptionStatementResultTotalChunks: 2,
+		OptionStatementResultTotalRows:   3,
+		OptionStatementResultTotalBytes:  1024,
+	} {
+		total, err := s.GetOptionInt(key)
+		require.NoError(t, err)
+		assert.Equal(t, want, total, key)
+	}
+
+	// Results without chunks have the schema of the manifest
+	chunks = nil
+	rdr, _, err := s.ExecuteQuery(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "id", rdr.Schema().Field(0).Name)
+	assert.Equal(t, arrow.PrimitiveTypes.Int64, rdr.Schema().Field(0).Type)
+	assert.False(t, rdr.Next())
+	rdr.Release()
+
+	polls = 1
+	state = "FAILED"
+	_, err = read()
+	assert.ErrorContains(t, err, "statement 01ef-stmt failed: [DIVIDE_BY_ZERO]")
+
+	polls = 1
+	state, format = "SUCCEEDED", "JSON_ARRAY"
+	_, err = read()
+	var adbcErr adbc.Error
+	require.ErrorAs(t, err, &adbcErr)
+	assert.Equal(t, adbc.StatusNotImplemented, adbcErr.Code)
+}
+
+func TestDownloadExternalLink(t *testing.T) {
+	var headers http.Header
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header.Clone()
+		_, _ = w.Write([]byte("chunk"))
+	}))
+	defer srv.Close()
+
+	d := newWarehouseTestDatabase(t, srv, "")
+	require.NoError(t, d.SetOption(OptionHTTPHeaderPrefix+"X-Gateway-Token", "secret"))
+	_, err := d.resolveConnectionOptions()
+	require.NoError(t, err)
+
+	// Cloud storage gets the headers of the link alone, and the body is
+	// read for as long as it takes
+	body, err := d.warehouse.download(context.Background(), externalLink{
+		ExternalLink: srv.URL + "/chunk",
+		HTTPHeaders:  map[string]string{"x-amz-server-side-encryption": "AES256"},
+	})
+	require.NoError(t, err)
+	data, err := io.ReadAll(body)
+	require.NoError(t, err)
+	require.NoError(t, body.Close())
+	assert.Equal(t, "chunk", string(data))
+	assert.Equal(t, "AES256", headers.Get("x-amz-server-side-encryption"))
+	assert.Empty(t, headers.Get("X-Gateway-Token"))
+	assert.Empty(t, headers.Get("Authorization"))
+	assert.Zero(t, d.warehouse.downloadClient.Timeout)
+}
+
+// fmtSscanf reports whether s is of the given format, scanning its values
+func fmtSscanf(s, format string, args ...any) bool {
+	_, err := fmt.Sscanf(s, format, args...)
+	return err == nil
+}
+
+func TestSubmitAsyncQuery(t *testing.T) {
+	var submits int
+	var canceled []string
+	state := "RUNNING"
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/api/2.0/sql/statements/":
+			submits++
+			_ = json.NewEncoder(w).Encode(map[string]any{"statement_id": fmt.Sprintf("stmt-%d", submits)})
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/cancel"):
+			canceled = append(canceled, strings.Split(r.URL.Path, "/")[5])
+		case r.Method == http.MethodGet:
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"status": map[string]any{"state": state},
+				"manifest": map[string]any{
+					"format": "ARROW_STREAM",
+					"schema": map[string]any{"columns": []map[string]any{{"name": "total", "type_name": "DECIMAL", "type_precision": 10, "type_scale": 2}}},
+				},
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	pool := sql.OpenDB(&fakeDriver{})
+	defer func() { _ = pool.Close() }()
+	d := newWarehouseTestDatabase(t, srv, "")
+	s := &statementImpl{conn: &connectionImpl{warehouse: d.warehouse, pool: pool}}
+	s.conn.Alloc = memory.DefaultAllocator
+	require.NoError(t, s.SetOption(OptionStatementSubmitAsync, adbc.OptionValueEnabled))
+	require.NoError(t, s.SetSqlQuery("SELECT sum(total) FROM orders"))
+
+	// Submitting returns at once, leaving the query to be polled and
+	// canceled
+	_, err := s.ExecuteUpdate(context.Background())
+	require.NoError(t, err)
+	val, err := s.GetOption(OptionStatementSubmittedState)
+	require.NoError(t, err)
+	assert.Equal(t, "RUNNING", val)
+	require.NoError(t, s.SetOption(OptionStatementSubmittedCancel, adbc.OptionValueEnabled))
+	assert.Equal(t, []string{"stmt-1"}, canceled)
+
+	// Its results are read once it is done, without submitting it again
+	state = "SUCCEEDED"
+	rdr, _, err := s.ExecuteQuery(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, &arrow.Decimal128Type{Precision: 10, Scale: 2}, rdr.Schema().Field(0).Type)
+	rdr.Release()
+	assert.Equal(t, 1, submits)
+
+	// A new query is submitted by ExecuteQuery
+	require.NoError(t, s.SetSqlQuery("SELECT count(*) FROM orders"))
+	rdr, _, err = s.ExecuteQuery(context.Background())
+	require.NoError(t, err)
+	rdr.Release()
+	assert.Equal(t, 2, submits)
+	val, err = s.GetOption(OptionStatementSubmittedID)
+	require.NoError(t, err)
+	assert.Equal(t, "stmt-2", val)
+
+	// One given up on while running is canceled
+	state = "RUNNING"
+	require.NoError(t, s.SetSqlQuery("SELECT * FROM orders"))
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, _, err = s.ExecuteQuery(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, []string{"stmt-1", "stmt-3"}, canceled)
+
+	require.NoError(t, s.SetSqlQuery("SELECT 1"))
+	requireInvalidState(t, s.SetOption(OptionStatementSubmittedCancel, adbc.OptionValueEnabled))
+}
+
+func TestSubmitAsyncSession(t *testing.T) {
+	var submitted map[string]string
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&submitted))
+		_ = json.NewEncoder(w).Encode(map[string]any{"statement_id": "01ef-stmt"})
+	}))
+	defer srv.Close()
+
+	connector := newNamespaceConnector("main", "sales")
+	db := sql.OpenDB(connector)
+	defer func() { _ = db.Close() }()
+	conn, err := db.Conn(context.Background())
+	require.NoError(t, err)
+	d := newWarehouseTestDatabase(t, srv, "")
+	s := &statementImpl{conn: &connectionImpl{warehouse: d.warehouse, conn: conn, catalog: "hive_metastore", dbSchema: "default"}}
+	require.NoError(t, s.SetOption(OptionStatementSubmitAsync, adbc.OptionValueEnabled))
+	require.NoError(t, s.SetSqlQuery("INSERT INTO events VALUES (1)"))
+
+	// After a USE statement, statements are submitted in the namespace it
+	// moved the session to
+	s.conn.afterNamespaceChange()
+	_, err = s.ExecuteUpdate(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "main", submitted["catalog"])
+	assert.Equal(t, "sales", submitted["schema"])
+	assert.True(t, s.chunkPartitionsAvailable())
+
+	// Other settings of the session cannot follow them
+	_, err = s.conn.beforeStatement(context.Background(), "SET ansi_mode = false")
+	require.NoError(t, err)
+	_, err = s.ExecuteUpdate(context.Background())
+	requireInvalidState(t, err)
+	assert.ErrorContains(t, err, "after a SET statement changed the session")
+	assert.False(t, s.chunkPartitionsAvailable())
+
+	s.conn = &connectionImpl{warehouse: d.warehouse, conn: conn, sessionSettings: []string{OptionInitSQL}}
+	_, err = s.ExecuteUpdate(context.Background())
+	requireInvalidState(t, err)
+	assert.ErrorContains(t, err, "after databricks.init_sql changed the session")
+}
@@ -0,0 +1,257 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+)
+
+const (
+	// Formats of the results of a statement in the Statement Execution API
+	submittedFormatArrow = "ARROW_STREAM"
+
+	// Interval of the first check of a submitted statement's state while
+	// waiting for it to finish, doubling up to the warehouse poll interval
+	submittedFirstPollInterval = 100 * time.Millisecond
+)
+
+// submittedColumn is a column of the results of a submitted statement
+type submittedColumn struct {
+	Name          string `json:"name"`
+	TypeName      string `json:"type_name"`
+	TypePrecision int32  `json:"type_precision"`
+	TypeScale     int32  `json:"type_scale"`
+}
+
+// submittedManifest describes the results of a submitted statement
+type submittedManifest struct {
+	Format          string `json:"format"`
+	TotalChunkCount int    `json:"total_chunk_count"`
+	TotalRowCount   int64  `json:"total_row_count"`
+	TotalByteCount  int64  `json:"total_byte_count"`
+	Schema          struct {
+		Columns []submittedColumn `json:"columns"`
+	} `json:"schema"`
+}
+
+// externalLink is a presigned URL to download a chunk of results from
+type externalLink struct {
+	ExternalLink string            `json:"external_link"`
+	HTTPHeaders  map[string]string `json:"http_headers"`
+}
+
+// resultChunkLinks returns the links to download a chunk of the results
+// of a submitted statement from
+func (w *warehouseClient) resultChunkLinks(ctx context.Context, id string, chunk int) ([]externalLink, error) {
+	var resp struct {
+		ExternalLinks []externalLink `json:"external_links"`
+	}
+	path := fmt.Sprintf("/api/2.0/sql/statements/%s/result/chunks/%d", url.PathEscape(id), chunk)
+	err := w.do(ctx, http.MethodGet, path, nil, &resp)
+	return resp.ExternalLinks, err
+}
+
+// download opens the content of an external link. Links are presigned,
+// so the request is not authenticated, and as they point to cloud
+// storage, only the headers of the link are sent. The body is read for as
+// long as the reader of the results takes.
+func (w *warehouseClient) download(ctx context.Context, link externalLink) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, link.ExternalLink, nil)
+	if err != nil {
+		return nil, err
+	}
+	for name, value := range link.HTTPHeaders {
+		req.Header.Set(name, value)
+	}
+	resp, err := w.downloadClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("downloading results failed with status %d", resp.StatusCode)
+	}
+	return resp.Body, nil
+}
+
+// fetchSubmittedResults waits for the statement of OptionStatementSubmittedID
+// to finish and returns a reader of its results, downloading their
+// chunks as they are read
+func (s *statementImpl) fetchSubmittedResults(ctx context.Context) (array.RecordReader, error) {
+	api, err := s.warehouseAPI()
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := s.waitSubmitted(ctx, api)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &resultStats{manifest: &manifest}
+	memStats := &memoryStats{}
+	iterator := &submittedResultIterator{ctx: ctx, api: api, id: s.submittedID, manifest: manifest}
+	reader, err := newIPCStreamReader(ctx, iterator, nil, stats, newTrackingAllocator(s.conn.Alloc, memStats, s.conn.memoryBudget))
+	if err != nil {
+		iterator.Close()
+		return nil, err
+	}
+	s.resultStats = stats
+	s.memoryStats = memStats
+	return reader, nil
+}
+
+// waitSubmitted waits for the statement of OptionStatementSubmittedID to
+// succeed and returns the description of its results
+func (s *statementImpl) waitSubmitted(ctx context.Context, api *warehouseClient) (submittedManifest, error) {
+	wait := submittedFirstPollInterval
+	for {
+		status, err := api.statementStatus(ctx, s.submittedID)
+		if err != nil {
+			return submittedManifest{}, s.ErrorHelper.Errorf(adbc.StatusIO, "failed to get state of statement %s: %v", s.submittedID, err)
+		}
+		switch status.State {
+		case "SUCCEEDED":
+			if status.Manifest.Format != submittedFormatArrow {
+				return submittedManifest{}, s.ErrorHelper.Errorf(adbc.StatusNotImplemented,
+					"statement %s returns its results as %s; only %s results can be read", s.submittedID, status.Manifest.Format, submittedFormatArrow)
+			}
+			return status.Manifest, nil
+		case "FAILED":
+			return submittedManifest{}, s.ErrorHelper.Errorf(adbc.StatusInternal, "statement %s failed: %s", s.submittedID, status.Error)
+		case "CANCELED":
+			return submittedManifest{}, s.ErrorHelper.Errorf(adbc.StatusCancelled, "statement %s was canceled", s.submittedID)
+		case "CLOSED":
+			return submittedManifest{}, s.ErrorHelper.Errorf(adbc.StatusInvalidState, "statement %s is closed; its results are no longer available", s.submittedID)
+		}
+		if err := sleepContext(ctx, wait); err != nil {
+			return submittedManifest{}, err
+		}
+		wait = min(wait*2, max(api.pollInterval, submittedFirstPollInterval))
+	}
+}
+
+// submittedResultIterator downloads the chunks of the results of a
+// submitted statement, each an Arrow IPC stream, one at a time
+type submittedResultIterator struct {
+	ctx      context.Context
+	api      *warehouseClient
+	id       string
+	manifest submittedManifest
+
+	chunk   int
+	links   []externalLink
+	current io.ReadCloser
+}
+
+func (it *submittedResultIterator) HasNext() bool {
+	return len(it.links) > 0 || it.chunk < it.manifest.TotalChunkCount
+}
+
+func (it *submittedResultIterator) Next() (io.Reader, error) {
+	it.closeCurrent()
+	for len(it.links) == 0 {
+		if it.chunk >= it.manifest.TotalChunkCount {
+			return nil, io.EOF
+		}
+		links, err := it.api.resultChunkLinks(it.ctx, it.id, it.chunk)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get links of chunk %d: %w", it.chunk, err)
+		}
+		it.chunk++
+		it.links = links
+	}
+
+	body, err := it.api.download(it.ctx, it.links[0])
+	if err != nil {
+		return nil, err
+	}
+	it.links = it.links[1:]
+	it.current = body
+	return body, nil
+}
+
+func (it *submittedResultIterator) Close() {
+	it.closeCurrent()
+}
+
+func (it *submittedResultIterator) closeCurrent() {
+	if it.current != nil {
+		_ = it.current.Close()
+		it.current = nil
+	}
+}
+
+// SchemaBytes returns the schema of results without chunks, from the
+// column types of the manifest
+func (it *submittedResultIterator) SchemaBytes() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := ipc.NewWriter(&buf, ipc.WithSchema(it.manifest.arrowSchema())).Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// arrowSchema returns the schema of the results from their column types
+func (m submittedManifest) arrowSchema() *arrow.Schema {
+	fields := make([]arrow.Field, len(m.Schema.Columns))
+	for i, col := range m.Schema.Columns {
+		fields[i] = arrow.Field{Name: col.Name, Type: submittedColumnType(col), Nullable: true}
+	}
+	return arrow.NewSchema(fields, nil)
+}
+
+// submittedColumnType is the Arrow type of a column of results. Types
+// without an Arrow equivalent are read as strings.
+func submittedColumnType(col submittedColumn) arrow.DataType {
+	switch strings.ToUpper(col.TypeName) {
+	case "BOOLEAN":
+		return arrow.FixedWidthTypes.Boolean
+	case "BYTE":
+		return arrow.PrimitiveTypes.Int8
+	case "SHORT":
+		return arrow.PrimitiveTypes.Int16
+	case "INT":
+		return arrow.PrimitiveTypes.Int32
+	case "LONG":
+		return arrow.PrimitiveTypes.Int64
+	case "FLOAT":
+		return arrow.PrimitiveTypes.Float32
+	case "DOUBLE":
+		return arrow.PrimitiveTypes.Float64
+	case "DECIMAL":
+		return &arrow.Decimal128Type{Precision: col.TypePrecision, Scale: col.TypeScale}
+	case "DATE":
+		return arrow.FixedWidthTypes.Date32
+	case "TIMESTAMP":
+		return &arrow.TimestampType{Unit: arrow.Microsecond, TimeZone: "Etc/UTC"}
+	case "TIMESTAMP_NTZ":
+		return &arrow.TimestampType{Unit: arrow.Microsecond}
+	case "BINARY":
+		return arrow.BinaryTypes.Binary
+	}
+	return arrow.BinaryTypes.String
+}
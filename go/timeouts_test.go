@@ -0,0 +1,141 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestTimeoutTransport(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			select {
+			case <-release:
+			case <-r.Context().Done():
+			}
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := &http.Client{Transport: &requestTimeoutTransport{
+		base:    (&databaseImpl{}).newPooledTransport(nil, nil),
+		timeout: 100 * time.Millisecond,
+	}}
+
+	resp, err := client.Get(server.URL + "/fast")
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, "ok", string(body))
+
+	start := time.Now()
+	_, err = client.Get(server.URL + "/slow")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestTimeoutOptions(t *testing.T) {
+	d := &databaseImpl{}
+	require.NoError(t, d.SetOptions(map[string]string{
+		OptionServerHostname:     "example.cloud.databricks.com",
+		OptionHTTPPath:           "/sql/1.0/warehouses/abc",
+		OptionAccessToken:        "dapi123",
+		OptionConnectTimeout:     "5s",
+		OptionHTTPRequestTimeout: "2m",
+	}))
+
+	val, err := d.GetOption(OptionConnectTimeout)
+	require.NoError(t, err)
+	assert.Equal(t, "5s", val)
+	val, err = d.GetOption(OptionHTTPRequestTimeout)
+	require.NoError(t, err)
+	assert.Equal(t, "2m0s", val)
+
+	_, err = d.resolveConnectionOptions()
+	require.NoError(t, err)
+
+	for _, value := range []string{"soon", "-1s"} {
+		err := d.SetOption(OptionConnectTimeout, value)
+		var adbcErr adbc.Error
+		require.ErrorAs(t, err, &adbcErr, value)
+		assert.Equal(t, adbc.StatusInvalidArgument, adbcErr.Code)
+	}
+}
+
+func TestTransportTuning(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Proto))
+	}))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	defer server.Close()
+
+	proto := func(d *databaseImpl) string {
+		pool := x509.NewCertPool()
+		pool.AddCert(server.Certificate())
+		transport := d.newPooledTransport(&tls.Config{RootCAs: pool}, nil)
+		defer transport.CloseIdleConnections()
+		resp, err := (&http.Client{Transport: transport}).Get(server.URL)
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return string(body)
+	}
+
+	d := &databaseImpl{}
+	assert.False(t, d.tunesTransport())
+	assert.Equal(t, "HTTP/2.0", proto(d))
+
+	require.NoError(t, d.SetOption(OptionHTTP2, "false"))
+	require.NoError(t, d.SetOption(OptionMaxIdleConnsPerHost, "256"))
+	require.NoError(t, d.SetOption(OptionTCPKeepAlive, "0"))
+	assert.True(t, d.tunesTransport())
+	assert.Equal(t, "HTTP/1.1", proto(d))
+	// Downloads from cloud storage would not be tuned
+	d.serverHostname = "example.cloud.databricks.com"
+	d.httpPath = "/sql/1.0/warehouses/abc"
+	d.accessToken = "dapi"
+	assert.False(t, usesCloudFetch(t, d))
+	transport := d.newPooledTransport(nil, nil)
+	assert.Equal(t, 256, transport.MaxIdleConnsPerHost)
+	assert.Equal(t, 256, transport.MaxIdleConns)
+	for key, want := range map[string]string{OptionHTTP2: "false", OptionMaxIdleConnsPerHost: "256", OptionTCPKeepAlive: "0s"} {
+		got, err := d.GetOption(key)
+		require.NoError(t, err)
+		assert.Equal(t, want, got, key)
+	}
+
+	for key, value := range map[string]string{OptionHTTP2: "h2", OptionMaxIdleConnsPerHost: "0", OptionTCPKeepAlive: "-1s"} {
+		var adbcErr adbc.Error
+		require.ErrorAs(t, d.SetOption(key, value), &adbcErr, key)
+		assert.Equal(t, adbc.StatusInvalidArgument, adbcErr.Code)
+	}
+}
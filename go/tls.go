@@ -0,0 +1,212 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// loadCertificateData returns the contents of a certificate or key option,
+// which may be a file path, inline PEM text, or base64-encoded DER.
+func loadCertificateData(value string) ([]byte, error) {
+	trimmed := strings.TrimSpace(value)
+	if strings.HasPrefix(trimmed, "-----BEGIN") {
+		return []byte(trimmed), nil
+	}
+
+	data, err := os.ReadFile(value)
+	if err == nil {
+		return data, nil
+	}
+
+	// Not a readable file: accept inline DER, which must be base64 encoded
+	der, decodeErr := base64.StdEncoding.DecodeString(trimmed)
+	if decodeErr != nil {
+		return nil, fmt.Errorf("%w (value is neither a file, PEM, nor base64-encoded DER)", err)
+	}
+	return der, nil
+}
+
+// verifyCertificateChain returns a TLS connection check that verifies the
+// server certificate chain against roots (the system roots if nil) without
+// matching the host name, for OptionValueSSLModeVerifyCA
+func verifyCertificateChain(roots *x509.CertPool) func(tls.ConnectionState) error {
+	return func(cs tls.ConnectionState) error {
+		if len(cs.PeerCertificates) == 0 {
+			return errors.New("server did not present a certificate")
+		}
+		intermediates := x509.NewCertPool()
+		for _, cert := range cs.PeerCertificates[1:] {
+			intermediates.AddCert(cert)
+		}
+		_, err := cs.PeerCertificates[0].Verify(x509.VerifyOptions{
+			Roots:         roots,
+			Intermediates: intermediates,
+		})
+		return err
+	}
+}
+
+// systemCertPool returns the OS roots extended with the certificates named
+// by OptionSSLRootCert. On Windows and macOS, a pool derived from
+// x509.SystemCertPool verifies with the platform verifier first, so the
+// certificate store or keychain is consulted rather than a copy of it.
+func systemCertPool(extra string) (*x509.CertPool, error) {
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		return nil, err
+	}
+	certs, err := loadRootCertificates(extra)
+	if err != nil {
+		return nil, err
+	}
+	for _, cert := range certs {
+		pool.AddCert(cert)
+	}
+	return pool, nil
+}
+
+// loadRootCertificates loads the CA certificates named by OptionSSLRootCert:
+// inline PEM or base64-encoded DER, or a list of files and directories
+// separated by the OS path list separator. Files in a directory that do
+// not hold certificates are skipped.
+func loadRootCertificates(value string) ([]*x509.Certificate, error) {
+	trimmed := strings.TrimSpace(value)
+	if strings.HasPrefix(trimmed, "-----BEGIN") {
+		return parseCertificates([]byte(trimmed))
+	}
+
+	var certs []*x509.Certificate
+	for _, path := range filepath.SplitList(value) {
+		if path == "" {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil || !info.IsDir() {
+			data, err := loadCertificateData(path)
+			if err != nil {
+				return nil, err
+			}
+			fileCerts, err := parseCertificates(data)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", path, err)
+			}
+			certs = append(certs, fileCerts...)
+			continue
+		}
+
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			// Follows symlinks, as in OpenSSL hashed certificate directories
+			entryPath := filepath.Join(path, entry.Name())
+			if info, err := os.Stat(entryPath); err != nil || !info.Mode().IsRegular() {
+				continue
+			}
+			data, err := os.ReadFile(entryPath)
+			if err != nil {
+				return nil, err
+			}
+			if fileCerts, err := parseCertificates(data); err == nil {
+				certs = append(certs, fileCerts...)
+			}
+		}
+	}
+	if len(certs) == 0 {
+		return nil, errors.New("no certificates found")
+	}
+	return certs, nil
+}
+
+// parseCertificates parses one or more certificates in PEM or DER form
+func parseCertificates(data []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	rest := data
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) > 0 {
+		return certs, nil
+	}
+
+	certs, err := x509.ParseCertificates(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(certs) == 0 {
+		return nil, errors.New("no certificates found")
+	}
+	return certs, nil
+}
+
+// parsePrivateKey parses a PKCS #8, PKCS #1 or SEC 1 private key in PEM
+// or DER form
+func parsePrivateKey(data []byte) (crypto.PrivateKey, error) {
+	der := data
+	rest := data
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type == "PRIVATE KEY" || strings.HasSuffix(block.Type, " PRIVATE KEY") {
+			der = block.Bytes
+			break
+		}
+	}
+
+	if key, err := x509.ParsePKCS8PrivateKey(der); err == nil {
+		switch key := key.(type) {
+		case *rsa.PrivateKey, *ecdsa.PrivateKey, ed25519.PrivateKey:
+			return key, nil
+		default:
+			return nil, fmt.Errorf("unsupported private key type %T", key)
+		}
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(der); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParseECPrivateKey(der); err == nil {
+		return key, nil
+	}
+	return nil, errors.New("failed to parse private key")
+}
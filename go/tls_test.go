@@ -0,0 +1,204 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestCertificate returns a self-signed certificate and its key, both
+// DER encoded
+func newTestCertificate(t *testing.T) (certDER, keyDER []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "adbc-test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	certDER, err = x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err = x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	return certDER, keyDER
+}
+
+func TestSSLRootCertFormats(t *testing.T) {
+	certDER, _ := newTestCertificate(t)
+	certPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER}))
+
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(path, []byte(certPEM), 0o600))
+
+	for name, value := range map[string]string{
+		"file":       path,
+		"inline pem": certPEM,
+		"base64 der": base64.StdEncoding.EncodeToString(certDER),
+	} {
+		t.Run(name, func(t *testing.T) {
+			d := &databaseImpl{}
+			require.NoError(t, d.SetOption(OptionSSLRootCert, value))
+			assert.NotNil(t, d.sslCertPool)
+		})
+	}
+
+	d := &databaseImpl{}
+	err := d.SetOption(OptionSSLRootCert, "not a certificate")
+	var adbcErr adbc.Error
+	require.ErrorAs(t, err, &adbcErr)
+	assert.Equal(t, adbc.StatusInvalidArgument, adbcErr.Code)
+}
+
+func TestSSLClientCertificate(t *testing.T) {
+	certDER, keyDER := newTestCertificate(t)
+
+	d := &databaseImpl{
+		serverHostname: "example.cloud.databricks.com",
+		httpPath:       "/sql/1.0/warehouses/abc",
+		accessToken:    "dapi",
+	}
+	require.NoError(t, d.SetOption(OptionSSLClientCert, string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER}))))
+
+	_, err := d.resolveConnectionOptions()
+	assert.ErrorContains(t, err, "must be set together")
+
+	require.NoError(t, d.SetOption(OptionSSLClientKey, base64.StdEncoding.EncodeToString(keyDER)))
+	assert.NotNil(t, d.sslClientPrivateKey)
+	_, err = d.resolveConnectionOptions()
+	require.NoError(t, err)
+}
+
+func TestSSLDisablesCloudFetch(t *testing.T) {
+	certDER, _ := newTestCertificate(t)
+	newDatabase := func() *databaseImpl {
+		return &databaseImpl{
+			serverHostname: "example.cloud.databricks.com",
+			httpPath:       "/sql/1.0/warehouses/abc",
+			accessToken:    "dapi",
+		}
+	}
+	assert.True(t, usesCloudFetch(t, newDatabase()))
+
+	// Downloads from cloud storage would not trust the given roots
+	d := newDatabase()
+	require.NoError(t, d.SetOption(OptionSSLRootCert, base64.StdEncoding.EncodeToString(certDER)))
+	assert.False(t, usesCloudFetch(t, d))
+
+	d = newDatabase()
+	require.NoError(t, d.SetOption(OptionSSLMode, OptionValueSSLModeInsecure))
+	assert.False(t, usesCloudFetch(t, d))
+}
+
+func TestSSLRootCertBundles(t *testing.T) {
+	encode := func(der []byte) []byte {
+		return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	}
+	first, _ := newTestCertificate(t)
+	second, _ := newTestCertificate(t)
+	third, _ := newTestCertificate(t)
+
+	dir := t.TempDir()
+	bundle := filepath.Join(dir, "bundle.pem")
+	require.NoError(t, os.WriteFile(bundle, append(encode(first), encode(second)...), 0o600))
+
+	certDir := filepath.Join(dir, "certs")
+	require.NoError(t, os.Mkdir(certDir, 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(certDir, "ca.crt"), encode(third), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(certDir, "README"), []byte("not a certificate"), 0o600))
+
+	certs, err := loadRootCertificates(bundle + string(filepath.ListSeparator) + certDir)
+	require.NoError(t, err)
+	assert.Len(t, certs, 3)
+
+	d := &databaseImpl{}
+	require.NoError(t, d.SetOption(OptionSSLRootCert, certDir))
+	assert.NotNil(t, d.sslCertPool)
+
+	_, err = loadRootCertificates(t.TempDir())
+	assert.ErrorContains(t, err, "no certificates found")
+}
+
+func TestSSLModeVerifyCA(t *testing.T) {
+	srv := httptest.NewTLSServer(nil)
+	defer srv.Close()
+	roots := x509.NewCertPool()
+	roots.AddCert(srv.Certificate())
+
+	dial := func(config *tls.Config) error {
+		conn, err := tls.Dial("tcp", srv.Listener.Addr().String(), config)
+		if err == nil {
+			_ = conn.Close()
+		}
+		return err
+	}
+
+	// The test certificate is not issued for this name, so only the chain
+	// can be verified
+	assert.Error(t, dial(&tls.Config{RootCAs: roots, ServerName: "workspace.invalid"}))
+	assert.NoError(t, dial(&tls.Config{
+		RootCAs:            roots,
+		ServerName:         "workspace.invalid",
+		InsecureSkipVerify: true,
+		VerifyConnection:   verifyCertificateChain(roots),
+	}))
+	assert.Error(t, dial(&tls.Config{
+		ServerName:         "workspace.invalid",
+		InsecureSkipVerify: true,
+		VerifyConnection:   verifyCertificateChain(x509.NewCertPool()),
+	}))
+
+	d := &databaseImpl{}
+	require.NoError(t, d.SetOption(OptionSSLMode, "VERIFY-CA"))
+	assert.Equal(t, OptionValueSSLModeVerifyCA, d.sslMode)
+	var adbcErr adbc.Error
+	require.ErrorAs(t, d.SetOption(OptionSSLMode, "prefer"), &adbcErr)
+	assert.Equal(t, adbc.StatusInvalidArgument, adbcErr.Code)
+}
+
+func TestSSLModeSystem(t *testing.T) {
+	srv := httptest.NewTLSServer(nil)
+	defer srv.Close()
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+
+	// The extra certificate is trusted alongside the OS roots
+	pool, err := systemCertPool(string(certPEM))
+	require.NoError(t, err)
+	conn, err := tls.Dial("tcp", srv.Listener.Addr().String(), &tls.Config{RootCAs: pool, ServerName: "example.com"})
+	require.NoError(t, err)
+	_ = conn.Close()
+
+	d := &databaseImpl{}
+	require.NoError(t, d.SetOption(OptionSSLMode, "SYSTEM"))
+	assert.Equal(t, OptionValueSSLModeSystem, d.sslMode)
+}
@@ -0,0 +1,459 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-go/v18/arrow"
+	dbsqlerr "github.com/databricks/databricks-sql-go/errors"
+)
+
+// transactionMode is how a connection with autocommit disabled makes its
+// changes transactional
+type transactionMode int
+
+const (
+	// Autocommit is enabled
+	transactionNone transactionMode = iota
+	// Statements run in a transaction of the session
+	transactionNative
+	// The warehouse does not support transactions, so updates are
+	// buffered and bulk ingests written to staging tables, and both are
+	// applied to their Delta tables on commit, restoring the tables to
+	// their earlier versions if any fails and no other session wrote them
+	// since
+	transactionStaged
+)
+
+// Prefix of the names of staging tables
+const stagingTablePrefix = "_adbc_staging_"
+
+// stagedChange is a change to a table awaiting commit: an update to run,
+// or a bulk ingest written to a staging table
+type stagedChange struct {
+	tableName string
+	query     string
+	// The staging table and its columns, for an ingest
+	stagingName string
+	columns     string
+}
+
+// apply makes the change to its table
+func (change stagedChange) apply(ctx context.Context, c *connectionImpl) error {
+	query := change.query
+	if change.stagingName != "" {
+		query = fmt.Sprintf("INSERT INTO %s (%s) SELECT %s FROM %s", change.tableName, change.columns, change.columns, change.stagingName)
+	}
+	_, err := c.conn.ExecContext(ctx, query)
+	return err
+}
+
+// Identifier, optionally quoted with backticks
+const identifierPattern = "(?:`(?:[^`]|``)+`|[A-Za-z_][A-Za-z0-9_]*)"
+
+// identifierRe matches each part of a qualified name
+var identifierRe = regexp.MustCompile(identifierPattern)
+
+// updateTargetRe matches the statements that can be buffered, capturing
+// the table they change
+var updateTargetRe = regexp.MustCompile(`(?is)^\s*(?:INSERT\s+(?:INTO|OVERWRITE)(?:\s+TABLE)?|UPDATE|DELETE\s+FROM|MERGE\s+INTO)\s+((?:` +
+	identifierPattern + `\s*\.\s*){0,2}` + identifierPattern + `)`)
+
+// SQLSTATEs and error classes with which warehouses without transaction
+// support reject BEGIN TRANSACTION, as a syntax error or an unsupported
+// feature
+var (
+	transactionsUnsupportedStates  = []string{"42601", "0A000"}
+	transactionsUnsupportedClasses = []string{"[PARSE_SYNTAX_ERROR]", "[UNSUPPORTED_FEATURE", "[NOT_SUPPORTED"}
+)
+
+// transactionsUnsupported returns whether err is the warehouse rejecting
+// BEGIN TRANSACTION for lack of transaction support, rather than failing
+// to run it
+func transactionsUnsupported(err error) bool {
+	var dbExecutionErr dbsqlerr.DBExecutionError
+	if !errors.As(err, &dbExecutionErr) {
+		return false
+	}
+	if slices.Contains(transactionsUnsupportedStates, dbExecutionErr.SqlState()) {
+		return true
+	}
+	return slices.ContainsFunc(transactionsUnsupportedClasses, func(class string) bool {
+		return strings.Contains(err.Error(), class)
+	})
+}
+
+// SetAutocommit implements driverbase.AutocommitSetter. Disabling
+// autocommit begins a transaction of the session, or stages changes if
+// the warehouse does not support transactions. Enabling it commits the
+// transaction in progress.
+func (c *connectionImpl) SetAutocommit(autocommit bool) error {
+	if err := c.acquireExclusive(); err != nil {
+		return err
+	}
+	defer c.releaseExclusive()
+	ctx := context.Background()
+
+	if autocommit {
+		if c.txMode == transactionNone {
+			return nil
+		}
+		if err := c.commit(ctx, false); err != nil {
+			return err
+		}
+		c.txMode = transactionNone
+		return nil
+	}
+
+	if c.txMode != transactionNone {
+		return nil
+	}
+	if c.transactions != nil && !*c.transactions {
+		// The session rejected BEGIN TRANSACTION before
+		c.txMode = transactionStaged
+		return nil
+	}
+	if _, err := c.conn.ExecContext(ctx, "BEGIN TRANSACTION"); err != nil {
+		if !transactionsUnsupported(err) {
+			return c.ErrorHelper.Errorf(adbc.StatusIO, "failed to begin transaction: %v", err)
+		}
+		c.transactions = new(bool)
+		c.txMode = transactionStaged
+		return nil
+	}
+	supported := true
+	c.transactions = &supported
+	c.txMode = transactionNative
+	return nil
+}
+
+func (c *connectionImpl) Commit(ctx context.Context) error {
+	if err := c.acquireExclusive(); err != nil {
+		return err
+	}
+	defer c.releaseExclusive()
+	return c.commit(ctx, true)
+}
+
+func (c *connectionImpl) Rollback(ctx context.Context) error {
+	if err := c.acquireExclusive(); err != nil {
+		return err
+	}
+	defer c.releaseExclusive()
+
+	switch c.txMode {
+	case transactionNative:
+		if _, err := c.conn.ExecContext(ctx, "ROLLBACK"); err != nil {
+			return c.ErrorHelper.Errorf(adbc.StatusIO, "failed to roll back transaction: %v", err)
+		}
+		return c.beginNext(ctx)
+	case transactionStaged:
+		return c.dropStaged(ctx)
+	}
+	return c.ErrorHelper.Errorf(adbc.StatusInvalidState, "no transaction in progress")
+}
+
+// commit commits the transaction in progress, beginning the next one if
+// begin is set. The caller must hold the connection exclusively.
+func (c *connectionImpl) commit(ctx context.Context, begin bool) error {
+	switch c.txMode {
+	case transactionNative:
+		if _, err := c.conn.ExecContext(ctx, "COMMIT"); err != nil {
+			return c.ErrorHelper.Errorf(adbc.StatusIO, "failed to commit transaction: %v", err)
+		}
+		if begin {
+			return c.beginNext(ctx)
+		}
+		return nil
+	case transactionStaged:
+		return c.applyStaged(ctx)
+	}
+	return c.ErrorHelper.Errorf(adbc.StatusInvalidState, "no transaction in progress")
+}
+
+// beginNext begins the transaction following one that ended
+func (c *connectionImpl) beginNext(ctx context.Context) error {
+	if _, err := c.conn.ExecContext(ctx, "BEGIN TRANSACTION"); err != nil {
+		return c.ErrorHelper.Errorf(adbc.StatusIO, "failed to begin transaction: %v", err)
+	}
+	return nil
+}
+
+// applyStaged applies the staged changes in the order they were made.
+// The version of each table changed is recorded first, along with those
+// each change writes, and if a change fails, the tables already changed
+// are restored to their earlier versions, leaving the changes staged. A
+// table others have written since is not restored, as that would discard
+// their changes: the changes to it stay committed and are unstaged.
+func (c *connectionImpl) applyStaged(ctx context.Context) error {
+	c.stagedMu.Lock()
+	defer c.stagedMu.Unlock()
+
+	versions := map[string]int64{}
+	for _, change := range c.staged {
+		if _, ok := versions[change.tableName]; ok {
+			continue
+		}
+		version, err := c.tableVersion(ctx, change.tableName)
+		if err != nil {
+			return err
+		}
+		versions[change.tableName] = version
+	}
+
+	var changed []string
+	written := map[string][]int64{}
+	for i, change := range c.staged {
+		if !slices.Contains(changed, change.tableName) {
+			changed = append(changed, change.tableName)
+		}
+		if err := change.apply(ctx, c); err != nil {
+			err = c.ErrorHelper.Errorf(adbc.StatusIO, "failed to commit changes to %s: %v", change.tableName, err)
+			return errors.Join(err, c.restoreStaged(ctx, i, changed, versions, written))
+		}
+		version, err := c.tableVersion(ctx, change.tableName)
+		if err != nil {
+			return errors.Join(err, c.restoreStaged(ctx, i+1, changed, versions, written))
+		}
+		written[change.tableName] = append(written[change.tableName], version)
+	}
+
+	var err error
+	for _, change := range c.staged {
+		if change.stagingName == "" {
+			continue
+		}
+		if _, dropErr := c.conn.ExecContext(ctx, "DROP TABLE IF EXISTS "+change.stagingName); dropErr != nil {
+			err = errors.Join(err, c.ErrorHelper.Errorf(adbc.StatusIO, "committed changes, but failed to drop %s: %v", change.stagingName, dropErr))
+		}
+	}
+	c.staged = nil
+	return err
+}
+
+// restoreStaged undoes the first applied staged changes after a failed
+// commit, restoring each table changed to its version before the commit
+// unless its history shows versions not written by the commit. The
+// changes to tables not restored are unstaged, and reported.
+func (c *connectionImpl) restoreStaged(ctx context.Context, applied int, changed []string, versions map[string]int64, written map[string][]int64) error {
+	var err error
+	var kept []string
+	for _, table := range changed {
+		history, historyErr := c.tableHistory(ctx, table, versions[table])
+		if historyErr != nil {
+			err = errors.Join(err, c.ErrorHelper.Errorf(adbc.StatusIO,
+				"changes to %s were partially committed, as it could not be checked before restoring version %d: %v", table, versions[table], historyErr))
+			kept = append(kept, table)
+			continue
+		}
+		if others := slices.DeleteFunc(history, func(v int64) bool { return slices.Contains(written[table], v) }); len(others) > 0 {
+			slices.Reverse(others)
+			err = errors.Join(err, c.ErrorHelper.Errorf(adbc.StatusIO,
+				"changes to %s were partially committed, as versions %v of it were written by others since version %d", table, others, versions[table]))
+			kept = append(kept, table)
+			continue
+		}
+		query := fmt.Sprintf("RESTORE TABLE %s TO VERSION AS OF %d", table, versions[table])
+		if _, restoreErr := c.conn.ExecContext(ctx, query); restoreErr != nil {
+			err = errors.Join(err, c.ErrorHelper.Errorf(adbc.StatusIO, "failed to restore %s to version %d: %v", table, versions[table], restoreErr))
+		}
+	}
+
+	if len(kept) == 0 {
+		return err
+	}
+	var remaining []stagedChange
+	for _, change := range c.staged[:applied] {
+		if !slices.Contains(kept, change.tableName) {
+			remaining = append(remaining, change)
+			continue
+		}
+		if change.stagingName == "" {
+			continue
+		}
+		if _, dropErr := c.conn.ExecContext(ctx, "DROP TABLE IF EXISTS "+change.stagingName); dropErr != nil {
+			err = errors.Join(err, c.ErrorHelper.Errorf(adbc.StatusIO, "failed to drop %s: %v", change.stagingName, dropErr))
+		}
+	}
+	c.staged = append(remaining, c.staged[applied:]...)
+	return err
+}
+
+// tableVersion returns the current version of a Delta table, failing for
+// tables of other formats, whose changes cannot be undone
+func (c *connectionImpl) tableVersion(ctx context.Context, tableName string) (int64, error) {
+	versions, err := c.readHistory(ctx, "DESCRIBE HISTORY "+tableName+" LIMIT 1", tableName, -1)
+	if err != nil {
+		return 0, err
+	}
+	if len(versions) == 0 {
+		return 0, c.ErrorHelper.Errorf(adbc.StatusInternal, "failed to read history of %s: no version", tableName)
+	}
+	return versions[0], nil
+}
+
+// tableHistory returns the versions of a Delta table written after
+// version since, newest first
+func (c *connectionImpl) tableHistory(ctx context.Context, tableName string, since int64) ([]int64, error) {
+	return c.readHistory(ctx, "DESCRIBE HISTORY "+tableName, tableName, since)
+}
+
+// readHistory runs query, a DESCRIBE HISTORY of tableName, returning the
+// versions listed until one no later than since
+func (c *connectionImpl) readHistory(ctx context.Context, query, tableName string, since int64) (versions []int64, err error) {
+	rows, err := c.conn.QueryContext(ctx, query)
+	if err != nil {
+		var dbExecutionErr dbsqlerr.DBExecutionError
+		if errors.As(err, &dbExecutionErr) {
+			return nil, c.ErrorHelper.Errorf(adbc.StatusNotImplemented,
+				"changes to %s cannot be made transactional, as only those to Delta tables can: %v", tableName, err)
+		}
+		return nil, c.ErrorHelper.Errorf(adbc.StatusIO, "failed to read history of %s: %v", tableName, err)
+	}
+	defer func() {
+		err = errors.Join(err, rows.Close())
+	}()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, c.ErrorHelper.Errorf(adbc.StatusInternal, "failed to read history of %s: %v", tableName, err)
+	}
+	versionIdx := slices.Index(columns, "version")
+	if versionIdx < 0 {
+		return nil, c.ErrorHelper.Errorf(adbc.StatusInternal, "failed to read history of %s: no version", tableName)
+	}
+	values := make([]any, len(columns))
+	for i := range values {
+		values[i] = new(any)
+	}
+	for rows.Next() {
+		var version int64
+		values[versionIdx] = &version
+		if err := rows.Scan(values...); err != nil {
+			return nil, c.ErrorHelper.Errorf(adbc.StatusInternal, "failed to read history of %s: %v", tableName, err)
+		}
+		if version <= since {
+			break
+		}
+		versions = append(versions, version)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, c.ErrorHelper.Errorf(adbc.StatusIO, "failed to read history of %s: %v", tableName, err)
+	}
+	return versions, nil
+}
+
+// dropStaged discards the staged changes
+func (c *connectionImpl) dropStaged(ctx context.Context) error {
+	c.stagedMu.Lock()
+	defer c.stagedMu.Unlock()
+	for len(c.staged) > 0 {
+		change := c.staged[0]
+		if change.stagingName != "" {
+			if _, err := c.conn.ExecContext(ctx, "DROP TABLE IF EXISTS "+change.stagingName); err != nil {
+				return c.ErrorHelper.Errorf(adbc.StatusIO, "failed to drop %s: %v", change.stagingName, err)
+			}
+		}
+		c.staged = c.staged[1:]
+	}
+	return nil
+}
+
+// bufferUpdate stages the query of the statement to run on commit, once
+// checked to change a Delta table. The table is qualified with the
+// current catalog and schema, so that the query changes it even if they
+// change before commit.
+func (s *statementImpl) bufferUpdate(ctx context.Context) (int64, error) {
+	if s.query == "" {
+		return -1, s.ErrorHelper.Errorf(adbc.StatusInvalidState, "no query set")
+	}
+	match := updateTargetRe.FindStringSubmatchIndex(s.query)
+	if match == nil {
+		return -1, s.ErrorHelper.Errorf(adbc.StatusNotImplemented,
+			"only INSERT, UPDATE, DELETE and MERGE statements can run while autocommit is disabled on a warehouse without transaction support")
+	}
+	tableName := s.query[match[2]:match[3]]
+	if parts := len(identifierRe.FindAllString(tableName, -1)); parts < 3 {
+		catalog, dbSchema, err := s.conn.resolveNamespace(ctx)
+		if err != nil {
+			return -1, err
+		}
+		if parts == 1 {
+			tableName = quoteIdentifier(dbSchema) + "." + tableName
+		}
+		tableName = quoteIdentifier(catalog) + "." + tableName
+	}
+	if _, err := s.conn.tableVersion(ctx, tableName); err != nil {
+		return -1, err
+	}
+
+	query := s.query[:match[2]] + tableName + s.query[match[3]:]
+	s.conn.stagedMu.Lock()
+	defer s.conn.stagedMu.Unlock()
+	s.conn.staged = append(s.conn.staged, stagedChange{tableName: tableName, query: query})
+	return -1, nil
+}
+
+// stageIngest redirects the rows of an ingest into t to a new staging
+// table, in the same schema, when the transaction in progress stages
+// them. Temporary tables are written directly.
+func (s *statementImpl) stageIngest(ctx context.Context, t *ingestTarget, tableSchema *arrow.Schema) error {
+	if s.conn.txMode != transactionStaged || t.opts.Temporary {
+		return nil
+	}
+	if t.opts.Mode == adbc.OptionValueIngestModeReplace {
+		return s.ErrorHelper.Errorf(adbc.StatusNotImplemented,
+			"ingest mode %s requires a warehouse supporting transactions when autocommit is disabled", t.opts.Mode)
+	}
+
+	suffix := make([]byte, 8)
+	_, _ = rand.Read(suffix)
+	stagingOpts := *t.opts
+	stagingOpts.TableName = stagingTablePrefix + hex.EncodeToString(suffix)
+	stagingName, err := ingestTableName(&stagingOpts, s.conn.GetCurrentDbSchema)
+	if err != nil {
+		return err
+	}
+	if err := s.createTable(ctx, stagingName, tableSchema, false); err != nil {
+		return err
+	}
+
+	columns := make([]string, tableSchema.NumFields())
+	for i, field := range tableSchema.Fields() {
+		columns[i] = quoteIdentifier(field.Name)
+	}
+	s.conn.stagedMu.Lock()
+	s.conn.staged = append(s.conn.staged, stagedChange{
+		tableName:   t.tableName,
+		stagingName: stagingName,
+		columns:     strings.Join(columns, ", "),
+	})
+	s.conn.stagedMu.Unlock()
+
+	// Staging tables declare no defaults
+	t.tableName = stagingName
+	t.hasDefault = nil
+	t.insertSQL, err = buildInsertSQL(stagingName, tableSchema, nil)
+	return err
+}
@@ -0,0 +1,287 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/adbc-drivers/driverbase-go/driverbase"
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	dbsqlerr "github.com/databricks/databricks-sql-go/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// executionError is an error the warehouse reports for a statement it ran
+type executionError struct {
+	dbsqlerr.DBExecutionError
+	msg      string
+	sqlState string
+}
+
+func (e executionError) Error() string    { return e.msg }
+func (e executionError) SqlState() string { return e.sqlState }
+
+// newTransactionConnection returns a connection on drv, in main.default,
+// which rejects BEGIN TRANSACTION with beginErr if set
+func newTransactionConnection(t *testing.T, drv *fakeDriver, beginErr error) *connectionImpl {
+	drv.execErr = func([]any) error {
+		if drv.execs[len(drv.execs)-1] == "BEGIN TRANSACTION" {
+			return beginErr
+		}
+		return nil
+	}
+	db := sql.OpenDB(drv)
+	t.Cleanup(func() { _ = db.Close() })
+	conn, err := db.Conn(context.Background())
+	require.NoError(t, err)
+	return &connectionImpl{conn: conn, catalog: "main", dbSchema: "default"}
+}
+
+func TestNativeTransaction(t *testing.T) {
+	drv := &fakeDriver{}
+	c := newTransactionConnection(t, drv, nil)
+	ctx := context.Background()
+
+	err := c.Commit(ctx)
+	requireInvalidState(t, err)
+
+	require.NoError(t, c.SetAutocommit(false))
+	require.NoError(t, c.SetAutocommit(false))
+	assert.Equal(t, transactionNative, c.txMode)
+	require.NoError(t, c.Commit(ctx))
+	require.NoError(t, c.Rollback(ctx))
+	// Enabling autocommit commits without beginning another transaction
+	require.NoError(t, c.SetAutocommit(true))
+	assert.Equal(t, transactionNone, c.txMode)
+	assert.Equal(t, []string{
+		"BEGIN TRANSACTION",
+		"COMMIT", "BEGIN TRANSACTION",
+		"ROLLBACK", "BEGIN TRANSACTION",
+		"COMMIT",
+	}, drv.execs)
+}
+
+func TestStagedTransaction(t *testing.T) {
+	schema := arrow.NewSchema([]arrow.Field{{Name: "id", Type: arrow.PrimitiveTypes.Int64}}, nil)
+	bldr := array.NewRecordBuilder(memory.DefaultAllocator, schema)
+	defer bldr.Release()
+	bldr.Field(0).(*array.Int64Builder).AppendValues([]int64{10, 11}, nil)
+	rec := bldr.NewRecordBatch()
+	defer rec.Release()
+
+	// Tables are at version 7
+	drv := &fakeDriver{keys: []string{"7"}, keyColumn: "version"}
+	c := newTransactionConnection(t, drv, executionError{msg: "[PARSE_SYNTAX_ERROR] Syntax error at or near 'TRANSACTION'"})
+	ctx := context.Background()
+	require.NoError(t, c.SetAutocommit(false))
+	assert.Equal(t, transactionStaged, c.txMode)
+
+	ingest := func(mode string) error {
+		stream, err := array.NewRecordReader(schema, []arrow.RecordBatch{rec})
+		require.NoError(t, err)
+		s := &statementImpl{
+			conn:              c,
+			bulkIngestOptions: driverbase.NewBulkIngestOptions(),
+			boundStream:       stream,
+		}
+		s.bulkIngestOptions.TableName = "events"
+		s.bulkIngestOptions.SchemaName = "sales"
+		s.bulkIngestOptions.Mode = mode
+		_, err = s.executeIngest(ctx)
+		return err
+	}
+
+	// Rows are written to a staging table in the target's schema, and
+	// copied to the target on commit
+	drv.execs = nil
+	require.NoError(t, ingest(adbc.OptionValueIngestModeAppend))
+	require.Len(t, drv.execs, 3)
+	stagingName := regexp.MustCompile("^CREATE TABLE (`sales`.`_adbc_staging_[0-9a-f]{16}`) \\(`id` BIGINT NOT NULL\\)$").FindStringSubmatch(drv.execs[0])
+	require.NotNil(t, stagingName, drv.execs[0])
+	assert.Equal(t, "INSERT INTO "+stagingName[1]+" (`id`) VALUES (?)", drv.execs[1])
+
+	drv.execs = nil
+	require.NoError(t, c.Commit(ctx))
+	assert.Equal(t, []string{
+		"DESCRIBE HISTORY `sales`.`events` LIMIT 1",
+		"INSERT INTO `sales`.`events` (`id`) SELECT `id` FROM " + stagingName[1],
+		"DESCRIBE HISTORY `sales`.`events` LIMIT 1",
+		"DROP TABLE IF EXISTS " + stagingName[1],
+	}, drv.execs)
+	assert.Empty(t, c.staged)
+
+	// Rolling back drops the staging table
+	drv.execs = nil
+	require.NoError(t, ingest(adbc.OptionValueIngestModeAppend))
+	stagingName = regexp.MustCompile("`_adbc_staging_[0-9a-f]{16}`").FindStringSubmatch(drv.execs[0])
+	require.NotNil(t, stagingName)
+	drv.execs = nil
+	require.NoError(t, c.Rollback(ctx))
+	assert.Equal(t, []string{"DROP TABLE IF EXISTS `sales`." + stagingName[0]}, drv.execs)
+
+	// Replacing a table cannot be staged
+	err := ingest(adbc.OptionValueIngestModeReplace)
+	var adbcErr adbc.Error
+	require.ErrorAs(t, err, &adbcErr)
+	assert.Equal(t, adbc.StatusNotImplemented, adbcErr.Code)
+}
+
+func TestBeginTransactionFails(t *testing.T) {
+	drv := &fakeDriver{}
+	c := newTransactionConnection(t, drv, errors.New("connection reset by peer"))
+	err := c.SetAutocommit(false)
+	var adbcErr adbc.Error
+	require.ErrorAs(t, err, &adbcErr)
+	assert.Equal(t, adbc.StatusIO, adbcErr.Code)
+	assert.Equal(t, transactionNone, c.txMode)
+}
+
+func TestBeginTransactionRejected(t *testing.T) {
+	// Only warehouses without transaction support stage changes
+	drv := &fakeDriver{}
+	c := newTransactionConnection(t, drv, executionError{msg: "[UNSUPPORTED_FEATURE.TRANSACTIONS] transactions are not supported", sqlState: "0A000"})
+	require.NoError(t, c.SetAutocommit(false))
+	assert.Equal(t, transactionStaged, c.txMode)
+
+	drv = &fakeDriver{}
+	c = newTransactionConnection(t, drv, executionError{msg: "[INSUFFICIENT_PERMISSIONS] User does not have USE CATALOG", sqlState: "42501"})
+	err := c.SetAutocommit(false)
+	var adbcErr adbc.Error
+	require.ErrorAs(t, err, &adbcErr)
+	assert.Equal(t, adbc.StatusIO, adbcErr.Code)
+	assert.Equal(t, transactionNone, c.txMode)
+	assert.Nil(t, c.transactions)
+}
+
+func TestBufferedUpdates(t *testing.T) {
+	drv := &fakeDriver{keys: []string{"7"}, keyColumn: "version"}
+	c := newTransactionConnection(t, drv, executionError{msg: "[PARSE_SYNTAX_ERROR] Syntax error at or near 'TRANSACTION'"})
+	ctx := context.Background()
+	require.NoError(t, c.SetAutocommit(false))
+
+	update := func(query string) error {
+		stmt, err := c.NewStatement()
+		require.NoError(t, err)
+		require.NoError(t, stmt.SetSqlQuery(query))
+		rows, err := stmt.ExecuteUpdate(ctx)
+		if err == nil {
+			assert.Equal(t, int64(-1), rows)
+		}
+		return err
+	}
+
+	// Updates are only checked to change a Delta table until commit, and
+	// the tables they change are qualified
+	drv.execs = nil
+	require.NoError(t, update("UPDATE sales.events SET amount = 0 WHERE id = 1"))
+	require.NoError(t, update("insert into `order``s` values (1)"))
+	require.NoError(t, update("DELETE FROM sales.events WHERE id = 2"))
+	assert.Equal(t, []string{
+		"DESCRIBE HISTORY `main`.sales.events LIMIT 1",
+		"DESCRIBE HISTORY `main`.`default`.`order``s` LIMIT 1",
+		"DESCRIBE HISTORY `main`.sales.events LIMIT 1",
+	}, drv.execs)
+
+	var adbcErr adbc.Error
+	err := update("CREATE TABLE sales.refunds (id INT)")
+	require.ErrorAs(t, err, &adbcErr)
+	assert.Equal(t, adbc.StatusNotImplemented, adbcErr.Code)
+
+	// A failed change restores the tables changed so far, keeping the
+	// changes staged
+	drv.execErr = func([]any) error {
+		if strings.HasPrefix(drv.execs[len(drv.execs)-1], "DELETE") {
+			return errors.New("[DELTA_CONCURRENT_DELETE_READ] concurrent delete")
+		}
+		return nil
+	}
+	drv.execs = nil
+	err = c.Commit(ctx)
+	assert.ErrorContains(t, err, "failed to commit changes to `main`.sales.events: [DELTA_CONCURRENT_DELETE_READ] concurrent delete")
+	assert.Equal(t, []string{
+		"DESCRIBE HISTORY `main`.sales.events LIMIT 1",
+		"DESCRIBE HISTORY `main`.`default`.`order``s` LIMIT 1",
+		"UPDATE `main`.sales.events SET amount = 0 WHERE id = 1",
+		"DESCRIBE HISTORY `main`.sales.events LIMIT 1",
+		"insert into `main`.`default`.`order``s` values (1)",
+		"DESCRIBE HISTORY `main`.`default`.`order``s` LIMIT 1",
+		"DELETE FROM `main`.sales.events WHERE id = 2",
+		"DESCRIBE HISTORY `main`.sales.events",
+		"RESTORE TABLE `main`.sales.events TO VERSION AS OF 7",
+		"DESCRIBE HISTORY `main`.`default`.`order``s`",
+		"RESTORE TABLE `main`.`default`.`order``s` TO VERSION AS OF 7",
+	}, drv.execs)
+	assert.Len(t, c.staged, 3)
+
+	// A table others wrote since is not restored, and the changes applied
+	// to it are unstaged
+	history := map[string][]string{
+		"`main`.sales.events":         {"7"},
+		"`main`.`default`.`order``s`": {"7"},
+	}
+	drv.query = func(query string, _ []driver.NamedValue) (driver.Rows, error) {
+		table := strings.TrimSuffix(strings.TrimPrefix(query, "DESCRIBE HISTORY "), " LIMIT 1")
+		if strings.HasSuffix(query, " LIMIT 1") {
+			return keyRows(drv.keyColumn, history[table][:1]...), nil
+		}
+		return keyRows(drv.keyColumn, history[table]...), nil
+	}
+	drv.execErr = func([]any) error {
+		switch query := drv.execs[len(drv.execs)-1]; {
+		case strings.HasPrefix(query, "UPDATE"):
+			// Another session writes version 8 first
+			history["`main`.sales.events"] = []string{"9", "8", "7"}
+		case strings.HasPrefix(query, "insert"):
+			history["`main`.`default`.`order``s`"] = []string{"8", "7"}
+		case strings.HasPrefix(query, "DELETE"):
+			return errors.New("[DELTA_CONCURRENT_DELETE_READ] concurrent delete")
+		}
+		return nil
+	}
+	drv.execs = nil
+	err = c.Commit(ctx)
+	assert.ErrorContains(t, err, "failed to commit changes to `main`.sales.events: [DELTA_CONCURRENT_DELETE_READ] concurrent delete")
+	assert.ErrorContains(t, err, "changes to `main`.sales.events were partially committed, as versions [8] of it were written by others since version 7")
+	assert.Equal(t, []string{
+		"DESCRIBE HISTORY `main`.sales.events LIMIT 1",
+		"DESCRIBE HISTORY `main`.`default`.`order``s` LIMIT 1",
+		"UPDATE `main`.sales.events SET amount = 0 WHERE id = 1",
+		"DESCRIBE HISTORY `main`.sales.events LIMIT 1",
+		"insert into `main`.`default`.`order``s` values (1)",
+		"DESCRIBE HISTORY `main`.`default`.`order``s` LIMIT 1",
+		"DELETE FROM `main`.sales.events WHERE id = 2",
+		"DESCRIBE HISTORY `main`.sales.events",
+		"DESCRIBE HISTORY `main`.`default`.`order``s`",
+		"RESTORE TABLE `main`.`default`.`order``s` TO VERSION AS OF 7",
+	}, drv.execs)
+	require.Len(t, c.staged, 2)
+	assert.Equal(t, "insert into `main`.`default`.`order``s` values (1)", c.staged[0].query)
+	assert.Equal(t, "DELETE FROM `main`.sales.events WHERE id = 2", c.staged[1].query)
+
+	drv.execs = nil
+	require.NoError(t, c.Rollback(ctx))
+	assert.Empty(t, drv.execs)
+	assert.Empty(t, c.staged)
+}
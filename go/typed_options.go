@@ -0,0 +1,173 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/adbc-drivers/driverbase-go/driverbase"
+	"github.com/apache/arrow-adbc/go/adbc"
+)
+
+// Options whose string values are Go durations. Set or read as integers
+// or doubles, their values are in seconds.
+var durationOptions = map[string]bool{
+	OptionConnectTimeout:           true,
+	OptionHTTPRequestTimeout:       true,
+	OptionTCPKeepAlive:             true,
+	OptionQueryTimeout:             true,
+	OptionQueryPollInterval:        true,
+	OptionKeepAliveInterval:        true,
+	OptionSlowQueryThreshold:       true,
+	OptionRetryBudgetMaxTime:       true,
+	OptionWarehouseWaitForStart:    true,
+	OptionMetadataCacheTTL:         true,
+	OptionPoolIdleTimeout:          true,
+	OptionPoolMaxLifetime:          true,
+	OptionOAuthRefreshBeforeExpiry: true,
+}
+
+// formatIntOption renders an integer option value as SetOption takes it
+func formatIntOption(key string, val int64) string {
+	if durationOptions[canonicalOption(key)] {
+		return (time.Duration(val) * time.Second).String()
+	}
+	return strconv.FormatInt(val, 10)
+}
+
+// formatDoubleOption renders a double option value as SetOption takes it
+func formatDoubleOption(key string, val float64) string {
+	if durationOptions[canonicalOption(key)] {
+		return time.Duration(val * float64(time.Second)).String()
+	}
+	return strconv.FormatFloat(val, 'f', -1, 64)
+}
+
+// parseIntOption reads the value GetOption returned for key as an integer.
+// Unset options, whose value is empty, are 0.
+func parseIntOption(eh *driverbase.ErrorHelper, key, value string) (int64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	if durationOptions[canonicalOption(key)] {
+		d, err := time.ParseDuration(value)
+		if err != nil || d%time.Second != 0 {
+			return 0, eh.Errorf(adbc.StatusInvalidArgument, "option %s is not a whole number of seconds: %s", key, value)
+		}
+		return int64(d / time.Second), nil
+	}
+	val, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, eh.Errorf(adbc.StatusInvalidArgument, "option %s is not an integer: %s", key, value)
+	}
+	return val, nil
+}
+
+// parseDoubleOption reads the value GetOption returned for key as a
+// double. Unset options, whose value is empty, are 0.
+func parseDoubleOption(eh *driverbase.ErrorHelper, key, value string) (float64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	if durationOptions[canonicalOption(key)] {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return 0, eh.Errorf(adbc.StatusInvalidArgument, "option %s is not a duration: %s", key, value)
+		}
+		return d.Seconds(), nil
+	}
+	val, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, eh.Errorf(adbc.StatusInvalidArgument, "option %s is not a number: %s", key, value)
+	}
+	return val, nil
+}
+
+func (d *databaseImpl) SetOptionInt(key string, val int64) error {
+	return d.SetOption(key, formatIntOption(key, val))
+}
+
+func (d *databaseImpl) SetOptionDouble(key string, val float64) error {
+	return d.SetOption(key, formatDoubleOption(key, val))
+}
+
+func (d *databaseImpl) GetOptionInt(key string) (int64, error) {
+	value, err := d.GetOption(key)
+	if err != nil {
+		return 0, err
+	}
+	return parseIntOption(&d.ErrorHelper, key, value)
+}
+
+func (d *databaseImpl) GetOptionDouble(key string) (float64, error) {
+	value, err := d.GetOption(key)
+	if err != nil {
+		return 0, err
+	}
+	return parseDoubleOption(&d.ErrorHelper, key, value)
+}
+
+func (c *connectionImpl) SetOptionInt(key string, val int64) error {
+	return c.SetOption(key, formatIntOption(key, val))
+}
+
+func (c *connectionImpl) SetOptionDouble(key string, val float64) error {
+	return c.SetOption(key, formatDoubleOption(key, val))
+}
+
+func (c *connectionImpl) GetOptionInt(key string) (int64, error) {
+	value, err := c.GetOption(key)
+	if err != nil {
+		return 0, err
+	}
+	return parseIntOption(&c.ErrorHelper, key, value)
+}
+
+func (c *connectionImpl) GetOptionDouble(key string) (float64, error) {
+	value, err := c.GetOption(key)
+	if err != nil {
+		return 0, err
+	}
+	return parseDoubleOption(&c.ErrorHelper, key, value)
+}
+
+func (s *statementImpl) SetOptionInt(key string, val int64) error {
+	return s.SetOption(key, formatIntOption(key, val))
+}
+
+func (s *statementImpl) SetOptionDouble(key string, val float64) error {
+	return s.SetOption(key, formatDoubleOption(key, val))
+}
+
+func (s *statementImpl) GetOptionDouble(key string) (float64, error) {
+	if err := s.acquire(); err != nil {
+		return 0, err
+	}
+	defer s.mu.Unlock()
+
+	switch key {
+	case OptionStatementResultFetchedChunks, OptionStatementResultFetchedBatches, OptionStatementResultFetchedRows, OptionStatementResultFetchedBytes,
+		OptionStatementResultTotalChunks, OptionStatementResultTotalRows, OptionStatementResultTotalBytes,
+		OptionStatementMemoryCurrentBytes, OptionStatementMemoryPeakBytes, OptionStatementIngestShadowRowCount:
+		val, err := s.getOptionInt(key)
+		return float64(val), err
+	}
+	value, err := s.getOption(key)
+	if err != nil {
+		return 0, err
+	}
+	return parseDoubleOption(&s.ErrorHelper, key, value)
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/adbc-drivers/driverbase-go/driverbase"
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDatabaseTypedOptions(t *testing.T) {
+	d := &databaseImpl{}
+
+	require.NoError(t, d.SetOptionInt(OptionPort, 8443))
+	assert.Equal(t, 8443, d.port)
+	port, err := d.GetOptionInt(OptionPort)
+	require.NoError(t, err)
+	assert.Equal(t, int64(8443), port)
+
+	// Durations are in seconds
+	require.NoError(t, d.SetOptionInt(OptionConnectTimeout, 45))
+	assert.Equal(t, 45*time.Second, d.connectTimeout)
+	require.NoError(t, d.SetOptionDouble(OptionQueryPollInterval, 0.25))
+	assert.Equal(t, 250*time.Millisecond, d.pollInterval)
+	interval, err := d.GetOptionDouble(OptionQueryPollInterval)
+	require.NoError(t, err)
+	assert.Equal(t, 0.25, interval)
+	_, err = d.GetOptionInt(OptionQueryPollInterval)
+	assert.ErrorContains(t, err, "not a whole number of seconds")
+
+	require.NoError(t, d.SetOptionDouble(OptionMaxRows, 1000))
+	assert.Equal(t, 1000, d.maxRows)
+
+	// Unset options are 0
+	retries, err := d.GetOptionInt(OptionQueryRetryCount)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), retries)
+
+	var adbcErr adbc.Error
+	err = d.SetOptionInt(OptionPort, 70000)
+	require.ErrorAs(t, err, &adbcErr)
+	assert.Equal(t, adbc.StatusInvalidArgument, adbcErr.Code)
+
+	require.NoError(t, d.SetOption(OptionHTTPPath, "/sql/1.0/warehouses/abc"))
+	_, err = d.GetOptionInt(OptionHTTPPath)
+	require.ErrorAs(t, err, &adbcErr)
+	assert.Equal(t, adbc.StatusInvalidArgument, adbcErr.Code)
+}
+
+func TestStatementTypedOptions(t *testing.T) {
+	s := &statementImpl{
+		conn:              &connectionImpl{},
+		bulkIngestOptions: driverbase.NewBulkIngestOptions(),
+		deleteOptions:     newDeleteByKeysOptions(),
+	}
+
+	require.NoError(t, s.SetOptionInt(OptionStatementDeleteBatchSize, 250))
+	size, err := s.GetOptionInt(OptionStatementDeleteBatchSize)
+	require.NoError(t, err)
+	assert.Equal(t, int64(250), size)
+	sizeDouble, err := s.GetOptionDouble(OptionStatementDeleteBatchSize)
+	require.NoError(t, err)
+	assert.Equal(t, 250.0, sizeDouble)
+
+	s.resultStats = &resultStats{}
+	s.resultStats.rows.Store(12)
+	rows, err := s.GetOptionDouble(OptionStatementResultFetchedRows)
+	require.NoError(t, err)
+	assert.Equal(t, 12.0, rows)
+	// Totals are not known until the result is read in full
+	total, err := s.GetOptionInt(OptionStatementResultTotalRows)
+	require.NoError(t, err)
+	assert.Equal(t, int64(-1), total)
+	s.resultStats.complete.Store(true)
+	total, err = s.GetOptionInt(OptionStatementResultTotalRows)
+	require.NoError(t, err)
+	assert.Equal(t, int64(12), total)
+	val, err := s.GetOption(OptionStatementResultTotalBytes)
+	require.NoError(t, err)
+	assert.Equal(t, "0", val)
+
+	var adbcErr adbc.Error
+	err = s.SetOptionDouble(OptionStatementDeleteBatchSize, 2.5)
+	require.ErrorAs(t, err, &adbcErr)
+	assert.Equal(t, adbc.StatusInvalidArgument, adbcErr.Code)
+}
@@ -0,0 +1,166 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/databricks/databricks-sql-go/driverctx"
+)
+
+const (
+	// Most statements awaiting their metrics per connection; the metrics
+	// of statements beyond it are not counted
+	maxPendingUsageQueries = 10000
+	// Most statements whose metrics are asked for in one request
+	usageQueryBatchSize = 100
+)
+
+// Query history states of statements that will not run further
+var finalQueryStates = map[string]bool{
+	"FINISHED": true,
+	"FAILED":   true,
+	"CANCELED": true,
+}
+
+// queryMetrics are the metrics of one statement from the query history
+type queryMetrics struct {
+	ReadBytes       int64 `json:"read_bytes"`
+	TaskTotalTimeMs int64 `json:"task_total_time_ms"`
+}
+
+// connectionUsage totals the server-reported metrics of the statements
+// run by a connection. The query history only has the metrics once a
+// statement is done, so their query IDs are kept until then.
+type connectionUsage struct {
+	mu         sync.Mutex
+	pending    []string
+	statements int64
+	readBytes  int64
+	taskTimeMs int64
+}
+
+func (u *connectionUsage) add(queryID string) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if len(u.pending) < maxPendingUsageQueries {
+		u.pending = append(u.pending, queryID)
+	}
+}
+
+// withUsageTracking returns a context recording the query IDs of
+// statements run with it in the connection's usage
+func (c *connectionImpl) withUsageTracking(ctx context.Context) context.Context {
+	if c.usage == nil {
+		return ctx
+	}
+	prev, _ := ctx.Value(driverctx.QueryIdCallbackKey).(driverctx.IdCallbackFunc)
+	return driverctx.NewContextWithQueryIdCallback(ctx, func(id string) {
+		c.usage.add(id)
+		if prev != nil {
+			prev(id)
+		}
+	})
+}
+
+// queryMetrics returns the metrics of those of the given statements that
+// are done, by query ID. Statements not yet in the query history are left
+// out.
+func (w *warehouseClient) queryMetrics(ctx context.Context, ids []string) (map[string]queryMetrics, error) {
+	metrics := map[string]queryMetrics{}
+	for len(ids) > 0 {
+		batch := ids[:min(len(ids), usageQueryBatchSize)]
+		ids = ids[len(batch):]
+
+		params := url.Values{
+			"include_metrics": {"true"},
+			"max_results":     {strconv.Itoa(len(batch))},
+		}
+		params["filter_by.statement_ids"] = batch
+		var resp struct {
+			Res []struct {
+				QueryID string       `json:"query_id"`
+				Status  string       `json:"status"`
+				Metrics queryMetrics `json:"metrics"`
+			} `json:"res"`
+		}
+		if err := w.do(ctx, http.MethodGet, "/api/2.0/sql/history/queries?"+params.Encode(), nil, &resp); err != nil {
+			return nil, err
+		}
+		for _, query := range resp.Res {
+			if finalQueryStates[query.Status] {
+				metrics[query.QueryID] = query.Metrics
+			}
+		}
+	}
+	return metrics, nil
+}
+
+// refreshUsage adds the metrics of statements that are now done to the
+// connection's totals
+func (c *connectionImpl) refreshUsage(ctx context.Context) error {
+	if c.usage == nil {
+		return c.warehouseRequired("usage")
+	}
+	u := c.usage
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if len(u.pending) == 0 {
+		return nil
+	}
+
+	metrics, err := c.warehouse.queryMetrics(ctx, u.pending)
+	if err != nil {
+		return c.ErrorHelper.Errorf(adbc.StatusIO, "failed to get query metrics: %v", err)
+	}
+	pending := u.pending[:0]
+	for _, id := range u.pending {
+		m, ok := metrics[id]
+		if !ok {
+			pending = append(pending, id)
+			continue
+		}
+		u.statements++
+		u.readBytes += m.ReadBytes
+		u.taskTimeMs += m.TaskTotalTimeMs
+	}
+	u.pending = pending
+	return nil
+}
+
+// usageOption returns the value of a usage option, first adding the
+// metrics of statements done since it was last read
+func (c *connectionImpl) usageOption(key string) (string, error) {
+	if err := c.refreshUsage(context.Background()); err != nil {
+		return "", err
+	}
+	c.usage.mu.Lock()
+	defer c.usage.mu.Unlock()
+	switch key {
+	case OptionConnectionUsageStatementCount:
+		return strconv.FormatInt(c.usage.statements, 10), nil
+	case OptionConnectionUsageReadBytes:
+		return strconv.FormatInt(c.usage.readBytes, 10), nil
+	case OptionConnectionUsageTaskTimeMs:
+		return strconv.FormatInt(c.usage.taskTimeMs, 10), nil
+	default:
+		return strconv.Itoa(len(c.usage.pending)), nil
+	}
+}
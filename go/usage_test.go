@@ -0,0 +1,99 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/databricks/databricks-sql-go/driverctx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConnectionUsage(t *testing.T) {
+	// q3 is still running, and q4 is not in the query history yet
+	states := map[string]string{"q1": "FINISHED", "q2": "FAILED", "q3": "RUNNING"}
+	var requested [][]string
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/2.0/sql/history/queries" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		assert.Equal(t, "true", r.URL.Query().Get("include_metrics"))
+		ids := r.URL.Query()["filter_by.statement_ids"]
+		requested = append(requested, ids)
+		var res []map[string]any
+		for _, id := range ids {
+			if state, ok := states[id]; ok {
+				res = append(res, map[string]any{
+					"query_id": id,
+					"status":   state,
+					"metrics":  map[string]int64{"read_bytes": 1000, "task_total_time_ms": 20},
+				})
+			}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"res": res})
+	}))
+	defer srv.Close()
+
+	d := newWarehouseTestDatabase(t, srv, "")
+	c := &connectionImpl{warehouse: d.warehouse, usage: &connectionUsage{}}
+
+	// The driver reports query IDs through the context, as do callbacks
+	// set before tracking
+	var logged []string
+	ctx := driverctx.NewContextWithQueryIdCallback(context.Background(), func(id string) {
+		logged = append(logged, id)
+	})
+	ctx = c.withUsageTracking(ctx)
+	report := ctx.Value(driverctx.QueryIdCallbackKey).(driverctx.IdCallbackFunc)
+	for _, id := range []string{"q1", "q2", "q3", "q4"} {
+		report(id)
+	}
+	assert.Equal(t, []string{"q1", "q2", "q3", "q4"}, logged)
+
+	usage := func(key string) string {
+		val, err := c.GetOption(key)
+		require.NoError(t, err)
+		return val
+	}
+	assert.Equal(t, "2000", usage(OptionConnectionUsageReadBytes))
+	assert.Equal(t, "40", usage(OptionConnectionUsageTaskTimeMs))
+	assert.Equal(t, "2", usage(OptionConnectionUsageStatementCount))
+	assert.Equal(t, "2", usage(OptionConnectionUsagePendingCount))
+	assert.Equal(t, []string{"q1", "q2", "q3", "q4"}, requested[0])
+	assert.Equal(t, []string{"q3", "q4"}, requested[1])
+
+	states["q3"] = "FINISHED"
+	states["q4"] = "CANCELED"
+	assert.Equal(t, "4", usage(OptionConnectionUsageStatementCount))
+	assert.Equal(t, "4000", usage(OptionConnectionUsageReadBytes))
+	assert.Equal(t, "0", usage(OptionConnectionUsagePendingCount))
+}
+
+func TestConnectionUsageRequiresWarehouse(t *testing.T) {
+	c := &connectionImpl{}
+	ctx := context.Background()
+	assert.Equal(t, ctx, c.withUsageTracking(ctx))
+	_, err := c.GetOption(OptionConnectionUsageReadBytes)
+	var adbcErr adbc.Error
+	require.ErrorAs(t, err, &adbcErr)
+	assert.Equal(t, adbc.StatusNotImplemented, adbcErr.Code)
+}
@@ -0,0 +1,253 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/databricks/databricks-sql-go/auth"
+	"github.com/databricks/databricks-sql-go/auth/pat"
+)
+
+const (
+	// Interval between warehouse state checks while waiting for it to start
+	defaultWarehousePollInterval = 5 * time.Second
+
+	warehouseStateRunning  = "RUNNING"
+	warehouseStateStopped  = "STOPPED"
+	warehouseStateDeleting = "DELETING"
+	warehouseStateDeleted  = "DELETED"
+)
+
+// warehouseHTTPPathRe extracts the warehouse ID from an HTTP path
+var warehouseHTTPPathRe = regexp.MustCompile(`^/?sql/1\.0/(?:warehouses|endpoints)/([^/?]+)`)
+
+// warehouseClient calls the REST APIs of the workspace for a SQL
+// warehouse: the SQL warehouses API and the Statement Execution API
+type warehouseClient struct {
+	baseURL     string
+	warehouseID string
+	client      *http.Client
+	// Downloads presigned links to cloud storage, without the workspace's
+	// headers or a timeout for reading the whole body
+	downloadClient *http.Client
+	authr          auth.Authenticator
+	logger         *slog.Logger
+	pollInterval   time.Duration
+}
+
+// newWarehouseClient creates a client for the warehouse named by the HTTP
+// path, sending requests through transport and authenticating them with
+// authr, or with the access token if authr is nil. Results are downloaded
+// from cloud storage through downloads.
+func (d *databaseImpl) newWarehouseClient(transport, downloads http.RoundTripper, authr auth.Authenticator) (*warehouseClient, error) {
+	compute := httpPathCompute(d.httpPath)
+	switch compute.kind {
+	case InfoValueComputeCluster:
+		return nil, adbc.Error{
+			Code: adbc.StatusInvalidArgument,
+			Msg: fmt.Sprintf("[db] %s requires the HTTP path of a SQL warehouse, got %s, which is an all-purpose cluster's",
+				OptionWarehouseWaitForStart, d.httpPath),
+		}
+	case InfoValueComputeUnknown:
+		return nil, adbc.Error{
+			Code: adbc.StatusInvalidArgument,
+			Msg:  fmt.Sprintf("[db] %s requires the HTTP path of a SQL warehouse, got %s", OptionWarehouseWaitForStart, d.httpPath),
+		}
+	}
+
+	if transport == nil {
+		transport = d.newPooledTransport(nil, d.proxyFunc())
+	}
+	if downloads == nil {
+		downloads = d.newPooledTransport(nil, d.proxyFunc())
+	}
+	if authr == nil {
+		authr = &pat.PATAuth{AccessToken: d.accessToken}
+	}
+	port, err := d.endpointPort()
+	if err != nil {
+		return nil, err
+	}
+
+	return &warehouseClient{
+		baseURL:        fmt.Sprintf("https://%s:%d", d.serverHostname, port),
+		warehouseID:    compute.id,
+		client:         &http.Client{Transport: transport, Timeout: 30 * time.Second},
+		downloadClient: &http.Client{Transport: downloads},
+		authr:          authr,
+		logger:         d.Logger,
+		pollInterval:   defaultWarehousePollInterval,
+	}, nil
+}
+
+// do sends a request to the REST API, with in encoded as its JSON body
+// and the JSON response decoded into out, if they are not nil
+func (w *warehouseClient) do(ctx context.Context, method, path string, in, out any) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, w.baseURL+path, body)
+	if err != nil {
+		return err
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if err := w.authr.Authenticate(req); err != nil {
+		return err
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		var body struct {
+			ErrorCode string `json:"error_code"`
+			Message   string `json:"message"`
+		}
+		_ = json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&body)
+		return fmt.Errorf("%s %s failed with status %d: %s %s", method, path, resp.StatusCode, body.ErrorCode, body.Message)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// warehouseDetails are the properties of a warehouse that the driver
+// reads from the SQL warehouses API
+type warehouseDetails struct {
+	State                   string `json:"state"`
+	WarehouseType           string `json:"warehouse_type"`
+	EnableServerlessCompute bool   `json:"enable_serverless_compute"`
+}
+
+// details returns the current properties of the warehouse
+func (w *warehouseClient) details(ctx context.Context) (warehouseDetails, error) {
+	var body warehouseDetails
+	err := w.do(ctx, http.MethodGet, "/api/2.0/sql/warehouses/"+w.warehouseID, nil, &body)
+	return body, err
+}
+
+// state returns the current state of the warehouse
+func (w *warehouseClient) state(ctx context.Context) (string, error) {
+	details, err := w.details(ctx)
+	return details.State, err
+}
+
+// kind returns the type of the warehouse, as OptionWarehouseType.
+// Serverless warehouses are of type PRO.
+func (wd warehouseDetails) kind() string {
+	switch {
+	case wd.EnableServerlessCompute:
+		return OptionValueWarehouseTypeServerless
+	case wd.WarehouseType == "PRO":
+		return OptionValueWarehouseTypePro
+	case wd.WarehouseType == "CLASSIC":
+		return OptionValueWarehouseTypeClassic
+	}
+	return OptionValueWarehouseTypeUnknown
+}
+
+// waitForStart starts the warehouse if it is stopped and waits up to
+// maxWait for it to be running, logging its progress
+func (w *warehouseClient) waitForStart(ctx context.Context, maxWait time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, maxWait)
+	defer cancel()
+
+	start := time.Now()
+	waited := false
+	requestedStart := false
+	for {
+		state, err := w.state(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return w.timeoutError(ctx, maxWait)
+			}
+			return adbc.Error{
+				Code: adbc.StatusIO,
+				Msg:  fmt.Sprintf("[db] failed to get SQL warehouse state: %v", err),
+			}
+		}
+
+		switch state {
+		case warehouseStateRunning:
+			if waited {
+				w.logInfo("SQL warehouse is running", "warehouse_id", w.warehouseID, "waited", time.Since(start).Round(time.Second))
+			}
+			return nil
+		case warehouseStateDeleting, warehouseStateDeleted:
+			return adbc.Error{
+				Code: adbc.StatusNotFound,
+				Msg:  fmt.Sprintf("[db] SQL warehouse %s is %s", w.warehouseID, state),
+			}
+		case warehouseStateStopped:
+			if !requestedStart {
+				if err := w.do(ctx, http.MethodPost, "/api/2.0/sql/warehouses/"+w.warehouseID+"/start", nil, nil); err != nil {
+					return adbc.Error{
+						Code: adbc.StatusIO,
+						Msg:  fmt.Sprintf("[db] failed to start SQL warehouse: %v", err),
+					}
+				}
+				requestedStart = true
+			}
+		}
+
+		waited = true
+		w.logInfo("waiting for SQL warehouse to start", "warehouse_id", w.warehouseID, "state", state, "elapsed", time.Since(start).Round(time.Second))
+		select {
+		case <-ctx.Done():
+			return w.timeoutError(ctx, maxWait)
+		case <-time.After(w.pollInterval):
+		}
+	}
+}
+
+// timeoutError reports why waiting ended early: the caller's context, or
+// the warehouse not starting in time
+func (w *warehouseClient) timeoutError(ctx context.Context, maxWait time.Duration) error {
+	if err := context.Cause(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+	return adbc.Error{
+		Code: adbc.StatusTimeout,
+		Msg:  fmt.Sprintf("[db] SQL warehouse %s did not start within %s", w.warehouseID, maxWait),
+	}
+}
+
+func (w *warehouseClient) logInfo(msg string, args ...any) {
+	if w.logger != nil {
+		w.logger.Info(msg, args...)
+	}
+}
@@ -0,0 +1,185 @@
+// Copyright (c) 2026 ADBC Drivers Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//         http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databricks
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newWarehouseServer serves the warehouse API for warehouse "abc", which
+// is running after the given number of state checks following a start
+func newWarehouseServer(t *testing.T, initial string, checksUntilRunning int) (*httptest.Server, *[]string) {
+	var mu sync.Mutex
+	var calls []string
+	state := initial
+	remaining := checksUntilRunning
+
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		assert.Equal(t, "Bearer dapi-token", r.Header.Get("Authorization"))
+		calls = append(calls, r.Method+" "+r.URL.Path)
+
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/api/2.0/sql/warehouses/abc/start":
+			state = "STARTING"
+		case r.Method == http.MethodGet && r.URL.Path == "/api/2.0/sql/warehouses/abc":
+			if state == "STARTING" {
+				if remaining == 0 {
+					state = "RUNNING"
+				}
+				remaining--
+			}
+			_ = json.NewEncoder(w).Encode(map[string]string{"id": "abc", "state": state})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	return srv, &calls
+}
+
+// newWarehouseTestDatabase configures a database to trust and reach srv
+func newWarehouseTestDatabase(t *testing.T, srv *httptest.Server, wait string) *databaseImpl {
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+	port, err := strconv.Atoi(u.Port())
+	require.NoError(t, err)
+	pool := x509.NewCertPool()
+	pool.AddCert(srv.Certificate())
+
+	d := &databaseImpl{
+		serverHostname: u.Hostname(),
+		port:           port,
+		httpPath:       "/sql/1.0/warehouses/abc",
+		accessToken:    "dapi-token",
+		sslCertPool:    pool,
+	}
+	require.NoError(t, d.SetOption(OptionWarehouseWaitForStart, wait))
+	_, err = d.resolveConnectionOptions()
+	require.NoError(t, err)
+	require.NotNil(t, d.warehouse)
+	d.warehouse.pollInterval = 10 * time.Millisecond
+	return d
+}
+
+func TestWarehouseWaitForStart(t *testing.T) {
+	srv, calls := newWarehouseServer(t, "STOPPED", 2)
+	defer srv.Close()
+
+	d := newWarehouseTestDatabase(t, srv, "1m")
+	require.NoError(t, d.warehouse.waitForStart(context.Background(), d.warehouseWaitForStart))
+	assert.Equal(t, []string{
+		"GET /api/2.0/sql/warehouses/abc",
+		"POST /api/2.0/sql/warehouses/abc/start",
+		"GET /api/2.0/sql/warehouses/abc",
+		"GET /api/2.0/sql/warehouses/abc",
+		"GET /api/2.0/sql/warehouses/abc",
+	}, *calls)
+}
+
+func TestWarehouseWaitForStartTimeout(t *testing.T) {
+	srv, _ := newWarehouseServer(t, "STOPPED", 1000)
+	defer srv.Close()
+
+	d := newWarehouseTestDatabase(t, srv, "50ms")
+	err := d.warehouse.waitForStart(context.Background(), d.warehouseWaitForStart)
+	var adbcErr adbc.Error
+	require.ErrorAs(t, err, &adbcErr)
+	assert.Equal(t, adbc.StatusTimeout, adbcErr.Code)
+}
+
+func TestWarehouseWaitForStartRequiresWarehousePath(t *testing.T) {
+	d := &databaseImpl{
+		serverHostname: "example.cloud.databricks.com",
+		httpPath:       "/sql/protocolv1/o/123/0123-456789-abcdef",
+		accessToken:    "dapi-token",
+	}
+	require.NoError(t, d.SetOption(OptionWarehouseWaitForStart, "5m"))
+	_, err := d.resolveConnectionOptions()
+	var adbcErr adbc.Error
+	require.ErrorAs(t, err, &adbcErr)
+	assert.Equal(t, adbc.StatusInvalidArgument, adbcErr.Code)
+	assert.Contains(t, adbcErr.Msg, "an all-purpose cluster's")
+}
+
+func TestWarehouseTypeDetection(t *testing.T) {
+	var body map[string]any
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if body == nil {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(body)
+	}))
+	defer srv.Close()
+	d := newWarehouseTestDatabase(t, srv, "")
+
+	for _, tc := range []struct {
+		body map[string]any
+		want string
+	}{
+		{map[string]any{"warehouse_type": "PRO", "enable_serverless_compute": true}, OptionValueWarehouseTypeServerless},
+		{map[string]any{"warehouse_type": "PRO", "enable_serverless_compute": false}, OptionValueWarehouseTypePro},
+		{map[string]any{"warehouse_type": "CLASSIC"}, OptionValueWarehouseTypeClassic},
+		{map[string]any{"warehouse_type": "TYPE_UNSPECIFIED"}, OptionValueWarehouseTypeUnknown},
+		// Reading the type is not required to connect
+		{nil, OptionValueWarehouseTypeUnknown},
+	} {
+		body = tc.body
+		assert.Equal(t, tc.want, d.detectWarehouseType(context.Background()), "%v", tc.body)
+	}
+
+	c := &connectionImpl{warehouseType: OptionValueWarehouseTypeServerless}
+	val, err := c.GetOption(OptionWarehouseType)
+	require.NoError(t, err)
+	assert.Equal(t, OptionValueWarehouseTypeServerless, val)
+}
+
+func TestUseCachedResult(t *testing.T) {
+	d := &databaseImpl{
+		serverHostname: "example.cloud.databricks.com",
+		httpPath:       "/sql/1.0/warehouses/abc",
+		accessToken:    "dapi-token",
+	}
+	require.NoError(t, d.SetOption(OptionUseCachedResult, "FALSE"))
+	val, err := d.GetOption(OptionUseCachedResult)
+	require.NoError(t, err)
+	assert.Equal(t, "false", val)
+	_, err = d.resolveConnectionOptions()
+	require.NoError(t, err)
+
+	var adbcErr adbc.Error
+	require.ErrorAs(t, d.SetOption(OptionUseCachedResult, "sometimes"), &adbcErr)
+	assert.Equal(t, adbc.StatusInvalidArgument, adbcErr.Code)
+
+	// Clusters have no result cache
+	require.NoError(t, d.SetOption(OptionHTTPPath, "/sql/protocolv1/o/123/0123-456789-abcdef"))
+	_, err = d.resolveConnectionOptions()
+	require.ErrorAs(t, err, &adbcErr)
+	assert.Contains(t, adbcErr.Msg, "all-purpose clusters have no result cache")
+}